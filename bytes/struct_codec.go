@@ -0,0 +1,271 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbytes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// ErrShortBuffer is returned when a buffer is shorter than the packed size of a struct.
+var ErrShortBuffer = errors.New("gxbytes: short buffer")
+
+var (
+	// BigEndianCodec packs structs in network byte order.
+	BigEndianCodec = NewStructCodec(binary.BigEndian)
+	// LittleEndianCodec packs structs in little endian byte order.
+	LittleEndianCodec = NewStructCodec(binary.LittleEndian)
+)
+
+type packKind uint8
+
+const (
+	packBool packKind = iota
+	pack8
+	pack16
+	pack32
+	pack64
+	packBytes
+)
+
+// packOp packs a fixed size field locating at @offset of a struct.
+type packOp struct {
+	offset uintptr
+	kind   packKind
+	size   int
+}
+
+// structLayout is the flattened packing plan of a struct type.
+type structLayout struct {
+	ops  []packOp
+	size int
+}
+
+var layouts sync.Map // reflect.Type -> *structLayout
+
+// StructCodec packs structs of fixed layouts, such as binary protocol headers, in the
+// field order without padding. The layout of a struct type is resolved by reflection
+// only once, after which packing is plain memory copies.
+//
+// The supported fields are bool, the sized integers, float32, float64, and the arrays
+// and structs of them. int, uint, uintptr, pointers, slices, strings and maps have no
+// fixed size and are rejected. A field tagged `pack:"-"` or a blank field is skipped.
+type StructCodec struct {
+	order binary.ByteOrder
+}
+
+// NewStructCodec returns a codec packing the multi-byte fields in @order.
+func NewStructCodec(order binary.ByteOrder) *StructCodec {
+	if order == nil {
+		panic("@order is nil")
+	}
+
+	return &StructCodec{order: order}
+}
+
+// Size returns the packed size of @v which is a struct or a pointer to a struct.
+func (c *StructCodec) Size(v interface{}) (int, error) {
+	typ := reflect.TypeOf(v)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	l, err := layoutOf(typ)
+	if err != nil {
+		return 0, err
+	}
+
+	return l.size, nil
+}
+
+// Pack writes the struct pointed by @v into @dst, and returns the number of bytes written.
+func (c *StructCodec) Pack(dst []byte, v interface{}) (int, error) {
+	l, p, err := structPointer(v)
+	if err != nil {
+		return 0, err
+	}
+	if len(dst) < l.size {
+		return 0, ErrShortBuffer
+	}
+
+	c.pack(l, dst, p)
+
+	return l.size, nil
+}
+
+// Marshal packs the struct pointed by @v into a buffer acquired from the default
+// BytesPool. The buffer should be given back by ReleaseBytes after use.
+func (c *StructCodec) Marshal(v interface{}) (*[]byte, error) {
+	l, p, err := structPointer(v)
+	if err != nil {
+		return nil, err
+	}
+
+	bufp := AcquireBytes(l.size)
+	*bufp = (*bufp)[:l.size]
+	c.pack(l, *bufp, p)
+
+	return bufp, nil
+}
+
+// Unpack reads the struct pointed by @v from @src, and returns the number of bytes read.
+func (c *StructCodec) Unpack(src []byte, v interface{}) (int, error) {
+	l, p, err := structPointer(v)
+	if err != nil {
+		return 0, err
+	}
+	if len(src) < l.size {
+		return 0, ErrShortBuffer
+	}
+
+	pos := 0
+	for _, op := range l.ops {
+		f := unsafe.Pointer(uintptr(p) + op.offset)
+		switch op.kind {
+		case packBool:
+			*(*bool)(f) = src[pos] != 0
+		case pack8:
+			*(*uint8)(f) = src[pos]
+		case pack16:
+			*(*uint16)(f) = c.order.Uint16(src[pos:])
+		case pack32:
+			*(*uint32)(f) = c.order.Uint32(src[pos:])
+		case pack64:
+			*(*uint64)(f) = c.order.Uint64(src[pos:])
+		case packBytes:
+			copy(unsafe.Slice((*byte)(f), op.size), src[pos:pos+op.size])
+		}
+		pos += op.size
+	}
+
+	return l.size, nil
+}
+
+func (c *StructCodec) pack(l *structLayout, dst []byte, p unsafe.Pointer) {
+	pos := 0
+	for _, op := range l.ops {
+		f := unsafe.Pointer(uintptr(p) + op.offset)
+		switch op.kind {
+		case packBool:
+			dst[pos] = 0
+			if *(*bool)(f) {
+				dst[pos] = 1
+			}
+		case pack8:
+			dst[pos] = *(*uint8)(f)
+		case pack16:
+			c.order.PutUint16(dst[pos:], *(*uint16)(f))
+		case pack32:
+			c.order.PutUint32(dst[pos:], *(*uint32)(f))
+		case pack64:
+			c.order.PutUint64(dst[pos:], *(*uint64)(f))
+		case packBytes:
+			copy(dst[pos:pos+op.size], unsafe.Slice((*byte)(f), op.size))
+		}
+		pos += op.size
+	}
+}
+
+// structPointer returns the layout and the address of the struct pointed by @v.
+func structPointer(v interface{}) (*structLayout, unsafe.Pointer, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, nil, fmt.Errorf("gxbytes: %T is not a non-nil pointer to a struct", v)
+	}
+
+	l, err := layoutOf(rv.Type().Elem())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return l, unsafe.Pointer(rv.Pointer()), nil
+}
+
+func layoutOf(typ reflect.Type) (*structLayout, error) {
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("gxbytes: %v is not a struct", typ)
+	}
+	if l, ok := layouts.Load(typ); ok {
+		return l.(*structLayout), nil
+	}
+
+	l := &structLayout{}
+	if err := l.appendType(typ, 0); err != nil {
+		return nil, err
+	}
+	actual, _ := layouts.LoadOrStore(typ, l)
+
+	return actual.(*structLayout), nil
+}
+
+func (l *structLayout) appendOp(offset uintptr, kind packKind, size int) {
+	// the adjacent byte ops are merged into a single copy
+	if kind == pack8 || kind == packBytes {
+		if n := len(l.ops); n > 0 {
+			last := &l.ops[n-1]
+			if (last.kind == pack8 || last.kind == packBytes) && last.offset+uintptr(last.size) == offset {
+				last.kind = packBytes
+				last.size += size
+				l.size += size
+				return
+			}
+		}
+	}
+
+	l.ops = append(l.ops, packOp{offset: offset, kind: kind, size: size})
+	l.size += size
+}
+
+func (l *structLayout) appendType(typ reflect.Type, offset uintptr) error {
+	switch typ.Kind() {
+	case reflect.Bool:
+		l.appendOp(offset, packBool, 1)
+	case reflect.Int8, reflect.Uint8:
+		l.appendOp(offset, pack8, 1)
+	case reflect.Int16, reflect.Uint16:
+		l.appendOp(offset, pack16, 2)
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		l.appendOp(offset, pack32, 4)
+	case reflect.Int64, reflect.Uint64, reflect.Float64:
+		l.appendOp(offset, pack64, 8)
+	case reflect.Array:
+		elem := typ.Elem()
+		for i := 0; i < typ.Len(); i++ {
+			if err := l.appendType(elem, offset+uintptr(i)*elem.Size()); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if f.Name == "_" || f.Tag.Get("pack") == "-" {
+				continue
+			}
+			if err := l.appendType(f.Type, offset+f.Offset); err != nil {
+				return fmt.Errorf("gxbytes: field %s.%s: %w", typ, f.Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("type %v has no fixed packed size", typ)
+	}
+
+	return nil
+}
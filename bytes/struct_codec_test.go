@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbytes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type packedFlags struct {
+	Compressed bool
+	Priority   int8
+}
+
+type packedHeader struct {
+	Magic   [2]byte
+	Version uint8
+	Flags   packedFlags
+	Length  uint32
+	Seq     int64
+	Weight  float32
+	Ports   [2]uint16
+	cache   string `pack:"-"`
+}
+
+func TestStructCodec(t *testing.T) {
+	h := packedHeader{
+		Magic:   [2]byte{0xda, 0xbb},
+		Version: 2,
+		Flags:   packedFlags{Compressed: true, Priority: -3},
+		Length:  1024,
+		Seq:     -42,
+		Weight:  1.5,
+		Ports:   [2]uint16{80, 443},
+		cache:   "skipped",
+	}
+
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		c := NewStructCodec(order)
+		size, err := c.Size(h)
+		assert.Nil(t, err)
+		assert.Equal(t, 2+1+2+4+8+4+4, size)
+
+		// the same bytes as encoding/binary without the skipped field
+		var expected bytes.Buffer
+		assert.Nil(t, binary.Write(&expected, order, struct {
+			Magic   [2]byte
+			Version uint8
+			Flags   packedFlags
+			Length  uint32
+			Seq     int64
+			Weight  float32
+			Ports   [2]uint16
+		}{h.Magic, h.Version, h.Flags, h.Length, h.Seq, h.Weight, h.Ports}))
+
+		buf := make([]byte, size)
+		n, err := c.Pack(buf, &h)
+		assert.Nil(t, err)
+		assert.Equal(t, size, n)
+		assert.Equal(t, expected.Bytes(), buf)
+
+		var got packedHeader
+		n, err = c.Unpack(buf, &got)
+		assert.Nil(t, err)
+		assert.Equal(t, size, n)
+		h2 := h
+		h2.cache = ""
+		assert.Equal(t, h2, got)
+
+		bufp, err := c.Marshal(&h)
+		assert.Nil(t, err)
+		assert.Equal(t, buf, *bufp)
+		ReleaseBytes(bufp)
+	}
+}
+
+func TestStructCodecErrors(t *testing.T) {
+	var h packedHeader
+	_, err := BigEndianCodec.Pack(make([]byte, 3), &h)
+	assert.Equal(t, ErrShortBuffer, err)
+	_, err = BigEndianCodec.Unpack(make([]byte, 3), &h)
+	assert.Equal(t, ErrShortBuffer, err)
+
+	_, err = BigEndianCodec.Pack(make([]byte, 64), h)
+	assert.NotNil(t, err)
+	_, err = BigEndianCodec.Size(3)
+	assert.NotNil(t, err)
+
+	type unsized struct {
+		A uint16
+		B int
+	}
+	_, err = BigEndianCodec.Size(unsized{})
+	assert.NotNil(t, err)
+	_, err = BigEndianCodec.Pack(make([]byte, 64), &struct{ S []byte }{})
+	assert.NotNil(t, err)
+}
+
+func BenchmarkStructCodecPack(b *testing.B) {
+	h := packedHeader{Length: 1024, Seq: 7}
+	buf := make([]byte, 64)
+	for i := 0; i < b.N; i++ {
+		_, _ = BigEndianCodec.Pack(buf, &h)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbitset
+
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+// AtomicBitSet is a set of bits [0, Len) whose operations are atomic per bit, so it
+// can be shared without locks. A multi-bit read such as Count is not a snapshot of
+// a single instant.
+type AtomicBitSet struct {
+	n     int
+	words []uint64
+}
+
+// NewAtomic returns an atomic set of @n clear bits.
+func NewAtomic(n int) *AtomicBitSet {
+	if n < 0 {
+		panic("@n < 0")
+	}
+
+	return &AtomicBitSet{n: n, words: make([]uint64, wordsOf(n))}
+}
+
+func (b *AtomicBitSet) locate(i int) (*uint64, uint64) {
+	if i < 0 || i >= b.n {
+		panic("bit index out of range")
+	}
+
+	return &b.words[i/wordBits], 1 << (uint(i) % wordBits)
+}
+
+// Len returns the number of bits.
+func (b *AtomicBitSet) Len() int {
+	return b.n
+}
+
+// Set sets bit @i, and returns false if it has already been set.
+func (b *AtomicBitSet) Set(i int) bool {
+	addr, mask := b.locate(i)
+	for {
+		old := atomic.LoadUint64(addr)
+		if old&mask != 0 {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(addr, old, old|mask) {
+			return true
+		}
+	}
+}
+
+// Clear clears bit @i, and returns false if it has already been clear.
+func (b *AtomicBitSet) Clear(i int) bool {
+	addr, mask := b.locate(i)
+	for {
+		old := atomic.LoadUint64(addr)
+		if old&mask == 0 {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(addr, old, old&^mask) {
+			return true
+		}
+	}
+}
+
+// Test returns whether bit @i is set.
+func (b *AtomicBitSet) Test(i int) bool {
+	addr, mask := b.locate(i)
+	return atomic.LoadUint64(addr)&mask != 0
+}
+
+// Count returns the number of the set bits.
+func (b *AtomicBitSet) Count() int {
+	count := 0
+	for i := range b.words {
+		count += bits.OnesCount64(atomic.LoadUint64(&b.words[i]))
+	}
+
+	return count
+}
+
+// NextClear returns the first clear bit from @i on, or false if there is none.
+func (b *AtomicBitSet) NextClear(i int) (int, bool) {
+	return nextBit(b.load(), b.n, i, true)
+}
+
+// Acquire sets the first clear bit from @hint on, wrapping around to 0, and returns
+// it, or false if all the bits are set. It allocates an ID in [0, Len) without locks,
+// and Clear releases it. Passing the last acquired ID + 1 as @hint spreads the reuse.
+func (b *AtomicBitSet) Acquire(hint int) (int, bool) {
+	if b.n == 0 {
+		return 0, false
+	}
+	if hint < 0 || hint >= b.n {
+		hint = 0
+	}
+
+	for scanned, i := 0, hint; scanned < b.n; {
+		addr := &b.words[i/wordBits]
+		old := atomic.LoadUint64(addr)
+		free := ^old >> (uint(i) % wordBits)
+		if free == 0 {
+			// skip to the next word
+			next := (i/wordBits + 1) * wordBits
+			scanned += next - i
+			i = next
+			if i >= b.n {
+				i = 0
+			}
+			continue
+		}
+
+		j := i + bits.TrailingZeros64(free)
+		if j >= b.n {
+			scanned += b.n - i
+			i = 0
+			continue
+		}
+		if atomic.CompareAndSwapUint64(addr, old, old|1<<(uint(j)%wordBits)) {
+			return j, true
+		}
+		// lost the race, retry the same word
+	}
+
+	return 0, false
+}
+
+// Snapshot copies the bits into a BitSet.
+func (b *AtomicBitSet) Snapshot() *BitSet {
+	return &BitSet{n: b.n, words: b.load()}
+}
+
+func (b *AtomicBitSet) load() []uint64 {
+	words := make([]uint64, len(b.words))
+	for i := range b.words {
+		words[i] = atomic.LoadUint64(&b.words[i])
+	}
+
+	return words
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbitset
+
+import (
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicBitSet(t *testing.T) {
+	b := NewAtomic(100)
+	assert.True(t, b.Set(70))
+	assert.False(t, b.Set(70))
+	assert.True(t, b.Test(70))
+	assert.Equal(t, 1, b.Count())
+
+	i, ok := b.NextClear(70)
+	assert.True(t, ok)
+	assert.Equal(t, 71, i)
+
+	s := b.Snapshot()
+	assert.True(t, b.Clear(70))
+	assert.False(t, b.Clear(70))
+	assert.True(t, s.Test(70))
+	assert.Equal(t, 0, b.Count())
+}
+
+func TestAtomicBitSetAcquire(t *testing.T) {
+	b := NewAtomic(70)
+	i, ok := b.Acquire(68)
+	assert.True(t, ok)
+	assert.Equal(t, 68, i)
+	i, _ = b.Acquire(69)
+	assert.Equal(t, 69, i)
+	// wraps around
+	i, _ = b.Acquire(69)
+	assert.Equal(t, 0, i)
+
+	for n := 3; n < 70; n++ {
+		_, ok = b.Acquire(0)
+		assert.True(t, ok)
+	}
+	_, ok = b.Acquire(33)
+	assert.False(t, ok)
+
+	b.Clear(40)
+	i, ok = b.Acquire(50)
+	assert.True(t, ok)
+	assert.Equal(t, 40, i)
+
+	_, ok = NewAtomic(0).Acquire(0)
+	assert.False(t, ok)
+}
+
+func TestAtomicBitSetConcurrentAcquire(t *testing.T) {
+	const n = 1000
+	b := NewAtomic(n)
+
+	var (
+		wg  sync.WaitGroup
+		ids = make(chan int, n)
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for {
+				i, ok := b.Acquire(g * 100)
+				if !ok {
+					return
+				}
+				ids <- i
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool)
+	for i := range ids {
+		assert.False(t, seen[i], i)
+		seen[i] = true
+	}
+	assert.Equal(t, n, len(seen))
+	assert.Equal(t, n, b.Count())
+}
@@ -0,0 +1,195 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxbitset implements fixed length bit sets, a plain one and an atomic one
+// for lock-free ID allocation such as stream IDs and slot occupancy.
+package gxbitset
+
+import (
+	"math/bits"
+)
+
+const wordBits = 64
+
+func wordsOf(n int) int {
+	return (n + wordBits - 1) / wordBits
+}
+
+// BitSet is a set of bits [0, Len). It is not safe for concurrent use.
+type BitSet struct {
+	n     int
+	words []uint64
+}
+
+// New returns a set of @n clear bits.
+func New(n int) *BitSet {
+	if n < 0 {
+		panic("@n < 0")
+	}
+
+	return &BitSet{n: n, words: make([]uint64, wordsOf(n))}
+}
+
+func (b *BitSet) check(i int) {
+	if i < 0 || i >= b.n {
+		panic("bit index out of range")
+	}
+}
+
+// Len returns the number of bits.
+func (b *BitSet) Len() int {
+	return b.n
+}
+
+// Set sets bit @i.
+func (b *BitSet) Set(i int) {
+	b.check(i)
+	b.words[i/wordBits] |= 1 << (uint(i) % wordBits)
+}
+
+// Clear clears bit @i.
+func (b *BitSet) Clear(i int) {
+	b.check(i)
+	b.words[i/wordBits] &^= 1 << (uint(i) % wordBits)
+}
+
+// Flip inverts bit @i.
+func (b *BitSet) Flip(i int) {
+	b.check(i)
+	b.words[i/wordBits] ^= 1 << (uint(i) % wordBits)
+}
+
+// Test returns whether bit @i is set.
+func (b *BitSet) Test(i int) bool {
+	b.check(i)
+	return b.words[i/wordBits]&(1<<(uint(i)%wordBits)) != 0
+}
+
+// Count returns the number of the set bits.
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+
+	return count
+}
+
+// NextSet returns the first set bit from @i on, or false if there is none.
+func (b *BitSet) NextSet(i int) (int, bool) {
+	return nextBit(b.words, b.n, i, false)
+}
+
+// NextClear returns the first clear bit from @i on, or false if there is none.
+func (b *BitSet) NextClear(i int) (int, bool) {
+	return nextBit(b.words, b.n, i, true)
+}
+
+// ClearAll clears all the bits.
+func (b *BitSet) ClearAll() {
+	for i := range b.words {
+		b.words[i] = 0
+	}
+}
+
+// Clone returns a copy of the set.
+func (b *BitSet) Clone() *BitSet {
+	return &BitSet{n: b.n, words: append([]uint64(nil), b.words...)}
+}
+
+// Equals returns whether @other has the same length and bits.
+func (b *BitSet) Equals(other *BitSet) bool {
+	if b.n != other.n {
+		return false
+	}
+	for i, w := range b.words {
+		if w != other.words[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (b *BitSet) checkLen(other *BitSet) {
+	if b.n != other.n {
+		panic("bit set length mismatch")
+	}
+}
+
+// And keeps the bits set in both sets. @other should be of the same length.
+func (b *BitSet) And(other *BitSet) *BitSet {
+	b.checkLen(other)
+	for i, w := range other.words {
+		b.words[i] &= w
+	}
+
+	return b
+}
+
+// Or sets the bits set in @other, which should be of the same length.
+func (b *BitSet) Or(other *BitSet) *BitSet {
+	b.checkLen(other)
+	for i, w := range other.words {
+		b.words[i] |= w
+	}
+
+	return b
+}
+
+// Xor flips the bits set in @other, which should be of the same length.
+func (b *BitSet) Xor(other *BitSet) *BitSet {
+	b.checkLen(other)
+	for i, w := range other.words {
+		b.words[i] ^= w
+	}
+
+	return b
+}
+
+// AndNot clears the bits set in @other, which should be of the same length.
+func (b *BitSet) AndNot(other *BitSet) *BitSet {
+	b.checkLen(other)
+	for i, w := range other.words {
+		b.words[i] &^= w
+	}
+
+	return b
+}
+
+// nextBit scans @words of @n bits for the first bit from @i on which is clear if
+// @clear, or set otherwise.
+func nextBit(words []uint64, n, i int, clear bool) (int, bool) {
+	if i < 0 {
+		i = 0
+	}
+	for ; i < n; i = (i/wordBits + 1) * wordBits {
+		w := words[i/wordBits]
+		if clear {
+			w = ^w
+		}
+		w >>= uint(i) % wordBits
+		if w != 0 {
+			if j := i + bits.TrailingZeros64(w); j < n {
+				return j, true
+			}
+			return 0, false
+		}
+	}
+
+	return 0, false
+}
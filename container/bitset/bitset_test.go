@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbitset
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitSet(t *testing.T) {
+	b := New(130)
+	assert.Equal(t, 130, b.Len())
+	for _, i := range []int{0, 63, 64, 129} {
+		b.Set(i)
+		assert.True(t, b.Test(i))
+	}
+	assert.Equal(t, 4, b.Count())
+	assert.False(t, b.Test(1))
+
+	b.Clear(63)
+	assert.False(t, b.Test(63))
+	b.Flip(63)
+	assert.True(t, b.Test(63))
+	assert.Panics(t, func() { b.Set(130) })
+	assert.Panics(t, func() { b.Test(-1) })
+
+	var set []int
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		set = append(set, i)
+	}
+	assert.Equal(t, []int{0, 63, 64, 129}, set)
+
+	i, ok := b.NextClear(63)
+	assert.True(t, ok)
+	assert.Equal(t, 65, i)
+
+	full := New(70)
+	for i := 0; i < 70; i++ {
+		full.Set(i)
+	}
+	_, ok = full.NextClear(0)
+	assert.False(t, ok)
+	_, ok = New(70).NextSet(0)
+	assert.False(t, ok)
+
+	c := b.Clone()
+	b.ClearAll()
+	assert.Equal(t, 0, b.Count())
+	assert.Equal(t, 4, c.Count())
+}
+
+func TestBitSetOps(t *testing.T) {
+	a, b := New(100), New(100)
+	a.Set(1)
+	a.Set(2)
+	b.Set(2)
+	b.Set(99)
+
+	and := a.Clone().And(b)
+	assert.Equal(t, 1, and.Count())
+	assert.True(t, and.Test(2))
+
+	or := a.Clone().Or(b)
+	assert.Equal(t, 3, or.Count())
+
+	xor := a.Clone().Xor(b)
+	assert.Equal(t, 2, xor.Count())
+	assert.True(t, xor.Test(1))
+	assert.True(t, xor.Test(99))
+
+	andNot := a.Clone().AndNot(b)
+	assert.Equal(t, 1, andNot.Count())
+	assert.True(t, andNot.Test(1))
+
+	assert.True(t, a.Equals(a.Clone()))
+	assert.False(t, a.Equals(b))
+	assert.False(t, a.Equals(New(10)))
+	assert.Panics(t, func() { a.And(New(10)) })
+}
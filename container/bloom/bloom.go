@@ -0,0 +1,191 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxbloom implements a bloom filter and a counting bloom filter, for the
+// duplicate suppression of request IDs and gossip messages. The filters are not safe
+// for concurrent use.
+package gxbloom
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// ErrIncompatible is returned by Merge if the filters are of different sizes.
+var ErrIncompatible = errors.New("bloom: incompatible filters")
+
+// Estimate returns the number of bits and hash functions for @n items at the false
+// positive rate @fpRate.
+func Estimate(n int, fpRate float64) (m, k int) {
+	if n <= 0 {
+		panic("@n <= 0")
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		panic("@fpRate out of (0, 1)")
+	}
+
+	m = int(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k = int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return m, k
+}
+
+// locations returns the two base hashes of @key, the i-th location is h1 + i*h2 by
+// the Kirsch-Mitzenmacher double hashing.
+func locations(key []byte) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write(key)
+	h1 := h.Sum64()
+	h2 := mix(h1) | 1 // odd, so the locations never collapse into one
+
+	return h1, h2
+}
+
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
+
+// Filter is a standard bloom filter.
+type Filter struct {
+	m     uint64
+	k     int
+	bits  []uint64
+	count int
+}
+
+// New returns a filter sized for @n items at the false positive rate @fpRate.
+func New(n int, fpRate float64) *Filter {
+	m, k := Estimate(n, fpRate)
+	return NewWithSize(m, k)
+}
+
+// NewWithSize returns a filter of @m bits and @k hash functions.
+func NewWithSize(m, k int) *Filter {
+	if m <= 0 || k <= 0 {
+		panic("@m <= 0 || @k <= 0")
+	}
+
+	return &Filter{m: uint64(m), k: k, bits: make([]uint64, (m+63)/64)}
+}
+
+// M returns the number of bits.
+func (f *Filter) M() int {
+	return int(f.m)
+}
+
+// K returns the number of hash functions.
+func (f *Filter) K() int {
+	return f.k
+}
+
+// Count returns the number of the added items, counting a duplicate again.
+func (f *Filter) Count() int {
+	return f.count
+}
+
+// Add adds @key.
+func (f *Filter) Add(key []byte) {
+	h1, h2 := locations(key)
+	for i := 0; i < f.k; i++ {
+		pos := (h1 + uint64(i)*h2) % f.m
+		f.bits[pos/64] |= 1 << (pos % 64)
+	}
+	f.count++
+}
+
+// AddString adds @key.
+func (f *Filter) AddString(key string) {
+	f.Add([]byte(key))
+}
+
+// Test returns false if @key has definitely not been added.
+func (f *Filter) Test(key []byte) bool {
+	h1, h2 := locations(key)
+	for i := 0; i < f.k; i++ {
+		pos := (h1 + uint64(i)*h2) % f.m
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TestString returns false if @key has definitely not been added.
+func (f *Filter) TestString(key string) bool {
+	return f.Test([]byte(key))
+}
+
+// TestAndAdd adds @key, and returns whether it might have been added before. It is
+// the one call of a duplicate check.
+func (f *Filter) TestAndAdd(key []byte) bool {
+	h1, h2 := locations(key)
+	present := true
+	for i := 0; i < f.k; i++ {
+		pos := (h1 + uint64(i)*h2) % f.m
+		mask := uint64(1) << (pos % 64)
+		if f.bits[pos/64]&mask == 0 {
+			present = false
+			f.bits[pos/64] |= mask
+		}
+	}
+	f.count++
+
+	return present
+}
+
+// FalsePositiveRate estimates the current false positive rate by the set bits.
+func (f *Filter) FalsePositiveRate() float64 {
+	set := 0
+	for _, w := range f.bits {
+		set += bits.OnesCount64(w)
+	}
+
+	return math.Pow(float64(set)/float64(f.m), float64(f.k))
+}
+
+// Merge adds all the items of @other, which should be of the same size.
+func (f *Filter) Merge(other *Filter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrIncompatible
+	}
+	for i, w := range other.bits {
+		f.bits[i] |= w
+	}
+	f.count += other.count
+
+	return nil
+}
+
+// Clear removes all the items.
+func (f *Filter) Clear() {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+	f.count = 0
+}
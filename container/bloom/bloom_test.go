@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbloom
+
+import (
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimate(t *testing.T) {
+	m, k := Estimate(1000, 0.01)
+	assert.Equal(t, 9586, m)
+	assert.Equal(t, 7, k)
+
+	assert.Panics(t, func() { Estimate(0, 0.01) })
+	assert.Panics(t, func() { Estimate(10, 1) })
+}
+
+func TestFilter(t *testing.T) {
+	f := New(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+	assert.Equal(t, 1000, f.Count())
+	for i := 0; i < 1000; i++ {
+		assert.True(t, f.TestString(strconv.Itoa(i)))
+	}
+
+	fp := 0
+	for i := 1000; i < 11000; i++ {
+		if f.TestString(strconv.Itoa(i)) {
+			fp++
+		}
+	}
+	assert.True(t, fp < 200, fp)
+	assert.InDelta(t, 0.01, f.FalsePositiveRate(), 0.01)
+
+	f.Clear()
+	assert.False(t, f.TestString("1"))
+	assert.Equal(t, 0, f.Count())
+}
+
+func TestFilterTestAndAdd(t *testing.T) {
+	f := New(100, 0.001)
+	assert.False(t, f.TestAndAdd([]byte("req-1")))
+	assert.True(t, f.TestAndAdd([]byte("req-1")))
+	assert.True(t, f.Test([]byte("req-1")))
+}
+
+func TestFilterMerge(t *testing.T) {
+	a, b := New(100, 0.01), New(100, 0.01)
+	a.AddString("a")
+	b.AddString("b")
+	assert.Nil(t, a.Merge(b))
+	assert.True(t, a.TestString("a"))
+	assert.True(t, a.TestString("b"))
+	assert.Equal(t, 2, a.Count())
+
+	assert.Equal(t, ErrIncompatible, a.Merge(New(1000, 0.01)))
+}
@@ -0,0 +1,158 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbloom
+
+import (
+	"math"
+)
+
+// CountingFilter is a bloom filter of 8-bit counters instead of bits, which supports
+// removing items. A counter saturated at 255 is never decremented again, so it may
+// only cause false positives rather than false negatives.
+type CountingFilter struct {
+	m        uint64
+	k        int
+	counters []uint8
+	count    int
+}
+
+// NewCounting returns a counting filter sized for @n items at the false positive rate @fpRate.
+func NewCounting(n int, fpRate float64) *CountingFilter {
+	m, k := Estimate(n, fpRate)
+	return NewCountingWithSize(m, k)
+}
+
+// NewCountingWithSize returns a counting filter of @m counters and @k hash functions.
+func NewCountingWithSize(m, k int) *CountingFilter {
+	if m <= 0 || k <= 0 {
+		panic("@m <= 0 || @k <= 0")
+	}
+
+	return &CountingFilter{m: uint64(m), k: k, counters: make([]uint8, m)}
+}
+
+// M returns the number of counters.
+func (f *CountingFilter) M() int {
+	return int(f.m)
+}
+
+// K returns the number of hash functions.
+func (f *CountingFilter) K() int {
+	return f.k
+}
+
+// Count returns the number of the added items minus the removed ones.
+func (f *CountingFilter) Count() int {
+	return f.count
+}
+
+// Add adds @key.
+func (f *CountingFilter) Add(key []byte) {
+	h1, h2 := locations(key)
+	for i := 0; i < f.k; i++ {
+		pos := (h1 + uint64(i)*h2) % f.m
+		if f.counters[pos] < math.MaxUint8 {
+			f.counters[pos]++
+		}
+	}
+	f.count++
+}
+
+// AddString adds @key.
+func (f *CountingFilter) AddString(key string) {
+	f.Add([]byte(key))
+}
+
+// Remove removes @key, and returns false if it has definitely not been added. Removing
+// an item which has not been added may cause false negatives of the others.
+func (f *CountingFilter) Remove(key []byte) bool {
+	if !f.Test(key) {
+		return false
+	}
+
+	h1, h2 := locations(key)
+	for i := 0; i < f.k; i++ {
+		pos := (h1 + uint64(i)*h2) % f.m
+		if f.counters[pos] < math.MaxUint8 {
+			f.counters[pos]--
+		}
+	}
+	f.count--
+
+	return true
+}
+
+// RemoveString removes @key, see Remove.
+func (f *CountingFilter) RemoveString(key string) bool {
+	return f.Remove([]byte(key))
+}
+
+// Test returns false if @key has definitely not been added.
+func (f *CountingFilter) Test(key []byte) bool {
+	h1, h2 := locations(key)
+	for i := 0; i < f.k; i++ {
+		if f.counters[(h1+uint64(i)*h2)%f.m] == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TestString returns false if @key has definitely not been added.
+func (f *CountingFilter) TestString(key string) bool {
+	return f.Test([]byte(key))
+}
+
+// Merge adds all the items of @other, which should be of the same size.
+func (f *CountingFilter) Merge(other *CountingFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrIncompatible
+	}
+	for i, c := range other.counters {
+		if sum := int(f.counters[i]) + int(c); sum < math.MaxUint8 {
+			f.counters[i] = uint8(sum)
+		} else {
+			f.counters[i] = math.MaxUint8
+		}
+	}
+	f.count += other.count
+
+	return nil
+}
+
+// Filter returns the standard bloom filter of the same items.
+func (f *CountingFilter) Filter() *Filter {
+	b := NewWithSize(int(f.m), f.k)
+	for i, c := range f.counters {
+		if c > 0 {
+			b.bits[i/64] |= 1 << (uint(i) % 64)
+		}
+	}
+	b.count = f.count
+
+	return b
+}
+
+// Clear removes all the items.
+func (f *CountingFilter) Clear() {
+	for i := range f.counters {
+		f.counters[i] = 0
+	}
+	f.count = 0
+}
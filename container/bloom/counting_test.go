@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbloom
+
+import (
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountingFilter(t *testing.T) {
+	f := NewCounting(1000, 0.01)
+	for i := 0; i < 500; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+	for i := 0; i < 500; i += 2 {
+		assert.True(t, f.RemoveString(strconv.Itoa(i)))
+	}
+	assert.Equal(t, 250, f.Count())
+	for i := 1; i < 500; i += 2 {
+		assert.True(t, f.TestString(strconv.Itoa(i)))
+	}
+
+	removed := 0
+	for i := 0; i < 500; i += 2 {
+		if !f.TestString(strconv.Itoa(i)) {
+			removed++
+		}
+	}
+	assert.True(t, removed > 240, removed)
+	assert.False(t, f.RemoveString("absent"))
+
+	f.Clear()
+	assert.Equal(t, 0, f.Count())
+	assert.False(t, f.TestString("1"))
+}
+
+func TestCountingFilterSaturated(t *testing.T) {
+	f := NewCountingWithSize(8, 1)
+	for i := 0; i < 300; i++ {
+		f.AddString("hot")
+	}
+	for i := 0; i < 300; i++ {
+		f.RemoveString("hot")
+	}
+	// a saturated counter sticks
+	assert.True(t, f.TestString("hot"))
+}
+
+func TestCountingFilterMerge(t *testing.T) {
+	a, b := NewCounting(100, 0.01), NewCounting(100, 0.01)
+	a.AddString("a")
+	b.AddString("b")
+	assert.Nil(t, a.Merge(b))
+	assert.True(t, a.TestString("b"))
+	assert.True(t, a.RemoveString("b"))
+	assert.False(t, a.TestString("b"))
+	assert.True(t, a.TestString("a"))
+	assert.Equal(t, ErrIncompatible, a.Merge(NewCounting(10, 0.01)))
+
+	f := a.Filter()
+	assert.Equal(t, a.M(), f.M())
+	assert.Equal(t, a.K(), f.K())
+	assert.True(t, f.TestString("a"))
+	assert.Equal(t, 1, f.Count())
+}
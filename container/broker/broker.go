@@ -0,0 +1,296 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxbroker implements an in-process publish/subscribe broker of topics.
+package gxbroker
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrClosed is returned by the operations on a closed broker, and by Err of the
+	// subscribers closed with it.
+	ErrClosed = errors.New("gxbroker: broker closed")
+	// ErrSlowConsumer is returned by Err of a subscriber killed by the Kill policy.
+	ErrSlowConsumer = errors.New("gxbroker: subscriber killed as a slow consumer")
+	// ErrUnsubscribed is returned by Err of an unsubscribed subscriber.
+	ErrUnsubscribed = errors.New("gxbroker: unsubscribed")
+)
+
+// Policy decides what to do with a message published to a subscriber whose buffer is full.
+type Policy int
+
+const (
+	// DropOldest drops the oldest buffered message to make room for the new one.
+	DropOldest Policy = iota
+	// Block makes the publisher wait for room until its context is done.
+	Block
+	// Kill unsubscribes the subscriber, closing its channel.
+	Kill
+)
+
+func (p Policy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	case Block:
+		return "block"
+	case Kill:
+		return "kill"
+	}
+
+	return "unknown"
+}
+
+// Broker distributes the messages published to a topic to all its subscribers, each of
+// which has a bounded buffer of its own. It is goroutine safe.
+type Broker[T any] struct {
+	lock   sync.RWMutex
+	topics map[string]map[*Subscriber[T]]struct{}
+	closed bool
+}
+
+// New returns an empty broker.
+func New[T any]() *Broker[T] {
+	return &Broker[T]{topics: make(map[string]map[*Subscriber[T]]struct{})}
+}
+
+// Subscribe returns a subscriber of @topic buffering @buffer messages at most, which
+// are handled by @policy when it is full.
+func (b *Broker[T]) Subscribe(topic string, buffer int, policy Policy) (*Subscriber[T], error) {
+	if buffer <= 0 {
+		panic("@buffer <= 0")
+	}
+
+	s := &Subscriber[T]{
+		broker: b,
+		topic:  topic,
+		policy: policy,
+		ch:     make(chan T, buffer),
+		done:   make(chan struct{}),
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	subs, ok := b.topics[topic]
+	if !ok {
+		subs = make(map[*Subscriber[T]]struct{})
+		b.topics[topic] = subs
+	}
+	subs[s] = struct{}{}
+
+	return s, nil
+}
+
+// Publish sends @msg to the subscribers of @topic, and returns the number of the ones
+// it is delivered to. Only the subscribers of the Block policy can make it wait, and
+// if @ctx is done meanwhile, ctx.Err() is returned after the rest are tried.
+func (b *Broker[T]) Publish(ctx context.Context, topic string, msg T) (int, error) {
+	b.lock.RLock()
+	if b.closed {
+		b.lock.RUnlock()
+		return 0, ErrClosed
+	}
+	subs := make([]*Subscriber[T], 0, len(b.topics[topic]))
+	for s := range b.topics[topic] {
+		subs = append(subs, s)
+	}
+	b.lock.RUnlock()
+
+	var (
+		delivered int
+		err       error
+	)
+	for _, s := range subs {
+		ok, e := s.send(ctx, msg)
+		if ok {
+			delivered++
+		}
+		if e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return delivered, err
+}
+
+// Topics returns the sorted topics with subscribers.
+func (b *Broker[T]) Topics() []string {
+	b.lock.RLock()
+	topics := make([]string, 0, len(b.topics))
+	for topic := range b.topics {
+		topics = append(topics, topic)
+	}
+	b.lock.RUnlock()
+	sort.Strings(topics)
+
+	return topics
+}
+
+// Subscribers returns the number of subscribers of @topic.
+func (b *Broker[T]) Subscribers(topic string) int {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return len(b.topics[topic])
+}
+
+// Close closes all subscribers with ErrClosed, and rejects the later operations.
+func (b *Broker[T]) Close() {
+	b.lock.Lock()
+	if b.closed {
+		b.lock.Unlock()
+		return
+	}
+	b.closed = true
+	topics := b.topics
+	b.topics = make(map[string]map[*Subscriber[T]]struct{})
+	b.lock.Unlock()
+
+	for _, subs := range topics {
+		for s := range subs {
+			s.close(ErrClosed)
+		}
+	}
+}
+
+func (b *Broker[T]) remove(s *Subscriber[T]) {
+	b.lock.Lock()
+	if subs, ok := b.topics[s.topic]; ok {
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(b.topics, s.topic)
+		}
+	}
+	b.lock.Unlock()
+}
+
+// Subscriber receives the messages of a topic from its channel.
+type Subscriber[T any] struct {
+	dropped uint64 // first for the 64-bit alignment on the 32-bit platforms
+	broker  *Broker[T]
+	topic   string
+	policy  Policy
+	ch      chan T
+
+	sendLock  sync.Mutex // serializes the sends and the close of ch
+	done      chan struct{}
+	closeOnce sync.Once
+	err       error // set before done is closed
+}
+
+// C returns the channel of the messages, which is closed once the subscriber is closed.
+func (s *Subscriber[T]) C() <-chan T {
+	return s.ch
+}
+
+// Topic returns the subscribed topic.
+func (s *Subscriber[T]) Topic() string {
+	return s.topic
+}
+
+// Dropped returns the number of messages dropped by the DropOldest policy.
+func (s *Subscriber[T]) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Done returns a channel closed once the subscriber is closed.
+func (s *Subscriber[T]) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns why the subscriber is closed, or nil if it is still open.
+func (s *Subscriber[T]) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Unsubscribe stops receiving messages and closes the channel. The buffered messages
+// can still be received from it.
+func (s *Subscriber[T]) Unsubscribe() {
+	s.close(ErrUnsubscribed)
+}
+
+func (s *Subscriber[T]) close(err error) {
+	s.closeOnce.Do(func() {
+		s.err = err
+		close(s.done) // wakes up the blocked send holding sendLock
+		s.broker.remove(s)
+
+		s.sendLock.Lock()
+		close(s.ch)
+		s.sendLock.Unlock()
+	})
+}
+
+func (s *Subscriber[T]) send(ctx context.Context, msg T) (bool, error) {
+	s.sendLock.Lock()
+	select {
+	case <-s.done:
+		s.sendLock.Unlock()
+		return false, nil
+	default:
+	}
+
+	select {
+	case s.ch <- msg:
+		s.sendLock.Unlock()
+		return true, nil
+	default:
+	}
+
+	switch s.policy {
+	case DropOldest:
+		// the receiver may take one meanwhile, so popping is best effort
+		select {
+		case <-s.ch:
+			atomic.AddUint64(&s.dropped, 1)
+		default:
+		}
+		s.ch <- msg // never blocks as the sends are serialized
+		s.sendLock.Unlock()
+		return true, nil
+
+	case Block:
+		defer s.sendLock.Unlock()
+		select {
+		case s.ch <- msg:
+			return true, nil
+		case <-s.done:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+
+	default:
+		s.sendLock.Unlock()
+		s.close(ErrSlowConsumer)
+		return false, nil
+	}
+}
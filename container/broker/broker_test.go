@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbroker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func drain(s *Subscriber[int]) []int {
+	var msgs []int
+	for msg := range s.C() {
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func TestBroker(t *testing.T) {
+	b := New[int]()
+	ctx := context.Background()
+
+	s1, err := b.Subscribe("a", 4, DropOldest)
+	assert.Nil(t, err)
+	s2, err := b.Subscribe("a", 4, Block)
+	assert.Nil(t, err)
+	s3, err := b.Subscribe("b", 4, Kill)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, b.Topics())
+	assert.Equal(t, 2, b.Subscribers("a"))
+
+	n, err := b.Publish(ctx, "a", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	n, err = b.Publish(ctx, "c", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	s1.Unsubscribe()
+	assert.Equal(t, ErrUnsubscribed, s1.Err())
+	assert.Equal(t, []int{1}, drain(s1))
+	assert.Equal(t, 1, b.Subscribers("a"))
+	assert.Equal(t, "a", s2.Topic())
+
+	b.Close()
+	assert.Equal(t, ErrClosed, s2.Err())
+	assert.Equal(t, ErrClosed, s3.Err())
+	assert.Equal(t, []int{1}, drain(s2))
+	assert.Empty(t, drain(s3))
+	assert.Empty(t, b.Topics())
+
+	_, err = b.Publish(ctx, "a", 2)
+	assert.Equal(t, ErrClosed, err)
+	_, err = b.Subscribe("a", 1, Block)
+	assert.Equal(t, ErrClosed, err)
+}
+
+func TestBrokerDropOldest(t *testing.T) {
+	b := New[int]()
+	s, _ := b.Subscribe("t", 2, DropOldest)
+	for i := 0; i < 5; i++ {
+		n, err := b.Publish(context.Background(), "t", i)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+	}
+	assert.Equal(t, uint64(3), s.Dropped())
+	s.Unsubscribe()
+	assert.Equal(t, []int{3, 4}, drain(s))
+}
+
+func TestBrokerKill(t *testing.T) {
+	b := New[int]()
+	slow, _ := b.Subscribe("t", 1, Kill)
+	fast, _ := b.Subscribe("t", 2, Kill)
+
+	n, _ := b.Publish(context.Background(), "t", 1)
+	assert.Equal(t, 2, n)
+	n, _ = b.Publish(context.Background(), "t", 2)
+	assert.Equal(t, 1, n)
+
+	assert.Equal(t, ErrSlowConsumer, slow.Err())
+	assert.Nil(t, fast.Err())
+	assert.Equal(t, []int{1}, drain(slow))
+	assert.Equal(t, 1, b.Subscribers("t"))
+}
+
+func TestBrokerBlock(t *testing.T) {
+	b := New[int]()
+	s, _ := b.Subscribe("t", 1, Block)
+	b.Publish(context.Background(), "t", 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	n, err := b.Publish(ctx, "t", 2)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	published := make(chan int, 1)
+	go func() {
+		n, _ := b.Publish(context.Background(), "t", 3)
+		published <- n
+	}()
+	assert.Equal(t, 1, <-s.C())
+	assert.Equal(t, 1, <-published)
+	assert.Equal(t, 3, <-s.C())
+
+	// unsubscribing wakes up the blocked publisher
+	b.Publish(context.Background(), "t", 4)
+	go func() {
+		n, _ := b.Publish(context.Background(), "t", 5)
+		published <- n
+	}()
+	time.Sleep(10 * time.Millisecond)
+	s.Unsubscribe()
+	assert.Equal(t, 0, <-published)
+	<-s.Done()
+	assert.Equal(t, []int{4}, drain(s))
+	assert.Equal(t, "drop-oldest", DropOldest.String())
+}
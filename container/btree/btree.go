@@ -0,0 +1,340 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxbtree implements an in-memory B-tree sorted map and ordered set. Holding
+// many keys per node, it allocates far less and is more cache friendly than a skip
+// list or a binary tree for large sorted datasets. It is not safe for concurrent use.
+package gxbtree
+
+import (
+	"sort"
+)
+
+import (
+	gxsort "github.com/dubbogo/gost/sort"
+)
+
+// DefaultDegree gives nodes of 31 to 63 entries.
+const DefaultDegree = 32
+
+// Entry is a key value pair of a Map.
+type Entry[K gxsort.Ordered, V any] struct {
+	Key   K
+	Value V
+}
+
+type node[K gxsort.Ordered, V any] struct {
+	entries  []Entry[K, V]
+	children []*node[K, V] // empty for a leaf
+}
+
+func (n *node[K, V]) leaf() bool {
+	return len(n.children) == 0
+}
+
+// find returns the index of the first entry not less than @key, and whether it is @key.
+func (n *node[K, V]) find(key K) (int, bool) {
+	i := sort.Search(len(n.entries), func(i int) bool { return !(n.entries[i].Key < key) })
+	return i, i < len(n.entries) && n.entries[i].Key == key
+}
+
+// Map is a sorted map on a B-tree.
+type Map[K gxsort.Ordered, V any] struct {
+	degree int // the nodes hold [degree - 1, 2 * degree - 1] entries except the root
+	root   *node[K, V]
+	size   int
+}
+
+// New returns an empty map of @degree, which should be at least 2. See DefaultDegree.
+func New[K gxsort.Ordered, V any](degree int) *Map[K, V] {
+	if degree < 2 {
+		panic("@degree < 2")
+	}
+
+	return &Map[K, V]{degree: degree}
+}
+
+func (m *Map[K, V]) maxEntries() int {
+	return 2*m.degree - 1
+}
+
+// Len returns the number of entries.
+func (m *Map[K, V]) Len() int {
+	return m.size
+}
+
+// Get returns the value of @key.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	for n := m.root; n != nil; {
+		i, found := n.find(key)
+		if found {
+			return n.entries[i].Value, true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Has reports whether @key exists.
+func (m *Map[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Set sets the value of @key, and returns false if it replaces an existing value.
+func (m *Map[K, V]) Set(key K, value V) bool {
+	if m.root == nil {
+		m.root = &node[K, V]{}
+	}
+	if len(m.root.entries) == m.maxEntries() {
+		root := &node[K, V]{children: []*node[K, V]{m.root}}
+		m.split(root, 0)
+		m.root = root
+	}
+
+	added := m.insert(m.root, Entry[K, V]{key, value})
+	if added {
+		m.size++
+	}
+
+	return added
+}
+
+// split splits the full child @i of @n into two around its middle entry, which moves up to @n.
+func (m *Map[K, V]) split(n *node[K, V], i int) {
+	var (
+		c     = n.children[i]
+		mid   = c.entries[m.degree-1]
+		right = &node[K, V]{entries: append([]Entry[K, V](nil), c.entries[m.degree:]...)}
+	)
+	if !c.leaf() {
+		right.children = append([]*node[K, V](nil), c.children[m.degree:]...)
+		for j := m.degree; j < len(c.children); j++ {
+			c.children[j] = nil
+		}
+		c.children = c.children[:m.degree]
+	}
+	clearEntries(c.entries[m.degree-1:])
+	c.entries = c.entries[:m.degree-1]
+
+	n.entries = append(n.entries, Entry[K, V]{})
+	copy(n.entries[i+1:], n.entries[i:])
+	n.entries[i] = mid
+	n.children = append(n.children, nil)
+	copy(n.children[i+2:], n.children[i+1:])
+	n.children[i+1] = right
+}
+
+// insert puts @e into the non-full @n.
+func (m *Map[K, V]) insert(n *node[K, V], e Entry[K, V]) bool {
+	for {
+		i, found := n.find(e.Key)
+		if found {
+			n.entries[i].Value = e.Value
+			return false
+		}
+		if n.leaf() {
+			n.entries = append(n.entries, Entry[K, V]{})
+			copy(n.entries[i+1:], n.entries[i:])
+			n.entries[i] = e
+			return true
+		}
+
+		if len(n.children[i].entries) == m.maxEntries() {
+			m.split(n, i)
+			switch mid := n.entries[i].Key; {
+			case mid == e.Key:
+				n.entries[i].Value = e.Value
+				return false
+			case mid < e.Key:
+				i++
+			}
+		}
+		n = n.children[i]
+	}
+}
+
+// Delete removes @key, and returns false if it does not exist.
+func (m *Map[K, V]) Delete(key K) bool {
+	if m.root == nil {
+		return false
+	}
+
+	removed := m.remove(m.root, key)
+	if removed {
+		m.size--
+	}
+	if len(m.root.entries) == 0 {
+		if m.root.leaf() {
+			m.root = nil
+		} else {
+			m.root = m.root.children[0]
+		}
+	}
+
+	return removed
+}
+
+// remove deletes @key from the subtree @n, whose node has at least degree entries
+// unless it is the root, so that a removal never underflows it.
+func (m *Map[K, V]) remove(n *node[K, V], key K) bool {
+	for {
+		i, found := n.find(key)
+		if n.leaf() {
+			if found {
+				copy(n.entries[i:], n.entries[i+1:])
+				clearEntries(n.entries[len(n.entries)-1:])
+				n.entries = n.entries[:len(n.entries)-1]
+			}
+			return found
+		}
+
+		if found {
+			switch {
+			case len(n.children[i].entries) >= m.degree:
+				// replaced by the predecessor, which is removed from the left child instead
+				pred := n.children[i]
+				for !pred.leaf() {
+					pred = pred.children[len(pred.children)-1]
+				}
+				n.entries[i] = pred.entries[len(pred.entries)-1]
+				n, key = n.children[i], n.entries[i].Key
+			case len(n.children[i+1].entries) >= m.degree:
+				succ := n.children[i+1]
+				for !succ.leaf() {
+					succ = succ.children[0]
+				}
+				n.entries[i] = succ.entries[0]
+				n, key = n.children[i+1], n.entries[i].Key
+			default:
+				m.merge(n, i)
+				n = n.children[i]
+			}
+			continue
+		}
+
+		if len(n.children[i].entries) < m.degree {
+			i = m.grow(n, i)
+		}
+		n = n.children[i]
+	}
+}
+
+// grow gives the child @i of @n at least degree entries by borrowing from or merging with
+// a sibling, and returns the new index of the child.
+func (m *Map[K, V]) grow(n *node[K, V], i int) int {
+	c := n.children[i]
+	switch {
+	case i > 0 && len(n.children[i-1].entries) >= m.degree:
+		// rotate an entry from the left sibling through the parent
+		left := n.children[i-1]
+		c.entries = append(c.entries, Entry[K, V]{})
+		copy(c.entries[1:], c.entries)
+		c.entries[0] = n.entries[i-1]
+		n.entries[i-1] = left.entries[len(left.entries)-1]
+		clearEntries(left.entries[len(left.entries)-1:])
+		left.entries = left.entries[:len(left.entries)-1]
+		if !left.leaf() {
+			c.children = append(c.children, nil)
+			copy(c.children[1:], c.children)
+			c.children[0] = left.children[len(left.children)-1]
+			left.children[len(left.children)-1] = nil
+			left.children = left.children[:len(left.children)-1]
+		}
+		return i
+	case i < len(n.entries) && len(n.children[i+1].entries) >= m.degree:
+		right := n.children[i+1]
+		c.entries = append(c.entries, n.entries[i])
+		n.entries[i] = right.entries[0]
+		copy(right.entries, right.entries[1:])
+		clearEntries(right.entries[len(right.entries)-1:])
+		right.entries = right.entries[:len(right.entries)-1]
+		if !right.leaf() {
+			c.children = append(c.children, right.children[0])
+			copy(right.children, right.children[1:])
+			right.children[len(right.children)-1] = nil
+			right.children = right.children[:len(right.children)-1]
+		}
+		return i
+	case i < len(n.entries):
+		m.merge(n, i)
+		return i
+	default:
+		m.merge(n, i-1)
+		return i - 1
+	}
+}
+
+// merge joins the child @i of @n, the entry @i and the child @i+1 into the child @i.
+func (m *Map[K, V]) merge(n *node[K, V], i int) {
+	left, right := n.children[i], n.children[i+1]
+	left.entries = append(left.entries, n.entries[i])
+	left.entries = append(left.entries, right.entries...)
+	left.children = append(left.children, right.children...)
+
+	copy(n.entries[i:], n.entries[i+1:])
+	clearEntries(n.entries[len(n.entries)-1:])
+	n.entries = n.entries[:len(n.entries)-1]
+	copy(n.children[i+1:], n.children[i+2:])
+	n.children[len(n.children)-1] = nil
+	n.children = n.children[:len(n.children)-1]
+}
+
+func clearEntries[K gxsort.Ordered, V any](entries []Entry[K, V]) {
+	var zero Entry[K, V]
+	for i := range entries {
+		entries[i] = zero
+	}
+}
+
+// Min returns the entry of the smallest key.
+func (m *Map[K, V]) Min() (Entry[K, V], bool) {
+	if m.root == nil {
+		return Entry[K, V]{}, false
+	}
+
+	n := m.root
+	for !n.leaf() {
+		n = n.children[0]
+	}
+
+	return n.entries[0], true
+}
+
+// Max returns the entry of the largest key.
+func (m *Map[K, V]) Max() (Entry[K, V], bool) {
+	if m.root == nil {
+		return Entry[K, V]{}, false
+	}
+
+	n := m.root
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+
+	return n.entries[len(n.entries)-1], true
+}
+
+// Clear removes all the entries.
+func (m *Map[K, V]) Clear() {
+	m.root, m.size = nil, 0
+}
@@ -0,0 +1,201 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxsort "github.com/dubbogo/gost/sort"
+)
+
+// check verifies the B-tree invariants, and returns the height of @n.
+func check[K gxsort.Ordered, V any](t *testing.T, m *Map[K, V], n *node[K, V], root bool, lo, hi *K) int {
+	if !root {
+		assert.True(t, len(n.entries) >= m.degree-1, "underflow %d", len(n.entries))
+	}
+	assert.True(t, len(n.entries) <= m.maxEntries(), "overflow %d", len(n.entries))
+	for i, e := range n.entries {
+		if i > 0 {
+			assert.True(t, n.entries[i-1].Key < e.Key)
+		}
+		if lo != nil {
+			assert.True(t, *lo < e.Key)
+		}
+		if hi != nil {
+			assert.True(t, e.Key < *hi)
+		}
+	}
+	if n.leaf() {
+		return 1
+	}
+
+	assert.Equal(t, len(n.entries)+1, len(n.children))
+	height := -1
+	for i, c := range n.children {
+		clo, chi := lo, hi
+		if i > 0 {
+			clo = &n.entries[i-1].Key
+		}
+		if i < len(n.entries) {
+			chi = &n.entries[i].Key
+		}
+		h := check(t, m, c, false, clo, chi)
+		if height >= 0 {
+			assert.Equal(t, height, h)
+		}
+		height = h
+	}
+
+	return height + 1
+}
+
+func keysOf(m *Map[int, int]) []int {
+	var keys []int
+	m.Ascend(func(k, _ int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func TestMapRandom(t *testing.T) {
+	for _, degree := range []int{2, 3, DefaultDegree} {
+		var (
+			m     = New[int, int](degree)
+			model = map[int]int{}
+		)
+		for i := 0; i < 5000; i++ {
+			k := rand.Intn(1000)
+			if rand.Intn(3) == 0 {
+				_, ok := model[k]
+				assert.Equal(t, ok, m.Delete(k))
+				delete(model, k)
+			} else {
+				_, ok := model[k]
+				assert.Equal(t, !ok, m.Set(k, i))
+				model[k] = i
+			}
+		}
+		assert.Equal(t, len(model), m.Len())
+		if m.root != nil {
+			check(t, m, m.root, true, nil, nil)
+		}
+
+		expected := make([]int, 0, len(model))
+		for k, v := range model {
+			expected = append(expected, k)
+			got, ok := m.Get(k)
+			assert.True(t, ok)
+			assert.Equal(t, v, got)
+		}
+		sort.Ints(expected)
+		assert.Equal(t, expected, keysOf(m))
+
+		for _, k := range expected {
+			assert.True(t, m.Delete(k))
+		}
+		assert.Equal(t, 0, m.Len())
+		assert.Nil(t, m.root)
+	}
+	assert.Panics(t, func() { New[int, int](1) })
+}
+
+func TestMapIterate(t *testing.T) {
+	m := New[int, string](2)
+	for i := 0; i < 100; i += 2 {
+		m.Set(i, "v")
+	}
+
+	var got []int
+	m.AscendGreaterOrEqual(51, func(k int, _ string) bool {
+		got = append(got, k)
+		return len(got) < 3
+	})
+	assert.Equal(t, []int{52, 54, 56}, got)
+
+	got = nil
+	m.AscendRange(10, 20, func(k int, _ string) bool {
+		got = append(got, k)
+		return true
+	})
+	assert.Equal(t, []int{10, 12, 14, 16, 18}, got)
+
+	got = nil
+	m.Descend(func(k int, _ string) bool {
+		got = append(got, k)
+		return k > 94
+	})
+	assert.Equal(t, []int{98, 96, 94}, got)
+
+	min, _ := m.Min()
+	max, _ := m.Max()
+	assert.Equal(t, 0, min.Key)
+	assert.Equal(t, 98, max.Key)
+
+	m.Clear()
+	_, ok := m.Min()
+	assert.False(t, ok)
+	_, ok = m.Max()
+	assert.False(t, ok)
+}
+
+func TestMapBulkLoad(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 4, 50, 1000, 4321} {
+		m := New[int, int](3)
+		entries := make([]Entry[int, int], n)
+		for i := range entries {
+			entries[i] = Entry[int, int]{i * 2, i}
+		}
+		m.BulkLoad(entries)
+		assert.Equal(t, n, m.Len())
+		if n == 0 {
+			assert.Nil(t, m.root)
+			continue
+		}
+		check(t, m, m.root, true, nil, nil)
+
+		// still a valid tree for the later updates
+		m.Set(1, 1)
+		m.Delete(0)
+		check(t, m, m.root, true, nil, nil)
+		v, ok := m.Get(2 * (n - 1))
+		assert.True(t, ok || n == 1)
+		if n > 1 {
+			assert.Equal(t, n-1, v)
+		}
+	}
+
+	assert.Panics(t, func() {
+		New[int, int](3).BulkLoad([]Entry[int, int]{{2, 0}, {1, 0}})
+	})
+}
+
+func BenchmarkMapSet(b *testing.B) {
+	m := New[int, int](DefaultDegree)
+	for i := 0; i < b.N; i++ {
+		m.Set(rand.Int(), i)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbtree
+
+import (
+	gxsort "github.com/dubbogo/gost/sort"
+)
+
+// Ascend calls @f for every entry in the ascending order of keys until @f returns false.
+func (m *Map[K, V]) Ascend(f func(key K, value V) bool) {
+	ascend(m.root, nil, nil, f)
+}
+
+// AscendGreaterOrEqual calls @f for every entry whose key is not less than @pivot in
+// the ascending order until @f returns false.
+func (m *Map[K, V]) AscendGreaterOrEqual(pivot K, f func(key K, value V) bool) {
+	ascend(m.root, &pivot, nil, f)
+}
+
+// AscendRange calls @f for every entry whose key is in [@from, @to) in the ascending
+// order until @f returns false.
+func (m *Map[K, V]) AscendRange(from, to K, f func(key K, value V) bool) {
+	ascend(m.root, &from, &to, f)
+}
+
+// Descend calls @f for every entry in the descending order of keys until @f returns false.
+func (m *Map[K, V]) Descend(f func(key K, value V) bool) {
+	descend(m.root, f)
+}
+
+// ascend visits the entries of [@from, @to) of the subtree @n, where a nil bound is
+// unbounded, and returns false once @f stops it.
+func ascend[K gxsort.Ordered, V any](n *node[K, V], from, to *K, f func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	i := 0
+	if from != nil {
+		i, _ = n.find(*from)
+	}
+	for ; i < len(n.entries); i++ {
+		if !n.leaf() && !ascend(n.children[i], from, to, f) {
+			return false
+		}
+		e := n.entries[i]
+		if to != nil && !(e.Key < *to) {
+			return false
+		}
+		if !f(e.Key, e.Value) {
+			return false
+		}
+		// the later children are all above @from
+		from = nil
+	}
+	if !n.leaf() {
+		return ascend(n.children[len(n.entries)], from, to, f)
+	}
+
+	return true
+}
+
+func descend[K gxsort.Ordered, V any](n *node[K, V], f func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	for i := len(n.entries) - 1; i >= 0; i-- {
+		if !n.leaf() && !descend(n.children[i+1], f) {
+			return false
+		}
+		if !f(n.entries[i].Key, n.entries[i].Value) {
+			return false
+		}
+	}
+	if !n.leaf() {
+		return descend(n.children[0], f)
+	}
+
+	return true
+}
+
+// BulkLoad replaces the content of the map with @entries, which should be sorted by
+// strictly ascending keys. It builds the tree bottom-up in O(n) without any split,
+// and panics if @entries are not sorted.
+func (m *Map[K, V]) BulkLoad(entries []Entry[K, V]) {
+	for i := 1; i < len(entries); i++ {
+		if !(entries[i-1].Key < entries[i].Key) {
+			panic("@entries are not sorted by strictly ascending keys")
+		}
+	}
+
+	m.root, m.size = nil, len(entries)
+	if len(entries) == 0 {
+		return
+	}
+
+	// the capacity of a full subtree of each height
+	capacity := []int{0}
+	for capacity[len(capacity)-1] < len(entries) {
+		h := len(capacity)
+		capacity = append(capacity, (capacity[h-1]+1)*2*m.degree-1)
+	}
+	m.root = m.build(entries, len(capacity)-1, capacity)
+}
+
+// build returns a subtree of @height holding @entries. Splitting them into the fewest
+// children of a full height - 1 subtree, which are filled evenly, keeps every child
+// at least half full.
+func (m *Map[K, V]) build(entries []Entry[K, V], height int, capacity []int) *node[K, V] {
+	if height == 1 {
+		return &node[K, V]{entries: append([]Entry[K, V](nil), entries...)}
+	}
+
+	var (
+		sub   = capacity[height-1]
+		k     = (len(entries) + sub + 1) / (sub + 1) // ceil((n + 1) / (sub + 1))
+		rest  = len(entries) - (k - 1)
+		n     = &node[K, V]{entries: make([]Entry[K, V], 0, k-1), children: make([]*node[K, V], 0, k)}
+		start = 0
+	)
+	for j := 0; j < k; j++ {
+		size := rest / k
+		if j < rest%k {
+			size++
+		}
+		n.children = append(n.children, m.build(entries[start:start+size], height-1, capacity))
+		start += size
+		if j < k-1 {
+			n.entries = append(n.entries, entries[start])
+			start++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbtree
+
+import (
+	gxsort "github.com/dubbogo/gost/sort"
+)
+
+// Set is an ordered set on a B-tree.
+type Set[K gxsort.Ordered] struct {
+	m *Map[K, struct{}]
+}
+
+// NewSet returns an empty set of @degree, see New.
+func NewSet[K gxsort.Ordered](degree int) *Set[K] {
+	return &Set[K]{m: New[K, struct{}](degree)}
+}
+
+// Len returns the number of keys.
+func (s *Set[K]) Len() int {
+	return s.m.Len()
+}
+
+// Add adds @key, and returns false if it exists.
+func (s *Set[K]) Add(key K) bool {
+	return s.m.Set(key, struct{}{})
+}
+
+// Has reports whether @key exists.
+func (s *Set[K]) Has(key K) bool {
+	return s.m.Has(key)
+}
+
+// Delete removes @key, and returns false if it does not exist.
+func (s *Set[K]) Delete(key K) bool {
+	return s.m.Delete(key)
+}
+
+// Min returns the smallest key.
+func (s *Set[K]) Min() (K, bool) {
+	e, ok := s.m.Min()
+	return e.Key, ok
+}
+
+// Max returns the largest key.
+func (s *Set[K]) Max() (K, bool) {
+	e, ok := s.m.Max()
+	return e.Key, ok
+}
+
+// Ascend calls @f for every key in the ascending order until @f returns false.
+func (s *Set[K]) Ascend(f func(key K) bool) {
+	s.m.Ascend(func(key K, _ struct{}) bool { return f(key) })
+}
+
+// AscendGreaterOrEqual calls @f for every key not less than @pivot in the ascending
+// order until @f returns false.
+func (s *Set[K]) AscendGreaterOrEqual(pivot K, f func(key K) bool) {
+	s.m.AscendGreaterOrEqual(pivot, func(key K, _ struct{}) bool { return f(key) })
+}
+
+// AscendRange calls @f for every key in [@from, @to) in the ascending order until @f
+// returns false.
+func (s *Set[K]) AscendRange(from, to K, f func(key K) bool) {
+	s.m.AscendRange(from, to, func(key K, _ struct{}) bool { return f(key) })
+}
+
+// Descend calls @f for every key in the descending order until @f returns false.
+func (s *Set[K]) Descend(f func(key K) bool) {
+	s.m.Descend(func(key K, _ struct{}) bool { return f(key) })
+}
+
+// BulkLoad replaces the content of the set with @keys, which should be sorted in the
+// strictly ascending order.
+func (s *Set[K]) BulkLoad(keys []K) {
+	entries := make([]Entry[K, struct{}], len(keys))
+	for i, k := range keys {
+		entries[i].Key = k
+	}
+	s.m.BulkLoad(entries)
+}
+
+// Keys returns all the keys in the ascending order.
+func (s *Set[K]) Keys() []K {
+	keys := make([]K, 0, s.Len())
+	s.Ascend(func(key K) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	return keys
+}
@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbtree
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet[string](2)
+	for _, k := range []string{"d", "b", "a", "c", "e"} {
+		assert.True(t, s.Add(k))
+	}
+	assert.False(t, s.Add("a"))
+	assert.Equal(t, 5, s.Len())
+	assert.True(t, s.Has("c"))
+	assert.True(t, s.Delete("c"))
+	assert.False(t, s.Has("c"))
+	assert.Equal(t, []string{"a", "b", "d", "e"}, s.Keys())
+
+	min, _ := s.Min()
+	max, _ := s.Max()
+	assert.Equal(t, "a", min)
+	assert.Equal(t, "e", max)
+
+	var got []string
+	s.AscendGreaterOrEqual("c", func(k string) bool {
+		got = append(got, k)
+		return true
+	})
+	assert.Equal(t, []string{"d", "e"}, got)
+
+	got = nil
+	s.AscendRange("b", "e", func(k string) bool {
+		got = append(got, k)
+		return true
+	})
+	assert.Equal(t, []string{"b", "d"}, got)
+
+	got = nil
+	s.Descend(func(k string) bool {
+		got = append(got, k)
+		return true
+	})
+	assert.Equal(t, []string{"e", "d", "b", "a"}, got)
+
+	s.BulkLoad([]string{"x", "y", "z"})
+	assert.Equal(t, []string{"x", "y", "z"}, s.Keys())
+}
@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxcow
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Map is a copy-on-write map. Every mutation copies the whole map, so it suits the
+// maps which are read far more often than they are written. The maps returned by Load
+// are immutable snapshots shared by all readers, so they MUST NOT be modified.
+type Map[K comparable, V any] struct {
+	lock sync.Mutex // serializes the mutations
+	data atomic.Value
+}
+
+// NewMap returns an empty map.
+func NewMap[K comparable, V any]() *Map[K, V] {
+	m := &Map[K, V]{}
+	m.data.Store(map[K]V{})
+
+	return m
+}
+
+func (m *Map[K, V]) load() map[K]V {
+	data, _ := m.data.Load().(map[K]V)
+	return data
+}
+
+// clone should be invoked with the lock held.
+func (m *Map[K, V]) clone(extra int) map[K]V {
+	old := m.load()
+	cp := make(map[K]V, len(old)+extra)
+	for k, v := range old {
+		cp[k] = v
+	}
+
+	return cp
+}
+
+// Get returns the value of @key.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	v, ok := m.load()[key]
+	return v, ok
+}
+
+// Load returns the current snapshot.
+func (m *Map[K, V]) Load() map[K]V {
+	return m.load()
+}
+
+// Len returns the number of entries.
+func (m *Map[K, V]) Len() int {
+	return len(m.load())
+}
+
+// Range calls @f for every entry of the current snapshot until @f returns false.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	for k, v := range m.load() {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+// Set sets the value of @key.
+func (m *Map[K, V]) Set(key K, value V) {
+	m.lock.Lock()
+	cp := m.clone(1)
+	cp[key] = value
+	m.data.Store(cp)
+	m.lock.Unlock()
+}
+
+// SetIfAbsent sets the value of @key if it is absent, and returns the value kept in
+// the map and whether it is set by this call.
+func (m *Map[K, V]) SetIfAbsent(key K, value V) (V, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if v, ok := m.load()[key]; ok {
+		return v, false
+	}
+	cp := m.clone(1)
+	cp[key] = value
+	m.data.Store(cp)
+
+	return value, true
+}
+
+// Delete removes @key, and returns whether it existed.
+func (m *Map[K, V]) Delete(key K) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.load()[key]; !ok {
+		return false
+	}
+	cp := m.clone(0)
+	delete(cp, key)
+	m.data.Store(cp)
+
+	return true
+}
+
+// Replace replaces the map with a copy of @data.
+func (m *Map[K, V]) Replace(data map[K]V) {
+	cp := make(map[K]V, len(data))
+	for k, v := range data {
+		cp[k] = v
+	}
+
+	m.lock.Lock()
+	m.data.Store(cp)
+	m.lock.Unlock()
+}
+
+// Update applies @f to a private copy of the current snapshot and publishes it, so a
+// batch of mutations costs a single copy and becomes visible atomically.
+func (m *Map[K, V]) Update(f func(data map[K]V)) {
+	m.lock.Lock()
+	cp := m.clone(0)
+	f(cp)
+	m.data.Store(cp)
+	m.lock.Unlock()
+}
@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxcow
+
+import (
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Set("a", 1)
+	snapshot := m.Load()
+	m.Set("b", 2)
+	assert.Equal(t, map[string]int{"a": 1}, snapshot)
+	assert.Equal(t, 2, m.Len())
+
+	v, ok := m.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	v, ok = m.SetIfAbsent("a", 10)
+	assert.False(t, ok)
+	assert.Equal(t, 1, v)
+	v, ok = m.SetIfAbsent("c", 3)
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+
+	assert.True(t, m.Delete("c"))
+	assert.False(t, m.Delete("c"))
+
+	m.Update(func(data map[string]int) {
+		data["x"] = 7
+		delete(data, "a")
+	})
+	assert.Equal(t, map[string]int{"b": 2, "x": 7}, m.Load())
+
+	count := 0
+	m.Range(func(string, int) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+
+	data := map[string]int{"z": 26}
+	m.Replace(data)
+	data["y"] = 25
+	assert.Equal(t, map[string]int{"z": 26}, m.Load())
+}
+
+func TestMapConcurrent(t *testing.T) {
+	m := NewMap[int, int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Set(i*100+j, j)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Get(j)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 400, m.Len())
+}
@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxcow provides copy-on-write containers for read-mostly data, such as
+// provider lists and route configs. A read is a single atomic load without any lock,
+// and a mutation copies the data and swaps the pointer under a mutex.
+package gxcow
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Slice is a copy-on-write slice. The slices returned by Load are immutable
+// snapshots shared by all readers, so they MUST NOT be modified.
+type Slice[T any] struct {
+	lock sync.Mutex // serializes the mutations
+	data atomic.Value
+}
+
+// NewSlice returns a slice holding a copy of @items.
+func NewSlice[T any](items ...T) *Slice[T] {
+	s := &Slice[T]{}
+	s.Store(items)
+
+	return s
+}
+
+func (s *Slice[T]) load() []T {
+	if p, ok := s.data.Load().(*[]T); ok {
+		return *p
+	}
+	return nil
+}
+
+func (s *Slice[T]) store(items []T) {
+	s.data.Store(&items)
+}
+
+// Load returns the current snapshot.
+func (s *Slice[T]) Load() []T {
+	return s.load()
+}
+
+// Len returns the length of the current snapshot.
+func (s *Slice[T]) Len() int {
+	return len(s.load())
+}
+
+// At returns the item at @i of the current snapshot.
+func (s *Slice[T]) At(i int) T {
+	return s.load()[i]
+}
+
+// Store replaces the slice with a copy of @items.
+func (s *Slice[T]) Store(items []T) {
+	s.lock.Lock()
+	s.store(append([]T(nil), items...))
+	s.lock.Unlock()
+}
+
+// Append appends @items.
+func (s *Slice[T]) Append(items ...T) {
+	s.lock.Lock()
+	old := s.load()
+	cp := make([]T, len(old), len(old)+len(items))
+	copy(cp, old)
+	s.store(append(cp, items...))
+	s.lock.Unlock()
+}
+
+// RemoveFunc removes the items matched by @match, and returns the number of them.
+func (s *Slice[T]) RemoveFunc(match func(T) bool) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	old := s.load()
+	cp := make([]T, 0, len(old))
+	for _, v := range old {
+		if !match(v) {
+			cp = append(cp, v)
+		}
+	}
+	if removed := len(old) - len(cp); removed > 0 {
+		s.store(cp)
+		return removed
+	}
+
+	return 0
+}
+
+// Update replaces the slice with the result of @f, which is given a private copy of
+// the current snapshot. The mutations are serialized, so @f can do a read-modify-write.
+func (s *Slice[T]) Update(f func(items []T) []T) {
+	s.lock.Lock()
+	s.store(f(append([]T(nil), s.load()...)))
+	s.lock.Unlock()
+}
@@ -0,0 +1,82 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxcow
+
+import (
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlice(t *testing.T) {
+	s := NewSlice(1, 2)
+	snapshot := s.Load()
+
+	s.Append(3, 4)
+	assert.Equal(t, []int{1, 2}, snapshot)
+	assert.Equal(t, []int{1, 2, 3, 4}, s.Load())
+	assert.Equal(t, 4, s.Len())
+	assert.Equal(t, 3, s.At(2))
+
+	assert.Equal(t, 2, s.RemoveFunc(func(v int) bool { return v%2 == 0 }))
+	assert.Equal(t, 0, s.RemoveFunc(func(v int) bool { return v > 10 }))
+	assert.Equal(t, []int{1, 3}, s.Load())
+
+	s.Update(func(items []int) []int {
+		items[0] = 9
+		return items
+	})
+	assert.Equal(t, []int{9, 3}, s.Load())
+
+	items := []int{5}
+	s.Store(items)
+	items[0] = 6
+	assert.Equal(t, []int{5}, s.Load())
+
+	var empty Slice[string]
+	assert.Equal(t, 0, empty.Len())
+	empty.Append("a")
+	assert.Equal(t, []string{"a"}, empty.Load())
+}
+
+func TestSliceConcurrent(t *testing.T) {
+	s := NewSlice[int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Append(j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				for range s.Load() {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 400, s.Len())
+}
@@ -0,0 +1,151 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxdedup remembers the keys seen recently, e.g. to catch the retried requests.
+package gxdedup
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+import (
+	gxbloom "github.com/dubbogo/gost/container/bloom"
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+type bucket struct {
+	epoch  int64 // index of the time span covered by the bucket since the unix epoch
+	filter *gxbloom.Filter
+}
+
+// Window remembers the keys seen in the last @window duration. It is a ring of bloom
+// filters, each of which records the keys of a time span and is cleared for reuse once
+// its span drops out of the window, so a key takes a few bits instead of an entry and
+// the expiry costs no timer. As a bloom filter, a key never seen may be reported seen
+// at the false positive rate, but a key seen is never missed within the window. The
+// time is read by gxtime.Now. It is goroutine safe.
+type Window struct {
+	lock    sync.Mutex
+	span    time.Duration
+	buckets []bucket
+}
+
+// New returns a window of @window split into @buckets spans, which remembers a key for
+// @window at least and one span more at most. Every span is sized for @keysPerSpan keys,
+// and the false positive rate of the whole window is about @fpRate.
+func New(window time.Duration, buckets int, keysPerSpan int, fpRate float64) *Window {
+	if buckets <= 0 {
+		panic("@buckets <= 0")
+	}
+	if window < time.Duration(buckets) {
+		panic("@window < @buckets")
+	}
+
+	w := &Window{
+		span: window / time.Duration(buckets),
+		// one more for the current span, which is partly in the window
+		buckets: make([]bucket, buckets+1),
+	}
+	// a key is tested against every filter, so their false positives add up
+	m, k := gxbloom.Estimate(keysPerSpan, fpRate/float64(len(w.buckets)))
+	for i := range w.buckets {
+		w.buckets[i] = bucket{epoch: math.MinInt64, filter: gxbloom.NewWithSize(m, k)}
+	}
+
+	return w
+}
+
+// Size returns the duration remembered by the window.
+func (w *Window) Size() time.Duration {
+	return w.span * time.Duration(len(w.buckets)-1)
+}
+
+// current returns the epoch of now and its bucket, which is cleared if it is stale.
+// It should be invoked with the lock held.
+func (w *Window) current() (int64, *bucket) {
+	epoch := gxtime.Now().UnixNano() / int64(w.span)
+	b := &w.buckets[int(uint64(epoch)%uint64(len(w.buckets)))]
+	if b.epoch != epoch {
+		b.epoch = epoch
+		b.filter.Clear()
+	}
+
+	return epoch, b
+}
+
+// seen should be invoked with the lock held.
+func (w *Window) seen(key []byte, epoch int64) bool {
+	oldest := epoch - int64(len(w.buckets)-1)
+	for i := range w.buckets {
+		b := &w.buckets[i]
+		if b.epoch >= oldest && b.epoch <= epoch && b.filter.Test(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SeenAndRecord reports whether @key has been seen in the window, and records it.
+func (w *Window) SeenAndRecord(key []byte) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	epoch, b := w.current()
+	if w.seen(key, epoch) {
+		return true
+	}
+	b.filter.Add(key)
+
+	return false
+}
+
+// SeenAndRecordString is SeenAndRecord of a string key.
+func (w *Window) SeenAndRecordString(key string) bool {
+	return w.SeenAndRecord([]byte(key))
+}
+
+// Seen reports whether @key has been seen in the window without recording it.
+func (w *Window) Seen(key []byte) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	epoch, _ := w.current()
+	return w.seen(key, epoch)
+}
+
+// Record records @key.
+func (w *Window) Record(key []byte) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	_, b := w.current()
+	b.filter.Add(key)
+}
+
+// Reset forgets all keys.
+func (w *Window) Reset() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for i := range w.buckets {
+		w.buckets[i].epoch = math.MinInt64
+		w.buckets[i].filter.Clear()
+	}
+}
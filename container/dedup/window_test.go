@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxdedup
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+func TestWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	gxtime.SetTimeSource(func() time.Time { return now })
+	defer gxtime.SetTimeSource(nil)
+
+	w := New(10*time.Second, 10, 100, 0.01)
+	assert.Equal(t, 10*time.Second, w.Size())
+
+	assert.False(t, w.SeenAndRecordString("req-1"))
+	assert.True(t, w.SeenAndRecordString("req-1"))
+	assert.False(t, w.Seen([]byte("req-2")))
+	assert.False(t, w.Seen([]byte("req-2")))
+	w.Record([]byte("req-2"))
+	assert.True(t, w.Seen([]byte("req-2")))
+
+	// remembered for the whole window
+	now = now.Add(10 * time.Second)
+	assert.True(t, w.Seen([]byte("req-1")))
+	now = now.Add(time.Second)
+	assert.False(t, w.Seen([]byte("req-1")))
+	assert.False(t, w.SeenAndRecordString("req-2"))
+
+	w.Reset()
+	assert.False(t, w.Seen([]byte("req-2")))
+	assert.Panics(t, func() { New(time.Second, 0, 1, 0.01) })
+}
+
+func TestWindowFalsePositives(t *testing.T) {
+	now := time.Unix(1000, 0)
+	gxtime.SetTimeSource(func() time.Time { return now })
+	defer gxtime.SetTimeSource(nil)
+
+	w := New(time.Minute, 6, 1000, 0.01)
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 1000; j++ {
+			w.SeenAndRecordString(strconv.Itoa(i*1000 + j))
+		}
+		now = now.Add(10 * time.Second)
+	}
+
+	fp := 0
+	for i := 0; i < 10000; i++ {
+		if w.Seen([]byte("other-" + strconv.Itoa(i))) {
+			fp++
+		}
+	}
+	assert.True(t, fp < 300, "false positives %d", fp)
+}
@@ -0,0 +1,172 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxdeque implements a double-ended queue on a growable ring buffer.
+package gxdeque
+
+const minCapacity = 16
+
+// Deque is a double-ended queue with amortized O(1) push and pop at both ends. Its
+// buffer grows by doubling and shrinks once it is a quarter full. The zero value is
+// an empty deque ready to use. It is not goroutine safe.
+type Deque[T any] struct {
+	buf  []T // len(buf) is zero or a power of 2
+	head int
+	len  int
+}
+
+// New returns a deque whose buffer holds at least @capacity items before growing.
+func New[T any](capacity int) *Deque[T] {
+	size := minCapacity
+	for size < capacity {
+		size <<= 1
+	}
+
+	return &Deque[T]{buf: make([]T, size)}
+}
+
+// Len returns the number of items.
+func (d *Deque[T]) Len() int {
+	return d.len
+}
+
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) & (len(d.buf) - 1)
+}
+
+func (d *Deque[T]) resize(size int) {
+	buf := make([]T, size)
+	if d.head+d.len <= len(d.buf) {
+		copy(buf, d.buf[d.head:d.head+d.len])
+	} else {
+		n := copy(buf, d.buf[d.head:])
+		copy(buf[n:], d.buf[:d.len-n])
+	}
+	d.buf, d.head = buf, 0
+}
+
+func (d *Deque[T]) grow() {
+	if len(d.buf) == 0 {
+		d.buf = make([]T, minCapacity)
+	} else if d.len == len(d.buf) {
+		d.resize(len(d.buf) << 1)
+	}
+}
+
+func (d *Deque[T]) shrink() {
+	if len(d.buf) > minCapacity && d.len <= len(d.buf)/4 {
+		d.resize(len(d.buf) >> 1)
+	}
+}
+
+// PushBack adds @v at the back.
+func (d *Deque[T]) PushBack(v T) {
+	d.grow()
+	d.buf[d.index(d.len)] = v
+	d.len++
+}
+
+// PushFront adds @v at the front.
+func (d *Deque[T]) PushFront(v T) {
+	d.grow()
+	d.head = d.index(len(d.buf) - 1)
+	d.buf[d.head] = v
+	d.len++
+}
+
+// PopFront removes and returns the front item.
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.len == 0 {
+		return zero, false
+	}
+
+	v := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = d.index(1)
+	d.len--
+	d.shrink()
+
+	return v, true
+}
+
+// PopBack removes and returns the back item.
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.len == 0 {
+		return zero, false
+	}
+
+	i := d.index(d.len - 1)
+	v := d.buf[i]
+	d.buf[i] = zero
+	d.len--
+	d.shrink()
+
+	return v, true
+}
+
+// Front returns the front item without removing it.
+func (d *Deque[T]) Front() (T, bool) {
+	if d.len == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return d.buf[d.head], true
+}
+
+// Back returns the back item without removing it.
+func (d *Deque[T]) Back() (T, bool) {
+	if d.len == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return d.buf[d.index(d.len-1)], true
+}
+
+// At returns the @i-th item from the front. It panics if @i is out of range.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.len {
+		panic("deque index out of range")
+	}
+
+	return d.buf[d.index(i)]
+}
+
+// PeekN returns a copy of at most @n items from the front without removing them.
+func (d *Deque[T]) PeekN(n int) []T {
+	if n > d.len {
+		n = d.len
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	items := make([]T, n)
+	for i := range items {
+		items[i] = d.buf[d.index(i)]
+	}
+
+	return items
+}
+
+// Clear removes all items and releases the buffer.
+func (d *Deque[T]) Clear() {
+	d.buf, d.head, d.len = nil, 0, 0
+}
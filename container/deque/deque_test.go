@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxdeque
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeque(t *testing.T) {
+	var d Deque[int]
+	_, ok := d.PopFront()
+	assert.False(t, ok)
+	_, ok = d.Back()
+	assert.False(t, ok)
+
+	for i := 0; i < 10; i++ {
+		d.PushBack(i)
+		d.PushFront(-i - 1)
+	}
+	assert.Equal(t, 20, d.Len())
+	assert.Equal(t, -10, d.At(0))
+	assert.Equal(t, 9, d.At(19))
+	assert.Equal(t, []int{-10, -9, -8}, d.PeekN(3))
+	assert.Len(t, d.PeekN(100), 20)
+	assert.Nil(t, d.PeekN(0))
+	assert.Panics(t, func() { d.At(20) })
+
+	v, _ := d.Front()
+	assert.Equal(t, -10, v)
+	v, _ = d.PopBack()
+	assert.Equal(t, 9, v)
+	v, _ = d.PopFront()
+	assert.Equal(t, -10, v)
+	assert.Equal(t, 18, d.Len())
+
+	d.Clear()
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestDequeGrowShrink(t *testing.T) {
+	d := New[int](20)
+	assert.Equal(t, 32, len(d.buf))
+
+	// wrap the ring around before growing
+	for i := 0; i < 20; i++ {
+		d.PushBack(i)
+	}
+	for i := 0; i < 10; i++ {
+		d.PopFront()
+	}
+	for i := 20; i < 1000; i++ {
+		d.PushBack(i)
+	}
+	assert.Equal(t, 990, d.Len())
+	assert.Equal(t, 1024, len(d.buf))
+	for i := 10; i < 1000; i++ {
+		assert.Equal(t, i, d.At(i-10))
+	}
+
+	for i := 10; i < 990; i++ {
+		v, _ := d.PopFront()
+		assert.Equal(t, i, v)
+	}
+	assert.Equal(t, 10, d.Len())
+	assert.Equal(t, 32, len(d.buf))
+	assert.Equal(t, []int{990, 991}, d.PeekN(2))
+}
@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxhamt implements a persistent hash array mapped trie. A mutation copies
+// only the path from the root to the changed entry, so an immutable point-in-time
+// view of the whole map costs nothing, which suits the hot-reloaded configs whose
+// readers must see a consistent version.
+package gxhamt
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Snapshot is an immutable version of a map. It is safe for concurrent use, and its
+// Set and Delete return new versions sharing the most of the trie with it.
+type Snapshot[K comparable, V any] struct {
+	root   *node[K, V]
+	size   int
+	hasher func(K) uint64
+}
+
+// Empty returns an empty snapshot. @hasher hashes the keys, a nil one hashes the
+// integers and strings directly and the other keys by fmt.
+func Empty[K comparable, V any](hasher func(K) uint64) *Snapshot[K, V] {
+	if hasher == nil {
+		hasher = hashKey[K]
+	}
+
+	return &Snapshot[K, V]{root: &node[K, V]{}, hasher: hasher}
+}
+
+// Len returns the number of entries.
+func (s *Snapshot[K, V]) Len() int {
+	return s.size
+}
+
+// Get returns the value of @key.
+func (s *Snapshot[K, V]) Get(key K) (V, bool) {
+	return s.root.get(s.hasher(key), key)
+}
+
+// Set returns a new version in which @key is set to @value.
+func (s *Snapshot[K, V]) Set(key K, value V) *Snapshot[K, V] {
+	root, added := s.root.with(0, s.hasher(key), key, value)
+	size := s.size
+	if added {
+		size++
+	}
+
+	return &Snapshot[K, V]{root: root, size: size, hasher: s.hasher}
+}
+
+// Delete returns a new version without @key, or s itself if @key does not exist.
+func (s *Snapshot[K, V]) Delete(key K) *Snapshot[K, V] {
+	root, removed := s.root.without(0, s.hasher(key), key)
+	if !removed {
+		return s
+	}
+	if root == nil {
+		root = &node[K, V]{}
+	}
+
+	return &Snapshot[K, V]{root: root, size: s.size - 1, hasher: s.hasher}
+}
+
+// Range calls @f for every entry in the hash order until @f returns false.
+func (s *Snapshot[K, V]) Range(f func(key K, value V) bool) {
+	s.root.rangeEntries(f)
+}
+
+// Map is a concurrent map on the persistent trie. A read loads the current version
+// atomically without any lock, and the writes are serialized.
+type Map[K comparable, V any] struct {
+	lock    sync.Mutex // serializes the writes
+	current atomic.Value
+}
+
+// New returns an empty map, see Empty for @hasher.
+func New[K comparable, V any](hasher func(K) uint64) *Map[K, V] {
+	m := &Map[K, V]{}
+	m.current.Store(Empty[K, V](hasher))
+
+	return m
+}
+
+// Snapshot returns the current version, which never changes afterwards.
+func (m *Map[K, V]) Snapshot() *Snapshot[K, V] {
+	return m.current.Load().(*Snapshot[K, V])
+}
+
+// Len returns the number of entries.
+func (m *Map[K, V]) Len() int {
+	return m.Snapshot().Len()
+}
+
+// Get returns the value of @key.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	return m.Snapshot().Get(key)
+}
+
+// Range calls @f for every entry of the current version until @f returns false.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	m.Snapshot().Range(f)
+}
+
+// Set sets the value of @key.
+func (m *Map[K, V]) Set(key K, value V) {
+	m.Update(func(s *Snapshot[K, V]) *Snapshot[K, V] {
+		return s.Set(key, value)
+	})
+}
+
+// Delete removes @key.
+func (m *Map[K, V]) Delete(key K) {
+	m.Update(func(s *Snapshot[K, V]) *Snapshot[K, V] {
+		return s.Delete(key)
+	})
+}
+
+// Update publishes the version returned by @f, which is given the current version.
+// The readers see either the old version or the whole batch of @f.
+func (m *Map[K, V]) Update(f func(s *Snapshot[K, V]) *Snapshot[K, V]) {
+	m.lock.Lock()
+	m.current.Store(f(m.Snapshot()))
+	m.lock.Unlock()
+}
+
+func hashKey[K comparable](key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return hashString(k)
+	case int:
+		return mix(uint64(k))
+	case int32:
+		return mix(uint64(k))
+	case int64:
+		return mix(uint64(k))
+	case uint:
+		return mix(uint64(k))
+	case uint32:
+		return mix(uint64(k))
+	case uint64:
+		return mix(k)
+	case float64:
+		return mix(math.Float64bits(k))
+	}
+
+	return hashString(fmt.Sprintf("%#v", key))
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+
+	return h.Sum64()
+}
+
+// mix is the finalizer of splitmix64, which spreads the bits of sequential integers.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxhamt
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	s0 := Empty[string, int](nil)
+	s1 := s0.Set("a", 1).Set("b", 2)
+	s2 := s1.Set("a", 10).Delete("b")
+
+	assert.Equal(t, 0, s0.Len())
+	assert.Equal(t, 2, s1.Len())
+	assert.Equal(t, 1, s2.Len())
+
+	v, ok := s1.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	v, _ = s2.Get("a")
+	assert.Equal(t, 10, v)
+	_, ok = s2.Get("b")
+	assert.False(t, ok)
+
+	assert.Equal(t, s2, s2.Delete("none"))
+	assert.Equal(t, 0, s2.Delete("a").Len())
+}
+
+func TestSnapshotCollision(t *testing.T) {
+	// every key collides, then splits on the low bits only
+	s := Empty[int, int](func(k int) uint64 { return uint64(k % 4) })
+	for i := 0; i < 64; i++ {
+		s = s.Set(i, i)
+	}
+	assert.Equal(t, 64, s.Len())
+	for i := 0; i < 64; i++ {
+		v, ok := s.Get(i)
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	for i := 0; i < 64; i += 2 {
+		s = s.Delete(i)
+	}
+	assert.Equal(t, 32, s.Len())
+	_, ok := s.Get(2)
+	assert.False(t, ok)
+	v, _ := s.Get(3)
+	assert.Equal(t, 3, v)
+}
+
+func TestSnapshotRandom(t *testing.T) {
+	var (
+		s     = Empty[int, int](nil)
+		model = map[int]int{}
+	)
+	for i := 0; i < 20000; i++ {
+		k := rand.Intn(2000)
+		if rand.Intn(3) == 0 {
+			s = s.Delete(k)
+			delete(model, k)
+		} else {
+			s = s.Set(k, i)
+			model[k] = i
+		}
+	}
+
+	assert.Equal(t, len(model), s.Len())
+	got := map[int]int{}
+	s.Range(func(k, v int) bool {
+		got[k] = v
+		return true
+	})
+	assert.Equal(t, model, got)
+
+	count := 0
+	s.Range(func(int, int) bool {
+		count++
+		return count < 3
+	})
+	assert.Equal(t, 3, count)
+}
+
+func TestMap(t *testing.T) {
+	m := New[string, int](nil)
+	m.Set("a", 1)
+	snapshot := m.Snapshot()
+	m.Set("b", 2)
+	m.Delete("a")
+
+	assert.Equal(t, 1, snapshot.Len())
+	v, ok := snapshot.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	assert.Equal(t, 1, m.Len())
+	_, ok = m.Get("a")
+	assert.False(t, ok)
+
+	m.Update(func(s *Snapshot[string, int]) *Snapshot[string, int] {
+		return s.Set("x", 1).Set("y", 2)
+	})
+	assert.Equal(t, 3, m.Len())
+}
+
+func TestMapConcurrent(t *testing.T) {
+	m := New[string, int](nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Set(strconv.Itoa(i*100+j), j)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s := m.Snapshot()
+				n := 0
+				s.Range(func(string, int) bool {
+					n++
+					return true
+				})
+				assert.Equal(t, s.Len(), n)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 400, m.Len())
+}
@@ -0,0 +1,224 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxhamt
+
+import (
+	"math/bits"
+)
+
+const (
+	bitsPerLevel = 5
+	levelMask    = 1<<bitsPerLevel - 1
+)
+
+type kv[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// leaf holds the entries whose keys share the same full hash.
+type leaf[K comparable, V any] struct {
+	hash    uint64
+	entries []kv[K, V]
+}
+
+// child is either a sub node or a leaf.
+type child[K comparable, V any] struct {
+	node *node[K, V]
+	leaf *leaf[K, V]
+}
+
+// node is an immutable bitmap indexed node, a mutation returns a new node sharing
+// the untouched children with the old one.
+type node[K comparable, V any] struct {
+	bitmap   uint32
+	children []child[K, V]
+}
+
+func position(bitmap uint32, hash uint64, depth int) (uint32, int) {
+	bit := uint32(1) << ((hash >> (uint(depth) * bitsPerLevel)) & levelMask)
+	return bit, bits.OnesCount32(bitmap & (bit - 1))
+}
+
+func (n *node[K, V]) get(hash uint64, key K) (V, bool) {
+	for depth := 0; n != nil; depth++ {
+		bit, pos := position(n.bitmap, hash, depth)
+		if n.bitmap&bit == 0 {
+			break
+		}
+
+		c := n.children[pos]
+		if c.node != nil {
+			n = c.node
+			continue
+		}
+		if c.leaf.hash == hash {
+			for _, e := range c.leaf.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+		}
+		break
+	}
+
+	var zero V
+	return zero, false
+}
+
+// with returns the node in which @key is set to @value, and whether @key is new.
+func (n *node[K, V]) with(depth int, hash uint64, key K, value V) (*node[K, V], bool) {
+	bit, pos := position(n.bitmap, hash, depth)
+	if n.bitmap&bit == 0 {
+		children := make([]child[K, V], len(n.children)+1)
+		copy(children, n.children[:pos])
+		children[pos] = child[K, V]{leaf: &leaf[K, V]{hash: hash, entries: []kv[K, V]{{key, value}}}}
+		copy(children[pos+1:], n.children[pos:])
+		return &node[K, V]{bitmap: n.bitmap | bit, children: children}, true
+	}
+
+	var (
+		c     = n.children[pos]
+		added bool
+	)
+	switch {
+	case c.node != nil:
+		var sub *node[K, V]
+		sub, added = c.node.with(depth+1, hash, key, value)
+		c = child[K, V]{node: sub}
+	case c.leaf.hash == hash:
+		var l *leaf[K, V]
+		l, added = c.leaf.with(key, value)
+		c = child[K, V]{leaf: l}
+	default:
+		// push the leaf down a level, two different hashes differ within 64 bits,
+		// so the split always ends
+		bit, _ := position(0, c.leaf.hash, depth+1)
+		sub := &node[K, V]{bitmap: bit, children: []child[K, V]{c}}
+		sub, _ = sub.with(depth+1, hash, key, value)
+		c, added = child[K, V]{node: sub}, true
+	}
+
+	return n.replace(pos, c), added
+}
+
+// without returns the node from which @key is removed, or nil if the node becomes
+// empty, and whether @key existed.
+func (n *node[K, V]) without(depth int, hash uint64, key K) (*node[K, V], bool) {
+	bit, pos := position(n.bitmap, hash, depth)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	c := n.children[pos]
+	if c.node != nil {
+		sub, removed := c.node.without(depth+1, hash, key)
+		if !removed {
+			return n, false
+		}
+		switch {
+		case sub == nil:
+			return n.remove(pos, bit), true
+		case len(sub.children) == 1 && sub.children[0].leaf != nil:
+			// collapse a single leaf path
+			return n.replace(pos, sub.children[0]), true
+		}
+		return n.replace(pos, child[K, V]{node: sub}), true
+	}
+
+	if c.leaf.hash != hash {
+		return n, false
+	}
+	l, removed := c.leaf.without(key)
+	if !removed {
+		return n, false
+	}
+	if l == nil {
+		return n.remove(pos, bit), true
+	}
+
+	return n.replace(pos, child[K, V]{leaf: l}), true
+}
+
+func (n *node[K, V]) replace(pos int, c child[K, V]) *node[K, V] {
+	children := make([]child[K, V], len(n.children))
+	copy(children, n.children)
+	children[pos] = c
+
+	return &node[K, V]{bitmap: n.bitmap, children: children}
+}
+
+func (n *node[K, V]) remove(pos int, bit uint32) *node[K, V] {
+	if len(n.children) == 1 {
+		return nil
+	}
+
+	children := make([]child[K, V], 0, len(n.children)-1)
+	children = append(children, n.children[:pos]...)
+	children = append(children, n.children[pos+1:]...)
+
+	return &node[K, V]{bitmap: n.bitmap &^ bit, children: children}
+}
+
+func (n *node[K, V]) rangeEntries(f func(K, V) bool) bool {
+	for _, c := range n.children {
+		if c.node != nil {
+			if !c.node.rangeEntries(f) {
+				return false
+			}
+			continue
+		}
+		for _, e := range c.leaf.entries {
+			if !f(e.key, e.value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (l *leaf[K, V]) with(key K, value V) (*leaf[K, V], bool) {
+	entries := make([]kv[K, V], len(l.entries), len(l.entries)+1)
+	copy(entries, l.entries)
+	for i := range entries {
+		if entries[i].key == key {
+			entries[i].value = value
+			return &leaf[K, V]{hash: l.hash, entries: entries}, false
+		}
+	}
+
+	return &leaf[K, V]{hash: l.hash, entries: append(entries, kv[K, V]{key, value})}, true
+}
+
+func (l *leaf[K, V]) without(key K) (*leaf[K, V], bool) {
+	for i, e := range l.entries {
+		if e.key != key {
+			continue
+		}
+		if len(l.entries) == 1 {
+			return nil, true
+		}
+		entries := make([]kv[K, V], 0, len(l.entries)-1)
+		entries = append(entries, l.entries[:i]...)
+		entries = append(entries, l.entries[i+1:]...)
+		return &leaf[K, V]{hash: l.hash, entries: entries}, true
+	}
+
+	return l, false
+}
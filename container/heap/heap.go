@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxheap implements a generic binary heap and an indexed priority queue.
+package gxheap
+
+// Heap is a binary heap ordered by a less func: it is a min heap for a < func, and
+// a max heap for a > func. It is not goroutine safe.
+type Heap[T any] struct {
+	less  func(a, b T) bool
+	items []T
+}
+
+// New returns a heap ordered by @less, which is heapified from @items. The heap
+// takes the ownership of @items.
+func New[T any](less func(a, b T) bool, items ...T) *Heap[T] {
+	h := &Heap[T]{less: less, items: items}
+	for i := len(items)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+
+	return h
+}
+
+// Len returns the number of items.
+func (h *Heap[T]) Len() int {
+	return len(h.items)
+}
+
+// Push adds @item in O(log n).
+func (h *Heap[T]) Push(item T) {
+	h.items = append(h.items, item)
+	h.up(len(h.items) - 1)
+}
+
+// Peek returns the top item without removing it.
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return h.items[0], true
+}
+
+// Pop removes and returns the top item in O(log n).
+func (h *Heap[T]) Pop() (T, bool) {
+	var zero T
+
+	n := len(h.items) - 1
+	if n < 0 {
+		return zero, false
+	}
+	top := h.items[0]
+	h.items[0] = h.items[n]
+	h.items[n] = zero
+	h.items = h.items[:n]
+	if n > 0 {
+		h.down(0)
+	}
+
+	return top, true
+}
+
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.items[i], h.items[parent]) {
+			break
+		}
+		h.items[i], h.items[parent] = h.items[parent], h.items[i]
+		i = parent
+	}
+}
+
+func (h *Heap[T]) down(i int) {
+	n := len(h.items)
+	for {
+		min := i
+		if l := 2*i + 1; l < n && h.less(h.items[l], h.items[min]) {
+			min = l
+		}
+		if r := 2*i + 2; r < n && h.less(h.items[r], h.items[min]) {
+			min = r
+		}
+		if min == i {
+			return
+		}
+		h.items[i], h.items[min] = h.items[min], h.items[i]
+		i = min
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxheap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeap(t *testing.T) {
+	h := New(func(a, b int) bool { return a < b }, 5, 3, 8)
+	_, ok := New(func(a, b int) bool { return a < b }).Pop()
+	assert.False(t, ok)
+
+	values := rand.New(rand.NewSource(1)).Perm(100)
+	for _, v := range values {
+		h.Push(v + 10)
+	}
+	assert.Equal(t, 103, h.Len())
+	top, _ := h.Peek()
+	assert.Equal(t, 3, top)
+
+	var got []int
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		got = append(got, v)
+	}
+	assert.True(t, sort.IntsAreSorted(got))
+
+	max := New(func(a, b string) bool { return a > b }, "a", "c", "b")
+	v, _ := max.Pop()
+	assert.Equal(t, "c", v)
+}
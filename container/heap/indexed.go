@@ -0,0 +1,202 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxheap
+
+type pqItem[K comparable, P any] struct {
+	key      K
+	priority P
+}
+
+// IndexedPQ is a priority queue of unique keys, whose items can be found by key, so the
+// priority of a key can be changed and a key can be removed in O(log n). It is not
+// goroutine safe.
+type IndexedPQ[K comparable, P any] struct {
+	less  func(a, b P) bool
+	items []pqItem[K, P]
+	index map[K]int // key -> position in items
+}
+
+// NewIndexedPQ returns an empty queue whose top is the least priority by @less.
+func NewIndexedPQ[K comparable, P any](less func(a, b P) bool) *IndexedPQ[K, P] {
+	return &IndexedPQ[K, P]{
+		less:  less,
+		index: make(map[K]int),
+	}
+}
+
+// Len returns the number of keys.
+func (q *IndexedPQ[K, P]) Len() int {
+	return len(q.items)
+}
+
+// Contains reports whether @key is in the queue.
+func (q *IndexedPQ[K, P]) Contains(key K) bool {
+	_, ok := q.index[key]
+	return ok
+}
+
+// Priority returns the priority of @key.
+func (q *IndexedPQ[K, P]) Priority(key K) (P, bool) {
+	i, ok := q.index[key]
+	if !ok {
+		var zero P
+		return zero, false
+	}
+
+	return q.items[i].priority, true
+}
+
+// Push adds @key with @priority, or updates the priority if @key exists.
+func (q *IndexedPQ[K, P]) Push(key K, priority P) {
+	if i, ok := q.index[key]; ok {
+		q.items[i].priority = priority
+		q.fix(i)
+		return
+	}
+
+	q.items = append(q.items, pqItem[K, P]{key: key, priority: priority})
+	q.index[key] = len(q.items) - 1
+	q.up(len(q.items) - 1)
+}
+
+// Update changes the priority of @key. It returns false if @key is absent.
+func (q *IndexedPQ[K, P]) Update(key K, priority P) bool {
+	i, ok := q.index[key]
+	if !ok {
+		return false
+	}
+	q.items[i].priority = priority
+	q.fix(i)
+
+	return true
+}
+
+// DecreaseKey moves @key towards the top with @priority. It returns false if @key is
+// absent or @priority is not less than the current one.
+func (q *IndexedPQ[K, P]) DecreaseKey(key K, priority P) bool {
+	i, ok := q.index[key]
+	if !ok || !q.less(priority, q.items[i].priority) {
+		return false
+	}
+	q.items[i].priority = priority
+	q.up(i)
+
+	return true
+}
+
+// Peek returns the top key and its priority without removing it.
+func (q *IndexedPQ[K, P]) Peek() (K, P, bool) {
+	if len(q.items) == 0 {
+		var (
+			zeroK K
+			zeroP P
+		)
+		return zeroK, zeroP, false
+	}
+
+	return q.items[0].key, q.items[0].priority, true
+}
+
+// Pop removes and returns the top key and its priority.
+func (q *IndexedPQ[K, P]) Pop() (K, P, bool) {
+	if len(q.items) == 0 {
+		var (
+			zeroK K
+			zeroP P
+		)
+		return zeroK, zeroP, false
+	}
+
+	top := q.items[0]
+	q.removeAt(0)
+
+	return top.key, top.priority, true
+}
+
+// Remove removes @key and returns its priority.
+func (q *IndexedPQ[K, P]) Remove(key K) (P, bool) {
+	i, ok := q.index[key]
+	if !ok {
+		var zero P
+		return zero, false
+	}
+
+	priority := q.items[i].priority
+	q.removeAt(i)
+
+	return priority, true
+}
+
+func (q *IndexedPQ[K, P]) removeAt(i int) {
+	n := len(q.items) - 1
+	delete(q.index, q.items[i].key)
+	if i != n {
+		q.items[i] = q.items[n]
+		q.index[q.items[i].key] = i
+	}
+	q.items[n] = pqItem[K, P]{}
+	q.items = q.items[:n]
+	if i < n {
+		q.fix(i)
+	}
+}
+
+func (q *IndexedPQ[K, P]) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.index[q.items[i].key] = i
+	q.index[q.items[j].key] = j
+}
+
+func (q *IndexedPQ[K, P]) fix(i int) {
+	if !q.up(i) {
+		q.down(i)
+	}
+}
+
+// up reports whether the item moves.
+func (q *IndexedPQ[K, P]) up(i int) bool {
+	moved := false
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i].priority, q.items[parent].priority) {
+			break
+		}
+		q.swap(i, parent)
+		i, moved = parent, true
+	}
+
+	return moved
+}
+
+func (q *IndexedPQ[K, P]) down(i int) {
+	n := len(q.items)
+	for {
+		min := i
+		if l := 2*i + 1; l < n && q.less(q.items[l].priority, q.items[min].priority) {
+			min = l
+		}
+		if r := 2*i + 2; r < n && q.less(q.items[r].priority, q.items[min].priority) {
+			min = r
+		}
+		if min == i {
+			return
+		}
+		q.swap(i, min)
+		i = min
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxheap
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexedPQ(t *testing.T) {
+	q := NewIndexedPQ[string, int](func(a, b int) bool { return a < b })
+	_, _, ok := q.Pop()
+	assert.False(t, ok)
+
+	q.Push("a", 30)
+	q.Push("b", 20)
+	q.Push("c", 10)
+	q.Push("d", 40)
+	k, p, _ := q.Peek()
+	assert.Equal(t, "c", k)
+	assert.Equal(t, 10, p)
+
+	assert.True(t, q.DecreaseKey("d", 5))
+	assert.False(t, q.DecreaseKey("d", 50))
+	assert.False(t, q.DecreaseKey("x", 1))
+	k, _, _ = q.Peek()
+	assert.Equal(t, "d", k)
+
+	assert.True(t, q.Update("d", 100))
+	q.Push("b", 1)
+	p, ok = q.Remove("c")
+	assert.True(t, ok)
+	assert.Equal(t, 10, p)
+	_, ok = q.Remove("c")
+	assert.False(t, ok)
+	assert.True(t, q.Contains("a"))
+	p, _ = q.Priority("a")
+	assert.Equal(t, 30, p)
+
+	var keys []string
+	for q.Len() > 0 {
+		k, _, _ := q.Pop()
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []string{"b", "a", "d"}, keys)
+	assert.False(t, q.Contains("a"))
+}
+
+func TestIndexedPQDeadlines(t *testing.T) {
+	q := NewIndexedPQ[int, time.Time](func(a, b time.Time) bool { return a.Before(b) })
+	now := time.Now()
+	for i := 0; i < 50; i++ {
+		q.Push(i, now.Add(time.Duration(50-i)*time.Second))
+	}
+	for i := 0; i < 50; i += 2 {
+		q.Remove(i)
+	}
+
+	prev := time.Time{}
+	for q.Len() > 0 {
+		k, deadline, _ := q.Pop()
+		assert.Equal(t, 1, k%2)
+		assert.True(t, deadline.After(prev))
+		prev = deadline
+	}
+}
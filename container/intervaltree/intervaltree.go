@@ -0,0 +1,239 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxintervaltree implements an interval tree over half-open ranges [start, end),
+// to index time based route rules or IP ranges (as integers) by the points they cover.
+package gxintervaltree
+
+import (
+	"math/rand"
+)
+
+import (
+	gxsort "github.com/dubbogo/gost/sort"
+)
+
+// Interval is a range [Start, End) with its value.
+type Interval[K gxsort.Ordered, V any] struct {
+	Start K
+	End   K
+	Value V
+}
+
+// node is a treap node ordered by (start, end), augmented with the max end of its subtree.
+type node[K gxsort.Ordered, V any] struct {
+	iv          Interval[K, V]
+	max         K
+	prio        uint32
+	left, right *node[K, V]
+}
+
+func (n *node[K, V]) less(start, end K) bool {
+	return n.iv.Start < start || (n.iv.Start == start && n.iv.End < end)
+}
+
+func (n *node[K, V]) update() {
+	n.max = n.iv.End
+	if n.left != nil && n.max < n.left.max {
+		n.max = n.left.max
+	}
+	if n.right != nil && n.max < n.right.max {
+		n.max = n.right.max
+	}
+}
+
+// Tree is an interval tree, a randomized balanced search tree whose operations take
+// O(log n) expected time, and O(log n + k) for a query matching k intervals. The same
+// range can be inserted more than once. It is not safe for concurrent use.
+type Tree[K gxsort.Ordered, V any] struct {
+	root *node[K, V]
+	size int
+	rand *rand.Rand
+}
+
+// New returns an empty tree.
+func New[K gxsort.Ordered, V any]() *Tree[K, V] {
+	return &Tree[K, V]{rand: rand.New(rand.NewSource(rand.Int63()))}
+}
+
+// Len returns the number of intervals.
+func (t *Tree[K, V]) Len() int {
+	return t.size
+}
+
+// Insert adds [@start, @end) with @value. It panics if the range is empty.
+func (t *Tree[K, V]) Insert(start, end K, value V) {
+	if !(start < end) {
+		panic("@start >= @end")
+	}
+
+	n := &node[K, V]{iv: Interval[K, V]{Start: start, End: end, Value: value}, prio: t.rand.Uint32()}
+	n.max = end
+	t.root = insert(t.root, n)
+	t.size++
+}
+
+func insert[K gxsort.Ordered, V any](root, n *node[K, V]) *node[K, V] {
+	if root == nil {
+		return n
+	}
+	if n.prio > root.prio {
+		n.left, n.right = split(root, n.iv.Start, n.iv.End)
+		n.update()
+		return n
+	}
+
+	if n.less(root.iv.Start, root.iv.End) {
+		root.left = insert(root.left, n)
+	} else {
+		root.right = insert(root.right, n)
+	}
+	root.update()
+
+	return root
+}
+
+// split splits @n into the nodes less than (start, end) and the others.
+func split[K gxsort.Ordered, V any](n *node[K, V], start, end K) (*node[K, V], *node[K, V]) {
+	if n == nil {
+		return nil, nil
+	}
+
+	if n.less(start, end) {
+		l, r := split(n.right, start, end)
+		n.right = l
+		n.update()
+		return n, r
+	}
+
+	l, r := split(n.left, start, end)
+	n.left = r
+	n.update()
+
+	return l, n
+}
+
+// merge joins @l and @r, every node of which is not less than any node of @l.
+func merge[K gxsort.Ordered, V any](l, r *node[K, V]) *node[K, V] {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.prio > r.prio:
+		l.right = merge(l.right, r)
+		l.update()
+		return l
+	default:
+		r.left = merge(l, r.left)
+		r.update()
+		return r
+	}
+}
+
+// Delete removes an interval of exactly [@start, @end), and returns false if there is none.
+func (t *Tree[K, V]) Delete(start, end K) bool {
+	var removed bool
+	t.root, removed = remove(t.root, start, end)
+	if removed {
+		t.size--
+	}
+
+	return removed
+}
+
+func remove[K gxsort.Ordered, V any](n *node[K, V], start, end K) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	if n.iv.Start == start && n.iv.End == end {
+		return merge(n.left, n.right), true
+	}
+
+	var removed bool
+	if n.less(start, end) {
+		n.right, removed = remove(n.right, start, end)
+	} else {
+		n.left, removed = remove(n.left, start, end)
+	}
+	if removed {
+		n.update()
+	}
+
+	return n, removed
+}
+
+// Stab returns the intervals containing @point, in the order of (start, end).
+func (t *Tree[K, V]) Stab(point K) []Interval[K, V] {
+	var result []Interval[K, V]
+	stab(t.root, point, &result)
+
+	return result
+}
+
+func stab[K gxsort.Ordered, V any](n *node[K, V], point K, result *[]Interval[K, V]) {
+	// no interval of the subtree ends after @point
+	if n == nil || !(point < n.max) {
+		return
+	}
+
+	stab(n.left, point, result)
+	if point < n.iv.Start {
+		// so do all the intervals of the right subtree
+		return
+	}
+	if point < n.iv.End {
+		*result = append(*result, n.iv)
+	}
+	stab(n.right, point, result)
+}
+
+// Overlap returns the intervals overlapping [@start, @end), in the order of (start, end).
+func (t *Tree[K, V]) Overlap(start, end K) []Interval[K, V] {
+	var result []Interval[K, V]
+	overlap(t.root, start, end, &result)
+
+	return result
+}
+
+func overlap[K gxsort.Ordered, V any](n *node[K, V], start, end K, result *[]Interval[K, V]) {
+	if n == nil || !(start < n.max) {
+		return
+	}
+
+	overlap(n.left, start, end, result)
+	if !(n.iv.Start < end) {
+		return
+	}
+	if start < n.iv.End {
+		*result = append(*result, n.iv)
+	}
+	overlap(n.right, start, end, result)
+}
+
+// Range calls @f for every interval in the order of (start, end) until @f returns false.
+func (t *Tree[K, V]) Range(f func(iv Interval[K, V]) bool) {
+	walk(t.root, f)
+}
+
+func walk[K gxsort.Ordered, V any](n *node[K, V], f func(iv Interval[K, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return walk(n.left, f) && f(n.iv) && walk(n.right, f)
+}
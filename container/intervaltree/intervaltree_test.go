@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxintervaltree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	tree := New[int, string]()
+	tree.Insert(0, 10, "a")
+	tree.Insert(5, 15, "b")
+	tree.Insert(20, 30, "c")
+	tree.Insert(5, 15, "b2")
+	assert.Equal(t, 4, tree.Len())
+	assert.Panics(t, func() { tree.Insert(3, 3, "empty") })
+
+	values := func(ivs []Interval[int, string]) []string {
+		var vs []string
+		for _, iv := range ivs {
+			vs = append(vs, iv.Value)
+		}
+		return vs
+	}
+
+	assert.Equal(t, []string{"a"}, values(tree.Stab(0)))
+	assert.Equal(t, 3, len(tree.Stab(7)))
+	assert.Nil(t, tree.Stab(15))
+	assert.Equal(t, []string{"c"}, values(tree.Stab(29)))
+	assert.Nil(t, tree.Stab(30))
+
+	assert.Equal(t, 4, len(tree.Overlap(9, 21)))
+	assert.Equal(t, []string{"c"}, values(tree.Overlap(15, 20+1)))
+	assert.Nil(t, tree.Overlap(15, 20))
+
+	assert.True(t, tree.Delete(5, 15))
+	assert.Equal(t, 2, len(tree.Stab(7)))
+	assert.True(t, tree.Delete(5, 15))
+	assert.False(t, tree.Delete(5, 15))
+	assert.Equal(t, []string{"a"}, values(tree.Stab(7)))
+	assert.Equal(t, 2, tree.Len())
+
+	var starts []int
+	tree.Range(func(iv Interval[int, string]) bool {
+		starts = append(starts, iv.Start)
+		return true
+	})
+	assert.Equal(t, []int{0, 20}, starts)
+}
+
+func TestTreeRandom(t *testing.T) {
+	var (
+		tree  = New[int, int]()
+		model []Interval[int, int]
+	)
+	for i := 0; i < 2000; i++ {
+		start := rand.Intn(1000)
+		end := start + 1 + rand.Intn(50)
+		if len(model) > 0 && rand.Intn(3) == 0 {
+			j := rand.Intn(len(model))
+			assert.True(t, tree.Delete(model[j].Start, model[j].End))
+			model = append(model[:j], model[j+1:]...)
+			continue
+		}
+		tree.Insert(start, end, i)
+		model = append(model, Interval[int, int]{start, end, i})
+	}
+	assert.Equal(t, len(model), tree.Len())
+
+	for p := 0; p < 1100; p += 7 {
+		expected := 0
+		for _, iv := range model {
+			if iv.Start <= p && p < iv.End {
+				expected++
+			}
+		}
+		got := tree.Stab(p)
+		assert.Equal(t, expected, len(got), p)
+		for _, iv := range got {
+			assert.True(t, iv.Start <= p && p < iv.End)
+		}
+
+		expected = 0
+		for _, iv := range model {
+			if iv.Start < p+20 && p < iv.End {
+				expected++
+			}
+		}
+		assert.Equal(t, expected, len(tree.Overlap(p, p+20)), p)
+	}
+}
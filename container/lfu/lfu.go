@@ -0,0 +1,264 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxlfu implements a W-TinyLFU cache, whose admission policy keeps the
+// frequently used entries against scans which flush a plain LRU cache.
+// ref: https://arxiv.org/abs/1512.00727
+package gxlfu
+
+import (
+	"container/list"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+const (
+	segmentWindow = iota
+	segmentProbation
+	segmentProtected
+)
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	hash    uint64
+	segment int
+}
+
+// Cache is a W-TinyLFU cache. New entries go to a small LRU window. An entry leaving
+// the window is admitted into the main segmented LRU only if it is estimated to be used
+// more frequently than the victim of the main one. An entry hit in the probation
+// segment of the main LRU is promoted to the protected segment. It is goroutine safe.
+type Cache[K comparable, V any] struct {
+	lock         sync.Mutex
+	hasher       func(K) uint64
+	onEvict      func(K, V)
+	sketch       *countMinSketch
+	entries      map[K]*list.Element
+	window       *list.List
+	probation    *list.List
+	protected    *list.List
+	windowCap    int
+	mainCap      int
+	protectedCap int
+}
+
+// New returns a cache of at most @capacity entries, which should be positive. @hasher
+// hashes the keys for the frequency sketch; a nil one hashes the integers and strings
+// directly and the other keys by fmt. @onEvict can be nil.
+func New[K comparable, V any](capacity int, hasher func(K) uint64, onEvict func(K, V)) *Cache[K, V] {
+	if capacity <= 0 {
+		panic("@capacity <= 0")
+	}
+	if hasher == nil {
+		hasher = hashKey[K]
+	}
+
+	windowCap := capacity / 100
+	if windowCap < 1 {
+		windowCap = 1
+	}
+	mainCap := capacity - windowCap
+
+	return &Cache[K, V]{
+		hasher:       hasher,
+		onEvict:      onEvict,
+		sketch:       newCountMinSketch(capacity),
+		entries:      make(map[K]*list.Element),
+		window:       list.New(),
+		probation:    list.New(),
+		protected:    list.New(),
+		windowCap:    windowCap,
+		mainCap:      mainCap,
+		protectedCap: mainCap * 8 / 10,
+	}
+}
+
+func (c *Cache[K, V]) segment(s int) *list.List {
+	switch s {
+	case segmentWindow:
+		return c.window
+	case segmentProbation:
+		return c.probation
+	}
+
+	return c.protected
+}
+
+// Get returns the value of @key.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		c.sketch.increment(c.hasher(key))
+		var zero V
+		return zero, false
+	}
+	ent := e.Value.(*entry[K, V])
+	c.sketch.increment(ent.hash)
+	c.touch(e, ent)
+
+	return ent.value, true
+}
+
+// touch should be invoked with the lock held.
+func (c *Cache[K, V]) touch(e *list.Element, ent *entry[K, V]) {
+	switch ent.segment {
+	case segmentWindow:
+		c.window.MoveToFront(e)
+	case segmentProtected:
+		c.protected.MoveToFront(e)
+	case segmentProbation:
+		c.probation.Remove(e)
+		ent.segment = segmentProtected
+		c.entries[ent.key] = c.protected.PushFront(ent)
+		if c.protected.Len() > c.protectedCap {
+			demoted := c.protected.Remove(c.protected.Back()).(*entry[K, V])
+			demoted.segment = segmentProbation
+			c.entries[demoted.key] = c.probation.PushFront(demoted)
+		}
+	}
+}
+
+// Set puts @key with @value.
+func (c *Cache[K, V]) Set(key K, value V) {
+	var evicted *entry[K, V]
+
+	c.lock.Lock()
+	if e, ok := c.entries[key]; ok {
+		ent := e.Value.(*entry[K, V])
+		ent.value = value
+		c.sketch.increment(ent.hash)
+		c.touch(e, ent)
+		c.lock.Unlock()
+		return
+	}
+
+	ent := &entry[K, V]{key: key, value: value, hash: c.hasher(key), segment: segmentWindow}
+	c.sketch.increment(ent.hash)
+	c.entries[key] = c.window.PushFront(ent)
+	if c.window.Len() > c.windowCap {
+		candidate := c.window.Remove(c.window.Back()).(*entry[K, V])
+		evicted = c.admit(candidate)
+	}
+	c.lock.Unlock()
+
+	if evicted != nil && c.onEvict != nil {
+		c.onEvict(evicted.key, evicted.value)
+	}
+}
+
+// admit moves @candidate from the window into the main LRU, and returns the entry
+// evicted for it, which is either the victim of the main LRU or @candidate itself.
+// It should be invoked with the lock held.
+func (c *Cache[K, V]) admit(candidate *entry[K, V]) *entry[K, V] {
+	candidate.segment = segmentProbation
+	if c.probation.Len()+c.protected.Len() < c.mainCap {
+		c.entries[candidate.key] = c.probation.PushFront(candidate)
+		return nil
+	}
+
+	// a cache of capacity 1 has no main LRU, so the window holds its only entry
+	if c.mainCap == 0 {
+		delete(c.entries, candidate.key)
+		return candidate
+	}
+
+	victims := c.probation
+	if victims.Len() == 0 {
+		victims = c.protected
+	}
+	victim := victims.Back().Value.(*entry[K, V])
+	if c.sketch.estimate(candidate.hash) <= c.sketch.estimate(victim.hash) {
+		delete(c.entries, candidate.key)
+		return candidate
+	}
+
+	victims.Remove(victims.Back())
+	delete(c.entries, victim.key)
+	c.entries[candidate.key] = c.probation.PushFront(candidate)
+
+	return victim
+}
+
+// Remove removes @key without invoking the eviction callback. It returns false if
+// @key is absent.
+func (c *Cache[K, V]) Remove(key K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.entries[key]
+	if ok {
+		c.segment(e.Value.(*entry[K, V]).segment).Remove(e)
+		delete(c.entries, key)
+	}
+
+	return ok
+}
+
+// Len returns the number of entries.
+func (c *Cache[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.entries)
+}
+
+func hashKey[K comparable](key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return hashString(k)
+	case int:
+		return mix(uint64(k))
+	case int32:
+		return mix(uint64(k))
+	case int64:
+		return mix(uint64(k))
+	case uint:
+		return mix(uint64(k))
+	case uint32:
+		return mix(uint64(k))
+	case uint64:
+		return mix(k)
+	case float64:
+		return mix(math.Float64bits(k))
+	}
+
+	return hashString(fmt.Sprintf("%#v", key))
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+
+	return h.Sum64()
+}
+
+// mix is the finalizer of splitmix64, which spreads the bits of sequential integers.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxlfu
+
+import (
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	evicted := 0
+	c := New[string, int](10, nil, func(string, int) { evicted++ })
+
+	c.Set("a", 1)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	c.Set("a", 2)
+	v, _ = c.Get("a")
+	assert.Equal(t, 2, v)
+
+	for i := 0; i < 100; i++ {
+		c.Set(strconv.Itoa(i), i)
+	}
+	assert.Equal(t, 10, c.Len())
+	assert.Equal(t, 91, evicted)
+
+	c.Set("b", 3)
+	assert.True(t, c.Remove("b"))
+	assert.False(t, c.Remove("b"))
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+}
+
+func TestCacheScanResistance(t *testing.T) {
+	c := New[int, int](100, nil, nil)
+	for round := 0; round < 10; round++ {
+		for k := 0; k < 50; k++ {
+			if _, ok := c.Get(k); !ok {
+				c.Set(k, k)
+			}
+		}
+	}
+
+	// a scan of one-off keys
+	for k := 1000; k < 11000; k++ {
+		c.Set(k, k)
+	}
+	assert.Equal(t, 100, c.Len())
+
+	hits := 0
+	for k := 0; k < 50; k++ {
+		if _, ok := c.Get(k); ok {
+			hits++
+		}
+	}
+	assert.True(t, hits >= 45, "hot keys left %d", hits)
+}
+
+func TestCacheCapacityOne(t *testing.T) {
+	var evicted []string
+	c := New[string, int](1, nil, func(k string, _ int) { evicted = append(evicted, k) })
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	assert.Equal(t, 1, c.Len())
+	assert.Equal(t, []string{"a", "b"}, evicted)
+	v, ok := c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+}
+
+func TestCountMinSketch(t *testing.T) {
+	s := newCountMinSketch(64)
+	h := hashKey("hot")
+	for i := 0; i < 20; i++ {
+		s.increment(h)
+	}
+	assert.Equal(t, uint8(sketchMaxCounter), s.estimate(h))
+	assert.Equal(t, uint8(0), s.estimate(hashKey("cold")))
+
+	s.reset()
+	assert.Equal(t, uint8(sketchMaxCounter/2), s.estimate(h))
+
+	type key struct{ a, b int }
+	assert.Equal(t, hashKey(key{1, 2}), hashKey(key{1, 2}))
+	assert.NotEqual(t, hashKey(1), hashKey(2))
+}
@@ -0,0 +1,419 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxlru implements a goroutine safe LRU cache with per entry TTL.
+package gxlru
+
+import (
+	"container/list"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+// ErrLoadPanicked is returned by GetOrLoad to the calls joining a load which panicked.
+var ErrLoadPanicked = errors.New("gxlru: load panicked")
+
+// EvictReason tells why an entry is evicted.
+type EvictReason int
+
+const (
+	// EvictCapacity means the entry is the least recently used one when the cache is full.
+	EvictCapacity EvictReason = iota
+	// EvictExpired means the TTL of the entry has passed.
+	EvictExpired
+	// EvictCost means the entry is among the least recently used ones when the total
+	// cost of a weighted cache exceeds its high watermark.
+	EvictCost
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictCost:
+		return "cost"
+	}
+
+	return "unknown"
+}
+
+// EvictFunc is invoked for every evicted entry out of the cache lock.
+type EvictFunc[K comparable, V any] func(key K, value V, reason EvictReason)
+
+type entry[K comparable, V any] struct {
+	key      K
+	value    V
+	deadline time.Time     // zero means no expiry
+	delta    time.Duration // time of the load which produced the value, see SetEarlyRefresh
+	cost     int64
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.deadline.IsZero() && !now.Before(e.deadline)
+}
+
+// early decides whether to refresh the entry before its deadline by XFetch, whose
+// probability grows as @now approaches the deadline, and with the load time and @beta.
+func (e *entry[K, V]) early(now time.Time, beta float64) bool {
+	if beta <= 0 || e.delta <= 0 || e.deadline.IsZero() {
+		return false
+	}
+	gap := time.Duration(float64(e.delta) * beta * rand.ExpFloat64())
+
+	return !now.Add(gap).Before(e.deadline)
+}
+
+type loadCall[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// Cache is a LRU cache of at most maxEntries entries, whose entries can expire.
+// The expiry is judged by gxtime.Now. Besides being evicted on access, the expired
+// entries are swept by a Set once the default ttl has passed since the last sweep,
+// so a Cache holds no timer and needs no stop.
+type Cache[K comparable, V any] struct {
+	lock       sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	onEvict    EvictFunc[K, V]
+	queue      *list.List // the most recently used entry is at the front
+	entries    map[K]*list.Element
+	calls      map[K]*loadCall[V]
+	lastSweep  time.Time
+	beta       float64 // XFetch factor of the early refresh, 0 means off
+
+	// the cost limit of a weighted cache, see NewWeighted
+	cost      func(key K, value V) int64
+	highCost  int64
+	lowCost   int64
+	totalCost int64
+}
+
+type evicted[K comparable, V any] struct {
+	entry  *entry[K, V]
+	reason EvictReason
+}
+
+// New returns a cache of at most @maxEntries entries, a non-positive one means no limit.
+// @ttl is the default TTL of the entries, a non-positive one means no expiry. @onEvict
+// can be nil.
+func New[K comparable, V any](maxEntries int, ttl time.Duration, onEvict EvictFunc[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		onEvict:    onEvict,
+		queue:      list.New(),
+		entries:    make(map[K]*list.Element),
+		calls:      make(map[K]*loadCall[V]),
+		lastSweep:  gxtime.Now(),
+	}
+
+	return c
+}
+
+// NewWeighted returns a cache which is limited by the total cost of its entries, such
+// as their bytes, instead of the number of them. @cost returns the cost of an entry.
+// Once the total cost exceeds @high, the least recently used entries are evicted
+// until it is at most @low, so that a burst of sets does not evict on every set. An
+// entry costing more than @high alone is evicted at once without evicting the others,
+// and drops the old value of its key. @ttl and @onEvict are the same as those of New.
+func NewWeighted[K comparable, V any](high, low int64, cost func(key K, value V) int64, ttl time.Duration, onEvict EvictFunc[K, V]) *Cache[K, V] {
+	if high <= 0 {
+		panic("@high <= 0")
+	}
+	if low < 0 || low > high {
+		panic("@low out of [0, @high]")
+	}
+	if cost == nil {
+		panic("@cost is nil")
+	}
+
+	c := New[K, V](0, ttl, onEvict)
+	c.cost, c.highCost, c.lowCost = cost, high, low
+
+	return c
+}
+
+func (c *Cache[K, V]) notify(evicts []evicted[K, V]) {
+	if c.onEvict == nil {
+		return
+	}
+	for _, e := range evicts {
+		c.onEvict(e.entry.key, e.entry.value, e.reason)
+	}
+}
+
+// removeElement should be invoked with the lock held.
+func (c *Cache[K, V]) removeElement(e *list.Element) *entry[K, V] {
+	ent := c.queue.Remove(e).(*entry[K, V])
+	delete(c.entries, ent.key)
+	c.totalCost -= ent.cost
+
+	return ent
+}
+
+// SetEarlyRefresh turns on the probabilistic early refresh of GetOrLoad, so that the
+// hot keys are reloaded by one call before they expire instead of by all the calls
+// missing at once at the deadline. The earlier the refresh tends to happen, the longer
+// the last load of a key took and the larger @beta is, where 1 is the common choice.
+// A non-positive @beta turns it off.
+func (c *Cache[K, V]) SetEarlyRefresh(beta float64) {
+	c.lock.Lock()
+	c.beta = beta
+	c.lock.Unlock()
+}
+
+// Set puts @key with @value of the default TTL.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL puts @key with @value which expires after @ttl. A non-positive @ttl means no expiry.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.set(key, value, ttl, 0)
+}
+
+// set puts @key with @value loaded in @delta.
+func (c *Cache[K, V]) set(key K, value V, ttl, delta time.Duration) {
+	var (
+		deadline time.Time
+		now      = gxtime.Now()
+	)
+	if ttl > 0 {
+		deadline = now.Add(ttl)
+	}
+
+	var cost int64
+	if c.cost != nil {
+		cost = c.cost(key, value)
+	}
+
+	var evicts []evicted[K, V]
+	c.lock.Lock()
+	if c.cost != nil && cost > c.highCost {
+		// it never fits, so it is evicted alone, and replaces an old value of @key
+		if e, ok := c.entries[key]; ok {
+			c.removeElement(e)
+		}
+		c.lock.Unlock()
+		c.notify([]evicted[K, V]{{&entry[K, V]{key: key, value: value, cost: cost}, EvictCost}})
+		return
+	}
+	if e, ok := c.entries[key]; ok {
+		ent := e.Value.(*entry[K, V])
+		c.totalCost += cost - ent.cost
+		ent.value, ent.deadline, ent.delta, ent.cost = value, deadline, delta, cost
+		c.queue.MoveToFront(e)
+	} else {
+		c.entries[key] = c.queue.PushFront(&entry[K, V]{key: key, value: value, deadline: deadline, delta: delta, cost: cost})
+		c.totalCost += cost
+		for c.maxEntries > 0 && c.queue.Len() > c.maxEntries {
+			evicts = append(evicts, evicted[K, V]{c.removeElement(c.queue.Back()), EvictCapacity})
+		}
+	}
+	if c.cost != nil && c.totalCost > c.highCost {
+		for c.queue.Len() > 0 && c.totalCost > c.lowCost {
+			evicts = append(evicts, evicted[K, V]{c.removeElement(c.queue.Back()), EvictCost})
+		}
+	}
+	if c.ttl > 0 && now.Sub(c.lastSweep) >= c.ttl {
+		evicts = c.sweep(now, evicts)
+	}
+	c.lock.Unlock()
+
+	c.notify(evicts)
+}
+
+// resize limits the cache to @maxEntries entries, evicting the least recently used
+// ones beyond it.
+func (c *Cache[K, V]) resize(maxEntries int) {
+	var evicts []evicted[K, V]
+	c.lock.Lock()
+	c.maxEntries = maxEntries
+	for maxEntries > 0 && c.queue.Len() > maxEntries {
+		evicts = append(evicts, evicted[K, V]{c.removeElement(c.queue.Back()), EvictCapacity})
+	}
+	c.lock.Unlock()
+
+	c.notify(evicts)
+}
+
+// Get returns the value of @key and marks it as the most recently used one.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	value, _, ok := c.get(key, true)
+	return value, ok
+}
+
+// Peek returns the value of @key without updating its recency.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	value, _, ok := c.get(key, false)
+	return value, ok
+}
+
+// get returns the value of @key, and whether it should be refreshed early.
+func (c *Cache[K, V]) get(key K, touch bool) (value V, early, ok bool) {
+	c.lock.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		c.lock.Unlock()
+		return value, false, false
+	}
+	ent := e.Value.(*entry[K, V])
+	now := gxtime.Now()
+	if ent.expired(now) {
+		c.removeElement(e)
+		c.lock.Unlock()
+		c.notify([]evicted[K, V]{{ent, EvictExpired}})
+		return value, false, false
+	}
+	if touch {
+		c.queue.MoveToFront(e)
+	}
+	value, early = ent.value, ent.early(now, c.beta)
+	c.lock.Unlock()
+
+	return value, early, true
+}
+
+// GetOrLoad returns the value of @key, or loads it by @load and puts it with the
+// default TTL on a miss. Concurrent misses of the same key share a single @load.
+// The error of @load is returned to all of them and is not cached. If @load panics,
+// the panic goes on unrecovered in the loading call, and the joined ones get ErrLoadPanicked.
+// With SetEarlyRefresh, a hit may reload the key before it expires, while the other
+// hits keep getting the cached value, which is also returned if the early load fails.
+func (c *Cache[K, V]) GetOrLoad(key K, load func(key K) (V, error)) (V, error) {
+	cached, early, hit := c.get(key, true)
+	if hit && !early {
+		return cached, nil
+	}
+
+	c.lock.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.lock.Unlock()
+		if hit {
+			return cached, nil
+		}
+		call.wg.Wait()
+		return call.value, call.err
+	}
+	call := &loadCall[V]{}
+	call.wg.Add(1)
+	c.calls[key] = call
+	c.lock.Unlock()
+
+	// the panic of @load is not recovered to keep its stack, and is told by @returned
+	returned := false
+	defer func() {
+		if !returned {
+			var zero V
+			call.value, call.err = zero, ErrLoadPanicked
+		}
+		c.lock.Lock()
+		delete(c.calls, key)
+		c.lock.Unlock()
+		call.wg.Done()
+	}()
+
+	start := gxtime.Now()
+	call.value, call.err = load(key)
+	returned = true
+	if call.err == nil {
+		c.set(key, call.value, c.ttl, gxtime.Now().Sub(start))
+	} else if hit {
+		return cached, nil
+	}
+
+	return call.value, call.err
+}
+
+// Remove removes @key without invoking the eviction callback. It returns false if
+// @key is absent.
+func (c *Cache[K, V]) Remove(key K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.entries[key]
+	if ok {
+		c.removeElement(e)
+	}
+
+	return ok
+}
+
+// Len returns the number of entries, including the expired ones not swept yet.
+func (c *Cache[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.queue.Len()
+}
+
+// Cost returns the total cost of the entries of a weighted cache, which is 0 for the
+// other caches.
+func (c *Cache[K, V]) Cost() int64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.totalCost
+}
+
+// Keys returns the keys from the most recently used to the least recently used one.
+func (c *Cache[K, V]) Keys() []K {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keys := make([]K, 0, c.queue.Len())
+	for e := c.queue.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*entry[K, V]).key)
+	}
+
+	return keys
+}
+
+// Purge removes all entries without invoking the eviction callback.
+func (c *Cache[K, V]) Purge() {
+	c.lock.Lock()
+	c.queue.Init()
+	c.entries = make(map[K]*list.Element)
+	c.totalCost = 0
+	c.lock.Unlock()
+}
+
+// sweep appends the expired entries to @evicts after removing them. It should be
+// invoked with the lock held.
+func (c *Cache[K, V]) sweep(now time.Time, evicts []evicted[K, V]) []evicted[K, V] {
+	c.lastSweep = now
+	for e := c.queue.Front(); e != nil; {
+		next := e.Next()
+		if ent := e.Value.(*entry[K, V]); ent.expired(now) {
+			evicts = append(evicts, evicted[K, V]{c.removeElement(e), EvictExpired})
+		}
+		e = next
+	}
+
+	return evicts
+}
@@ -0,0 +1,250 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxlru
+
+import (
+	"errors"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+func TestCache(t *testing.T) {
+	var evicts []string
+	c := New[string, int](2, 0, func(key string, _ int, reason EvictReason) {
+		evicts = append(evicts, key+":"+reason.String())
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	c.Set("c", 3)
+	assert.Equal(t, []string{"b:capacity"}, evicts)
+	assert.Equal(t, []string{"c", "a"}, c.Keys())
+
+	_, ok = c.Peek("a")
+	assert.True(t, ok)
+	c.Set("d", 4)
+	assert.Equal(t, []string{"b:capacity", "a:capacity"}, evicts)
+
+	assert.True(t, c.Remove("d"))
+	assert.False(t, c.Remove("d"))
+	assert.Equal(t, 1, c.Len())
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+	assert.Len(t, evicts, 2)
+}
+
+func TestCacheTTL(t *testing.T) {
+	evicted := make(chan string, 4)
+	c := New[string, int](0, 50*time.Millisecond, func(key string, _ int, reason EvictReason) {
+		assert.Equal(t, EvictExpired, reason)
+		evicted <- key
+	})
+
+	c.Set("swept", 1)
+	c.SetWithTTL("lazy", 2, 10*time.Millisecond)
+	c.SetWithTTL("forever", 3, 0)
+	time.Sleep(20 * time.Millisecond)
+	_, ok := c.Get("lazy")
+	assert.False(t, ok)
+	assert.Equal(t, "lazy", <-evicted)
+
+	// not swept until a Set once the default ttl has passed
+	assert.Equal(t, []string{"forever", "swept"}, c.Keys())
+	time.Sleep(40 * time.Millisecond)
+	c.SetWithTTL("set", 4, 0)
+	assert.Equal(t, "swept", <-evicted)
+	assert.Equal(t, []string{"set", "forever"}, c.Keys())
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	c := New[int, int](0, 0, nil)
+	var loads int64
+	load := func(key int) (int, error) {
+		atomic.AddInt64(&loads, 1)
+		time.Sleep(20 * time.Millisecond)
+		return key * 10, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrLoad(1, load)
+			assert.Nil(t, err)
+			assert.Equal(t, 10, v)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(1), atomic.LoadInt64(&loads))
+
+	errLoad := errors.New("load")
+	_, err := c.GetOrLoad(2, func(int) (int, error) { return 0, errLoad })
+	assert.Equal(t, errLoad, err)
+	_, ok := c.Peek(2)
+	assert.False(t, ok)
+}
+
+func TestCacheGetOrLoadPanic(t *testing.T) {
+	c := New[int, int](10, 0, nil)
+
+	var (
+		loading = make(chan struct{})
+		joined  = make(chan error)
+	)
+	go func() {
+		<-loading
+		_, err := c.GetOrLoad(1, func(int) (int, error) { return 1, nil })
+		joined <- err
+	}()
+	var stack string
+	func() {
+		defer func() {
+			assert.Equal(t, "boom", recover())
+			stack = string(debug.Stack())
+		}()
+		c.GetOrLoad(1, func(int) (int, error) {
+			close(loading)
+			// lets the other call join the load
+			time.Sleep(20 * time.Millisecond)
+			panic("boom")
+		})
+	}()
+	assert.Equal(t, ErrLoadPanicked, <-joined)
+	// the panic goes on with the stack of the load instead of being raised again
+	assert.Equal(t, 1, strings.Count(stack, "\npanic("), stack)
+	assert.Contains(t, stack, "TestCacheGetOrLoadPanic.func2.2")
+
+	// the failed load is not cached, and the next call loads again
+	v, err := c.GetOrLoad(1, func(int) (int, error) { return 2, nil })
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestCacheGetOrLoadEarlyRefresh(t *testing.T) {
+	now := time.Unix(1000, 0).UnixNano()
+	advance := func(d time.Duration) { atomic.AddInt64(&now, int64(d)) }
+	gxtime.SetTimeSource(func() time.Time { return time.Unix(0, atomic.LoadInt64(&now)) })
+	defer gxtime.SetTimeSource(nil)
+
+	c := New[int, int](0, time.Minute, nil)
+	var loads int
+	load := func(int) (int, error) {
+		// every load takes 1s
+		advance(time.Second)
+		loads++
+		return loads, nil
+	}
+
+	v, err := c.GetOrLoad(1, load)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v)
+
+	// off by default
+	advance(time.Minute - time.Millisecond)
+	v, _ = c.GetOrLoad(1, load)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 1, loads)
+
+	// a refresh is almost sure to happen once the deadline is far closer than the load time
+	c.SetEarlyRefresh(1)
+	for i := 0; i < 100; i++ {
+		v, err = c.GetOrLoad(1, load)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 2, loads)
+
+	// and is almost sure not to happen long before the deadline
+	for i := 0; i < 100; i++ {
+		v, _ = c.GetOrLoad(1, load)
+	}
+	assert.Equal(t, 2, loads)
+
+	// the cached value is kept if the early load fails
+	advance(time.Minute - time.Millisecond)
+	errLoad := errors.New("load")
+	v, err = c.GetOrLoad(1, func(int) (int, error) {
+		advance(time.Nanosecond)
+		return 0, errLoad
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestWeightedCache(t *testing.T) {
+	var evicts []string
+	c := NewWeighted[string, []byte](100, 60, func(_ string, v []byte) int64 { return int64(len(v)) }, 0,
+		func(key string, _ []byte, reason EvictReason) {
+			evicts = append(evicts, key+":"+reason.String())
+		})
+
+	c.Set("a", make([]byte, 30))
+	c.Set("b", make([]byte, 30))
+	c.Set("c", make([]byte, 30))
+	assert.Equal(t, int64(90), c.Cost())
+	c.Get("a")
+
+	// over the high watermark, evicted down to the low one
+	c.Set("d", make([]byte, 20))
+	assert.Equal(t, []string{"b:cost", "c:cost"}, evicts)
+	assert.Equal(t, int64(50), c.Cost())
+	assert.Equal(t, []string{"d", "a"}, c.Keys())
+
+	// an update changes the cost
+	c.Set("a", make([]byte, 5))
+	assert.Equal(t, int64(25), c.Cost())
+	assert.True(t, c.Remove("d"))
+	assert.Equal(t, int64(5), c.Cost())
+
+	// an entry over the high watermark alone does not stay, and leaves the others alone
+	c.Set("b", make([]byte, 30))
+	c.Set("c", make([]byte, 30))
+	c.Set("huge", make([]byte, 101))
+	assert.Equal(t, []string{"b:cost", "c:cost", "huge:cost"}, evicts)
+	assert.Equal(t, int64(65), c.Cost())
+	assert.Equal(t, []string{"c", "b", "a"}, c.Keys())
+
+	// and replaces the old value of its key
+	c.Set("b", make([]byte, 101))
+	assert.Equal(t, []string{"b:cost", "c:cost", "huge:cost", "b:cost"}, evicts)
+	assert.Equal(t, int64(35), c.Cost())
+	assert.Equal(t, []string{"c", "a"}, c.Keys())
+
+	c.Set("e", make([]byte, 10))
+	c.Purge()
+	assert.Equal(t, int64(0), c.Cost())
+
+	assert.Panics(t, func() { NewWeighted[string, int](10, 20, func(string, int) int64 { return 1 }, 0, nil) })
+	assert.Panics(t, func() { NewWeighted[string, int](10, 5, nil, 0, nil) })
+}
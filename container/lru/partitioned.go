@@ -0,0 +1,224 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxlru
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PartitionStats is a snapshot of the counters of a tenant of a Partitioned cache.
+type PartitionStats struct {
+	Len       int
+	Quota     int
+	Hits      uint64
+	Misses    uint64 // the misses of Get, and the loads of GetOrLoad
+	Evictions uint64
+}
+
+type partition[K comparable, V any] struct {
+	hits      uint64 // first for the 64-bit alignment on the 32-bit platforms
+	misses    uint64
+	evictions uint64
+	cache     *Cache[K, V]
+	quota     int // protected by the lock of Partitioned
+}
+
+// Partitioned is a cache shared by tenants, in which every tenant has its own LRU
+// partition bounded by its quota, so that a noisy tenant only evicts its own entries.
+// The partition of a tenant is created by its first Set or by SetQuota only, so that
+// the lookups of unknown tenants, e.g. from the requests, hold no memory.
+// It is goroutine safe.
+type Partitioned[T comparable, K comparable, V any] struct {
+	lock         sync.RWMutex
+	defaultQuota int
+	quotas       map[T]int
+	ttl          time.Duration
+	onEvict      func(tenant T, key K, value V, reason EvictReason)
+	parts        map[T]*partition[K, V]
+}
+
+// NewPartitioned returns a partitioned cache whose tenants have at most @defaultQuota
+// entries unless SetQuota says otherwise, a non-positive one means no limit. @ttl and
+// @onEvict are the same as those of New, except that @onEvict also gets the tenant.
+func NewPartitioned[T comparable, K comparable, V any](defaultQuota int, ttl time.Duration,
+	onEvict func(tenant T, key K, value V, reason EvictReason)) *Partitioned[T, K, V] {
+	return &Partitioned[T, K, V]{
+		defaultQuota: defaultQuota,
+		quotas:       make(map[T]int),
+		ttl:          ttl,
+		onEvict:      onEvict,
+		parts:        make(map[T]*partition[K, V]),
+	}
+}
+
+// SetQuota registers @tenant limited to @quota entries, or evicts the least recently
+// used entries of a registered one beyond @quota at once. A non-positive @quota means
+// no limit.
+func (p *Partitioned[T, K, V]) SetQuota(tenant T, quota int) {
+	p.lock.Lock()
+	p.quotas[tenant] = quota
+	part, ok := p.parts[tenant]
+	if ok {
+		part.quota = quota
+	}
+	p.lock.Unlock()
+
+	if ok {
+		part.cache.resize(quota)
+	} else {
+		p.partition(tenant)
+	}
+}
+
+// lookup returns the partition of @tenant if it exists.
+func (p *Partitioned[T, K, V]) lookup(tenant T) (*partition[K, V], bool) {
+	p.lock.RLock()
+	part, ok := p.parts[tenant]
+	p.lock.RUnlock()
+
+	return part, ok
+}
+
+// partition returns the partition of @tenant, which is created if it does not exist.
+func (p *Partitioned[T, K, V]) partition(tenant T) *partition[K, V] {
+	if part, ok := p.lookup(tenant); ok {
+		return part
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	part, ok := p.parts[tenant]
+	if ok {
+		return part
+	}
+
+	quota, ok := p.quotas[tenant]
+	if !ok {
+		quota = p.defaultQuota
+	}
+	part = &partition[K, V]{quota: quota}
+	part.cache = New[K, V](quota, p.ttl, func(key K, value V, reason EvictReason) {
+		atomic.AddUint64(&part.evictions, 1)
+		if p.onEvict != nil {
+			p.onEvict(tenant, key, value, reason)
+		}
+	})
+	p.parts[tenant] = part
+
+	return part
+}
+
+// Set puts @key with @value of the default TTL into the partition of @tenant.
+func (p *Partitioned[T, K, V]) Set(tenant T, key K, value V) {
+	p.partition(tenant).cache.Set(key, value)
+}
+
+// SetWithTTL puts @key with @value which expires after @ttl into the partition of @tenant.
+func (p *Partitioned[T, K, V]) SetWithTTL(tenant T, key K, value V, ttl time.Duration) {
+	p.partition(tenant).cache.SetWithTTL(key, value, ttl)
+}
+
+// Get returns the value of @key of @tenant.
+func (p *Partitioned[T, K, V]) Get(tenant T, key K) (V, bool) {
+	part, ok := p.lookup(tenant)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	value, ok := part.cache.Get(key)
+	if ok {
+		atomic.AddUint64(&part.hits, 1)
+	} else {
+		atomic.AddUint64(&part.misses, 1)
+	}
+
+	return value, ok
+}
+
+// GetOrLoad is the same as Cache.GetOrLoad in the partition of @tenant. The value of
+// an unknown tenant is loaded but not cached.
+func (p *Partitioned[T, K, V]) GetOrLoad(tenant T, key K, load func(key K) (V, error)) (V, error) {
+	part, ok := p.lookup(tenant)
+	if !ok {
+		return load(key)
+	}
+	loaded := false
+	value, err := part.cache.GetOrLoad(key, func(key K) (V, error) {
+		loaded = true
+		return load(key)
+	})
+	if loaded {
+		atomic.AddUint64(&part.misses, 1)
+	} else {
+		atomic.AddUint64(&part.hits, 1)
+	}
+
+	return value, err
+}
+
+// Remove removes @key of @tenant without invoking the eviction callback.
+func (p *Partitioned[T, K, V]) Remove(tenant T, key K) bool {
+	part, ok := p.lookup(tenant)
+
+	return ok && part.cache.Remove(key)
+}
+
+// RemoveTenant drops the partition of @tenant with its entries and stats, without
+// invoking the eviction callback. Its quota is kept.
+func (p *Partitioned[T, K, V]) RemoveTenant(tenant T) {
+	p.lock.Lock()
+	delete(p.parts, tenant)
+	p.lock.Unlock()
+}
+
+// Tenants returns the tenants owning a partition.
+func (p *Partitioned[T, K, V]) Tenants() []T {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	tenants := make([]T, 0, len(p.parts))
+	for tenant := range p.parts {
+		tenants = append(tenants, tenant)
+	}
+
+	return tenants
+}
+
+// Stats returns the stats of @tenant, or false if it owns no partition.
+func (p *Partitioned[T, K, V]) Stats(tenant T) (PartitionStats, bool) {
+	p.lock.RLock()
+	part, ok := p.parts[tenant]
+	var quota int
+	if ok {
+		quota = part.quota
+	}
+	p.lock.RUnlock()
+	if !ok {
+		return PartitionStats{}, false
+	}
+
+	return PartitionStats{
+		Len:       part.cache.Len(),
+		Quota:     quota,
+		Hits:      atomic.LoadUint64(&part.hits),
+		Misses:    atomic.LoadUint64(&part.misses),
+		Evictions: atomic.LoadUint64(&part.evictions),
+	}, true
+}
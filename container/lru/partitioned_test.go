@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxlru
+
+import (
+	"sort"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitioned(t *testing.T) {
+	var evicts []string
+	p := NewPartitioned[string, int, int](2, 0, func(tenant string, key int, _ int, reason EvictReason) {
+		evicts = append(evicts, tenant+":"+reason.String())
+	})
+	p.SetQuota("big", 10)
+
+	// the noisy tenant only evicts its own entries
+	p.Set("quiet", 1, 1)
+	for i := 0; i < 10; i++ {
+		p.Set("noisy", i, i)
+		p.Set("big", i, i)
+	}
+	v, ok := p.Get("quiet", 1)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	_, ok = p.Get("noisy", 0)
+	assert.False(t, ok)
+	assert.Len(t, evicts, 8)
+	for _, e := range evicts {
+		assert.Equal(t, "noisy:capacity", e)
+	}
+
+	s, ok := p.Stats("noisy")
+	assert.True(t, ok)
+	assert.Equal(t, PartitionStats{Len: 2, Quota: 2, Misses: 1, Evictions: 8}, s)
+	s, _ = p.Stats("quiet")
+	assert.Equal(t, PartitionStats{Len: 1, Quota: 2, Hits: 1}, s)
+
+	v, err := p.GetOrLoad("quiet", 2, func(key int) (int, error) { return key * 10, nil })
+	assert.Nil(t, err)
+	assert.Equal(t, 20, v)
+	v, _ = p.GetOrLoad("quiet", 2, func(key int) (int, error) { return 0, nil })
+	assert.Equal(t, 20, v)
+	s, _ = p.Stats("quiet")
+	assert.Equal(t, PartitionStats{Len: 2, Quota: 2, Hits: 2, Misses: 1}, s)
+
+	// shrinking the quota evicts at once
+	p.SetQuota("big", 3)
+	s, _ = p.Stats("big")
+	assert.Equal(t, PartitionStats{Len: 3, Quota: 3, Evictions: 7}, s)
+
+	assert.True(t, p.Remove("big", 9))
+	assert.False(t, p.Remove("none", 9))
+	tenants := p.Tenants()
+	sort.Strings(tenants)
+	assert.Equal(t, []string{"big", "noisy", "quiet"}, tenants)
+
+	// the lookups of unknown tenants create no partition
+	_, ok = p.Get("unknown", 1)
+	assert.False(t, ok)
+	v, err = p.GetOrLoad("unknown", 1, func(key int) (int, error) { return key, nil })
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v)
+	assert.False(t, p.Remove("unknown", 1))
+	_, ok = p.Stats("unknown")
+	assert.False(t, ok)
+	assert.Len(t, p.Tenants(), 3)
+
+	// SetQuota registers a tenant
+	p.SetQuota("new", 5)
+	s, ok = p.Stats("new")
+	assert.True(t, ok)
+	assert.Equal(t, PartitionStats{Quota: 5}, s)
+
+	p.RemoveTenant("big")
+	_, ok = p.Stats("big")
+	assert.False(t, ok)
+	p.Set("big", 1, 1)
+	s, _ = p.Stats("big")
+	assert.Equal(t, 3, s.Quota)
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxmap
+
+// Change is the values of a key before and after an update.
+type Change[V any] struct {
+	Old V
+	New V
+}
+
+// MapDiff is the change set from one map to another.
+type MapDiff[K comparable, V any] struct {
+	Added   map[K]V
+	Removed map[K]V
+	Updated map[K]Change[V]
+}
+
+// IsEmpty reports whether there is no change at all.
+func (d MapDiff[K, V]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Updated) == 0
+}
+
+// Diff compares @old against @new, and returns the keys only in @new, the ones only in
+// @old and the ones in both whose values are not @equal, e.g. to notify the change of
+// the metadata of service instances.
+func Diff[K comparable, V any](old, new map[K]V, equal func(a, b V) bool) MapDiff[K, V] {
+	d := MapDiff[K, V]{
+		Added:   make(map[K]V),
+		Removed: make(map[K]V),
+		Updated: make(map[K]Change[V]),
+	}
+	for k, ov := range old {
+		nv, ok := new[k]
+		if !ok {
+			d.Removed[k] = ov
+		} else if !equal(ov, nv) {
+			d.Updated[k] = Change[V]{Old: ov, New: nv}
+		}
+	}
+	for k, nv := range new {
+		if _, ok := old[k]; !ok {
+			d.Added[k] = nv
+		}
+	}
+
+	return d
+}
+
+// DiffComparable is Diff of the values compared by ==.
+func DiffComparable[K, V comparable](old, new map[K]V) MapDiff[K, V] {
+	return Diff(old, new, func(a, b V) bool { return a == b })
+}
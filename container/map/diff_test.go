@@ -0,0 +1,47 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxmap
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	d := DiffComparable(
+		map[string]int{"a": 1, "b": 2, "c": 3},
+		map[string]int{"b": 2, "c": 4, "d": 5},
+	)
+	assert.Equal(t, map[string]int{"d": 5}, d.Added)
+	assert.Equal(t, map[string]int{"a": 1}, d.Removed)
+	assert.Equal(t, map[string]Change[int]{"c": {Old: 3, New: 4}}, d.Updated)
+	assert.False(t, d.IsEmpty())
+
+	assert.True(t, DiffComparable(map[string]int{"a": 1}, map[string]int{"a": 1}).IsEmpty())
+
+	d2 := Diff(
+		map[string][]string{"a": {"x"}},
+		map[string][]string{"a": {"x"}, "b": nil},
+		func(a, b []string) bool { return len(a) == len(b) && (len(a) == 0 || a[0] == b[0]) },
+	)
+	assert.Empty(t, d2.Updated)
+	assert.Equal(t, map[string][]string{"b": nil}, d2.Added)
+}
@@ -0,0 +1,267 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxmap implements maps with extra policies on their entries.
+package gxmap
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	gxheap "github.com/dubbogo/gost/container/heap"
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+// EvictReason tells why an entry is evicted.
+type EvictReason int
+
+const (
+	// EvictExpired means the TTL of the entry has passed.
+	EvictExpired EvictReason = iota
+	// EvictCapacity means the entry is the one to expire soonest when the map is full.
+	EvictCapacity
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictExpired:
+		return "expired"
+	case EvictCapacity:
+		return "capacity"
+	}
+
+	return "unknown"
+}
+
+// EvictFunc is invoked for every evicted entry out of the map lock.
+type EvictFunc[K comparable, V any] func(key K, value V, reason EvictReason)
+
+type expiringEntry[V any] struct {
+	value V
+	ttl   time.Duration
+}
+
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// ExpiringMap is a map whose entries expire after their TTLs. The deadlines are kept
+// in a priority queue swept by a single timer of a shared wheel, so an entry costs no
+// goroutine or timer of its own, and an entry expired but not swept yet is already
+// invisible. The expiry is judged by gxtime.Now. It is goroutine safe.
+type ExpiringMap[K comparable, V any] struct {
+	ttl     time.Duration
+	maxSize int
+	onEvict EvictFunc[K, V]
+	timer   *gxtime.Timer
+
+	lock      sync.Mutex
+	entries   map[K]*expiringEntry[V]
+	deadlines *gxheap.IndexedPQ[K, time.Time]
+}
+
+// NewExpiringMap returns a map whose entries expire after @ttl by default, and which
+// keeps at most @maxSize entries, a non-positive one means no limit. The expired
+// entries are swept by @wheel every 1/16 of @ttl but no more often than its span, a nil
+// @wheel means the default wheel. @onEvict can be nil.
+func NewExpiringMap[K comparable, V any](wheel *gxtime.Wheel, ttl time.Duration, maxSize int, onEvict EvictFunc[K, V]) *ExpiringMap[K, V] {
+	if ttl <= 0 {
+		panic("@ttl <= 0")
+	}
+	if wheel == nil {
+		wheel = gxtime.GetDefaultWheel()
+	}
+
+	m := &ExpiringMap[K, V]{
+		ttl:       ttl,
+		maxSize:   maxSize,
+		onEvict:   onEvict,
+		entries:   make(map[K]*expiringEntry[V]),
+		deadlines: gxheap.NewIndexedPQ[K](func(a, b time.Time) bool { return a.Before(b) }),
+	}
+	interval := ttl / 16
+	if span := wheel.Span(); interval < span {
+		interval = span
+	}
+	m.timer = wheel.AddTimer(func(interface{}) { m.sweep() }, interval, nil)
+
+	return m
+}
+
+func (m *ExpiringMap[K, V]) notify(evictions []eviction[K, V]) {
+	if m.onEvict == nil {
+		return
+	}
+	for _, e := range evictions {
+		m.onEvict(e.key, e.value, e.reason)
+	}
+}
+
+// remove should be invoked with the lock held.
+func (m *ExpiringMap[K, V]) remove(key K) V {
+	value := m.entries[key].value
+	delete(m.entries, key)
+	m.deadlines.Remove(key)
+
+	return value
+}
+
+// Set puts @key with @value of the default TTL.
+func (m *ExpiringMap[K, V]) Set(key K, value V) {
+	m.SetWithTTL(key, value, m.ttl)
+}
+
+// SetWithTTL puts @key with @value which expires after @ttl, a non-positive @ttl means
+// the default one. Setting a key refreshes its TTL.
+func (m *ExpiringMap[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = m.ttl
+	}
+
+	var evictions []eviction[K, V]
+	m.lock.Lock()
+	if e, ok := m.entries[key]; ok {
+		e.value, e.ttl = value, ttl
+	} else {
+		for m.maxSize > 0 && len(m.entries) >= m.maxSize {
+			victim, _, _ := m.deadlines.Peek()
+			evictions = append(evictions, eviction[K, V]{victim, m.remove(victim), EvictCapacity})
+		}
+		m.entries[key] = &expiringEntry[V]{value: value, ttl: ttl}
+	}
+	m.deadlines.Push(key, gxtime.Now().Add(ttl))
+	m.lock.Unlock()
+
+	m.notify(evictions)
+}
+
+// load returns the entry of @key, evicting it if it has expired.
+func (m *ExpiringMap[K, V]) load(key K, refresh bool) (V, bool) {
+	var zero V
+
+	m.lock.Lock()
+	e, ok := m.entries[key]
+	if !ok {
+		m.lock.Unlock()
+		return zero, false
+	}
+	now := gxtime.Now()
+	if deadline, _ := m.deadlines.Priority(key); !now.Before(deadline) {
+		value := m.remove(key)
+		m.lock.Unlock()
+		m.notify([]eviction[K, V]{{key, value, EvictExpired}})
+		return zero, false
+	}
+	if refresh {
+		m.deadlines.Update(key, now.Add(e.ttl))
+	}
+	value := e.value
+	m.lock.Unlock()
+
+	return value, true
+}
+
+// Get returns the value of @key without refreshing its TTL.
+func (m *ExpiringMap[K, V]) Get(key K) (V, bool) {
+	return m.load(key, false)
+}
+
+// LoadAndRefresh returns the value of @key and restarts its TTL, e.g. for a session
+// kept alive by its accesses.
+func (m *ExpiringMap[K, V]) LoadAndRefresh(key K) (V, bool) {
+	return m.load(key, true)
+}
+
+// Deadline returns the time @key expires at.
+func (m *ExpiringMap[K, V]) Deadline(key K) (time.Time, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.deadlines.Priority(key)
+}
+
+// Delete removes @key without invoking the eviction callback, and returns its value.
+func (m *ExpiringMap[K, V]) Delete(key K) (V, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.entries[key]; !ok {
+		var zero V
+		return zero, false
+	}
+
+	return m.remove(key), true
+}
+
+// Len returns the number of entries, including the expired ones not swept yet.
+func (m *ExpiringMap[K, V]) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return len(m.entries)
+}
+
+// Range calls @f with a snapshot of the entries not expired until @f returns false.
+func (m *ExpiringMap[K, V]) Range(f func(key K, value V) bool) {
+	type kv struct {
+		key   K
+		value V
+	}
+
+	now := gxtime.Now()
+	m.lock.Lock()
+	snapshot := make([]kv, 0, len(m.entries))
+	for key, e := range m.entries {
+		if deadline, _ := m.deadlines.Priority(key); now.Before(deadline) {
+			snapshot = append(snapshot, kv{key, e.value})
+		}
+	}
+	m.lock.Unlock()
+
+	for _, e := range snapshot {
+		if !f(e.key, e.value) {
+			return
+		}
+	}
+}
+
+func (m *ExpiringMap[K, V]) sweep() {
+	var (
+		evictions []eviction[K, V]
+		now       = gxtime.Now()
+	)
+	m.lock.Lock()
+	for {
+		key, deadline, ok := m.deadlines.Peek()
+		if !ok || now.Before(deadline) {
+			break
+		}
+		evictions = append(evictions, eviction[K, V]{key, m.remove(key), EvictExpired})
+	}
+	m.lock.Unlock()
+
+	m.notify(evictions)
+}
+
+// Stop stops sweeping the expired entries. They are still evicted lazily on access.
+func (m *ExpiringMap[K, V]) Stop() {
+	m.timer.Stop()
+}
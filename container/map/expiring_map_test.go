@@ -0,0 +1,176 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxmap
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+type fakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	c.lock.Unlock()
+}
+
+func newFakeClock() *fakeClock {
+	c := &fakeClock{now: time.Unix(1000, 0)}
+	gxtime.SetTimeSource(c.Now)
+	return c
+}
+
+type evicted struct {
+	key    string
+	value  int
+	reason EvictReason
+}
+
+func TestExpiringMap(t *testing.T) {
+	clock := newFakeClock()
+	defer gxtime.SetTimeSource(nil)
+
+	m := NewExpiringMap[string, int](nil, time.Minute, 0, nil)
+	defer m.Stop()
+
+	m.Set("a", 1)
+	m.SetWithTTL("b", 2, 2*time.Minute)
+	v, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 2, m.Len())
+
+	deadline, ok := m.Deadline("b")
+	assert.True(t, ok)
+	assert.Equal(t, clock.Now().Add(2*time.Minute), deadline)
+
+	clock.Advance(time.Minute)
+	_, ok = m.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, m.Len())
+
+	var keys []string
+	m.Range(func(key string, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []string{"b"}, keys)
+
+	v, ok = m.Delete("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	_, ok = m.Delete("b")
+	assert.False(t, ok)
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestExpiringMapLoadAndRefresh(t *testing.T) {
+	clock := newFakeClock()
+	defer gxtime.SetTimeSource(nil)
+
+	m := NewExpiringMap[string, int](nil, time.Minute, 0, nil)
+	defer m.Stop()
+
+	m.Set("session", 1)
+	for i := 0; i < 3; i++ {
+		clock.Advance(50 * time.Second)
+		v, ok := m.LoadAndRefresh("session")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+	}
+
+	clock.Advance(50 * time.Second)
+	_, ok := m.Get("session")
+	assert.True(t, ok)
+	clock.Advance(10 * time.Second)
+	_, ok = m.LoadAndRefresh("session")
+	assert.False(t, ok)
+}
+
+func TestExpiringMapCapacity(t *testing.T) {
+	newFakeClock()
+	defer gxtime.SetTimeSource(nil)
+
+	var got []evicted
+	m := NewExpiringMap[string, int](nil, time.Minute, 2, func(key string, value int, reason EvictReason) {
+		got = append(got, evicted{key, value, reason})
+	})
+	defer m.Stop()
+
+	m.SetWithTTL("long", 1, time.Hour)
+	m.Set("short", 2)
+	m.Set("short", 3) // an update takes no room
+	assert.Empty(t, got)
+
+	m.Set("new", 4)
+	assert.Equal(t, []evicted{{"short", 3, EvictCapacity}}, got)
+	assert.Equal(t, 2, m.Len())
+	_, ok := m.Get("long")
+	assert.True(t, ok)
+	assert.Equal(t, "capacity", EvictCapacity.String())
+}
+
+func TestExpiringMapSweep(t *testing.T) {
+	clock := newFakeClock()
+	defer gxtime.SetTimeSource(nil)
+
+	wheel := gxtime.NewWheel(time.Millisecond, 100)
+	defer wheel.Stop()
+
+	var (
+		lock sync.Mutex
+		got  []evicted
+	)
+	m := NewExpiringMap[string, int](wheel, 16*time.Millisecond, 0, func(key string, value int, reason EvictReason) {
+		lock.Lock()
+		got = append(got, evicted{key, value, reason})
+		lock.Unlock()
+	})
+	defer m.Stop()
+
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.SetWithTTL("c", 3, time.Hour)
+	clock.Advance(time.Second)
+
+	assert.Eventually(t, func() bool { return m.Len() == 1 }, time.Second, time.Millisecond)
+	lock.Lock()
+	assert.ElementsMatch(t, []evicted{{"a", 1, EvictExpired}, {"b", 2, EvictExpired}}, got)
+	lock.Unlock()
+	_, ok := m.Get("c")
+	assert.True(t, ok)
+}
@@ -0,0 +1,340 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxmultiindex implements a map of records which can be looked up by several
+// keys, e.g. by service name, by interface and version and by registration ID, and
+// keeps all of its indexes consistent on every insertion and deletion.
+package gxmultiindex
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrDuplicateKey is returned on a conflict with a record on a unique index.
+var ErrDuplicateKey = errors.New("gxmultiindex: duplicate key")
+
+type indexer[R any] interface {
+	add(id uint64, r R)
+	remove(id uint64, r R)
+	conflict(r R) bool
+	name() string
+}
+
+// Map is a set of records indexed by the indexes created by NewIndex and NewUniqueIndex.
+// It is goroutine safe.
+type Map[R any] struct {
+	lock    sync.RWMutex
+	records map[uint64]R
+	nextID  uint64 // the records are kept in the insertion order by their ids
+	indexes []indexer[R]
+}
+
+// New returns an empty map without indexes.
+func New[R any]() *Map[R] {
+	return &Map[R]{records: make(map[uint64]R)}
+}
+
+// Len returns the number of records.
+func (m *Map[R]) Len() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return len(m.records)
+}
+
+// conflict should be invoked with the lock held.
+func (m *Map[R]) conflict(r R) error {
+	for _, index := range m.indexes {
+		if index.conflict(r) {
+			return fmt.Errorf("%w on index %s", ErrDuplicateKey, index.name())
+		}
+	}
+
+	return nil
+}
+
+// Insert adds @r to all the indexes. It returns ErrDuplicateKey and adds nothing if
+// a key of @r on a unique index is taken.
+func (m *Map[R]) Insert(r R) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if err := m.conflict(r); err != nil {
+		return err
+	}
+	m.nextID++
+	m.add(m.nextID, r)
+
+	return nil
+}
+
+// add should be invoked with the lock held.
+func (m *Map[R]) add(id uint64, r R) {
+	m.records[id] = r
+	for _, index := range m.indexes {
+		index.add(id, r)
+	}
+}
+
+// remove should be invoked with the lock held.
+func (m *Map[R]) remove(id uint64) R {
+	r := m.records[id]
+	delete(m.records, id)
+	for _, index := range m.indexes {
+		index.remove(id, r)
+	}
+
+	return r
+}
+
+// sortedIDs should be invoked with the lock held.
+func (m *Map[R]) sortedIDs() []uint64 {
+	ids := make([]uint64, 0, len(m.records))
+	for id := range m.records {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// Range calls @f with the records in the insertion order until @f returns false.
+// @f must not modify the map.
+func (m *Map[R]) Range(f func(r R) bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	for _, id := range m.sortedIDs() {
+		if !f(m.records[id]) {
+			return
+		}
+	}
+}
+
+// DeleteFunc deletes the records satisfying @match, and returns the number deleted.
+func (m *Map[R]) DeleteFunc(match func(r R) bool) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	n := 0
+	for id, r := range m.records {
+		if match(r) {
+			m.remove(id)
+			n++
+		}
+	}
+
+	return n
+}
+
+// Clear deletes all the records.
+func (m *Map[R]) Clear() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for id := range m.records {
+		m.remove(id)
+	}
+}
+
+// Index is an index of the records of a Map by the keys of type K.
+type Index[R any, K comparable] struct {
+	m       *Map[R]
+	label   string
+	key     func(R) K
+	unique  bool
+	entries map[K]map[uint64]struct{}
+}
+
+// NewIndex adds an index named @name of the records by @key to @m, which indexes the
+// records in @m at once. A key can be shared by many records.
+func NewIndex[R any, K comparable](m *Map[R], name string, key func(R) K) *Index[R, K] {
+	return newIndex(m, name, key, false)
+}
+
+// NewUniqueIndex is the same as NewIndex, except that a key can be taken by only one
+// record. It panics if the records in @m conflict.
+func NewUniqueIndex[R any, K comparable](m *Map[R], name string, key func(R) K) *Index[R, K] {
+	return newIndex(m, name, key, true)
+}
+
+func newIndex[R any, K comparable](m *Map[R], name string, key func(R) K, unique bool) *Index[R, K] {
+	index := &Index[R, K]{m: m, label: name, key: key, unique: unique, entries: make(map[K]map[uint64]struct{})}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for id, r := range m.records {
+		if index.conflict(r) {
+			panic(fmt.Sprintf("records conflict on unique index %s", name))
+		}
+		index.add(id, r)
+	}
+	m.indexes = append(m.indexes, index)
+
+	return index
+}
+
+func (x *Index[R, K]) name() string {
+	return x.label
+}
+
+func (x *Index[R, K]) add(id uint64, r R) {
+	k := x.key(r)
+	ids, ok := x.entries[k]
+	if !ok {
+		ids = make(map[uint64]struct{}, 1)
+		x.entries[k] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+func (x *Index[R, K]) remove(id uint64, r R) {
+	k := x.key(r)
+	if ids, ok := x.entries[k]; ok {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(x.entries, k)
+		}
+	}
+}
+
+func (x *Index[R, K]) conflict(r R) bool {
+	if !x.unique {
+		return false
+	}
+	_, ok := x.entries[x.key(r)]
+
+	return ok
+}
+
+// ids returns the ids of @key in the insertion order. It should be invoked with the lock held.
+func (x *Index[R, K]) ids(key K) []uint64 {
+	entries := x.entries[key]
+	ids := make([]uint64, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// Name returns the name of the index.
+func (x *Index[R, K]) Name() string {
+	return x.label
+}
+
+// Get returns the records of @key in the insertion order.
+func (x *Index[R, K]) Get(key K) []R {
+	x.m.lock.RLock()
+	defer x.m.lock.RUnlock()
+
+	ids := x.ids(key)
+	records := make([]R, 0, len(ids))
+	for _, id := range ids {
+		records = append(records, x.m.records[id])
+	}
+
+	return records
+}
+
+// First returns the earliest record of @key, which is the only one on a unique index.
+func (x *Index[R, K]) First(key K) (R, bool) {
+	x.m.lock.RLock()
+	defer x.m.lock.RUnlock()
+
+	if ids := x.ids(key); len(ids) > 0 {
+		return x.m.records[ids[0]], true
+	}
+
+	var zero R
+	return zero, false
+}
+
+// Has reports whether any record has @key.
+func (x *Index[R, K]) Has(key K) bool {
+	x.m.lock.RLock()
+	defer x.m.lock.RUnlock()
+
+	_, ok := x.entries[key]
+
+	return ok
+}
+
+// Count returns the number of the records of @key.
+func (x *Index[R, K]) Count(key K) int {
+	x.m.lock.RLock()
+	defer x.m.lock.RUnlock()
+
+	return len(x.entries[key])
+}
+
+// Keys returns the distinct keys of the index in no particular order.
+func (x *Index[R, K]) Keys() []K {
+	x.m.lock.RLock()
+	defer x.m.lock.RUnlock()
+
+	keys := make([]K, 0, len(x.entries))
+	for k := range x.entries {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Delete deletes the records of @key from the map and all of its indexes, and returns them.
+func (x *Index[R, K]) Delete(key K) []R {
+	x.m.lock.Lock()
+	defer x.m.lock.Unlock()
+
+	ids := x.ids(key)
+	records := make([]R, 0, len(ids))
+	for _, id := range ids {
+		records = append(records, x.m.remove(id))
+	}
+
+	return records
+}
+
+// Replace replaces the records of @key with @r in one step, and returns the records
+// replaced. It returns ErrDuplicateKey and changes nothing if @r conflicts with the
+// other records on a unique index.
+func (x *Index[R, K]) Replace(key K, r R) ([]R, error) {
+	m := x.m
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ids := x.ids(key)
+	old := make([]R, 0, len(ids))
+	for _, id := range ids {
+		old = append(old, m.remove(id))
+	}
+	if err := m.conflict(r); err != nil {
+		for i, id := range ids {
+			m.add(id, old[i])
+		}
+		return nil, err
+	}
+	m.nextID++
+	m.add(m.nextID, r)
+
+	return old, nil
+}
@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxmultiindex
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type provider struct {
+	ID        string
+	Service   string
+	Interface string
+	Version   string
+}
+
+type ifaceVersion struct {
+	Interface string
+	Version   string
+}
+
+func newProviders() (*Map[provider], *Index[provider, string], *Index[provider, string], *Index[provider, ifaceVersion]) {
+	m := New[provider]()
+	byID := NewUniqueIndex(m, "id", func(p provider) string { return p.ID })
+	byService := NewIndex(m, "service", func(p provider) string { return p.Service })
+	byIface := NewIndex(m, "interface", func(p provider) ifaceVersion { return ifaceVersion{p.Interface, p.Version} })
+
+	return m, byID, byService, byIface
+}
+
+func TestMultiIndexMap(t *testing.T) {
+	m, byID, byService, byIface := newProviders()
+	assert.Nil(t, m.Insert(provider{"1", "user", "UserService", "1.0"}))
+	assert.Nil(t, m.Insert(provider{"2", "user", "UserService", "2.0"}))
+	assert.Nil(t, m.Insert(provider{"3", "order", "OrderService", "1.0"}))
+
+	err := m.Insert(provider{"1", "pay", "PayService", "1.0"})
+	assert.True(t, errors.Is(err, ErrDuplicateKey))
+	assert.Equal(t, "gxmultiindex: duplicate key on index id", err.Error())
+	assert.False(t, byService.Has("pay"), "nothing is added on a conflict")
+	assert.Equal(t, 3, m.Len())
+
+	users := byService.Get("user")
+	assert.Len(t, users, 2)
+	assert.Equal(t, "1", users[0].ID)
+	assert.Equal(t, 2, byService.Count("user"))
+	p, ok := byIface.First(ifaceVersion{"UserService", "2.0"})
+	assert.True(t, ok)
+	assert.Equal(t, "2", p.ID)
+	_, ok = byID.First("9")
+	assert.False(t, ok)
+
+	keys := byService.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"order", "user"}, keys)
+
+	// a deletion by one index is seen by all of them
+	deleted := byID.Delete("1")
+	assert.Len(t, deleted, 1)
+	assert.Len(t, byService.Get("user"), 1)
+	assert.False(t, byIface.Has(ifaceVersion{"UserService", "1.0"}))
+
+	assert.Len(t, byService.Delete("user"), 1)
+	assert.False(t, byID.Has("2"))
+	assert.Equal(t, 1, m.Len())
+	assert.Empty(t, byService.Delete("user"))
+}
+
+func TestMultiIndexReplace(t *testing.T) {
+	m, byID, byService, _ := newProviders()
+	assert.Nil(t, m.Insert(provider{"1", "user", "UserService", "1.0"}))
+	assert.Nil(t, m.Insert(provider{"2", "order", "OrderService", "1.0"}))
+
+	old, err := byID.Replace("1", provider{"1", "account", "AccountService", "1.0"})
+	assert.Nil(t, err)
+	assert.Equal(t, "user", old[0].Service)
+	assert.False(t, byService.Has("user"))
+	assert.True(t, byService.Has("account"))
+
+	_, err = byID.Replace("1", provider{"2", "account", "AccountService", "2.0"})
+	assert.True(t, errors.Is(err, ErrDuplicateKey))
+	p, _ := byID.First("1")
+	assert.Equal(t, "account", p.Service, "restored on a conflict")
+	assert.Equal(t, 2, m.Len())
+
+	var ids []string
+	m.Range(func(p provider) bool {
+		ids = append(ids, p.ID)
+		return true
+	})
+	assert.Equal(t, []string{"2", "1"}, ids)
+
+	assert.Equal(t, 1, m.DeleteFunc(func(p provider) bool { return p.Service == "order" }))
+	assert.False(t, byID.Has("2"))
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+	assert.Empty(t, byService.Keys())
+}
+
+func TestMultiIndexLateIndex(t *testing.T) {
+	m := New[provider]()
+	assert.Nil(t, m.Insert(provider{ID: "1", Service: "user"}))
+	assert.Nil(t, m.Insert(provider{ID: "2", Service: "user"}))
+
+	byService := NewIndex(m, "service", func(p provider) string { return p.Service })
+	assert.Len(t, byService.Get("user"), 2)
+	assert.Equal(t, "service", byService.Name())
+	assert.Panics(t, func() { NewUniqueIndex(m, "service", func(p provider) string { return p.Service }) })
+}
@@ -0,0 +1,184 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+import (
+	gxdeque "github.com/dubbogo/gost/container/deque"
+)
+
+// BlockingQueue is a bounded FIFO queue whose Put blocks while it is full and whose
+// Take blocks while it is empty. The waiters are woken up by a broadcast channel, a
+// condition variable which can also be waited with a context or a timeout.
+type BlockingQueue[T any] struct {
+	lock     sync.Mutex
+	items    *gxdeque.Deque[T]
+	capacity int
+	changed  chan struct{} // closed and replaced on every change
+	disposed bool
+}
+
+// NewBlockingQueue returns a queue of at most @capacity items.
+func NewBlockingQueue[T any](capacity int) *BlockingQueue[T] {
+	if capacity <= 0 {
+		panic("@capacity <= 0")
+	}
+
+	return &BlockingQueue[T]{
+		items:    gxdeque.New[T](capacity),
+		capacity: capacity,
+		changed:  make(chan struct{}),
+	}
+}
+
+// broadcast should be invoked with the lock held.
+func (q *BlockingQueue[T]) broadcast() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// Put adds @item, waiting for room until @ctx is done.
+func (q *BlockingQueue[T]) Put(ctx context.Context, item T) error {
+	return q.put(ctx.Done(), item, ctx.Err)
+}
+
+// Offer adds @item, waiting for room at most @timeout. It returns ErrTimeout if the
+// queue is still full, and a non-positive @timeout means no wait.
+func (q *BlockingQueue[T]) Offer(item T, timeout time.Duration) error {
+	expired, stop := timeoutChan(timeout)
+	defer stop()
+
+	return q.put(expired, item, timeoutErr)
+}
+
+func (q *BlockingQueue[T]) put(done <-chan struct{}, item T, doneErr func() error) error {
+	q.lock.Lock()
+	for {
+		if q.disposed {
+			q.lock.Unlock()
+			return ErrDisposed
+		}
+		if q.items.Len() < q.capacity {
+			q.items.PushBack(item)
+			q.broadcast()
+			q.lock.Unlock()
+			return nil
+		}
+
+		changed := q.changed
+		q.lock.Unlock()
+		select {
+		case <-changed:
+		case <-done:
+			return doneErr()
+		}
+		q.lock.Lock()
+	}
+}
+
+// Take removes and returns the oldest item, waiting for one until @ctx is done.
+func (q *BlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	return q.take(ctx.Done(), ctx.Err)
+}
+
+// Poll removes and returns the oldest item, waiting for one at most @timeout. It
+// returns ErrTimeout if the queue is still empty, and a non-positive @timeout means
+// no wait.
+func (q *BlockingQueue[T]) Poll(timeout time.Duration) (T, error) {
+	expired, stop := timeoutChan(timeout)
+	defer stop()
+
+	return q.take(expired, timeoutErr)
+}
+
+func (q *BlockingQueue[T]) take(done <-chan struct{}, doneErr func() error) (T, error) {
+	var zero T
+
+	q.lock.Lock()
+	for {
+		if item, ok := q.items.PopFront(); ok {
+			q.broadcast()
+			q.lock.Unlock()
+			return item, nil
+		}
+		if q.disposed {
+			q.lock.Unlock()
+			return zero, ErrDisposed
+		}
+
+		changed := q.changed
+		q.lock.Unlock()
+		select {
+		case <-changed:
+		case <-done:
+			return zero, doneErr()
+		}
+		q.lock.Lock()
+	}
+}
+
+// Len returns the number of items.
+func (q *BlockingQueue[T]) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.items.Len()
+}
+
+// Cap returns the capacity of the queue.
+func (q *BlockingQueue[T]) Cap() int {
+	return q.capacity
+}
+
+// Dispose rejects the later puts with ErrDisposed. The items left can still be taken,
+// after which Take returns ErrDisposed too.
+func (q *BlockingQueue[T]) Dispose() {
+	q.lock.Lock()
+	if !q.disposed {
+		q.disposed = true
+		q.broadcast()
+	}
+	q.lock.Unlock()
+}
+
+func timeoutErr() error {
+	return ErrTimeout
+}
+
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// timeoutChan returns a channel closed after @timeout and the func releasing its timer.
+func timeoutChan(timeout time.Duration) (<-chan struct{}, func()) {
+	if timeout <= 0 {
+		return closedChan, func() {}
+	}
+
+	c := make(chan struct{})
+	t := time.AfterFunc(timeout, func() { close(c) })
+
+	return c, func() { t.Stop() }
+}
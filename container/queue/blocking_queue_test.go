@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxqueue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockingQueue(t *testing.T) {
+	q := NewBlockingQueue[int](2)
+	assert.Equal(t, 2, q.Cap())
+	assert.Nil(t, q.Offer(1, 0))
+	assert.Nil(t, q.Put(context.Background(), 2))
+	assert.Equal(t, ErrTimeout, q.Offer(3, 0))
+
+	start := time.Now()
+	assert.Equal(t, ErrTimeout, q.Offer(3, 30*time.Millisecond))
+	assert.True(t, time.Since(start) >= 30*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, q.Put(ctx, 3))
+
+	v, err := q.Take(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v)
+	v, err = q.Poll(0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v)
+	_, err = q.Poll(20 * time.Millisecond)
+	assert.Equal(t, ErrTimeout, err)
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestBlockingQueueWakeUp(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+
+	got := make(chan int)
+	go func() {
+		v, err := q.Poll(time.Second)
+		assert.Nil(t, err)
+		got <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.Nil(t, q.Put(context.Background(), 7))
+	assert.Equal(t, 7, <-got)
+
+	// a producer blocked on the full queue
+	assert.Nil(t, q.Put(context.Background(), 8))
+	done := make(chan error)
+	go func() { done <- q.Offer(9, time.Second) }()
+	time.Sleep(10 * time.Millisecond)
+	v, _ := q.Take(context.Background())
+	assert.Equal(t, 8, v)
+	assert.Nil(t, <-done)
+}
+
+func TestBlockingQueueProducerConsumer(t *testing.T) {
+	const N = 1000
+	q := NewBlockingQueue[int](8)
+
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < N; i++ {
+				assert.Nil(t, q.Put(context.Background(), 1))
+			}
+		}()
+	}
+
+	sum := 0
+	for i := 0; i < 4*N; i++ {
+		v, err := q.Take(context.Background())
+		assert.Nil(t, err)
+		sum += v
+	}
+	wg.Wait()
+	assert.Equal(t, 4*N, sum)
+}
+
+func TestBlockingQueueDispose(t *testing.T) {
+	q := NewBlockingQueue[int](2)
+	assert.Nil(t, q.Offer(1, 0))
+
+	errs := make(chan error)
+	go func() {
+		_, err := q.Take(context.Background())
+		errs <- err
+		_, err = q.Take(context.Background())
+		errs <- err
+	}()
+	assert.Nil(t, <-errs)
+	time.Sleep(10 * time.Millisecond)
+	q.Dispose()
+	assert.Equal(t, ErrDisposed, <-errs)
+	assert.Equal(t, ErrDisposed, q.Offer(2, 0))
+}
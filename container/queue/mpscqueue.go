@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxqueue
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// MPSCLockFreeQ is a lock-free fixed-size multi-producer, single-consumer ring queue,
+// ref: Dmitry Vyukov's bounded MPMC queue. Push can be invoked by any goroutine, while
+// Pop and PopBatch must be invoked by a single goroutine.
+type MPSCLockFreeQ[T any] struct {
+	head uint64 // only written by the consumer
+	_    cacheLinePad
+	tail uint64 // claimed by the producers with CAS
+	_    cacheLinePad
+	mask uint64
+	// seqs[i] is the tail slot i is waiting for while it is free, and that tail + 1
+	// once vals[i] is published. They are apart from the values, so that they are
+	// 64-bit aligned on the 32-bit platforms whatever the size of T is.
+	seqs []uint64
+	vals []T
+}
+
+// NewMPSCLockFreeQ returns a MPSCLockFreeQ of size @n, which must be a power of 2.
+func NewMPSCLockFreeQ[T any](n int) (*MPSCLockFreeQ[T], error) {
+	if n <= 0 || n&(n-1) != 0 {
+		return nil, errors.New("the size of queue must be a power of 2")
+	}
+
+	q := &MPSCLockFreeQ[T]{
+		mask: uint64(n - 1),
+		seqs: make([]uint64, n),
+		vals: make([]T, n),
+	}
+	for i := range q.seqs {
+		q.seqs[i] = uint64(i)
+	}
+
+	return q, nil
+}
+
+// Push adds @val at the tail of the queue. It returns false if the queue is full.
+func (q *MPSCLockFreeQ[T]) Push(val T) bool {
+	for {
+		tail := atomic.LoadUint64(&q.tail)
+		i := tail & q.mask
+		diff := int64(atomic.LoadUint64(&q.seqs[i]) - tail)
+		switch {
+		case diff == 0:
+			if atomic.CompareAndSwapUint64(&q.tail, tail, tail+1) {
+				q.vals[i] = val
+				atomic.StoreUint64(&q.seqs[i], tail+1)
+				return true
+			}
+		case diff < 0:
+			// the slot still holds the value of the previous round
+			return false
+		}
+		// another producer has claimed the slot, retry
+	}
+}
+
+// Pop removes and returns the value at the head of the queue. It returns false if the
+// queue is empty or the producer of the head value has not published it yet.
+func (q *MPSCLockFreeQ[T]) Pop() (T, bool) {
+	var zero T
+
+	head := atomic.LoadUint64(&q.head)
+	i := head & q.mask
+	if atomic.LoadUint64(&q.seqs[i]) != head+1 {
+		return zero, false
+	}
+
+	val := q.vals[i]
+	q.vals[i] = zero
+	atomic.StoreUint64(&q.seqs[i], head+uint64(len(q.seqs)))
+	atomic.StoreUint64(&q.head, head+1)
+
+	return val, true
+}
+
+// PopBatch pops at most len(@dst) published values into @dst, and returns the number of them.
+func (q *MPSCLockFreeQ[T]) PopBatch(dst []T) int {
+	var zero T
+
+	head := atomic.LoadUint64(&q.head)
+	n := 0
+	for ; n < len(dst); n++ {
+		i := head & q.mask
+		if atomic.LoadUint64(&q.seqs[i]) != head+1 {
+			break
+		}
+		dst[n] = q.vals[i]
+		q.vals[i] = zero
+		atomic.StoreUint64(&q.seqs[i], head+uint64(len(q.seqs)))
+		head++
+	}
+	atomic.StoreUint64(&q.head, head)
+
+	return n
+}
+
+// Len returns the number of values claimed by the producers, including the ones
+// being published.
+func (q *MPSCLockFreeQ[T]) Len() int {
+	head := atomic.LoadUint64(&q.head)
+	return int(atomic.LoadUint64(&q.tail) - head)
+}
+
+// Cap returns the size of the queue.
+func (q *MPSCLockFreeQ[T]) Cap() int {
+	return len(q.seqs)
+}
@@ -0,0 +1,119 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxqueue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMPSCLockFreeQ(t *testing.T) {
+	_, err := NewMPSCLockFreeQ[int](6)
+	assert.EqualError(t, err, "the size of queue must be a power of 2")
+
+	q, err := NewMPSCLockFreeQ[int](4)
+	assert.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		assert.True(t, q.Push(i))
+	}
+	assert.False(t, q.Push(4))
+	assert.Equal(t, 4, q.Len())
+
+	v, ok := q.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 0, v)
+	assert.True(t, q.Push(4))
+
+	dst := make([]int, 8)
+	assert.Equal(t, 4, q.PopBatch(dst))
+	assert.Equal(t, []int{1, 2, 3, 4}, dst[:4])
+	_, ok = q.Pop()
+	assert.False(t, ok)
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestMPSCLockFreeQConcurrent(t *testing.T) {
+	const P = 8
+	N := 20000
+	if testing.Short() {
+		N = 1000
+	}
+	q, _ := NewMPSCLockFreeQ[int](128)
+
+	var wg sync.WaitGroup
+	for p := 0; p < P; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < N; {
+				if q.Push(p*N + i) {
+					i++
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	// values of each producer arrive in order
+	next := make([]int, P)
+	dst := make([]int, 32)
+	for got := 0; got < P*N; {
+		n := q.PopBatch(dst)
+		if n == 0 {
+			runtime.Gosched()
+		}
+		for _, v := range dst[:n] {
+			p := v / N
+			assert.Equal(t, next[p], v%N)
+			next[p]++
+		}
+		got += n
+	}
+	wg.Wait()
+	assert.Equal(t, 0, q.Len())
+}
+
+func BenchmarkMPSCLockFreeQ(b *testing.B) {
+	q, _ := NewMPSCLockFreeQ[int](1024)
+	done := make(chan struct{})
+	go func() {
+		dst := make([]int, 64)
+		for n := 0; n < b.N; {
+			if m := q.PopBatch(dst); m > 0 {
+				n += m
+			} else {
+				runtime.Gosched()
+			}
+		}
+		close(done)
+	}()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for !q.Push(1) {
+				runtime.Gosched()
+			}
+		}
+	})
+	<-done
+}
@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxqueue
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// cacheLinePad keeps the indexes written by different goroutines in separate cache lines.
+type cacheLinePad [64 - 8]byte
+
+// SPSCLockFreeQ is a lock-free fixed-size single-producer, single-consumer ring queue.
+// Push must be invoked by one goroutine, and Pop and PopBatch by another one.
+type SPSCLockFreeQ[T any] struct {
+	head uint64 // next slot to pop, only written by the consumer
+	_    cacheLinePad
+	tail uint64 // next slot to push, only written by the producer
+	_    cacheLinePad
+	mask uint64
+	vals []T
+}
+
+// NewSPSCLockFreeQ returns a SPSCLockFreeQ of size @n, which must be a power of 2.
+func NewSPSCLockFreeQ[T any](n int) (*SPSCLockFreeQ[T], error) {
+	if n <= 0 || n&(n-1) != 0 {
+		return nil, errors.New("the size of queue must be a power of 2")
+	}
+
+	return &SPSCLockFreeQ[T]{
+		mask: uint64(n - 1),
+		vals: make([]T, n),
+	}, nil
+}
+
+// Push adds @val at the tail of the queue. It returns false if the queue is full.
+func (q *SPSCLockFreeQ[T]) Push(val T) bool {
+	tail := atomic.LoadUint64(&q.tail)
+	if tail-atomic.LoadUint64(&q.head) == uint64(len(q.vals)) {
+		return false
+	}
+
+	q.vals[tail&q.mask] = val
+	atomic.StoreUint64(&q.tail, tail+1)
+
+	return true
+}
+
+// Pop removes and returns the value at the head of the queue. It returns false if
+// the queue is empty.
+func (q *SPSCLockFreeQ[T]) Pop() (T, bool) {
+	var zero T
+
+	head := atomic.LoadUint64(&q.head)
+	if head == atomic.LoadUint64(&q.tail) {
+		return zero, false
+	}
+
+	slot := &q.vals[head&q.mask]
+	val := *slot
+	*slot = zero
+	atomic.StoreUint64(&q.head, head+1)
+
+	return val, true
+}
+
+// PopBatch pops at most len(@dst) values into @dst with a single index update, and
+// returns the number of them.
+func (q *SPSCLockFreeQ[T]) PopBatch(dst []T) int {
+	var zero T
+
+	head := atomic.LoadUint64(&q.head)
+	n := atomic.LoadUint64(&q.tail) - head
+	if n > uint64(len(dst)) {
+		n = uint64(len(dst))
+	}
+	for i := uint64(0); i < n; i++ {
+		slot := &q.vals[(head+i)&q.mask]
+		dst[i] = *slot
+		*slot = zero
+	}
+	atomic.StoreUint64(&q.head, head+n)
+
+	return int(n)
+}
+
+// Len returns the number of values in the queue.
+func (q *SPSCLockFreeQ[T]) Len() int {
+	head := atomic.LoadUint64(&q.head)
+	return int(atomic.LoadUint64(&q.tail) - head)
+}
+
+// Cap returns the size of the queue.
+func (q *SPSCLockFreeQ[T]) Cap() int {
+	return len(q.vals)
+}
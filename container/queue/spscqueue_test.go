@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxqueue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSPSCLockFreeQ(t *testing.T) {
+	_, err := NewSPSCLockFreeQ[int](15)
+	assert.EqualError(t, err, "the size of queue must be a power of 2")
+	_, err = NewSPSCLockFreeQ[int](0)
+	assert.EqualError(t, err, "the size of queue must be a power of 2")
+	q, err := NewSPSCLockFreeQ[int](8)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, q.Cap())
+}
+
+func TestSPSCLockFreeQ(t *testing.T) {
+	q, _ := NewSPSCLockFreeQ[int](4)
+	for i := 0; i < 4; i++ {
+		assert.True(t, q.Push(i))
+	}
+	assert.False(t, q.Push(4))
+	assert.Equal(t, 4, q.Len())
+
+	v, ok := q.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 0, v)
+
+	dst := make([]int, 8)
+	assert.Equal(t, 3, q.PopBatch(dst))
+	assert.Equal(t, []int{1, 2, 3}, dst[:3])
+	_, ok = q.Pop()
+	assert.False(t, ok)
+	assert.Equal(t, 0, q.PopBatch(dst))
+}
+
+func TestSPSCLockFreeQConcurrent(t *testing.T) {
+	N := 100000
+	if testing.Short() {
+		N = 1000
+	}
+	q, _ := NewSPSCLockFreeQ[int](64)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < N; {
+			if q.Push(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+
+	dst := make([]int, 16)
+	for next := 0; next < N; {
+		n := q.PopBatch(dst)
+		if n == 0 {
+			runtime.Gosched()
+		}
+		for _, v := range dst[:n] {
+			assert.Equal(t, next, v)
+			next++
+		}
+	}
+	wg.Wait()
+}
+
+func BenchmarkSPSCLockFreeQ(b *testing.B) {
+	q, _ := NewSPSCLockFreeQ[int](1024)
+	done := make(chan struct{})
+	go func() {
+		dst := make([]int, 64)
+		for n := 0; n < b.N; {
+			if m := q.PopBatch(dst); m > 0 {
+				n += m
+			} else {
+				runtime.Gosched()
+			}
+		}
+		close(done)
+	}()
+	for i := 0; i < b.N; {
+		if q.Push(i) {
+			i++
+		} else {
+			runtime.Gosched()
+		}
+	}
+	<-done
+}
@@ -0,0 +1,315 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxroaring
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	// arrayMaxSize is the max cardinality of an array container, over which a bitmap
+	// container of 8KB takes less memory.
+	arrayMaxSize = 4096
+	bitmapWords  = 1 << 16 / 64
+)
+
+// container holds the low 16 bits of the values sharing the same high 16 bits,
+// either in a sorted array or in a bitmap.
+type container struct {
+	array  []uint16
+	bitmap []uint64 // nil for an array container
+	card   int
+}
+
+func (c *container) isBitmap() bool {
+	return c.bitmap != nil
+}
+
+func (c *container) clone() *container {
+	n := &container{card: c.card}
+	if c.isBitmap() {
+		n.bitmap = append([]uint64(nil), c.bitmap...)
+	} else {
+		n.array = append([]uint16(nil), c.array...)
+	}
+
+	return n
+}
+
+func (c *container) search(v uint16) int {
+	return sort.Search(len(c.array), func(i int) bool { return c.array[i] >= v })
+}
+
+func (c *container) contains(v uint16) bool {
+	if c.isBitmap() {
+		return c.bitmap[v>>6]&(1<<(v&63)) != 0
+	}
+	i := c.search(v)
+
+	return i < len(c.array) && c.array[i] == v
+}
+
+func (c *container) add(v uint16) bool {
+	if c.isBitmap() {
+		w, bit := &c.bitmap[v>>6], uint64(1)<<(v&63)
+		if *w&bit != 0 {
+			return false
+		}
+		*w |= bit
+		c.card++
+		return true
+	}
+
+	i := c.search(v)
+	if i < len(c.array) && c.array[i] == v {
+		return false
+	}
+	c.array = append(c.array, 0)
+	copy(c.array[i+1:], c.array[i:])
+	c.array[i] = v
+	c.card++
+	if c.card > arrayMaxSize {
+		c.bitmap = c.words()
+		c.array = nil
+	}
+
+	return true
+}
+
+func (c *container) remove(v uint16) bool {
+	if c.isBitmap() {
+		w, bit := &c.bitmap[v>>6], uint64(1)<<(v&63)
+		if *w&bit == 0 {
+			return false
+		}
+		*w &^= bit
+		c.card--
+		c.normalize()
+		return true
+	}
+
+	i := c.search(v)
+	if i == len(c.array) || c.array[i] != v {
+		return false
+	}
+	c.array = append(c.array[:i], c.array[i+1:]...)
+	c.card--
+
+	return true
+}
+
+// normalize converts the container to the representation fitting its cardinality.
+func (c *container) normalize() {
+	if c.isBitmap() && c.card <= arrayMaxSize {
+		array := make([]uint16, 0, c.card)
+		c.iterate(func(v uint16) bool {
+			array = append(array, v)
+			return true
+		})
+		c.array, c.bitmap = array, nil
+	} else if !c.isBitmap() && c.card > arrayMaxSize {
+		c.bitmap, c.array = c.words(), nil
+	}
+}
+
+// words returns the bitmap of the container, which is a copy for an array container.
+func (c *container) words() []uint64 {
+	if c.isBitmap() {
+		return c.bitmap
+	}
+
+	words := make([]uint64, bitmapWords)
+	for _, v := range c.array {
+		words[v>>6] |= 1 << (v & 63)
+	}
+
+	return words
+}
+
+func (c *container) iterate(f func(v uint16) bool) bool {
+	if !c.isBitmap() {
+		for _, v := range c.array {
+			if !f(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i, w := range c.bitmap {
+		for w != 0 {
+			if !f(uint16(i<<6 + bits.TrailingZeros64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+
+	return true
+}
+
+// rank returns the number of values not greater than @v.
+func (c *container) rank(v uint16) int {
+	if !c.isBitmap() {
+		return sort.Search(len(c.array), func(i int) bool { return c.array[i] > v })
+	}
+
+	n, last := 0, int(v>>6)
+	for _, w := range c.bitmap[:last] {
+		n += bits.OnesCount64(w)
+	}
+
+	return n + bits.OnesCount64(c.bitmap[last]&(^uint64(0)>>(63-v&63)))
+}
+
+// selectAt returns the @i-th smallest value, @i should be less than c.card.
+func (c *container) selectAt(i int) uint16 {
+	if !c.isBitmap() {
+		return c.array[i]
+	}
+
+	for j, w := range c.bitmap {
+		n := bits.OnesCount64(w)
+		if i >= n {
+			i -= n
+			continue
+		}
+		for ; i > 0; i-- {
+			w &= w - 1
+		}
+		return uint16(j<<6 + bits.TrailingZeros64(w))
+	}
+
+	panic("@i is out of range")
+}
+
+// bitmapContainer returns a normalized container of @words, or nil if it is empty.
+func bitmapContainer(words []uint64) *container {
+	card := 0
+	for _, w := range words {
+		card += bits.OnesCount64(w)
+	}
+	if card == 0 {
+		return nil
+	}
+
+	c := &container{bitmap: words, card: card}
+	c.normalize()
+
+	return c
+}
+
+func arrayContainer(array []uint16) *container {
+	if len(array) == 0 {
+		return nil
+	}
+
+	c := &container{array: array, card: len(array)}
+	c.normalize()
+
+	return c
+}
+
+func (c *container) and(o *container) *container {
+	if c.isBitmap() || o.isBitmap() {
+		a, b := c.words(), o.words()
+		words := make([]uint64, bitmapWords)
+		for i := range words {
+			words[i] = a[i] & b[i]
+		}
+		return bitmapContainer(words)
+	}
+
+	var array []uint16
+	for i, j := 0, 0; i < len(c.array) && j < len(o.array); {
+		switch {
+		case c.array[i] < o.array[j]:
+			i++
+		case c.array[i] > o.array[j]:
+			j++
+		default:
+			array = append(array, c.array[i])
+			i++
+			j++
+		}
+	}
+
+	return arrayContainer(array)
+}
+
+func (c *container) or(o *container) *container {
+	if c.isBitmap() || o.isBitmap() {
+		a, b := c.words(), o.words()
+		words := make([]uint64, bitmapWords)
+		for i := range words {
+			words[i] = a[i] | b[i]
+		}
+		return bitmapContainer(words)
+	}
+
+	array := make([]uint16, 0, len(c.array)+len(o.array))
+	i, j := 0, 0
+	for i < len(c.array) && j < len(o.array) {
+		switch {
+		case c.array[i] < o.array[j]:
+			array = append(array, c.array[i])
+			i++
+		case c.array[i] > o.array[j]:
+			array = append(array, o.array[j])
+			j++
+		default:
+			array = append(array, c.array[i])
+			i++
+			j++
+		}
+	}
+	array = append(array, c.array[i:]...)
+	array = append(array, o.array[j:]...)
+
+	return arrayContainer(array)
+}
+
+func (c *container) andNot(o *container) *container {
+	if c.isBitmap() || o.isBitmap() {
+		a, b := c.words(), o.words()
+		words := make([]uint64, bitmapWords)
+		for i := range words {
+			words[i] = a[i] &^ b[i]
+		}
+		return bitmapContainer(words)
+	}
+
+	var array []uint16
+	i, j := 0, 0
+	for i < len(c.array) && j < len(o.array) {
+		switch {
+		case c.array[i] < o.array[j]:
+			array = append(array, c.array[i])
+			i++
+		case c.array[i] > o.array[j]:
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	array = append(array, c.array[i:]...)
+
+	return arrayContainer(array)
+}
@@ -0,0 +1,279 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxroaring implements roaring bitmaps, compressed sets of uint32 which
+// are efficient for large sets of ids, e.g. instance ids of routing computations.
+// ref: https://roaringbitmap.org
+package gxroaring
+
+import (
+	"sort"
+)
+
+// Bitmap is a roaring bitmap. The values are partitioned by their high 16 bits,
+// and the low 16 bits of each partition are kept in a sorted array while the
+// partition is sparse, or in a 8KB bitmap once it is dense. The zero value is an
+// empty bitmap ready to use. It is not goroutine safe.
+type Bitmap struct {
+	keys       []uint16 // sorted high 16 bits
+	containers []*container
+}
+
+// New returns an empty bitmap.
+func New() *Bitmap {
+	return &Bitmap{}
+}
+
+// BitmapOf returns a bitmap of @values.
+func BitmapOf(values ...uint32) *Bitmap {
+	b := New()
+	for _, v := range values {
+		b.Add(v)
+	}
+
+	return b
+}
+
+func split(x uint32) (uint16, uint16) {
+	return uint16(x >> 16), uint16(x)
+}
+
+func (b *Bitmap) find(key uint16) (int, bool) {
+	i := sort.Search(len(b.keys), func(i int) bool { return b.keys[i] >= key })
+	return i, i < len(b.keys) && b.keys[i] == key
+}
+
+// Add adds @x and reports whether it is absent before.
+func (b *Bitmap) Add(x uint32) bool {
+	hi, lo := split(x)
+	i, ok := b.find(hi)
+	if !ok {
+		b.keys = append(b.keys, 0)
+		copy(b.keys[i+1:], b.keys[i:])
+		b.keys[i] = hi
+		b.containers = append(b.containers, nil)
+		copy(b.containers[i+1:], b.containers[i:])
+		b.containers[i] = &container{}
+	}
+
+	return b.containers[i].add(lo)
+}
+
+// Remove removes @x and reports whether it is present before.
+func (b *Bitmap) Remove(x uint32) bool {
+	hi, lo := split(x)
+	i, ok := b.find(hi)
+	if !ok || !b.containers[i].remove(lo) {
+		return false
+	}
+	if b.containers[i].card == 0 {
+		b.keys = append(b.keys[:i], b.keys[i+1:]...)
+		b.containers = append(b.containers[:i], b.containers[i+1:]...)
+	}
+
+	return true
+}
+
+// Contains reports whether @x is in the bitmap.
+func (b *Bitmap) Contains(x uint32) bool {
+	hi, lo := split(x)
+	i, ok := b.find(hi)
+
+	return ok && b.containers[i].contains(lo)
+}
+
+// Cardinality returns the number of values in the bitmap.
+func (b *Bitmap) Cardinality() uint64 {
+	var n uint64
+	for _, c := range b.containers {
+		n += uint64(c.card)
+	}
+
+	return n
+}
+
+// IsEmpty reports whether the bitmap has no value.
+func (b *Bitmap) IsEmpty() bool {
+	return len(b.containers) == 0
+}
+
+// Clear removes all values.
+func (b *Bitmap) Clear() {
+	b.keys = nil
+	b.containers = nil
+}
+
+// Clone returns a deep copy of the bitmap.
+func (b *Bitmap) Clone() *Bitmap {
+	n := &Bitmap{
+		keys:       append([]uint16(nil), b.keys...),
+		containers: make([]*container, len(b.containers)),
+	}
+	for i, c := range b.containers {
+		n.containers[i] = c.clone()
+	}
+
+	return n
+}
+
+// Rank returns the number of values not greater than @x.
+func (b *Bitmap) Rank(x uint32) uint64 {
+	hi, lo := split(x)
+	var n uint64
+	for i, key := range b.keys {
+		if key > hi {
+			break
+		}
+		if key < hi {
+			n += uint64(b.containers[i].card)
+			continue
+		}
+		n += uint64(b.containers[i].rank(lo))
+	}
+
+	return n
+}
+
+// Select returns the @i-th smallest value, which starts from 0. It returns false if
+// @i is not less than the cardinality.
+func (b *Bitmap) Select(i uint64) (uint32, bool) {
+	for j, c := range b.containers {
+		if i >= uint64(c.card) {
+			i -= uint64(c.card)
+			continue
+		}
+		return uint32(b.keys[j])<<16 | uint32(c.selectAt(int(i))), true
+	}
+
+	return 0, false
+}
+
+// Iterate calls @f with the values in increasing order until it returns false.
+func (b *Bitmap) Iterate(f func(x uint32) bool) {
+	for i, c := range b.containers {
+		hi := uint32(b.keys[i]) << 16
+		if !c.iterate(func(lo uint16) bool { return f(hi | uint32(lo)) }) {
+			return
+		}
+	}
+}
+
+// ToArray returns the values in increasing order.
+func (b *Bitmap) ToArray() []uint32 {
+	values := make([]uint32, 0, b.Cardinality())
+	b.Iterate(func(x uint32) bool {
+		values = append(values, x)
+		return true
+	})
+
+	return values
+}
+
+func (b *Bitmap) append(key uint16, c *container) {
+	if c != nil {
+		b.keys = append(b.keys, key)
+		b.containers = append(b.containers, c)
+	}
+}
+
+// And returns the intersection of @b and @o.
+func (b *Bitmap) And(o *Bitmap) *Bitmap {
+	r := New()
+	for i, j := 0, 0; i < len(b.keys) && j < len(o.keys); {
+		switch {
+		case b.keys[i] < o.keys[j]:
+			i++
+		case b.keys[i] > o.keys[j]:
+			j++
+		default:
+			r.append(b.keys[i], b.containers[i].and(o.containers[j]))
+			i++
+			j++
+		}
+	}
+
+	return r
+}
+
+// Or returns the union of @b and @o.
+func (b *Bitmap) Or(o *Bitmap) *Bitmap {
+	r := New()
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(o.keys) {
+		switch {
+		case b.keys[i] < o.keys[j]:
+			r.append(b.keys[i], b.containers[i].clone())
+			i++
+		case b.keys[i] > o.keys[j]:
+			r.append(o.keys[j], o.containers[j].clone())
+			j++
+		default:
+			r.append(b.keys[i], b.containers[i].or(o.containers[j]))
+			i++
+			j++
+		}
+	}
+	for ; i < len(b.keys); i++ {
+		r.append(b.keys[i], b.containers[i].clone())
+	}
+	for ; j < len(o.keys); j++ {
+		r.append(o.keys[j], o.containers[j].clone())
+	}
+
+	return r
+}
+
+// AndNot returns the values of @b which are not in @o.
+func (b *Bitmap) AndNot(o *Bitmap) *Bitmap {
+	r := New()
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(o.keys) {
+		switch {
+		case b.keys[i] < o.keys[j]:
+			r.append(b.keys[i], b.containers[i].clone())
+			i++
+		case b.keys[i] > o.keys[j]:
+			j++
+		default:
+			r.append(b.keys[i], b.containers[i].andNot(o.containers[j]))
+			i++
+			j++
+		}
+	}
+	for ; i < len(b.keys); i++ {
+		r.append(b.keys[i], b.containers[i].clone())
+	}
+
+	return r
+}
+
+// Equals reports whether @b and @o have the same values.
+func (b *Bitmap) Equals(o *Bitmap) bool {
+	if len(b.keys) != len(o.keys) {
+		return false
+	}
+	for i, key := range b.keys {
+		if key != o.keys[i] || b.containers[i].card != o.containers[i].card {
+			return false
+		}
+		if b.containers[i].andNot(o.containers[i]) != nil {
+			return false
+		}
+	}
+
+	return true
+}
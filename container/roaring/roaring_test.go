@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxroaring
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// randomBitmap returns a bitmap mixing sparse values and a dense range, which
+// covers both array and bitmap containers, and its sorted values.
+func randomBitmap(r *rand.Rand, base uint32) (*Bitmap, []uint32) {
+	set := make(map[uint32]struct{})
+	for i := 0; i < 3000; i++ {
+		set[r.Uint32()%(1<<20)] = struct{}{}
+	}
+	for i := 0; i < 10000; i++ {
+		set[base+uint32(r.Intn(20000))] = struct{}{}
+	}
+
+	b := New()
+	values := make([]uint32, 0, len(set))
+	for v := range set {
+		b.Add(v)
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	return b, values
+}
+
+func toSet(values []uint32) map[uint32]bool {
+	set := make(map[uint32]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+
+	return set
+}
+
+func TestBitmap(t *testing.T) {
+	b := New()
+	assert.True(t, b.IsEmpty())
+	assert.True(t, b.Add(1<<20+3))
+	assert.False(t, b.Add(1<<20+3))
+	assert.True(t, b.Add(7))
+	assert.True(t, b.Contains(7))
+	assert.False(t, b.Contains(8))
+	assert.Equal(t, uint64(2), b.Cardinality())
+	assert.Equal(t, []uint32{7, 1<<20 + 3}, b.ToArray())
+
+	assert.True(t, b.Remove(7))
+	assert.False(t, b.Remove(7))
+	assert.Equal(t, 1, len(b.keys))
+	b.Clear()
+	assert.True(t, b.IsEmpty())
+
+	// a container turns into a bitmap and back to an array
+	for i := uint32(0); i < 5000; i++ {
+		b.Add(i * 2)
+	}
+	assert.True(t, b.containers[0].isBitmap())
+	for i := uint32(0); i < 1000; i++ {
+		b.Remove(i * 2)
+	}
+	assert.False(t, b.containers[0].isBitmap())
+	assert.Equal(t, uint64(4000), b.Cardinality())
+}
+
+func TestBitmapRankSelect(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	b, values := randomBitmap(r, 300000)
+	assert.Equal(t, uint64(len(values)), b.Cardinality())
+	assert.True(t, b.containers[len(b.containers)-1].card > 0)
+
+	for i, v := range values {
+		if i%37 != 0 {
+			continue
+		}
+		assert.Equal(t, uint64(i+1), b.Rank(v))
+		x, ok := b.Select(uint64(i))
+		assert.True(t, ok)
+		assert.Equal(t, v, x)
+	}
+	assert.Equal(t, uint64(0), b.Rank(values[0]-1))
+	_, ok := b.Select(uint64(len(values)))
+	assert.False(t, ok)
+}
+
+func TestBitmapSetOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	a, av := randomBitmap(r, 300000)
+	b, bv := randomBitmap(r, 310000)
+	as, bs := toSet(av), toSet(bv)
+
+	var and, or, andNot []uint32
+	for v := range as {
+		or = append(or, v)
+		if bs[v] {
+			and = append(and, v)
+		} else {
+			andNot = append(andNot, v)
+		}
+	}
+	for v := range bs {
+		if !as[v] {
+			or = append(or, v)
+		}
+	}
+	for _, s := range [][]uint32{and, or, andNot} {
+		sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	}
+
+	assert.Equal(t, and, a.And(b).ToArray())
+	assert.Equal(t, or, a.Or(b).ToArray())
+	assert.Equal(t, andNot, a.AndNot(b).ToArray())
+	assert.True(t, a.And(a).Equals(a))
+	assert.True(t, a.AndNot(a).IsEmpty())
+	assert.False(t, a.Equals(b))
+
+	// the results share nothing with the operands
+	c := a.Or(New())
+	c.Add(1<<31 + 1)
+	c.Remove(av[0])
+	assert.Equal(t, av, a.ToArray())
+	assert.True(t, a.Clone().Equals(a))
+}
+
+func TestBitmapIterate(t *testing.T) {
+	b := BitmapOf(5, 1, 1<<17, 3)
+	var got []uint32
+	b.Iterate(func(x uint32) bool {
+		got = append(got, x)
+		return len(got) < 3
+	})
+	assert.Equal(t, []uint32{1, 3, 5}, got)
+}
@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxroaring
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	serialVersion = 1
+
+	containerArray  = 0
+	containerBitmap = 1
+)
+
+// ErrInvalidBitmap is returned when unmarshaling malformed data.
+var ErrInvalidBitmap = errors.New("gxroaring: invalid bitmap data")
+
+// MarshalBinary implements encoding.BinaryMarshaler. The data is laid out as:
+//
+//	version(1) | containers(4) | { key(2) | type(1) | cardinality(4) | values } ...
+//
+// in little endian, where values are cardinality uint16s of an array container, or
+// 1024 uint64s of a bitmap container.
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	size := 5
+	for _, c := range b.containers {
+		size += 7
+		if c.isBitmap() {
+			size += bitmapWords * 8
+		} else {
+			size += len(c.array) * 2
+		}
+	}
+
+	data := make([]byte, size)
+	data[0] = serialVersion
+	binary.LittleEndian.PutUint32(data[1:], uint32(len(b.containers)))
+	off := 5
+	for i, c := range b.containers {
+		binary.LittleEndian.PutUint16(data[off:], b.keys[i])
+		binary.LittleEndian.PutUint32(data[off+3:], uint32(c.card))
+		off += 7
+		if c.isBitmap() {
+			data[off-5] = containerBitmap
+			for _, w := range c.bitmap {
+				binary.LittleEndian.PutUint64(data[off:], w)
+				off += 8
+			}
+		} else {
+			data[off-5] = containerArray
+			for _, v := range c.array {
+				binary.LittleEndian.PutUint16(data[off:], v)
+				off += 2
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 || data[0] != serialVersion {
+		return ErrInvalidBitmap
+	}
+
+	n := int(binary.LittleEndian.Uint32(data[1:]))
+	if n > 1<<16 {
+		return ErrInvalidBitmap
+	}
+
+	var (
+		keys       = make([]uint16, 0, n)
+		containers = make([]*container, 0, n)
+		off        = 5
+	)
+	for i := 0; i < n; i++ {
+		if len(data)-off < 7 {
+			return ErrInvalidBitmap
+		}
+		key := binary.LittleEndian.Uint16(data[off:])
+		typ := data[off+2]
+		card := int(binary.LittleEndian.Uint32(data[off+3:]))
+		off += 7
+		if (i > 0 && key <= keys[i-1]) || card == 0 || card > 1<<16 {
+			return ErrInvalidBitmap
+		}
+
+		c := &container{card: card}
+		switch typ {
+		case containerArray:
+			if card > arrayMaxSize || len(data)-off < card*2 {
+				return ErrInvalidBitmap
+			}
+			c.array = make([]uint16, card)
+			for j := range c.array {
+				c.array[j] = binary.LittleEndian.Uint16(data[off:])
+				if j > 0 && c.array[j] <= c.array[j-1] {
+					return ErrInvalidBitmap
+				}
+				off += 2
+			}
+		case containerBitmap:
+			if len(data)-off < bitmapWords*8 {
+				return ErrInvalidBitmap
+			}
+			words := make([]uint64, bitmapWords)
+			for j := range words {
+				words[j] = binary.LittleEndian.Uint64(data[off:])
+				off += 8
+			}
+			if c = bitmapContainer(words); c == nil || c.card != card {
+				return ErrInvalidBitmap
+			}
+		default:
+			return ErrInvalidBitmap
+		}
+		keys = append(keys, key)
+		containers = append(containers, c)
+	}
+	if off != len(data) {
+		return ErrInvalidBitmap
+	}
+
+	b.keys, b.containers = keys, containers
+
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxroaring
+
+import (
+	"math/rand"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitmapSerialization(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	b, values := randomBitmap(r, 500000)
+
+	data, err := b.MarshalBinary()
+	assert.Nil(t, err)
+	var got Bitmap
+	assert.Nil(t, got.UnmarshalBinary(data))
+	assert.True(t, got.Equals(b))
+	assert.Equal(t, values, got.ToArray())
+
+	data, err = New().MarshalBinary()
+	assert.Nil(t, err)
+	assert.Nil(t, got.UnmarshalBinary(data))
+	assert.True(t, got.IsEmpty())
+}
+
+func TestBitmapUnmarshalInvalid(t *testing.T) {
+	data, _ := BitmapOf(1, 2, 3).MarshalBinary()
+	var b Bitmap
+	assert.Equal(t, ErrInvalidBitmap, b.UnmarshalBinary(nil))
+	assert.Equal(t, ErrInvalidBitmap, b.UnmarshalBinary(data[:len(data)-1]))
+	assert.Equal(t, ErrInvalidBitmap, b.UnmarshalBinary(append(data, 0)))
+
+	bad := append([]byte(nil), data...)
+	bad[0] = 2
+	assert.Equal(t, ErrInvalidBitmap, b.UnmarshalBinary(bad))
+
+	// unsorted values
+	bad = append([]byte(nil), data...)
+	bad[len(bad)-1], bad[len(bad)-2] = 0, 0
+	assert.Equal(t, ErrInvalidBitmap, b.UnmarshalBinary(bad))
+}
@@ -0,0 +1,324 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxseglist implements a doubly linked list whose nodes are stored in chunks
+// of contiguous slabs and linked by indexes, so that it does no allocation per node
+// and leaves few pointers for the garbage collector to scan.
+package gxseglist
+
+// DefaultChunkSize is the default number of nodes of a chunk.
+const DefaultChunkSize = 256
+
+const nilIndex = 0
+
+// Handle refers to an element of a List. It stays valid until the element is removed,
+// after which the list rejects it even if the slot is reused by a new element.
+// The zero value refers to no element.
+type Handle struct {
+	index uint32 // index of the slot plus one, 0 means nil
+	gen   uint32 // generation of the slot when the element is added
+}
+
+// IsNil reports whether @h refers to no element.
+func (h Handle) IsNil() bool {
+	return h.index == nilIndex
+}
+
+type node[T any] struct {
+	value T
+	prev  uint32
+	next  uint32
+	gen   uint32 // bumped when the slot is freed
+	used  bool
+}
+
+// List is a doubly linked list of T on chunked slabs. The zero value is an empty list
+// of DefaultChunkSize chunks. It is not goroutine safe.
+type List[T any] struct {
+	chunks    [][]node[T]
+	chunkSize int
+	free      uint32 // head of the free slots linked by next
+	slots     uint32 // slots taken from the chunks so far
+	front     uint32
+	back      uint32
+	len       int
+}
+
+// New returns a list of chunks of @chunkSize nodes.
+func New[T any](chunkSize int) *List[T] {
+	if chunkSize <= 0 {
+		panic("@chunkSize <= 0")
+	}
+
+	return &List[T]{chunkSize: chunkSize}
+}
+
+func (l *List[T]) node(index uint32) *node[T] {
+	i := int(index - 1)
+	return &l.chunks[i/l.chunkSize][i%l.chunkSize]
+}
+
+// lookup returns the node of @h, or nil if @h is stale or nil.
+func (l *List[T]) lookup(h Handle) *node[T] {
+	if h.index == nilIndex || h.index > l.slots {
+		return nil
+	}
+	n := l.node(h.index)
+	if !n.used || n.gen != h.gen {
+		return nil
+	}
+
+	return n
+}
+
+func (l *List[T]) handle(index uint32) Handle {
+	if index == nilIndex {
+		return Handle{}
+	}
+
+	return Handle{index: index, gen: l.node(index).gen}
+}
+
+func (l *List[T]) alloc(v T) uint32 {
+	if l.chunkSize == 0 {
+		l.chunkSize = DefaultChunkSize
+	}
+
+	var index uint32
+	if l.free != nilIndex {
+		index = l.free
+		l.free = l.node(index).next
+	} else {
+		if int(l.slots) == len(l.chunks)*l.chunkSize {
+			l.chunks = append(l.chunks, make([]node[T], l.chunkSize))
+		}
+		l.slots++
+		index = l.slots
+	}
+
+	n := l.node(index)
+	n.value = v
+	n.used = true
+
+	return index
+}
+
+// link links the node of @index between the adjacent nodes @prev and @next.
+func (l *List[T]) link(index, prev, next uint32) Handle {
+	n := l.node(index)
+	n.prev, n.next = prev, next
+	if prev != nilIndex {
+		l.node(prev).next = index
+	} else {
+		l.front = index
+	}
+	if next != nilIndex {
+		l.node(next).prev = index
+	} else {
+		l.back = index
+	}
+	l.len++
+
+	return Handle{index: index, gen: n.gen}
+}
+
+// Len returns the number of elements.
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+// Front returns the handle of the first element, which is nil if the list is empty.
+func (l *List[T]) Front() Handle {
+	return l.handle(l.front)
+}
+
+// Back returns the handle of the last element, which is nil if the list is empty.
+func (l *List[T]) Back() Handle {
+	return l.handle(l.back)
+}
+
+// Next returns the handle of the element after @h, which is nil at the end or if @h is stale.
+func (l *List[T]) Next(h Handle) Handle {
+	n := l.lookup(h)
+	if n == nil {
+		return Handle{}
+	}
+
+	return l.handle(n.next)
+}
+
+// Prev returns the handle of the element before @h, which is nil at the front or if @h is stale.
+func (l *List[T]) Prev(h Handle) Handle {
+	n := l.lookup(h)
+	if n == nil {
+		return Handle{}
+	}
+
+	return l.handle(n.prev)
+}
+
+// PushFront inserts @v at the front and returns its handle.
+func (l *List[T]) PushFront(v T) Handle {
+	return l.link(l.alloc(v), nilIndex, l.front)
+}
+
+// PushBack inserts @v at the back and returns its handle.
+func (l *List[T]) PushBack(v T) Handle {
+	return l.link(l.alloc(v), l.back, nilIndex)
+}
+
+// InsertAfter inserts @v right after the element of @mark, and returns its handle. It
+// returns a nil handle if @mark is stale.
+func (l *List[T]) InsertAfter(v T, mark Handle) Handle {
+	n := l.lookup(mark)
+	if n == nil {
+		return Handle{}
+	}
+	next := n.next
+
+	return l.link(l.alloc(v), mark.index, next)
+}
+
+// InsertBefore inserts @v right before the element of @mark, and returns its handle. It
+// returns a nil handle if @mark is stale.
+func (l *List[T]) InsertBefore(v T, mark Handle) Handle {
+	n := l.lookup(mark)
+	if n == nil {
+		return Handle{}
+	}
+	prev := n.prev
+
+	return l.link(l.alloc(v), prev, mark.index)
+}
+
+// Get returns the value of the element of @h, and false if @h is stale.
+func (l *List[T]) Get(h Handle) (T, bool) {
+	n := l.lookup(h)
+	if n == nil {
+		var zero T
+		return zero, false
+	}
+
+	return n.value, true
+}
+
+// Set replaces the value of the element of @h, and returns false if @h is stale.
+func (l *List[T]) Set(h Handle, v T) bool {
+	n := l.lookup(h)
+	if n == nil {
+		return false
+	}
+	n.value = v
+
+	return true
+}
+
+// unlink should be invoked with a valid node.
+func (l *List[T]) unlink(n *node[T]) {
+	if n.prev != nilIndex {
+		l.node(n.prev).next = n.next
+	} else {
+		l.front = n.next
+	}
+	if n.next != nilIndex {
+		l.node(n.next).prev = n.prev
+	} else {
+		l.back = n.prev
+	}
+	l.len--
+}
+
+// Remove removes the element of @h and returns its value. It returns false if @h is
+// stale, e.g. removed already, so removing twice is harmless.
+func (l *List[T]) Remove(h Handle) (T, bool) {
+	n := l.lookup(h)
+	if n == nil {
+		var zero T
+		return zero, false
+	}
+	l.unlink(n)
+
+	var zero T
+	v := n.value
+	n.value = zero
+	n.used = false
+	n.gen++
+	n.prev = nilIndex
+	n.next = l.free
+	l.free = h.index
+
+	return v, true
+}
+
+// MoveToFront moves the element of @h to the front, and returns false if @h is stale.
+func (l *List[T]) MoveToFront(h Handle) bool {
+	n := l.lookup(h)
+	if n == nil {
+		return false
+	}
+	if l.front != h.index {
+		l.unlink(n)
+		l.link(h.index, nilIndex, l.front)
+	}
+
+	return true
+}
+
+// MoveToBack moves the element of @h to the back, and returns false if @h is stale.
+func (l *List[T]) MoveToBack(h Handle) bool {
+	n := l.lookup(h)
+	if n == nil {
+		return false
+	}
+	if l.back != h.index {
+		l.unlink(n)
+		l.link(h.index, l.back, nilIndex)
+	}
+
+	return true
+}
+
+// Range calls @f with the elements from the front until @f returns false. @f can
+// remove the element passed to it.
+func (l *List[T]) Range(f func(h Handle, v T) bool) {
+	for index := l.front; index != nilIndex; {
+		n := l.node(index)
+		next := n.next
+		if !f(Handle{index: index, gen: n.gen}, n.value) {
+			return
+		}
+		index = next
+	}
+}
+
+// Clear removes all the elements, and keeps the chunks for the new ones. The handles
+// given out become stale.
+func (l *List[T]) Clear() {
+	var zero T
+	for index := l.front; index != nilIndex; {
+		n := l.node(index)
+		next := n.next
+		n.value = zero
+		n.used = false
+		n.gen++
+		n.prev = nilIndex
+		n.next = l.free
+		l.free = index
+		index = next
+	}
+	l.front, l.back, l.len = nilIndex, nilIndex, 0
+}
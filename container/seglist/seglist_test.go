@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxseglist
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func values[T any](l *List[T]) []T {
+	var vs []T
+	l.Range(func(h Handle, v T) bool {
+		vs = append(vs, v)
+		return true
+	})
+
+	return vs
+}
+
+func reversed[T any](l *List[T]) []T {
+	var vs []T
+	for h := l.Back(); !h.IsNil(); h = l.Prev(h) {
+		v, _ := l.Get(h)
+		vs = append(vs, v)
+	}
+
+	return vs
+}
+
+func TestList(t *testing.T) {
+	var l List[int]
+	assert.True(t, l.Front().IsNil())
+	assert.True(t, l.Back().IsNil())
+
+	h2 := l.PushBack(2)
+	h0 := l.PushFront(0)
+	h1 := l.InsertBefore(1, h2)
+	h3 := l.InsertAfter(3, h2)
+	assert.Equal(t, []int{0, 1, 2, 3}, values(&l))
+	assert.Equal(t, []int{3, 2, 1, 0}, reversed(&l))
+	assert.Equal(t, 4, l.Len())
+	assert.Equal(t, h0, l.Front())
+	assert.Equal(t, h1, l.Next(h0))
+
+	v, ok := l.Remove(h1)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	_, ok = l.Remove(h1)
+	assert.False(t, ok)
+	assert.Equal(t, []int{0, 2, 3}, values(&l))
+
+	// the slot of h1 is reused, but h1 stays stale
+	h4 := l.PushBack(4)
+	assert.Equal(t, h1.index, h4.index)
+	_, ok = l.Get(h1)
+	assert.False(t, ok)
+	assert.False(t, l.Set(h1, 9))
+	assert.True(t, l.InsertAfter(9, h1).IsNil())
+	assert.True(t, l.Next(h1).IsNil())
+
+	assert.True(t, l.MoveToFront(h3))
+	assert.True(t, l.MoveToBack(h0))
+	assert.True(t, l.Set(h2, 20))
+	assert.Equal(t, []int{3, 20, 4, 0}, values(&l))
+	assert.Equal(t, []int{0, 4, 20, 3}, reversed(&l))
+
+	l.Range(func(h Handle, v int) bool {
+		if v%2 == 0 {
+			l.Remove(h)
+		}
+		return true
+	})
+	assert.Equal(t, []int{3}, values(&l))
+
+	l.Clear()
+	assert.Equal(t, 0, l.Len())
+	_, ok = l.Get(h3)
+	assert.False(t, ok)
+	assert.True(t, l.Front().IsNil())
+}
+
+func TestListChunks(t *testing.T) {
+	l := New[int](4)
+	handles := make([]Handle, 0, 100)
+	for i := 0; i < 100; i++ {
+		handles = append(handles, l.PushBack(i))
+	}
+	assert.Len(t, l.chunks, 25)
+
+	for i := 0; i < 100; i += 2 {
+		l.Remove(handles[i])
+	}
+	for i := 0; i < 50; i++ {
+		l.PushFront(-i)
+	}
+	assert.Len(t, l.chunks, 25, "the free slots are reused")
+	assert.Equal(t, 100, l.Len())
+
+	stop := 0
+	l.Range(func(h Handle, v int) bool {
+		stop++
+		return stop < 3
+	})
+	assert.Equal(t, 3, stop)
+
+	assert.Panics(t, func() { New[int](0) })
+}
+
+func BenchmarkListPushRemove(b *testing.B) {
+	l := New[int](DefaultChunkSize)
+	for i := 0; i < b.N; i++ {
+		h := l.PushBack(i)
+		if i%2 == 0 {
+			l.Remove(h)
+		}
+	}
+}
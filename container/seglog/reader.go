@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxseglog
+
+import (
+	"context"
+)
+
+// Reader reads the entries of a log independently of the other readers. The entries
+// read are delivered again after Rewind until they are acked. It is goroutine safe,
+// but the entries are meant to be consumed in order by a single goroutine.
+type Reader struct {
+	log     *Log
+	name    string
+	next    uint64 // sequence number of the next entry to read
+	acked   uint64 // all the entries before it are acked
+	dropped uint64
+	closed  bool
+}
+
+// NewReader returns a reader named @name, which starts from the oldest entry held by the log.
+func (l *Log) NewReader(name string) (*Reader, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, ok := l.readers[name]; ok {
+		return nil, ErrReaderExists
+	}
+	first := l.first()
+	r := &Reader{log: l, name: name, next: first, acked: first}
+	l.readers[name] = r
+
+	return r, nil
+}
+
+// Name returns the name of the reader.
+func (r *Reader) Name() string {
+	return r.name
+}
+
+// Read returns at most @max entries after the ones read, waiting for new entries until
+// @ctx is done. It returns ErrClosed once all the entries of a closed log are read.
+func (r *Reader) Read(ctx context.Context, max int) ([]Entry, error) {
+	if max <= 0 {
+		panic("@max <= 0")
+	}
+
+	l := r.log
+	for {
+		l.lock.Lock()
+		if r.closed {
+			l.lock.Unlock()
+			return nil, ErrReaderClosed
+		}
+		if r.next < l.next {
+			entries := l.read(r.next, max)
+			r.next += uint64(len(entries))
+			l.lock.Unlock()
+			return entries, nil
+		}
+		if l.closed {
+			l.lock.Unlock()
+			return nil, ErrClosed
+		}
+		changed := l.changed
+		l.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Ack acks all the entries read up to @seq, whose segments are recycled once all the
+// readers ack them. The Data of the acked entries must not be used any more.
+func (r *Reader) Ack(seq uint64) {
+	l := r.log
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if r.closed || seq < r.acked {
+		return
+	}
+	r.acked = seq + 1
+	if r.acked > r.next {
+		r.acked = r.next
+	}
+	l.recycle()
+}
+
+// Rewind moves the reader back to the first entry not acked, so that the entries read
+// but not acked, e.g. the ones failed to export, are delivered again.
+func (r *Reader) Rewind() {
+	r.log.lock.Lock()
+	r.next = r.acked
+	r.log.lock.Unlock()
+}
+
+// Lag returns the number of entries not acked yet.
+func (r *Reader) Lag() int {
+	r.log.lock.Lock()
+	defer r.log.lock.Unlock()
+
+	return int(r.log.next - r.acked)
+}
+
+// Dropped returns the number of entries dropped before the reader read them.
+func (r *Reader) Dropped() uint64 {
+	r.log.lock.Lock()
+	defer r.log.lock.Unlock()
+
+	return r.dropped
+}
+
+// Close removes the reader from the log, so that it no longer holds back the recycling.
+func (r *Reader) Close() {
+	l := r.log
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if !r.closed {
+		r.closed = true
+		delete(l.readers, r.name)
+		l.recycle()
+		close(l.changed)
+		l.changed = make(chan struct{})
+	}
+}
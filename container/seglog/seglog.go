@@ -0,0 +1,259 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxseglog implements an in-memory append-only log, which is stored in
+// segments of pooled buffers and consumed by independent readers with acking.
+package gxseglog
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+import (
+	gxbytes "github.com/dubbogo/gost/bytes"
+)
+
+var (
+	// ErrClosed is returned by Append of a closed log, and by Read once a reader has
+	// read all the entries of a closed log.
+	ErrClosed = errors.New("gxseglog: log closed")
+	// ErrReaderClosed is returned by Read of a closed reader.
+	ErrReaderClosed = errors.New("gxseglog: reader closed")
+	// ErrReaderExists is returned by NewReader for a name in use.
+	ErrReaderExists = errors.New("gxseglog: reader exists")
+)
+
+// Entry is an entry of a log. Data refers to the segment buffer, so it is only valid
+// until the entry is acked by the reader which read it.
+type Entry struct {
+	Seq  uint64
+	Data []byte
+}
+
+type segment struct {
+	base uint64  // sequence number of the first entry
+	buf  *[]byte // pooled buffer of the entry data
+	ends []int   // end offset of every entry in buf
+}
+
+func (s *segment) end() uint64 {
+	return s.base + uint64(len(s.ends))
+}
+
+func (s *segment) data(seq uint64) []byte {
+	i := int(seq - s.base)
+	start := 0
+	if i > 0 {
+		start = s.ends[i-1]
+	}
+
+	return (*s.buf)[start:s.ends[i]:s.ends[i]]
+}
+
+// Log is an append-only log of byte entries numbered by sequence from 0. The entries are
+// copied into segments of @segmentSize bytes. A segment is recycled into the buffer pool
+// once it is acked by all the readers, or dropped once the log holds more than
+// @maxSegments segments, in which case the readers lagging behind skip its entries. It
+// is goroutine safe.
+type Log struct {
+	lock        sync.Mutex
+	segmentSize int
+	maxSegments int
+	segments    []*segment // oldest first, the last one is appended to
+	next        uint64     // sequence number of the next entry
+	readers     map[string]*Reader
+	changed     chan struct{} // closed and replaced on every append
+	closed      bool
+}
+
+// New returns a log of segments of @segmentSize bytes, which holds at most @maxSegments
+// segments.
+func New(segmentSize, maxSegments int) *Log {
+	if segmentSize <= 0 {
+		panic("@segmentSize <= 0")
+	}
+	if maxSegments <= 0 {
+		panic("@maxSegments <= 0")
+	}
+
+	return &Log{
+		segmentSize: segmentSize,
+		maxSegments: maxSegments,
+		readers:     make(map[string]*Reader),
+		changed:     make(chan struct{}),
+	}
+}
+
+// Append copies @data into the log, and returns its sequence number. An entry larger
+// than the segment size takes a segment of its own.
+func (l *Log) Append(data []byte) (uint64, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.closed {
+		return 0, ErrClosed
+	}
+
+	tail := l.tail()
+	if tail == nil || len(*tail.buf)+len(data) > l.segmentSize {
+		size := l.segmentSize
+		if len(data) > size {
+			size = len(data)
+		}
+		buf := gxbytes.AcquireBytes(size)
+		*buf = (*buf)[:0]
+		tail = &segment{base: l.next, buf: buf}
+		l.segments = append(l.segments, tail)
+		l.trim()
+	}
+
+	*tail.buf = append(*tail.buf, data...)
+	tail.ends = append(tail.ends, len(*tail.buf))
+	seq := l.next
+	l.next++
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+
+	return seq, nil
+}
+
+// tail should be invoked with the lock held.
+func (l *Log) tail() *segment {
+	if len(l.segments) == 0 {
+		return nil
+	}
+
+	return l.segments[len(l.segments)-1]
+}
+
+// trim drops the oldest segments over the limit. It should be invoked with the lock held.
+func (l *Log) trim() {
+	for len(l.segments) > l.maxSegments {
+		s := l.segments[0]
+		l.segments = l.segments[1:]
+
+		held := false
+		for _, r := range l.readers {
+			if r.acked < s.end() && r.next > s.base {
+				// the reader still refers to its data, leave the buffer to the GC
+				held = true
+			}
+			if r.next < s.end() {
+				r.dropped += s.end() - r.next
+				r.next = s.end()
+			}
+			if r.acked < s.end() {
+				r.acked = s.end()
+			}
+		}
+		if !held {
+			gxbytes.ReleaseBytes(s.buf)
+		}
+	}
+}
+
+// recycle releases the oldest segments acked by all the readers. A log without readers
+// only drops segments over the limit. It should be invoked with the lock held.
+func (l *Log) recycle() {
+	if len(l.readers) == 0 {
+		return
+	}
+
+	acked := l.next
+	for _, r := range l.readers {
+		if r.acked < acked {
+			acked = r.acked
+		}
+	}
+	// the tail is kept to be appended to
+	for len(l.segments) > 1 && l.segments[0].end() <= acked {
+		gxbytes.ReleaseBytes(l.segments[0].buf)
+		l.segments = l.segments[1:]
+	}
+}
+
+// first should be invoked with the lock held.
+func (l *Log) first() uint64 {
+	if len(l.segments) == 0 {
+		return l.next
+	}
+
+	return l.segments[0].base
+}
+
+// read returns at most @max entries from @seq. It should be invoked with the lock held.
+func (l *Log) read(seq uint64, max int) []Entry {
+	i := sort.Search(len(l.segments), func(i int) bool { return l.segments[i].end() > seq })
+
+	var entries []Entry
+	for ; i < len(l.segments) && len(entries) < max; i++ {
+		s := l.segments[i]
+		for ; seq < s.end() && len(entries) < max; seq++ {
+			entries = append(entries, Entry{Seq: seq, Data: s.data(seq)})
+		}
+	}
+
+	return entries
+}
+
+// FirstSeq returns the sequence number of the oldest entry held by the log, which is
+// NextSeq if the log is empty.
+func (l *Log) FirstSeq() uint64 {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.first()
+}
+
+// NextSeq returns the sequence number of the next entry to append.
+func (l *Log) NextSeq() uint64 {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.next
+}
+
+// Len returns the number of entries held by the log.
+func (l *Log) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return int(l.next - l.first())
+}
+
+// Segments returns the number of segments held by the log.
+func (l *Log) Segments() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return len(l.segments)
+}
+
+// Close rejects later appends. The readers can still read the remaining entries.
+func (l *Log) Close() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if !l.closed {
+		l.closed = true
+		close(l.changed)
+		l.changed = make(chan struct{})
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxseglog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func appendN(t *testing.T, l *Log, from, to int) {
+	for i := from; i < to; i++ {
+		seq, err := l.Append([]byte(fmt.Sprintf("e%02d", i)))
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(i), seq)
+	}
+}
+
+func TestLogReadAck(t *testing.T) {
+	l := New(8, 100) // two entries of 3 bytes a segment
+	r, err := l.NewReader("exporter")
+	assert.Nil(t, err)
+	_, err = l.NewReader("exporter")
+	assert.Equal(t, ErrReaderExists, err)
+
+	appendN(t, l, 0, 6)
+	assert.Equal(t, 6, l.Len())
+	assert.Equal(t, 3, l.Segments())
+
+	entries, err := r.Read(context.Background(), 4)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 4)
+	for i, e := range entries {
+		assert.Equal(t, uint64(i), e.Seq)
+		assert.Equal(t, fmt.Sprintf("e%02d", i), string(e.Data))
+	}
+	assert.Equal(t, 6, r.Lag())
+
+	// the failed entries are delivered again
+	r.Ack(1)
+	r.Rewind()
+	entries, err = r.Read(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, uint64(2), entries[0].Seq)
+	assert.Equal(t, 2, l.Segments(), "first segment recycled")
+	assert.Equal(t, uint64(2), l.FirstSeq())
+
+	// an ack can not go beyond the entries read
+	r.Ack(100)
+	assert.Equal(t, 0, r.Lag())
+	assert.Equal(t, 1, l.Segments())
+	assert.Equal(t, uint64(6), l.NextSeq())
+}
+
+func TestLogIndependentReaders(t *testing.T) {
+	l := New(8, 100)
+	fast, _ := l.NewReader("fast")
+	slow, _ := l.NewReader("slow")
+	appendN(t, l, 0, 6)
+
+	entries, _ := fast.Read(context.Background(), 10)
+	fast.Ack(entries[len(entries)-1].Seq)
+	assert.Equal(t, 3, l.Segments(), "held by the slow reader")
+
+	entries, _ = slow.Read(context.Background(), 3)
+	slow.Ack(entries[len(entries)-1].Seq)
+	assert.Equal(t, 2, l.Segments())
+
+	slow.Close()
+	assert.Equal(t, 1, l.Segments())
+	_, err := slow.Read(context.Background(), 1)
+	assert.Equal(t, ErrReaderClosed, err)
+
+	late, _ := l.NewReader("slow")
+	entries, _ = late.Read(context.Background(), 10)
+	assert.Equal(t, uint64(4), entries[0].Seq)
+}
+
+func TestLogDrop(t *testing.T) {
+	l := New(8, 2)
+	r, _ := l.NewReader("r")
+	appendN(t, l, 0, 2)
+	held, _ := r.Read(context.Background(), 1)
+
+	appendN(t, l, 2, 10)
+	assert.Equal(t, 2, l.Segments())
+	assert.Equal(t, uint64(6), l.FirstSeq())
+	assert.Equal(t, uint64(5), r.Dropped())
+	// the dropped buffer is not recycled under the entry held
+	assert.Equal(t, "e00", string(held[0].Data))
+
+	entries, err := r.Read(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, uint64(6), entries[0].Seq)
+
+	big := make([]byte, 20)
+	seq, err := l.Append(big)
+	assert.Nil(t, err)
+	entries, _ = r.Read(context.Background(), 10)
+	assert.Equal(t, seq, entries[0].Seq)
+	assert.Len(t, entries[0].Data, 20)
+}
+
+func TestLogBlockingRead(t *testing.T) {
+	l := New(64, 4)
+	r, _ := l.NewReader("r")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := r.Read(ctx, 1)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	var (
+		wg  sync.WaitGroup
+		got []string
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			entries, err := r.Read(context.Background(), 2)
+			if err != nil {
+				assert.Equal(t, ErrClosed, err)
+				return
+			}
+			for _, e := range entries {
+				got = append(got, string(e.Data))
+			}
+			r.Ack(entries[len(entries)-1].Seq)
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		_, err := l.Append([]byte(fmt.Sprint(i)))
+		assert.Nil(t, err)
+	}
+	l.Close()
+	_, err = l.Append([]byte("x"))
+	assert.Equal(t, ErrClosed, err)
+	wg.Wait()
+
+	assert.Len(t, got, 20)
+	assert.Equal(t, "19", got[19])
+	assert.Equal(t, uint64(0), r.Dropped())
+}
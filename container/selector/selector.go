@@ -0,0 +1,188 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxselector implements the weighted selectors of load balancers: a weighted
+// random pick by the alias method and the smooth weighted round-robin of Nginx.
+package gxselector
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Weighted is an element with its weight. The elements of non-positive weights are
+// never selected.
+type Weighted[T any] struct {
+	Value  T
+	Weight int
+}
+
+func checkWeights[T any](elems []Weighted[T]) {
+	for _, e := range elems {
+		if e.Weight > 0 {
+			return
+		}
+	}
+
+	panic("no element of positive weight")
+}
+
+// Random picks elements at random with the probabilities proportional to their
+// weights. It is built in O(n) by Vose's alias method, and picks in O(1). It is
+// immutable and goroutine safe.
+type Random[T any] struct {
+	values []T
+	prob   []float64 // probability of picking the column itself rather than its alias
+	alias  []int
+}
+
+// NewRandom returns a selector of @elems, at least one of which has a positive weight.
+func NewRandom[T any](elems []Weighted[T]) *Random[T] {
+	checkWeights(elems)
+
+	var (
+		values []T
+		total  int
+	)
+	for _, e := range elems {
+		if e.Weight > 0 {
+			values = append(values, e.Value)
+			total += e.Weight
+		}
+	}
+
+	n := len(values)
+	r := &Random[T]{values: values, prob: make([]float64, n), alias: make([]int, n)}
+	scaled := make([]float64, 0, n)
+	for _, e := range elems {
+		if e.Weight > 0 {
+			scaled = append(scaled, float64(e.Weight)*float64(n)/float64(total))
+		}
+	}
+
+	var small, large []int
+	for i, p := range scaled {
+		if p < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		r.prob[s], r.alias[s] = scaled[s], l
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+	// the rest are 1 but for the rounding errors
+	for _, i := range append(small, large...) {
+		r.prob[i] = 1
+	}
+
+	return r
+}
+
+// Len returns the number of elements of positive weights.
+func (r *Random[T]) Len() int {
+	return len(r.values)
+}
+
+// Pick returns a random element by the global source of math/rand.
+func (r *Random[T]) Pick() T {
+	return r.pick(rand.Intn(len(r.values)), rand.Float64())
+}
+
+// PickWith returns a random element by @rnd, which is not goroutine safe by itself.
+func (r *Random[T]) PickWith(rnd *rand.Rand) T {
+	return r.pick(rnd.Intn(len(r.values)), rnd.Float64())
+}
+
+func (r *Random[T]) pick(column int, f float64) T {
+	if f < r.prob[column] {
+		return r.values[column]
+	}
+
+	return r.values[r.alias[column]]
+}
+
+type rrElem[T any] struct {
+	value   T
+	weight  int
+	current int
+}
+
+// RoundRobin selects elements in the smooth weighted round-robin of Nginx: in every
+// round of the sum of the weights, an element is selected as many times as its weight,
+// and its selections are spread evenly over the round instead of in a burst. A
+// selection costs O(n). It is goroutine safe.
+type RoundRobin[T any] struct {
+	lock  sync.Mutex
+	elems []rrElem[T]
+	total int
+}
+
+// NewRoundRobin returns a selector of @elems, at least one of which has a positive weight.
+func NewRoundRobin[T any](elems []Weighted[T]) *RoundRobin[T] {
+	checkWeights(elems)
+
+	rr := &RoundRobin[T]{}
+	for _, e := range elems {
+		if e.Weight > 0 {
+			rr.elems = append(rr.elems, rrElem[T]{value: e.Value, weight: e.Weight})
+			rr.total += e.Weight
+		}
+	}
+
+	return rr
+}
+
+// Len returns the number of elements of positive weights.
+func (rr *RoundRobin[T]) Len() int {
+	return len(rr.elems)
+}
+
+// Next returns the next element.
+func (rr *RoundRobin[T]) Next() T {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+
+	best := 0
+	for i := range rr.elems {
+		e := &rr.elems[i]
+		e.current += e.weight
+		if e.current > rr.elems[best].current {
+			best = i
+		}
+	}
+	rr.elems[best].current -= rr.total
+
+	return rr.elems[best].value
+}
+
+// Reset restarts the round.
+func (rr *RoundRobin[T]) Reset() {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+
+	for i := range rr.elems {
+		rr.elems[i].current = 0
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxselector
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundRobin(t *testing.T) {
+	rr := NewRoundRobin([]Weighted[string]{{"a", 5}, {"b", 1}, {"c", 1}, {"off", 0}})
+	assert.Equal(t, 3, rr.Len())
+
+	var seq []string
+	for i := 0; i < 14; i++ {
+		seq = append(seq, rr.Next())
+	}
+	assert.Equal(t, "aabacaaaabacaa", strings.Join(seq, ""))
+
+	rr.Next()
+	rr.Reset()
+	assert.Equal(t, "a", rr.Next())
+
+	assert.Panics(t, func() { NewRoundRobin([]Weighted[int]{{1, 0}}) })
+	assert.Panics(t, func() { NewRoundRobin[int](nil) })
+}
+
+func TestRandom(t *testing.T) {
+	r := NewRandom([]Weighted[string]{{"a", 6}, {"b", 3}, {"c", 1}, {"off", -1}})
+	assert.Equal(t, 3, r.Len())
+
+	rnd := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	const picks = 100000
+	for i := 0; i < picks; i++ {
+		counts[r.PickWith(rnd)]++
+	}
+	assert.Len(t, counts, 3)
+	assert.InDelta(t, 0.6, float64(counts["a"])/picks, 0.01)
+	assert.InDelta(t, 0.3, float64(counts["b"])/picks, 0.01)
+	assert.InDelta(t, 0.1, float64(counts["c"])/picks, 0.01)
+
+	single := NewRandom([]Weighted[int]{{7, 3}})
+	assert.Equal(t, 7, single.Pick())
+	assert.Panics(t, func() { NewRandom([]Weighted[int]{{1, 0}}) })
+}
+
+func BenchmarkRandomPick(b *testing.B) {
+	elems := make([]Weighted[int], 100)
+	for i := range elems {
+		elems[i] = Weighted[int]{Value: i, Weight: i + 1}
+	}
+	r := NewRandom(elems)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Pick()
+	}
+}
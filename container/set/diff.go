@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+// Diff compares @old against @new, and returns the items only in @new, the ones only
+// in @old and the ones in both, e.g. to notify the change of the instances of a service.
+func Diff[T comparable](old, new Set[T]) (added, removed, kept Set[T]) {
+	added, removed, kept = make(Set[T]), make(Set[T]), make(Set[T])
+	for item := range old {
+		if _, ok := new[item]; ok {
+			kept[item] = itemExists
+		} else {
+			removed[item] = itemExists
+		}
+	}
+	for item := range new {
+		if _, ok := old[item]; !ok {
+			added[item] = itemExists
+		}
+	}
+
+	return added, removed, kept
+}
+
+// DiffSlices is Diff over the slices @old and @new, whose duplicates count once.
+func DiffSlices[T comparable](old, new []T) (added, removed, kept Set[T]) {
+	return Diff(Of(old...), Of(new...))
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	added, removed, kept := Diff(Of(1, 2, 3), Of(2, 3, 4, 5))
+	assert.Equal(t, Of(4, 5), added)
+	assert.Equal(t, Of(1), removed)
+	assert.Equal(t, Of(2, 3), kept)
+
+	added, removed, kept = Diff(nil, Of(1))
+	assert.Equal(t, Of(1), added)
+	assert.Empty(t, removed)
+	assert.Empty(t, kept)
+
+	sa, sr, sk := DiffSlices([]string{"a", "a", "b"}, []string{"b"})
+	assert.Empty(t, sa)
+	assert.Equal(t, Of("a"), sr)
+	assert.Equal(t, Of("b"), sk)
+}
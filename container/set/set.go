@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"encoding/json"
+)
+
+// Set is a typed hash set. It is not safe for concurrent use, see SyncSet and
+// ShardedSet for that. It is (un)marshalled as a JSON array in no particular order.
+type Set[T comparable] map[T]struct{}
+
+// Of returns a set of @items.
+func Of[T comparable](items ...T) Set[T] {
+	s := make(Set[T], len(items))
+	s.Add(items...)
+
+	return s
+}
+
+// Add adds @items.
+func (s Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s[item] = itemExists
+	}
+}
+
+// Remove removes @items.
+func (s Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s, item)
+	}
+}
+
+// Contains reports whether all @items are in the set.
+func (s Set[T]) Contains(items ...T) bool {
+	for _, item := range items {
+		if _, ok := s[item]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Len returns the number of items.
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Values returns the items in no particular order.
+func (s Set[T]) Values() []T {
+	values := make([]T, 0, len(s))
+	for item := range s {
+		values = append(values, item)
+	}
+
+	return values
+}
+
+// Clone returns a copy of the set.
+func (s Set[T]) Clone() Set[T] {
+	c := make(Set[T], len(s))
+	for item := range s {
+		c[item] = itemExists
+	}
+
+	return c
+}
+
+// Union returns a new set of the items in either set.
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	u := s.Clone()
+	for item := range other {
+		u[item] = itemExists
+	}
+
+	return u
+}
+
+// Intersect returns a new set of the items in both sets.
+func (s Set[T]) Intersect(other Set[T]) Set[T] {
+	small, large := s, other
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+
+	i := make(Set[T])
+	for item := range small {
+		if _, ok := large[item]; ok {
+			i[item] = itemExists
+		}
+	}
+
+	return i
+}
+
+// Difference returns a new set of the items in s but not in @other.
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	d := make(Set[T])
+	for item := range s {
+		if _, ok := other[item]; !ok {
+			d[item] = itemExists
+		}
+	}
+
+	return d
+}
+
+// IsSubset reports whether every item of s is in @other.
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for item := range s {
+		if _, ok := other[item]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equal reports whether both sets have the same items.
+func (s Set[T]) Equal(other Set[T]) bool {
+	return len(s) == len(other) && s.IsSubset(other)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Values())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The items are added to the set.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if *s == nil {
+		*s = make(Set[T], len(items))
+	}
+	s.Add(items...)
+
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	a := Of(1, 2, 3, 4)
+	b := Of(3, 4, 5)
+
+	assert.True(t, a.Union(b).Equal(Of(1, 2, 3, 4, 5)))
+	assert.True(t, a.Intersect(b).Equal(Of(3, 4)))
+	assert.True(t, a.Difference(b).Equal(Of(1, 2)))
+	assert.True(t, b.Difference(a).Equal(Of(5)))
+	assert.Equal(t, 4, a.Len())
+	assert.Equal(t, 3, b.Len())
+
+	assert.True(t, Of(3, 4).IsSubset(a))
+	assert.False(t, b.IsSubset(a))
+	assert.True(t, Of[int]().IsSubset(a))
+	assert.False(t, a.Equal(b))
+}
+
+func TestSetBasic(t *testing.T) {
+	s := Of("a", "b")
+	s.Add("c", "a")
+	assert.Equal(t, 3, s.Len())
+	assert.True(t, s.Contains("a", "c"))
+	assert.False(t, s.Contains("a", "d"))
+
+	s.Remove("a", "d")
+	values := s.Values()
+	sort.Strings(values)
+	assert.Equal(t, []string{"b", "c"}, values)
+
+	c := s.Clone()
+	c.Add("x")
+	assert.False(t, s.Contains("x"))
+}
+
+func TestSetJSON(t *testing.T) {
+	data, err := json.Marshal(Of(7))
+	assert.Nil(t, err)
+	assert.Equal(t, "[7]", string(data))
+
+	var s Set[int]
+	assert.Nil(t, json.Unmarshal([]byte("[1,2,2,3]"), &s))
+	assert.True(t, s.Equal(Of(1, 2, 3)))
+
+	v := struct {
+		Tags Set[string] `json:"tags"`
+	}{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"tags":["x","y"]}`), &v))
+	assert.True(t, v.Tags.Equal(Of("x", "y")))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"a":1}`), &s))
+}
@@ -0,0 +1,275 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// SyncSet is a Set guarded by a RWMutex. The zero value is an empty set.
+type SyncSet[T comparable] struct {
+	lock sync.RWMutex
+	set  Set[T]
+}
+
+// NewSyncSet returns a concurrent set of @items.
+func NewSyncSet[T comparable](items ...T) *SyncSet[T] {
+	return &SyncSet[T]{set: Of(items...)}
+}
+
+// Add adds @items.
+func (s *SyncSet[T]) Add(items ...T) {
+	s.lock.Lock()
+	if s.set == nil {
+		s.set = make(Set[T])
+	}
+	s.set.Add(items...)
+	s.lock.Unlock()
+}
+
+// AddIfAbsent adds @item, and returns false if it exists.
+func (s *SyncSet[T]) AddIfAbsent(item T) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.set[item]; ok {
+		return false
+	}
+	if s.set == nil {
+		s.set = make(Set[T])
+	}
+	s.set[item] = itemExists
+
+	return true
+}
+
+// Remove removes @items.
+func (s *SyncSet[T]) Remove(items ...T) {
+	s.lock.Lock()
+	s.set.Remove(items...)
+	s.lock.Unlock()
+}
+
+// Contains reports whether all @items are in the set.
+func (s *SyncSet[T]) Contains(items ...T) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.set.Contains(items...)
+}
+
+// Len returns the number of items.
+func (s *SyncSet[T]) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.set)
+}
+
+// Clear removes all the items.
+func (s *SyncSet[T]) Clear() {
+	s.lock.Lock()
+	s.set = make(Set[T])
+	s.lock.Unlock()
+}
+
+// Snapshot returns a copy of the items.
+func (s *SyncSet[T]) Snapshot() Set[T] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.set.Clone()
+}
+
+// Range calls @f for every item of a snapshot until @f returns false, so @f can
+// modify the set.
+func (s *SyncSet[T]) Range(f func(item T) bool) {
+	for item := range s.Snapshot() {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *SyncSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Snapshot())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The items are added to the set.
+func (s *SyncSet[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	s.Add(items...)
+
+	return nil
+}
+
+// ShardedSet spreads its items over shards of SyncSet by hash, so that the writers of
+// different shards do not contend with each other.
+type ShardedSet[T comparable] struct {
+	shards []SyncSet[T]
+	hasher func(T) uint64
+}
+
+// NewShardedSet returns a set of @shards shards. @hasher hashes the items, a nil one
+// hashes the integers and strings directly and the others by fmt.
+func NewShardedSet[T comparable](shards int, hasher func(T) uint64) *ShardedSet[T] {
+	if shards <= 0 {
+		panic("@shards <= 0")
+	}
+	if hasher == nil {
+		hasher = hashItem[T]
+	}
+
+	return &ShardedSet[T]{shards: make([]SyncSet[T], shards), hasher: hasher}
+}
+
+func (s *ShardedSet[T]) shard(item T) *SyncSet[T] {
+	return &s.shards[s.hasher(item)%uint64(len(s.shards))]
+}
+
+// Add adds @items.
+func (s *ShardedSet[T]) Add(items ...T) {
+	for _, item := range items {
+		s.shard(item).Add(item)
+	}
+}
+
+// AddIfAbsent adds @item, and returns false if it exists.
+func (s *ShardedSet[T]) AddIfAbsent(item T) bool {
+	return s.shard(item).AddIfAbsent(item)
+}
+
+// Remove removes @items.
+func (s *ShardedSet[T]) Remove(items ...T) {
+	for _, item := range items {
+		s.shard(item).Remove(item)
+	}
+}
+
+// Contains reports whether all @items are in the set.
+func (s *ShardedSet[T]) Contains(items ...T) bool {
+	for _, item := range items {
+		if !s.shard(item).Contains(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Len returns the number of items. It is not a snapshot of a single instant.
+func (s *ShardedSet[T]) Len() int {
+	n := 0
+	for i := range s.shards {
+		n += s.shards[i].Len()
+	}
+
+	return n
+}
+
+// Clear removes all the items.
+func (s *ShardedSet[T]) Clear() {
+	for i := range s.shards {
+		s.shards[i].Clear()
+	}
+}
+
+// Snapshot returns a copy of the items, which are copied shard by shard.
+func (s *ShardedSet[T]) Snapshot() Set[T] {
+	snapshot := make(Set[T])
+	for i := range s.shards {
+		s.shards[i].lock.RLock()
+		for item := range s.shards[i].set {
+			snapshot[item] = itemExists
+		}
+		s.shards[i].lock.RUnlock()
+	}
+
+	return snapshot
+}
+
+// Range calls @f for every item of a snapshot until @f returns false.
+func (s *ShardedSet[T]) Range(f func(item T) bool) {
+	for item := range s.Snapshot() {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *ShardedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Snapshot())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The items are added to the set.
+func (s *ShardedSet[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	s.Add(items...)
+
+	return nil
+}
+
+func hashItem[T comparable](item T) uint64 {
+	switch v := any(item).(type) {
+	case string:
+		return hashString(v)
+	case int:
+		return mix(uint64(v))
+	case int32:
+		return mix(uint64(v))
+	case int64:
+		return mix(uint64(v))
+	case uint:
+		return mix(uint64(v))
+	case uint32:
+		return mix(uint64(v))
+	case uint64:
+		return mix(v)
+	}
+
+	return hashString(fmt.Sprintf("%#v", item))
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+
+	return h.Sum64()
+}
+
+// mix is the finalizer of splitmix64, which spreads the bits of sequential integers.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncSet(t *testing.T) {
+	var s SyncSet[int]
+	assert.False(t, s.Contains(1))
+	assert.True(t, s.AddIfAbsent(1))
+	assert.False(t, s.AddIfAbsent(1))
+	s.Add(2, 3)
+	s.Remove(3)
+	assert.Equal(t, 2, s.Len())
+	assert.True(t, s.Snapshot().Equal(Of(1, 2)))
+
+	n := 0
+	s.Range(func(item int) bool {
+		s.Remove(item)
+		n++
+		return true
+	})
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 0, s.Len())
+
+	assert.Nil(t, json.Unmarshal([]byte("[4,5]"), &s))
+	data, err := json.Marshal(&s)
+	assert.Nil(t, err)
+	var back Set[int]
+	assert.Nil(t, json.Unmarshal(data, &back))
+	assert.True(t, back.Equal(Of(4, 5)))
+}
+
+func TestShardedSet(t *testing.T) {
+	s := NewShardedSet[string](8, nil)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				s.Add(strconv.Itoa(i))
+				s.Contains(strconv.Itoa(i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 500, s.Len())
+	assert.True(t, s.Contains("0", "499"))
+	assert.False(t, s.AddIfAbsent("1"))
+	s.Remove("1", "2")
+	assert.Equal(t, 498, s.Snapshot().Len())
+
+	data, err := json.Marshal(s)
+	assert.Nil(t, err)
+	other := NewShardedSet[string](3, nil)
+	assert.Nil(t, json.Unmarshal(data, other))
+	assert.True(t, other.Snapshot().Equal(s.Snapshot()))
+
+	stop := 0
+	s.Range(func(string) bool {
+		stop++
+		return stop < 10
+	})
+	assert.Equal(t, 10, stop)
+
+	s.Clear()
+	assert.Equal(t, 0, s.Len())
+
+	ints := NewShardedSet(4, func(i int) uint64 { return uint64(i) })
+	ints.Add(1, 5, 9)
+	assert.Equal(t, 3, ints.shards[1].Len())
+
+	assert.Panics(t, func() { NewShardedSet[int](0, nil) })
+}
@@ -0,0 +1,325 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxshardmap implements a concurrent map split into shards of their own locks.
+package gxshardmap
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// DefaultShards is the number of shards of a Map by New with a non-positive count.
+const DefaultShards = 32
+
+type shardFields[K comparable, V any] struct {
+	size      int64 // len(items) read without the lock, first for the 64-bit alignment
+	lock      sync.RWMutex
+	items     map[K]V
+	snapshots []*shardSnapshot[K, V] // of the IterStable walks not past the shard yet
+}
+
+// shard is padded to a multiple of 64 bytes, which keeps the hot shards apart on the
+// cache lines, and keeps the size of every shard in the slice 64-bit aligned on the
+// 32-bit platforms. The size of shardFields does not depend on K and V.
+type shard[K comparable, V any] struct {
+	shardFields[K, V]
+	_ [64 - unsafe.Sizeof(shardFields[int, int]{})%64]byte
+}
+
+// Map is a concurrent map whose keys are spread over shards by their hashes, so the
+// writers of different shards never contend, unlike those of sync.Map which suits
+// the read-mostly data.
+type Map[K comparable, V any] struct {
+	shards []shard[K, V]
+	hasher func(K) uint64
+}
+
+// New returns a map of @shards shards, a non-positive one means DefaultShards. @hasher
+// hashes the keys, a nil one hashes the booleans, numbers, strings (by FNV-1a), pointers
+// and channels directly, and panics for the other keys such as structs, whose equal
+// values may not be hashed the same by their formats, e.g. 0.0 and -0.0 fields.
+func New[K comparable, V any](shards int, hasher func(K) uint64) *Map[K, V] {
+	if shards <= 0 {
+		shards = DefaultShards
+	}
+	if hasher == nil {
+		var key K
+		if kind := reflect.TypeOf(&key).Elem().Kind(); !hashable(kind) {
+			panic(fmt.Sprintf("@hasher is nil for the keys of kind %s", kind))
+		}
+		hasher = hashKey[K]
+	}
+
+	m := &Map[K, V]{shards: make([]shard[K, V], shards), hasher: hasher}
+	for i := range m.shards {
+		m.shards[i].items = make(map[K]V)
+	}
+
+	return m
+}
+
+func (m *Map[K, V]) shard(key K) *shard[K, V] {
+	return &m.shards[m.hasher(key)%uint64(len(m.shards))]
+}
+
+// Shards returns the number of shards.
+func (m *Map[K, V]) Shards() int {
+	return len(m.shards)
+}
+
+// Get returns the value of @key.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	s := m.shard(key)
+	s.lock.RLock()
+	v, ok := s.items[key]
+	s.lock.RUnlock()
+
+	return v, ok
+}
+
+// Set puts @key with @value.
+func (m *Map[K, V]) Set(key K, value V) {
+	s := m.shard(key)
+	s.lock.Lock()
+	s.preserve(key)
+	if _, ok := s.items[key]; !ok {
+		atomic.AddInt64(&s.size, 1)
+	}
+	s.items[key] = value
+	s.lock.Unlock()
+}
+
+// GetOrSet returns the value of @key if it exists, otherwise puts @key with @value
+// and returns it. The bool result is true if the value is loaded.
+func (m *Map[K, V]) GetOrSet(key K, value V) (V, bool) {
+	s := m.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if v, ok := s.items[key]; ok {
+		return v, true
+	}
+	s.preserve(key)
+	s.items[key] = value
+	atomic.AddInt64(&s.size, 1)
+
+	return value, false
+}
+
+// Compute updates @key by @f atomically, which is given the current value and whether
+// it exists, and returns the new value and whether to keep it. @f runs with the shard
+// locked, so it should be quick and must not access the map.
+func (m *Map[K, V]) Compute(key K, f func(old V, ok bool) (V, bool)) (V, bool) {
+	s := m.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	old, ok := s.items[key]
+	v, keep := f(old, ok)
+	if keep || ok {
+		s.preserve(key)
+	}
+	switch {
+	case keep:
+		if !ok {
+			atomic.AddInt64(&s.size, 1)
+		}
+		s.items[key] = v
+	case ok:
+		delete(s.items, key)
+		atomic.AddInt64(&s.size, -1)
+	}
+
+	return v, keep
+}
+
+// Delete removes @key, and returns its value.
+func (m *Map[K, V]) Delete(key K) (V, bool) {
+	s := m.shard(key)
+	s.lock.Lock()
+	v, ok := s.items[key]
+	if ok {
+		s.preserve(key)
+		delete(s.items, key)
+		atomic.AddInt64(&s.size, -1)
+	}
+	s.lock.Unlock()
+
+	return v, ok
+}
+
+// Len returns the number of entries by the counters of the shards without locking
+// them, so it is a moment of each shard rather than of the whole map.
+func (m *Map[K, V]) Len() int {
+	var n int64
+	for i := range m.shards {
+		n += atomic.LoadInt64(&m.shards[i].size)
+	}
+
+	return int(n)
+}
+
+// Range calls @f for the entries shard by shard until @f returns false. Each shard is
+// read locked while its entries are visited, so @f must not write the map.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.RLock()
+		for k, v := range s.items {
+			if !f(k, v) {
+				s.lock.RUnlock()
+				return
+			}
+		}
+		s.lock.RUnlock()
+	}
+}
+
+// RangeStable calls @f for a snapshot of the entries taken shard by shard until @f
+// returns false. No lock is held while @f runs, so @f can write the map, and the
+// writers are blocked just for copying a shard. The entries written by @f into the
+// shards not copied yet are visited too.
+func (m *Map[K, V]) RangeStable(f func(key K, value V) bool) {
+	type entry struct {
+		key   K
+		value V
+	}
+
+	var snapshot []entry
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.RLock()
+		snapshot = snapshot[:0]
+		for k, v := range s.items {
+			snapshot = append(snapshot, entry{k, v})
+		}
+		s.lock.RUnlock()
+
+		for _, e := range snapshot {
+			if !f(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Clear removes all entries.
+func (m *Map[K, V]) Clear() {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.Lock()
+		if len(s.snapshots) > 0 {
+			for k := range s.items {
+				s.preserve(k)
+			}
+		}
+		s.items = make(map[K]V)
+		atomic.StoreInt64(&s.size, 0)
+		s.lock.Unlock()
+	}
+}
+
+func hashable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String, reflect.Ptr, reflect.UnsafePointer, reflect.Chan,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+
+	return false
+}
+
+func hashKey[K comparable](key K) uint64 {
+	switch v := any(key).(type) {
+	case string:
+		return hashString(v)
+	case int:
+		return mix(uint64(v))
+	case int32:
+		return mix(uint64(v))
+	case int64:
+		return mix(uint64(v))
+	case uint:
+		return mix(uint64(v))
+	case uint32:
+		return mix(uint64(v))
+	case uint64:
+		return mix(v)
+	}
+
+	// the other kinds accepted by hashable, and the types defined on them
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.String:
+		return hashString(v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			return mix(1)
+		}
+		return mix(0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return mix(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return mix(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return hashFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return hashFloat(real(c)) ^ mix(hashFloat(imag(c)))
+	}
+
+	return mix(uint64(v.Pointer()))
+}
+
+// hashFloat hashes the equal floats the same, adding 0 turns -0 into +0.
+func hashFloat(f float64) uint64 {
+	return mix(math.Float64bits(f + 0))
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// hashString is FNV-1a inlined, as hash/fnv allocates the hasher and the bytes of @s.
+func hashString(s string) uint64 {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+
+	return h
+}
+
+// mix is the finalizer of splitmix64, which spreads the bits of sequential integers.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
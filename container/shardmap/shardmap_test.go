@@ -0,0 +1,201 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxshardmap
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	m := New[string, int](4, nil)
+	assert.Equal(t, 4, m.Shards())
+
+	m.Set("a", 1)
+	m.Set("a", 2)
+	v, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 1, m.Len())
+
+	v, loaded := m.GetOrSet("a", 3)
+	assert.True(t, loaded)
+	assert.Equal(t, 2, v)
+	v, loaded = m.GetOrSet("b", 3)
+	assert.False(t, loaded)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 2, m.Len())
+
+	inc := func(old int, ok bool) (int, bool) { return old + 1, true }
+	v, _ = m.Compute("c", inc)
+	assert.Equal(t, 1, v)
+	v, _ = m.Compute("c", inc)
+	assert.Equal(t, 2, v)
+	_, keep := m.Compute("c", func(int, bool) (int, bool) { return 0, false })
+	assert.False(t, keep)
+	_, ok = m.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, 2, m.Len())
+
+	v, ok = m.Delete("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	_, ok = m.Delete("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, m.Len())
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+	_, ok = m.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, DefaultShards, New[int, int](0, nil).Shards())
+}
+
+func TestMapHashKey(t *testing.T) {
+	type id int64
+
+	f := New[float64, int](16, nil)
+	negZero := math.Copysign(0, -1)
+	f.Set(0, 1)
+	f.Set(negZero, 2)
+	assert.Equal(t, 1, f.Len())
+	v, ok := f.Get(0)
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	ids := New[id, int](16, nil)
+	for i := 0; i < 100; i++ {
+		ids.Set(id(i), i)
+	}
+	assert.Equal(t, 100, ids.Len())
+	v, _ = ids.Get(42)
+	assert.Equal(t, 42, v)
+
+	// the same as hash/fnv without its allocations
+	h := fnv.New64a()
+	h.Write([]byte("gost"))
+	assert.Equal(t, h.Sum64(), hashString("gost"))
+	strs := New[string, int](16, nil)
+	strs.Set("gost", 1)
+	assert.Equal(t, float64(0), testing.AllocsPerRun(100, func() {
+		strs.Get("gost")
+		strs.Set("gost", 2)
+	}))
+
+	type point struct{ x, y float64 }
+	assert.Panics(t, func() { New[point, int](16, nil) })
+	assert.NotPanics(t, func() {
+		New[point, int](16, func(p point) uint64 { return hashFloat(p.x) ^ hashFloat(p.y) })
+	})
+}
+
+func TestMapRange(t *testing.T) {
+	m := New[int, int](8, nil)
+	for i := 0; i < 100; i++ {
+		m.Set(i, i*i)
+	}
+
+	var keys []int
+	m.Range(func(k, v int) bool {
+		assert.Equal(t, k*k, v)
+		keys = append(keys, k)
+		return true
+	})
+	sort.Ints(keys)
+	assert.Len(t, keys, 100)
+	assert.Equal(t, 99, keys[99])
+
+	n := 0
+	m.Range(func(int, int) bool {
+		n++
+		return n < 10
+	})
+	assert.Equal(t, 10, n)
+
+	// the snapshot allows writing the map meanwhile, and the later shards may show the writes
+	n = 0
+	m.RangeStable(func(k, _ int) bool {
+		if k < 1000 {
+			m.Delete(k)
+			m.Set(k+1000, k)
+			n++
+		}
+		return true
+	})
+	assert.Equal(t, 100, n)
+	assert.Equal(t, 100, m.Len())
+	_, ok := m.Get(1050)
+	assert.True(t, ok)
+}
+
+func TestMapConcurrent(t *testing.T) {
+	m := New[string, int](16, nil)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := strconv.Itoa(i % 100)
+				m.Compute(key, func(old int, _ bool) (int, bool) { return old + 1, true })
+				m.Get(key)
+				m.Len()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, m.Len())
+	total := 0
+	m.RangeStable(func(_ string, v int) bool {
+		total += v
+		return true
+	})
+	assert.Equal(t, 8*500, total)
+}
+
+func BenchmarkMapSet(b *testing.B) {
+	m := New[int, int](0, nil)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Set(i&1023, i)
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncMapStore(b *testing.B) {
+	var m sync.Map
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Store(i&1023, i)
+			i++
+		}
+	})
+}
@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxshardmap
+
+// undoEntry is the state of a key at the moment of a snapshot.
+type undoEntry[V any] struct {
+	value   V
+	existed bool
+}
+
+// shardSnapshot records the states of the keys of a shard before their first writes
+// since the moment of the snapshot, so that the shard at that moment is the current
+// one rolled back by them.
+type shardSnapshot[K comparable, V any] struct {
+	undo map[K]undoEntry[V]
+}
+
+// preserve records the state of @key into the snapshots of the shard not recording it
+// yet. It should be invoked with the lock held before @key is written.
+func (s *shard[K, V]) preserve(key K) {
+	for _, sn := range s.snapshots {
+		if _, ok := sn.undo[key]; !ok {
+			v, existed := s.items[key]
+			sn.undo[key] = undoEntry[V]{value: v, existed: existed}
+		}
+	}
+}
+
+// release drops @sn from the snapshots of the shard. It should be invoked with the
+// lock held.
+func (s *shard[K, V]) release(sn *shardSnapshot[K, V]) {
+	for i, x := range s.snapshots {
+		if x == sn {
+			last := len(s.snapshots) - 1
+			s.snapshots[i] = s.snapshots[last]
+			s.snapshots[last] = nil
+			s.snapshots = s.snapshots[:last]
+			return
+		}
+	}
+}
+
+// IterStable calls @f for the entries of a consistent snapshot of the whole map until
+// @f returns false, for the walks over large maps such as metrics exporting. Unlike
+// RangeStable, no write after the snapshot is visited on any shard. The snapshot is
+// taken by locking all the shards just to register it, and then the shards are copied
+// one by one, rolled back by the previous values that their writers record for the
+// keys written since the snapshot. So it neither copies the whole map at once nor
+// blocks the writers while @f runs, and @f can write the map.
+func (m *Map[K, V]) IterStable(f func(key K, value V) bool) {
+	type entry struct {
+		key   K
+		value V
+	}
+
+	snapshots := make([]*shardSnapshot[K, V], len(m.shards))
+	for i := range m.shards {
+		m.shards[i].lock.Lock()
+	}
+	for i := range m.shards {
+		s := &m.shards[i]
+		snapshots[i] = &shardSnapshot[K, V]{undo: make(map[K]undoEntry[V])}
+		s.snapshots = append(s.snapshots, snapshots[i])
+	}
+	for i := range m.shards {
+		m.shards[i].lock.Unlock()
+	}
+
+	next := 0
+	defer func() {
+		// stop recording for the shards not visited once @f stops the walk or panics
+		for ; next < len(m.shards); next++ {
+			s := &m.shards[next]
+			s.lock.Lock()
+			s.release(snapshots[next])
+			s.lock.Unlock()
+		}
+	}()
+
+	var shot []entry
+	for next < len(m.shards) {
+		s, sn := &m.shards[next], snapshots[next]
+		s.lock.Lock()
+		shot = shot[:0]
+		for k, v := range s.items {
+			if u, ok := sn.undo[k]; ok {
+				if u.existed {
+					shot = append(shot, entry{k, u.value})
+				}
+				continue
+			}
+			shot = append(shot, entry{k, v})
+		}
+		for k, u := range sn.undo {
+			if _, ok := s.items[k]; !ok && u.existed {
+				shot = append(shot, entry{k, u.value})
+			}
+		}
+		s.release(sn)
+		s.lock.Unlock()
+		next++
+
+		for _, e := range shot {
+			if !f(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxshardmap
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapIterStable(t *testing.T) {
+	m := New[int, int](8, nil)
+	for i := 0; i < 1000; i++ {
+		m.Set(i, i)
+	}
+
+	seen := make(map[int]int)
+	first := true
+	m.IterStable(func(k, v int) bool {
+		if first {
+			// none of the writes after the snapshot is visited
+			first = false
+			for i := 0; i < 500; i++ {
+				m.Delete(i)
+			}
+			for i := 500; i < 1000; i++ {
+				m.Set(i, -1)
+			}
+			for i := 1000; i < 2000; i++ {
+				m.Set(i, i)
+			}
+			m.Compute(1, func(int, bool) (int, bool) { return -1, true })
+		}
+		seen[k] = v
+		return true
+	})
+	assert.Equal(t, 1000, len(seen))
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, i, seen[i])
+	}
+	assert.Equal(t, 1501, m.Len())
+
+	// the snapshot of the map cleared during the walk
+	m.Clear()
+	m.Set(1, 1)
+	m.Set(2, 2)
+	n := 0
+	m.IterStable(func(k, v int) bool {
+		m.Clear()
+		n++
+		return true
+	})
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestMapIterStableStop(t *testing.T) {
+	m := New[int, int](8, nil)
+	for i := 0; i < 100; i++ {
+		m.Set(i, i)
+	}
+
+	n := 0
+	m.IterStable(func(k, v int) bool {
+		n++
+		return false
+	})
+	assert.Equal(t, 1, n)
+
+	assert.Panics(t, func() {
+		m.IterStable(func(k, v int) bool { panic("boom") })
+	})
+	for i := range m.shards {
+		assert.Empty(t, m.shards[i].snapshots)
+	}
+}
+
+func TestMapIterStableConcurrent(t *testing.T) {
+	const writers = 4
+
+	m := New[int, int](16, nil)
+	for i := 0; i < 1000; i++ {
+		m.Set(-i-1, 0)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		stop = make(chan struct{})
+	)
+	for w := 0; w < writers; w++ {
+		m.Set(w*1000, w)
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			// moves the token of @w to the next key, which is put before the old one
+			// is deleted, so a consistent snapshot holds one or two of them
+			for j := 0; ; j = (j + 1) % 1000 {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				m.Set(w*1000+(j+1)%1000, w)
+				m.Delete(w*1000 + j)
+				runtime.Gosched()
+			}
+		}(w)
+	}
+
+	for i := 0; i < 10; i++ {
+		tokens := make([]int, writers)
+		m.IterStable(func(k, v int) bool {
+			if k >= 0 {
+				tokens[v]++
+			}
+			// let the writers move the tokens during the walk
+			runtime.Gosched()
+			return true
+		})
+		for w, n := range tokens {
+			assert.True(t, n == 1 || n == 2, "writer %d has %d tokens", w, n)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
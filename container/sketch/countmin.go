@@ -0,0 +1,212 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxsketch implements probabilistic estimators for metrics pipelines: the
+// count-min sketch for the frequency of keys, such as hot key detection, and the
+// HyperLogLog for the number of distinct keys, such as unique callers. Both can be
+// merged across processes through their binary forms. They are not safe for
+// concurrent use.
+package gxsketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+)
+
+const (
+	serialVersion = 1
+
+	kindCountMin    = 'c'
+	kindHyperLogLog = 'h'
+)
+
+var (
+	// ErrIncompatible is returned by Merge if the sketches are of different sizes.
+	ErrIncompatible = errors.New("gxsketch: incompatible sketches")
+	// ErrInvalidSketch is returned when unmarshaling malformed data.
+	ErrInvalidSketch = errors.New("gxsketch: invalid sketch data")
+)
+
+// hash64 hashes @key by FNV-1a followed by the splitmix64 finalizer, as the bits of
+// FNV alone are not spread enough for the register index of HyperLogLog.
+func hash64(key []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(key)
+	x := h.Sum64()
+
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
+
+// CountMin is a count-min sketch. An estimated count is never less than the real
+// one, and exceeds it by at most epsilon * Total with probability 1 - delta.
+type CountMin struct {
+	width    uint32
+	depth    uint32
+	total    uint64
+	counters []uint64 // depth rows of width counters
+}
+
+// NewCountMin returns a sketch of @depth rows of @width counters.
+func NewCountMin(width, depth int) *CountMin {
+	if width <= 0 || depth <= 0 {
+		panic("@width <= 0 || @depth <= 0")
+	}
+
+	return &CountMin{
+		width:    uint32(width),
+		depth:    uint32(depth),
+		counters: make([]uint64, width*depth),
+	}
+}
+
+// NewCountMinWithEstimates returns a sketch whose error is at most @epsilon * Total
+// with probability 1 - @delta.
+func NewCountMinWithEstimates(epsilon, delta float64) *CountMin {
+	if epsilon <= 0 || delta <= 0 || delta >= 1 {
+		panic("illegal @epsilon or @delta")
+	}
+
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+
+	return NewCountMin(width, depth)
+}
+
+// Width returns the number of counters per row.
+func (s *CountMin) Width() int {
+	return int(s.width)
+}
+
+// Depth returns the number of rows.
+func (s *CountMin) Depth() int {
+	return int(s.depth)
+}
+
+// Total returns the sum of all the added counts.
+func (s *CountMin) Total() uint64 {
+	return s.total
+}
+
+func (s *CountMin) index(h uint64, row uint32) int {
+	h1, h2 := h, h>>32|h<<32|1
+	return int(row*s.width) + int((h1+uint64(row)*h2)%uint64(s.width))
+}
+
+// Add adds @n to the count of @key.
+func (s *CountMin) Add(key []byte, n uint64) {
+	h := hash64(key)
+	for row := uint32(0); row < s.depth; row++ {
+		s.counters[s.index(h, row)] += n
+	}
+	s.total += n
+}
+
+// AddString adds @n to the count of @key.
+func (s *CountMin) AddString(key string, n uint64) {
+	s.Add([]byte(key), n)
+}
+
+// Estimate returns the estimated count of @key.
+func (s *CountMin) Estimate(key []byte) uint64 {
+	var (
+		h   = hash64(key)
+		min = uint64(math.MaxUint64)
+	)
+	for row := uint32(0); row < s.depth; row++ {
+		if c := s.counters[s.index(h, row)]; c < min {
+			min = c
+		}
+	}
+
+	return min
+}
+
+// EstimateString returns the estimated count of @key.
+func (s *CountMin) EstimateString(key string) uint64 {
+	return s.Estimate([]byte(key))
+}
+
+// Merge adds all the counts of @other, which should be of the same size.
+func (s *CountMin) Merge(other *CountMin) error {
+	if s.width != other.width || s.depth != other.depth {
+		return ErrIncompatible
+	}
+	for i, c := range other.counters {
+		s.counters[i] += c
+	}
+	s.total += other.total
+
+	return nil
+}
+
+// Reset clears all the counts.
+func (s *CountMin) Reset() {
+	for i := range s.counters {
+		s.counters[i] = 0
+	}
+	s.total = 0
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The data is laid out as:
+//
+//	version(1) | kind(1) 'c' | width(4) | depth(4) | total(8) | counters(8) ...
+//
+// in little endian.
+func (s *CountMin) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 18+8*len(s.counters))
+	data[0] = serialVersion
+	data[1] = kindCountMin
+	binary.LittleEndian.PutUint32(data[2:], s.width)
+	binary.LittleEndian.PutUint32(data[6:], s.depth)
+	binary.LittleEndian.PutUint64(data[10:], s.total)
+	for i, c := range s.counters {
+		binary.LittleEndian.PutUint64(data[18+8*i:], c)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *CountMin) UnmarshalBinary(data []byte) error {
+	if len(data) < 18 || data[0] != serialVersion || data[1] != kindCountMin {
+		return ErrInvalidSketch
+	}
+
+	width := binary.LittleEndian.Uint32(data[2:])
+	depth := binary.LittleEndian.Uint32(data[6:])
+	n := uint64(width) * uint64(depth)
+	if width == 0 || depth == 0 || uint64(len(data)-18) != 8*n {
+		return ErrInvalidSketch
+	}
+
+	s.width, s.depth = width, depth
+	s.total = binary.LittleEndian.Uint64(data[10:])
+	s.counters = make([]uint64, n)
+	for i := range s.counters {
+		s.counters[i] = binary.LittleEndian.Uint64(data[18+8*i:])
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsketch
+
+import (
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountMin(t *testing.T) {
+	s := NewCountMinWithEstimates(0.001, 0.01)
+	assert.Equal(t, 2719, s.Width())
+	assert.Equal(t, 5, s.Depth())
+
+	s.AddString("hot", 1000)
+	for i := 0; i < 10000; i++ {
+		s.AddString(strconv.Itoa(i), 1)
+	}
+	assert.Equal(t, uint64(11000), s.Total())
+
+	hot := s.EstimateString("hot")
+	assert.True(t, hot >= 1000 && hot <= 1000+11, hot)
+	for i := 0; i < 100; i++ {
+		c := s.EstimateString(strconv.Itoa(i))
+		assert.True(t, c >= 1 && c <= 1+11, c)
+	}
+
+	s.Reset()
+	assert.Equal(t, uint64(0), s.EstimateString("hot"))
+	assert.Equal(t, uint64(0), s.Total())
+}
+
+func TestCountMinMergeAndSerialize(t *testing.T) {
+	a, b := NewCountMin(256, 4), NewCountMin(256, 4)
+	a.Add([]byte("k"), 3)
+	b.Add([]byte("k"), 4)
+	b.AddString("x", 1)
+
+	data, err := b.MarshalBinary()
+	assert.Nil(t, err)
+	var c CountMin
+	assert.Nil(t, c.UnmarshalBinary(data))
+	assert.Equal(t, b, &c)
+
+	assert.Nil(t, a.Merge(&c))
+	assert.Equal(t, uint64(7), a.Estimate([]byte("k")))
+	assert.Equal(t, uint64(8), a.Total())
+
+	assert.Equal(t, ErrIncompatible, a.Merge(NewCountMin(16, 4)))
+	assert.Equal(t, ErrInvalidSketch, c.UnmarshalBinary(data[:20]))
+	data[1] = kindHyperLogLog
+	assert.Equal(t, ErrInvalidSketch, c.UnmarshalBinary(data))
+}
@@ -0,0 +1,158 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsketch
+
+import (
+	"math"
+	"math/bits"
+)
+
+const (
+	minPrecision = 4
+	maxPrecision = 18
+)
+
+// HyperLogLog estimates the number of distinct keys in 2^precision bytes. Its
+// standard error is about 1.04 / sqrt(2^precision), e.g. 0.81% at precision 14.
+type HyperLogLog struct {
+	p         uint8
+	registers []uint8
+}
+
+// NewHyperLogLog returns an estimator of 2^@precision registers, where @precision
+// is in [4, 18].
+func NewHyperLogLog(precision int) *HyperLogLog {
+	if precision < minPrecision || precision > maxPrecision {
+		panic("@precision out of [4, 18]")
+	}
+
+	return &HyperLogLog{p: uint8(precision), registers: make([]uint8, 1<<precision)}
+}
+
+// Precision returns the precision.
+func (h *HyperLogLog) Precision() int {
+	return int(h.p)
+}
+
+// Add adds @key.
+func (h *HyperLogLog) Add(key []byte) {
+	x := hash64(key)
+	idx := x >> (64 - h.p)
+	// the rank of the first set bit of the remaining bits, which are never all zero
+	rank := uint8(bits.LeadingZeros64(x<<h.p|1<<(h.p-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+// AddString adds @key.
+func (h *HyperLogLog) AddString(key string) {
+	h.Add([]byte(key))
+}
+
+// Count returns the estimated number of distinct keys.
+func (h *HyperLogLog) Count() uint64 {
+	var (
+		m     = float64(len(h.registers))
+		sum   float64
+		zeros int
+	)
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha(len(h.registers)) * m * m / sum
+	// linear counting is more accurate for the small cardinalities
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// Merge adds all the keys of @other, which should be of the same precision.
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.p != other.p {
+		return ErrIncompatible
+	}
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+
+	return nil
+}
+
+// Reset removes all the keys.
+func (h *HyperLogLog) Reset() {
+	for i := range h.registers {
+		h.registers[i] = 0
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The data is laid out as:
+//
+//	version(1) | kind(1) 'h' | precision(1) | registers(1) ...
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 3+len(h.registers))
+	data[0] = serialVersion
+	data[1] = kindHyperLogLog
+	data[2] = h.p
+	copy(data[3:], h.registers)
+
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 || data[0] != serialVersion || data[1] != kindHyperLogLog {
+		return ErrInvalidSketch
+	}
+
+	p := data[2]
+	if p < minPrecision || p > maxPrecision || len(data)-3 != 1<<p {
+		return ErrInvalidSketch
+	}
+	for _, r := range data[3:] {
+		if r > 64-p+1 {
+			return ErrInvalidSketch
+		}
+	}
+
+	h.p = p
+	h.registers = append([]uint8(nil), data[3:]...)
+
+	return nil
+}
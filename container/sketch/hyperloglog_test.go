@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsketch
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	for _, n := range []int{10, 1000, 100000} {
+		h := NewHyperLogLog(14)
+		for i := 0; i < n; i++ {
+			h.AddString("caller-" + strconv.Itoa(i))
+			// the duplicates are not counted
+			h.AddString("caller-0")
+		}
+		err := math.Abs(float64(h.Count())-float64(n)) / float64(n)
+		assert.True(t, err < 0.03, "n %d, count %d", n, h.Count())
+	}
+
+	h := NewHyperLogLog(4)
+	assert.Equal(t, uint64(0), h.Count())
+	assert.Panics(t, func() { NewHyperLogLog(3) })
+}
+
+func TestHyperLogLogMergeAndSerialize(t *testing.T) {
+	a, b := NewHyperLogLog(12), NewHyperLogLog(12)
+	for i := 0; i < 5000; i++ {
+		a.AddString(strconv.Itoa(i))
+		b.AddString(strconv.Itoa(i + 2500))
+	}
+
+	data, err := b.MarshalBinary()
+	assert.Nil(t, err)
+	var c HyperLogLog
+	assert.Nil(t, c.UnmarshalBinary(data))
+	assert.Equal(t, b, &c)
+
+	assert.Nil(t, a.Merge(&c))
+	count := a.Count()
+	assert.True(t, count > 7200 && count < 7800, count)
+
+	assert.Equal(t, ErrIncompatible, a.Merge(NewHyperLogLog(10)))
+	assert.Equal(t, ErrInvalidSketch, c.UnmarshalBinary(data[:100]))
+
+	a.Reset()
+	assert.Equal(t, uint64(0), a.Count())
+}
@@ -0,0 +1,358 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxskiplist implements a concurrent ordered map on a lazy skip list.
+// ref: Herlihy et al., A Simple Optimistic Skiplist Algorithm
+package gxskiplist
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+import (
+	gxsort "github.com/dubbogo/gost/sort"
+)
+
+const (
+	maxLevel = 32
+	// 1/levelFactor of the nodes of a level are promoted to the next level
+	levelFactor = 4
+)
+
+type box[V any] struct {
+	v V
+}
+
+type node[K gxsort.Ordered, V any] struct {
+	key         K
+	value       atomic.Value // box[V]
+	next        []unsafe.Pointer
+	lock        sync.Mutex
+	marked      int32 // logically removed
+	fullyLinked int32 // linked at all levels
+}
+
+func newNode[K gxsort.Ordered, V any](key K, value V, level int) *node[K, V] {
+	n := &node[K, V]{key: key, next: make([]unsafe.Pointer, level)}
+	n.value.Store(box[V]{value})
+
+	return n
+}
+
+func (n *node[K, V]) loadNext(level int) *node[K, V] {
+	return (*node[K, V])(atomic.LoadPointer(&n.next[level]))
+}
+
+func (n *node[K, V]) storeNext(level int, next *node[K, V]) {
+	atomic.StorePointer(&n.next[level], unsafe.Pointer(next))
+}
+
+func (n *node[K, V]) load() V {
+	return n.value.Load().(box[V]).v
+}
+
+func (n *node[K, V]) isMarked() bool {
+	return atomic.LoadInt32(&n.marked) == 1
+}
+
+func (n *node[K, V]) isFullyLinked() bool {
+	return atomic.LoadInt32(&n.fullyLinked) == 1
+}
+
+// live reports whether @n is an element of the map.
+func (n *node[K, V]) live() bool {
+	return n.isFullyLinked() && !n.isMarked()
+}
+
+// Map is a concurrent ordered map. Reads never lock, and writes only lock the nodes
+// around the written key, so writes of distant keys do not contend. The zero value
+// is not usable, use New instead.
+type Map[K gxsort.Ordered, V any] struct {
+	length int64       // first for the 64-bit alignment on the 32-bit platforms
+	head   *node[K, V] // sentinel which is less than every key
+}
+
+// New returns an empty Map.
+func New[K gxsort.Ordered, V any]() *Map[K, V] {
+	var (
+		key   K
+		value V
+	)
+
+	return &Map[K, V]{head: newNode(key, value, maxLevel)}
+}
+
+func randomLevel() int {
+	level := 1
+	for level < maxLevel && rand.Intn(levelFactor) == 0 {
+		level++
+	}
+
+	return level
+}
+
+// find fills the predecessors and successors of @key at every level, and returns the
+// highest level at which a node of @key is found, or -1.
+func (m *Map[K, V]) find(key K, preds, succs *[maxLevel]*node[K, V]) int {
+	found := -1
+	pred := m.head
+	for level := maxLevel - 1; level >= 0; level-- {
+		curr := pred.loadNext(level)
+		for curr != nil && curr.key < key {
+			pred, curr = curr, curr.loadNext(level)
+		}
+		if found == -1 && curr != nil && curr.key == key {
+			found = level
+		}
+		preds[level], succs[level] = pred, curr
+	}
+
+	return found
+}
+
+// lower returns the last node whose key is less than @key, which may be the head.
+func (m *Map[K, V]) lower(key K) *node[K, V] {
+	pred := m.head
+	for level := maxLevel - 1; level >= 0; level-- {
+		for curr := pred.loadNext(level); curr != nil && curr.key < key; curr = curr.loadNext(level) {
+			pred = curr
+		}
+	}
+
+	return pred
+}
+
+func unlock[K gxsort.Ordered, V any](preds *[maxLevel]*node[K, V], highest int) {
+	var prev *node[K, V]
+	for level := 0; level <= highest; level++ {
+		if preds[level] != prev {
+			preds[level].lock.Unlock()
+			prev = preds[level]
+		}
+	}
+}
+
+// Load returns the value of @key.
+func (m *Map[K, V]) Load(key K) (V, bool) {
+	if n := m.lower(key).loadNext(0); n != nil && n.key == key && n.live() {
+		return n.load(), true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Store sets the value of @key.
+func (m *Map[K, V]) Store(key K, value V) {
+	m.store(key, value, true)
+}
+
+// LoadOrStore returns the existing value of @key if it is present. Otherwise it stores
+// @value and returns it. @loaded is true if the value is loaded.
+func (m *Map[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	return m.store(key, value, false)
+}
+
+func (m *Map[K, V]) store(key K, value V, overwrite bool) (V, bool) {
+	var preds, succs [maxLevel]*node[K, V]
+	top := randomLevel()
+	for {
+		if found := m.find(key, &preds, &succs); found != -1 {
+			n := succs[found]
+			if n.isMarked() {
+				// it is being removed, retry after it is unlinked
+				continue
+			}
+			for !n.isFullyLinked() {
+				runtime.Gosched()
+			}
+			if !overwrite {
+				return n.load(), true
+			}
+			n.value.Store(box[V]{value})
+			return value, false
+		}
+
+		var (
+			highest = -1
+			valid   = true
+			prev    *node[K, V]
+		)
+		for level := 0; valid && level < top; level++ {
+			pred, succ := preds[level], succs[level]
+			if pred != prev {
+				pred.lock.Lock()
+				highest, prev = level, pred
+			}
+			valid = !pred.isMarked() && (succ == nil || !succ.isMarked()) && pred.loadNext(level) == succ
+		}
+		if !valid {
+			unlock(&preds, highest)
+			continue
+		}
+
+		n := newNode(key, value, top)
+		for level := 0; level < top; level++ {
+			n.next[level] = unsafe.Pointer(succs[level])
+		}
+		for level := 0; level < top; level++ {
+			preds[level].storeNext(level, n)
+		}
+		atomic.StoreInt32(&n.fullyLinked, 1)
+		unlock(&preds, highest)
+		atomic.AddInt64(&m.length, 1)
+
+		return value, false
+	}
+}
+
+// Delete removes @key and reports whether it is present.
+func (m *Map[K, V]) Delete(key K) bool {
+	var (
+		preds, succs [maxLevel]*node[K, V]
+		victim       *node[K, V]
+		marked       bool
+		top          int
+	)
+	for {
+		found := m.find(key, &preds, &succs)
+		if !marked {
+			if found == -1 {
+				return false
+			}
+			victim = succs[found]
+			if !victim.live() || len(victim.next)-1 != found {
+				// being inserted or removed by another goroutine
+				if victim.isMarked() {
+					return false
+				}
+				continue
+			}
+
+			top = len(victim.next)
+			victim.lock.Lock()
+			if victim.isMarked() {
+				victim.lock.Unlock()
+				return false
+			}
+			atomic.StoreInt32(&victim.marked, 1)
+			marked = true
+		}
+
+		var (
+			highest = -1
+			valid   = true
+			prev    *node[K, V]
+		)
+		for level := 0; valid && level < top; level++ {
+			pred := preds[level]
+			if pred != prev {
+				pred.lock.Lock()
+				highest, prev = level, pred
+			}
+			valid = !pred.isMarked() && pred.loadNext(level) == victim
+		}
+		if !valid {
+			unlock(&preds, highest)
+			continue
+		}
+
+		for level := top - 1; level >= 0; level-- {
+			preds[level].storeNext(level, victim.loadNext(level))
+		}
+		victim.lock.Unlock()
+		unlock(&preds, highest)
+		atomic.AddInt64(&m.length, -1)
+
+		return true
+	}
+}
+
+// Len returns the number of keys.
+func (m *Map[K, V]) Len() int {
+	return int(atomic.LoadInt64(&m.length))
+}
+
+// Ceiling returns the least key not less than @key and its value.
+func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
+	for n := m.lower(key).loadNext(0); n != nil; n = n.loadNext(0) {
+		if n.live() {
+			return n.key, n.load(), true
+		}
+	}
+
+	var (
+		zeroK K
+		zeroV V
+	)
+	return zeroK, zeroV, false
+}
+
+// Floor returns the greatest key not greater than @key and its value.
+func (m *Map[K, V]) Floor(key K) (K, V, bool) {
+	if n := m.lower(key).loadNext(0); n != nil && n.key == key && n.live() {
+		return n.key, n.load(), true
+	}
+	if n, ok := m.floorBelow(key); ok {
+		return n.key, n.load(), true
+	}
+
+	var (
+		zeroK K
+		zeroV V
+	)
+	return zeroK, zeroV, false
+}
+
+// floorBelow returns the greatest live node whose key is less than @key.
+func (m *Map[K, V]) floorBelow(key K) (*node[K, V], bool) {
+	for {
+		pred := m.lower(key)
+		if pred == m.head {
+			return nil, false
+		}
+		if pred.live() {
+			return pred, true
+		}
+		// the predecessor is being removed or inserted, step below it
+		key = pred.key
+	}
+}
+
+// Range calls @f for every key in increasing order until it returns false. It is
+// not a snapshot: the keys stored or deleted during the iteration may or may not
+// be visited.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	for n := m.head.loadNext(0); n != nil; n = n.loadNext(0) {
+		if n.live() && !f(n.key, n.load()) {
+			return
+		}
+	}
+}
+
+// Scan calls @f for every key in [@from, @to) in increasing order until it returns false.
+func (m *Map[K, V]) Scan(from, to K, f func(key K, value V) bool) {
+	for n := m.lower(from).loadNext(0); n != nil && n.key < to; n = n.loadNext(0) {
+		if n.live() && !f(n.key, n.load()) {
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxskiplist
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	m := New[int, string]()
+	_, ok := m.Load(1)
+	assert.False(t, ok)
+
+	for _, k := range []int{50, 10, 30, 20, 40} {
+		m.Store(k, "v")
+	}
+	m.Store(30, "thirty")
+	assert.Equal(t, 5, m.Len())
+	v, ok := m.Load(30)
+	assert.True(t, ok)
+	assert.Equal(t, "thirty", v)
+
+	v, loaded := m.LoadOrStore(30, "x")
+	assert.True(t, loaded)
+	assert.Equal(t, "thirty", v)
+	v, loaded = m.LoadOrStore(60, "sixty")
+	assert.False(t, loaded)
+	assert.Equal(t, "sixty", v)
+
+	var keys []int
+	m.Range(func(k int, _ string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{10, 20, 30, 40, 50, 60}, keys)
+
+	keys = keys[:0]
+	m.Scan(20, 50, func(k int, _ string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{20, 30, 40}, keys)
+
+	assert.True(t, m.Delete(30))
+	assert.False(t, m.Delete(30))
+	assert.Equal(t, 5, m.Len())
+
+	k, _, ok := m.Ceiling(30)
+	assert.True(t, ok)
+	assert.Equal(t, 40, k)
+	k, _, ok = m.Floor(30)
+	assert.True(t, ok)
+	assert.Equal(t, 20, k)
+	k, _, _ = m.Floor(40)
+	assert.Equal(t, 40, k)
+	_, _, ok = m.Floor(5)
+	assert.False(t, ok)
+	_, _, ok = m.Ceiling(61)
+	assert.False(t, ok)
+}
+
+func TestMapConcurrent(t *testing.T) {
+	const (
+		P = 8
+		N = 2000
+	)
+	m := New[int, int]()
+
+	var wg sync.WaitGroup
+	for p := 0; p < P; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(p)))
+			for i := 0; i < N; i++ {
+				k := p*N + i
+				m.Store(k, k)
+				if i%2 == 1 {
+					assert.True(t, m.Delete(k))
+				}
+				// concurrent readers of the other keys
+				m.Load(r.Intn(P * N))
+				m.Ceiling(r.Intn(P * N))
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	assert.Equal(t, P*N/2, m.Len())
+	var keys []int
+	m.Range(func(k, v int) bool {
+		assert.Equal(t, k, v)
+		keys = append(keys, k)
+		return true
+	})
+	assert.Len(t, keys, P*N/2)
+	assert.True(t, sort.IntsAreSorted(keys))
+	for _, k := range keys {
+		assert.Equal(t, 0, k%2)
+	}
+}
+
+func TestMapContendedKey(t *testing.T) {
+	m := New[string, int]()
+	var wg sync.WaitGroup
+	for p := 0; p < 8; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				m.Store("k", p)
+				m.Delete("k")
+				m.LoadOrStore("k", p)
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	_, ok := m.Load("k")
+	assert.True(t, ok)
+	assert.Equal(t, 1, m.Len())
+}
@@ -0,0 +1,283 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxslidingwindow aggregates values over a sliding time window, which is a ring
+// of time buckets driven by the gxtime clock.
+package gxslidingwindow
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+// DefaultSamples is the default number of values kept by a bucket for the percentiles.
+const DefaultSamples = 256
+
+type bucket struct {
+	epoch   int64 // index of the time span covered by the bucket since the unix epoch
+	count   int64
+	sum     float64
+	min     float64
+	max     float64
+	samples []float64 // a uniform reservoir sample of the values
+}
+
+func (b *bucket) reset(epoch int64) {
+	b.epoch = epoch
+	b.count = 0
+	b.sum = 0
+	b.min = math.Inf(1)
+	b.max = math.Inf(-1)
+	b.samples = b.samples[:0]
+}
+
+// Option configures a Window.
+type Option func(*Window)
+
+// WithSamples sets the number of values kept by every bucket for Percentile. A
+// non-positive @n disables the percentiles.
+func WithSamples(n int) Option {
+	return func(w *Window) {
+		w.maxSamples = n
+	}
+}
+
+// Window aggregates the values added in the last @buckets spans of @span, so that the
+// oldest span drops out of the aggregates as a new one begins. It is goroutine safe.
+type Window struct {
+	lock       sync.Mutex
+	span       time.Duration
+	buckets    []bucket
+	maxSamples int
+	rand       *rand.Rand
+}
+
+// New returns a window of @buckets buckets of @span, which covers @buckets * @span.
+func New(buckets int, span time.Duration, opts ...Option) *Window {
+	if buckets <= 0 {
+		panic("@buckets <= 0")
+	}
+	if span <= 0 {
+		panic("@span <= 0")
+	}
+
+	w := &Window{
+		span:       span,
+		buckets:    make([]bucket, buckets),
+		maxSamples: DefaultSamples,
+		rand:       rand.New(rand.NewSource(gxtime.Now().UnixNano())),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	for i := range w.buckets {
+		w.buckets[i].reset(math.MinInt64)
+	}
+
+	return w
+}
+
+// Size returns the time covered by the window.
+func (w *Window) Size() time.Duration {
+	return w.span * time.Duration(len(w.buckets))
+}
+
+// current returns the epoch of now and its bucket, which is reset if it is stale.
+// It should be invoked with the lock held.
+func (w *Window) current() (int64, *bucket) {
+	epoch := gxtime.Now().UnixNano() / int64(w.span)
+	b := &w.buckets[int(uint64(epoch)%uint64(len(w.buckets)))]
+	if b.epoch != epoch {
+		b.reset(epoch)
+	}
+
+	return epoch, b
+}
+
+// Add adds @v into the current bucket.
+func (w *Window) Add(v float64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	_, b := w.current()
+	b.count++
+	b.sum += v
+	if v < b.min {
+		b.min = v
+	}
+	if v > b.max {
+		b.max = v
+	}
+
+	if w.maxSamples <= 0 {
+		return
+	}
+	if len(b.samples) < w.maxSamples {
+		b.samples = append(b.samples, v)
+	} else if i := w.rand.Int63n(b.count); i < int64(w.maxSamples) {
+		b.samples[i] = v
+	}
+}
+
+// each calls @f with every bucket in the window. It should be invoked with the lock held.
+func (w *Window) each(f func(b *bucket)) {
+	epoch, _ := w.current()
+	oldest := epoch - int64(len(w.buckets)) + 1
+	for i := range w.buckets {
+		if b := &w.buckets[i]; b.epoch >= oldest && b.epoch <= epoch && b.count > 0 {
+			f(b)
+		}
+	}
+}
+
+// Count returns the number of values added in the window.
+func (w *Window) Count() int64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var count int64
+	w.each(func(b *bucket) { count += b.count })
+
+	return count
+}
+
+// Sum returns the sum of the values added in the window.
+func (w *Window) Sum() float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var sum float64
+	w.each(func(b *bucket) { sum += b.sum })
+
+	return sum
+}
+
+// Avg returns the mean of the values added in the window, or 0 if it is empty.
+func (w *Window) Avg() float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var (
+		count int64
+		sum   float64
+	)
+	w.each(func(b *bucket) {
+		count += b.count
+		sum += b.sum
+	})
+	if count == 0 {
+		return 0
+	}
+
+	return sum / float64(count)
+}
+
+// Max returns the largest value added in the window, or 0 if it is empty.
+func (w *Window) Max() float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	max := math.Inf(-1)
+	w.each(func(b *bucket) { max = math.Max(max, b.max) })
+	if math.IsInf(max, -1) {
+		return 0
+	}
+
+	return max
+}
+
+// Min returns the smallest value added in the window, or 0 if it is empty.
+func (w *Window) Min() float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	min := math.Inf(1)
+	w.each(func(b *bucket) { min = math.Min(min, b.min) })
+	if math.IsInf(min, 1) {
+		return 0
+	}
+
+	return min
+}
+
+// Percentile returns the @p-th percentile of the values in the window, 0 <= @p <= 100,
+// or 0 if it is empty. It is estimated by the samples of the buckets, weighting every
+// sample by the count of its bucket, and is exact while no bucket drops samples.
+func (w *Window) Percentile(p float64) float64 {
+	if p < 0 || p > 100 {
+		panic("@p out of [0, 100]")
+	}
+
+	type weighted struct {
+		v      float64
+		weight float64
+	}
+
+	w.lock.Lock()
+	var (
+		samples []weighted
+		total   float64
+	)
+	w.each(func(b *bucket) {
+		if len(b.samples) == 0 {
+			return
+		}
+		weight := float64(b.count) / float64(len(b.samples))
+		for _, v := range b.samples {
+			samples = append(samples, weighted{v: v, weight: weight})
+		}
+		total += float64(b.count)
+	})
+	w.lock.Unlock()
+
+	if len(samples) == 0 {
+		return 0
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].v < samples[j].v })
+
+	// the nearest rank of the weighted samples
+	rank := math.Ceil(p / 100 * total)
+	if rank < 1 {
+		rank = 1
+	}
+	var seen float64
+	for _, s := range samples {
+		seen += s.weight
+		if seen >= rank-1e-9 {
+			return s.v
+		}
+	}
+
+	return samples[len(samples)-1].v
+}
+
+// Reset drops all the values.
+func (w *Window) Reset() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for i := range w.buckets {
+		w.buckets[i].reset(math.MinInt64)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxslidingwindow
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+type fakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	c.lock.Unlock()
+}
+
+func useFakeClock(t *testing.T) *fakeClock {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	gxtime.SetTimeSource(clock.Now)
+	t.Cleanup(func() { gxtime.SetTimeSource(nil) })
+
+	return clock
+}
+
+func TestWindowSlides(t *testing.T) {
+	clock := useFakeClock(t)
+	w := New(4, time.Second)
+	assert.Equal(t, 4*time.Second, w.Size())
+	assert.Equal(t, int64(0), w.Count())
+	assert.Equal(t, 0.0, w.Avg())
+	assert.Equal(t, 0.0, w.Max())
+	assert.Equal(t, 0.0, w.Percentile(99))
+
+	for i := 1; i <= 4; i++ {
+		w.Add(float64(i))
+		w.Add(float64(i))
+		clock.Add(time.Second)
+	}
+	// the first bucket has dropped out
+	assert.Equal(t, int64(6), w.Count())
+	assert.Equal(t, 18.0, w.Sum())
+	assert.Equal(t, 3.0, w.Avg())
+	assert.Equal(t, 2.0, w.Min())
+	assert.Equal(t, 4.0, w.Max())
+
+	clock.Add(2 * time.Second)
+	assert.Equal(t, int64(2), w.Count())
+	assert.Equal(t, 4.0, w.Min())
+
+	clock.Add(time.Hour)
+	assert.Equal(t, int64(0), w.Count())
+	w.Add(7)
+	assert.Equal(t, 7.0, w.Sum())
+	w.Reset()
+	assert.Equal(t, int64(0), w.Count())
+}
+
+func TestWindowPercentile(t *testing.T) {
+	clock := useFakeClock(t)
+	w := New(10, 100*time.Millisecond)
+	for i := 1; i <= 100; i++ {
+		w.Add(float64(i))
+		if i%10 == 0 {
+			clock.Add(100 * time.Millisecond)
+		}
+	}
+	clock.Add(-100 * time.Millisecond)
+
+	assert.Equal(t, 1.0, w.Percentile(0))
+	assert.Equal(t, 50.0, w.Percentile(50))
+	assert.Equal(t, 99.0, w.Percentile(99))
+	assert.Equal(t, 100.0, w.Percentile(100))
+	assert.Panics(t, func() { w.Percentile(101) })
+
+	// the estimate by samples stays near the truth
+	sampled := New(1, time.Minute, WithSamples(64))
+	for i := 1; i <= 10000; i++ {
+		sampled.Add(float64(i))
+	}
+	assert.InDelta(t, 5000, sampled.Percentile(50), 1500)
+	assert.Equal(t, int64(10000), sampled.Count())
+
+	off := New(1, time.Minute, WithSamples(0))
+	off.Add(1)
+	assert.Equal(t, 0.0, off.Percentile(50))
+	assert.Equal(t, 1.0, off.Max())
+}
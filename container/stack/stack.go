@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxstack implements a LIFO stack on a slice and a lock-free Treiber stack.
+package gxstack
+
+// Stack is a LIFO stack on a growable slice. The zero value is an empty stack ready to
+// use. It is not goroutine safe, see TreiberStack for that.
+type Stack[T any] struct {
+	items []T
+}
+
+// New returns a stack with room for @capacity items.
+func New[T any](capacity int) *Stack[T] {
+	return &Stack[T]{items: make([]T, 0, capacity)}
+}
+
+// Push pushes @items in order, so the last one is on the top.
+func (s *Stack[T]) Push(items ...T) {
+	s.items = append(s.items, items...)
+}
+
+// Pop removes and returns the top item.
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+
+	n := len(s.items)
+	if n == 0 {
+		return zero, false
+	}
+	item := s.items[n-1]
+	s.items[n-1] = zero // drops the reference
+	s.items = s.items[:n-1]
+
+	return item, true
+}
+
+// Peek returns the top item without removing it.
+func (s *Stack[T]) Peek() (T, bool) {
+	if n := len(s.items); n > 0 {
+		return s.items[n-1], true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// Len returns the number of items.
+func (s *Stack[T]) Len() int {
+	return len(s.items)
+}
+
+// Range calls @f with the items from the top until @f returns false. @f must not
+// modify the stack.
+func (s *Stack[T]) Range(f func(item T) bool) {
+	for i := len(s.items) - 1; i >= 0; i-- {
+		if !f(s.items[i]) {
+			return
+		}
+	}
+}
+
+// Drain removes all items and returns them from the top. The stack keeps its room.
+func (s *Stack[T]) Drain() []T {
+	var zero T
+
+	n := len(s.items)
+	drained := make([]T, n)
+	for i := range drained {
+		drained[i] = s.items[n-1-i]
+		s.items[n-1-i] = zero
+	}
+	s.items = s.items[:0]
+
+	return drained
+}
@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxstack
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStack(t *testing.T) {
+	var s Stack[int]
+	_, ok := s.Pop()
+	assert.False(t, ok)
+	_, ok = s.Peek()
+	assert.False(t, ok)
+
+	s.Push(1, 2, 3)
+	assert.Equal(t, 3, s.Len())
+	v, ok := s.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	v, _ = s.Pop()
+	assert.Equal(t, 3, v)
+
+	var items []int
+	s.Push(4)
+	s.Range(func(item int) bool {
+		items = append(items, item)
+		return item != 2
+	})
+	assert.Equal(t, []int{4, 2}, items)
+
+	assert.Equal(t, []int{4, 2, 1}, s.Drain())
+	assert.Equal(t, 0, s.Len())
+	assert.Empty(t, s.Drain())
+
+	p := New[*int](4)
+	x := 1
+	p.Push(&x)
+	p.Pop()
+	assert.Nil(t, p.items[:cap(p.items)][0]) // the popped slot drops its reference
+}
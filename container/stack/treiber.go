@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxstack
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+type treiberNode[T any] struct {
+	item T
+	next *treiberNode[T]
+}
+
+// TreiberStack is a lock-free LIFO stack, ref: R. Kent Treiber's stack, e.g. for the
+// freelists shared by goroutines. Every Push allocates a new node, so the garbage
+// collector rules out the ABA problem. The zero value is an empty stack ready to use.
+type TreiberStack[T any] struct {
+	len int64          // first for the 64-bit alignment on the 32-bit platforms
+	top unsafe.Pointer // *treiberNode[T]
+}
+
+// Push pushes @item on the top.
+func (s *TreiberStack[T]) Push(item T) {
+	n := &treiberNode[T]{item: item}
+	for {
+		top := atomic.LoadPointer(&s.top)
+		n.next = (*treiberNode[T])(top)
+		if atomic.CompareAndSwapPointer(&s.top, top, unsafe.Pointer(n)) {
+			atomic.AddInt64(&s.len, 1)
+			return
+		}
+	}
+}
+
+// Pop removes and returns the top item.
+func (s *TreiberStack[T]) Pop() (T, bool) {
+	for {
+		top := atomic.LoadPointer(&s.top)
+		if top == nil {
+			var zero T
+			return zero, false
+		}
+		n := (*treiberNode[T])(top)
+		if atomic.CompareAndSwapPointer(&s.top, top, unsafe.Pointer(n.next)) {
+			atomic.AddInt64(&s.len, -1)
+			return n.item, true
+		}
+	}
+}
+
+// Peek returns the top item without removing it.
+func (s *TreiberStack[T]) Peek() (T, bool) {
+	if n := (*treiberNode[T])(atomic.LoadPointer(&s.top)); n != nil {
+		return n.item, true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// Len returns the number of items, which may be off by the pushes and pops in flight.
+func (s *TreiberStack[T]) Len() int {
+	if n := atomic.LoadInt64(&s.len); n > 0 {
+		return int(n)
+	}
+
+	return 0
+}
+
+// Drain removes all items at once and returns them from the top.
+func (s *TreiberStack[T]) Drain() []T {
+	var drained []T
+	for n := (*treiberNode[T])(atomic.SwapPointer(&s.top, nil)); n != nil; n = n.next {
+		drained = append(drained, n.item)
+	}
+	atomic.AddInt64(&s.len, -int64(len(drained)))
+
+	return drained
+}
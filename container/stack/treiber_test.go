@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxstack
+
+import (
+	"sort"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreiberStack(t *testing.T) {
+	var s TreiberStack[string]
+	_, ok := s.Pop()
+	assert.False(t, ok)
+
+	s.Push("a")
+	s.Push("b")
+	v, ok := s.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, "b", v)
+	assert.Equal(t, 2, s.Len())
+	v, _ = s.Pop()
+	assert.Equal(t, "b", v)
+
+	s.Push("c")
+	assert.Equal(t, []string{"c", "a"}, s.Drain())
+	assert.Equal(t, 0, s.Len())
+	_, ok = s.Peek()
+	assert.False(t, ok)
+}
+
+func TestTreiberStackConcurrent(t *testing.T) {
+	const (
+		goroutines = 8
+		n          = 1000
+	)
+
+	var (
+		s    TreiberStack[int]
+		wg   sync.WaitGroup
+		lock sync.Mutex
+		got  []int
+	)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			var popped []int
+			for i := 0; i < n; i++ {
+				s.Push(g*n + i)
+				if i%2 == 1 {
+					v, ok := s.Pop()
+					assert.True(t, ok)
+					popped = append(popped, v)
+				}
+			}
+			lock.Lock()
+			got = append(got, popped...)
+			lock.Unlock()
+		}(g)
+	}
+	wg.Wait()
+
+	got = append(got, s.Drain()...)
+	sort.Ints(got)
+	assert.Len(t, got, goroutines*n)
+	for i, v := range got {
+		assert.Equal(t, i, v)
+	}
+}
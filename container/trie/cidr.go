@@ -0,0 +1,175 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtrie
+
+import (
+	"net"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+type cidrNode[V any] struct {
+	children [2]*cidrNode[V]
+	prefix   *net.IPNet
+	value    V
+	has      bool
+}
+
+// CIDRTrie maps the IPv4 and IPv6 CIDR blocks to values, and looks up an IP by the
+// most specific block containing it, e.g. for ACL matching. The IPv4-mapped IPv6
+// addresses are treated as IPv4 ones.
+type CIDRTrie[V any] struct {
+	v4, v6 cidrNode[V]
+	size   int
+}
+
+// NewCIDRTrie returns an empty trie.
+func NewCIDRTrie[V any]() *CIDRTrie[V] {
+	return &CIDRTrie[V]{}
+}
+
+// normalize returns the bytes of @ip in its shortest form.
+func normalize(ip net.IP) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip.To16()
+}
+
+func (t *CIDRTrie[V]) rootOf(ip net.IP) *cidrNode[V] {
+	if len(ip) == net.IPv4len {
+		return &t.v4
+	}
+	return &t.v6
+}
+
+func bit(ip net.IP, i int) int {
+	return int(ip[i/8]>>(7-uint(i)%8)) & 1
+}
+
+// Len returns the number of blocks.
+func (t *CIDRTrie[V]) Len() int {
+	return t.size
+}
+
+// Insert maps the block @cidr, such as "10.0.0.0/8", to @value.
+func (t *CIDRTrie[V]) Insert(cidr string, value V) error {
+	_, prefix, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+
+	t.InsertNet(prefix, value)
+	return nil
+}
+
+// InsertNet maps the block @prefix to @value, and returns false if it replaces an
+// existing value.
+func (t *CIDRTrie[V]) InsertNet(prefix *net.IPNet, value V) bool {
+	ip, ones := t.key(prefix)
+	n := t.rootOf(ip)
+	for i := 0; i < ones; i++ {
+		b := bit(ip, i)
+		if n.children[b] == nil {
+			n.children[b] = &cidrNode[V]{}
+		}
+		n = n.children[b]
+	}
+
+	added := !n.has
+	if added {
+		t.size++
+	}
+	n.prefix = &net.IPNet{IP: ip.Mask(net.CIDRMask(ones, len(ip)*8)), Mask: net.CIDRMask(ones, len(ip)*8)}
+	n.value, n.has = value, true
+
+	return added
+}
+
+// key returns the normalized ip and prefix length of @prefix.
+func (t *CIDRTrie[V]) key(prefix *net.IPNet) (net.IP, int) {
+	ip := normalize(prefix.IP)
+	ones, bits := prefix.Mask.Size()
+	// an IPv4 block written as an IPv4-mapped IPv6 one
+	if len(ip) == net.IPv4len && bits == 8*net.IPv6len {
+		ones -= 8 * (net.IPv6len - net.IPv4len)
+		if ones < 0 {
+			ones = 0
+		}
+	}
+
+	return ip, ones
+}
+
+// Delete removes the block @cidr, and returns false if it does not exist.
+func (t *CIDRTrie[V]) Delete(cidr string) bool {
+	_, prefix, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+
+	ip, ones := t.key(prefix)
+	n := t.rootOf(ip)
+	for i := 0; i < ones && n != nil; i++ {
+		n = n.children[bit(ip, i)]
+	}
+	if n == nil || !n.has {
+		return false
+	}
+
+	var zero V
+	n.prefix, n.value, n.has = nil, zero, false
+	t.size--
+
+	return true
+}
+
+// Lookup returns the most specific block containing @ip and its value.
+func (t *CIDRTrie[V]) Lookup(ip net.IP) (*net.IPNet, V, bool) {
+	var (
+		best *cidrNode[V]
+		key  = normalize(ip)
+	)
+	if key != nil {
+		n := t.rootOf(key)
+		for i := 0; n != nil; i++ {
+			if n.has {
+				best = n
+			}
+			if i == len(key)*8 {
+				break
+			}
+			n = n.children[bit(key, i)]
+		}
+	}
+
+	if best == nil {
+		var zero V
+		return nil, zero, false
+	}
+
+	return best.prefix, best.value, true
+}
+
+// Contains reports whether @ip is in any block.
+func (t *CIDRTrie[V]) Contains(ip net.IP) bool {
+	_, _, ok := t.Lookup(ip)
+	return ok
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtrie
+
+import (
+	"net"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCIDRTrie(t *testing.T) {
+	trie := NewCIDRTrie[string]()
+	assert.Nil(t, trie.Insert("10.0.0.0/8", "private"))
+	assert.Nil(t, trie.Insert("10.1.0.0/16", "office"))
+	assert.Nil(t, trie.Insert("0.0.0.0/0", "any4"))
+	assert.Nil(t, trie.Insert("2001:db8::/32", "doc"))
+	assert.NotNil(t, trie.Insert("10.0.0.0/33", ""))
+	assert.Equal(t, 4, trie.Len())
+
+	prefix, v, ok := trie.Lookup(net.ParseIP("10.1.2.3"))
+	assert.True(t, ok)
+	assert.Equal(t, "office", v)
+	assert.Equal(t, "10.1.0.0/16", prefix.String())
+
+	_, v, _ = trie.Lookup(net.ParseIP("10.2.2.3"))
+	assert.Equal(t, "private", v)
+	_, v, _ = trie.Lookup(net.ParseIP("192.168.1.1"))
+	assert.Equal(t, "any4", v)
+	// an IPv4-mapped IPv6 address
+	_, v, _ = trie.Lookup(net.ParseIP("::ffff:10.1.0.1"))
+	assert.Equal(t, "office", v)
+
+	_, v, ok = trie.Lookup(net.ParseIP("2001:db8::1"))
+	assert.True(t, ok)
+	assert.Equal(t, "doc", v)
+	assert.False(t, trie.Contains(net.ParseIP("2001:db9::1")))
+	assert.False(t, trie.Contains(nil))
+
+	assert.True(t, trie.Delete("10.1.0.0/16"))
+	assert.False(t, trie.Delete("10.1.0.0/16"))
+	assert.False(t, trie.Delete("bad"))
+	_, v, _ = trie.Lookup(net.ParseIP("10.1.2.3"))
+	assert.Equal(t, "private", v)
+	assert.Equal(t, 3, trie.Len())
+}
+
+func TestCIDRTrieHost(t *testing.T) {
+	trie := NewCIDRTrie[int]()
+	assert.Nil(t, trie.Insert("192.168.1.7/32", 1))
+	assert.Nil(t, trie.Insert("192.168.1.0/24", 2))
+	_, v, _ := trie.Lookup(net.ParseIP("192.168.1.7"))
+	assert.Equal(t, 1, v)
+	_, v, _ = trie.Lookup(net.ParseIP("192.168.1.8"))
+	assert.Equal(t, 2, v)
+}
@@ -0,0 +1,243 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxtrie implements prefix tries for routing: PathTrie routes slash separated
+// service paths with wildcard segments, and CIDRTrie matches IP addresses against
+// CIDR blocks. Both look up by the longest prefix, and neither is safe for concurrent
+// writes.
+package gxtrie
+
+import (
+	"strings"
+)
+
+// Match is the result of a PathTrie lookup.
+type Match[V any] struct {
+	Pattern string
+	Value   V
+	Params  map[string]string // the segments captured by the named wildcards
+}
+
+type pathNode[V any] struct {
+	static   map[string]*pathNode[V]
+	param    *pathNode[V] // ":name" matches a single segment
+	catchAll *pathNode[V] // "*name" matches all the remaining segments
+	name     string       // the wildcard name of a param or catch-all node
+	pattern  string
+	value    V
+	has      bool
+}
+
+// PathTrie maps the path patterns to values. A pattern consists of slash separated
+// segments, where ":name" matches any single segment and a final "*name" matches all
+// the remaining ones, and the name of a wildcard can be empty. On a lookup the static
+// segments take precedence over ":" ones, which take precedence over "*" ones.
+type PathTrie[V any] struct {
+	root pathNode[V]
+	size int
+}
+
+// NewPathTrie returns an empty trie.
+func NewPathTrie[V any]() *PathTrie[V] {
+	return &PathTrie[V]{}
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+// Len returns the number of patterns.
+func (t *PathTrie[V]) Len() int {
+	return t.size
+}
+
+// Insert maps @pattern to @value, and returns false if it replaces an existing value.
+// The wildcards of different names at the same position are the same wildcard, which
+// keeps the name inserted first.
+func (t *PathTrie[V]) Insert(pattern string, value V) bool {
+	var (
+		n    = &t.root
+		segs = splitPath(pattern)
+	)
+	for i, seg := range segs {
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			if n.param == nil {
+				n.param = &pathNode[V]{name: seg[1:]}
+			}
+			n = n.param
+		case strings.HasPrefix(seg, "*"):
+			if i != len(segs)-1 {
+				panic("catch-all segment " + seg + " is not the last one of " + pattern)
+			}
+			if n.catchAll == nil {
+				n.catchAll = &pathNode[V]{name: seg[1:]}
+			}
+			n = n.catchAll
+		default:
+			if n.static == nil {
+				n.static = make(map[string]*pathNode[V])
+			}
+			child, ok := n.static[seg]
+			if !ok {
+				child = &pathNode[V]{}
+				n.static[seg] = child
+			}
+			n = child
+		}
+	}
+
+	added := !n.has
+	if added {
+		t.size++
+	}
+	n.pattern, n.value, n.has = "/"+strings.Join(segs, "/"), value, true
+
+	return added
+}
+
+// Get returns the value of exactly @pattern.
+func (t *PathTrie[V]) Get(pattern string) (V, bool) {
+	if n := t.find(pattern); n != nil && n.has {
+		return n.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Delete removes @pattern, and returns false if it does not exist. The emptied nodes
+// are kept for later inserts.
+func (t *PathTrie[V]) Delete(pattern string) bool {
+	n := t.find(pattern)
+	if n == nil || !n.has {
+		return false
+	}
+
+	var zero V
+	n.value, n.has, n.pattern = zero, false, ""
+	t.size--
+
+	return true
+}
+
+func (t *PathTrie[V]) find(pattern string) *pathNode[V] {
+	n := &t.root
+	for _, seg := range splitPath(pattern) {
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			n = n.param
+		case strings.HasPrefix(seg, "*"):
+			n = n.catchAll
+		default:
+			n = n.static[seg]
+		}
+		if n == nil {
+			return nil
+		}
+	}
+
+	return n
+}
+
+// Lookup returns the pattern matching the whole @path.
+func (t *PathTrie[V]) Lookup(path string) (Match[V], bool) {
+	segs := splitPath(path)
+	var best *pathNode[V]
+	var bestParams []string
+	search(&t.root, segs, 0, nil, func(n *pathNode[V], depth int, params []string) bool {
+		if depth == len(segs) {
+			best, bestParams = n, params
+			return true
+		}
+		return false
+	})
+
+	return result(best, bestParams)
+}
+
+// LongestPrefix returns the pattern matching the most leading segments of @path.
+func (t *PathTrie[V]) LongestPrefix(path string) (Match[V], bool) {
+	var (
+		segs       = splitPath(path)
+		best       *pathNode[V]
+		bestParams []string
+		bestDepth  = -1
+	)
+	search(&t.root, segs, 0, nil, func(n *pathNode[V], depth int, params []string) bool {
+		if depth > bestDepth {
+			best, bestParams, bestDepth = n, params, depth
+		}
+		return depth == len(segs)
+	})
+
+	return result(best, bestParams)
+}
+
+func result[V any](n *pathNode[V], params []string) (Match[V], bool) {
+	if n == nil {
+		return Match[V]{}, false
+	}
+
+	m := Match[V]{Pattern: n.pattern, Value: n.value}
+	if len(params) > 0 {
+		m.Params = make(map[string]string, len(params)/2)
+		for i := 0; i < len(params); i += 2 {
+			m.Params[params[i]] = params[i+1]
+		}
+	}
+
+	return m, true
+}
+
+// search visits the nodes with values matching the leading @depth segments in the order
+// of precedence, until @visit returns true. @params holds the captured name value pairs.
+func search[V any](n *pathNode[V], segs []string, depth int, params []string,
+	visit func(n *pathNode[V], depth int, params []string) bool) bool {
+	if n.has && visit(n, depth, params) {
+		return true
+	}
+	if depth == len(segs) {
+		return false
+	}
+
+	seg := segs[depth]
+	if child, ok := n.static[seg]; ok {
+		if search(child, segs, depth+1, params, visit) {
+			return true
+		}
+	}
+	if n.param != nil {
+		captured := append(params[:len(params):len(params)], n.param.name, seg)
+		if search(n.param, segs, depth+1, captured, visit) {
+			return true
+		}
+	}
+	if c := n.catchAll; c != nil && c.has {
+		captured := append(params[:len(params):len(params)], c.name, strings.Join(segs[depth:], "/"))
+		if visit(c, len(segs), captured) {
+			return true
+		}
+	}
+
+	return false
+}
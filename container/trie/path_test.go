@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtrie
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathTrieLookup(t *testing.T) {
+	trie := NewPathTrie[string]()
+	assert.True(t, trie.Insert("/users", "list"))
+	assert.True(t, trie.Insert("/users/:id", "get"))
+	assert.True(t, trie.Insert("/users/me", "me"))
+	assert.True(t, trie.Insert("/users/:id/orders/:order", "order"))
+	assert.True(t, trie.Insert("/static/*file", "static"))
+	assert.False(t, trie.Insert("users/me/", "me2"))
+	assert.Equal(t, 5, trie.Len())
+
+	m, ok := trie.Lookup("/users/me")
+	assert.True(t, ok)
+	assert.Equal(t, "me2", m.Value)
+	assert.Nil(t, m.Params)
+
+	m, ok = trie.Lookup("/users/42")
+	assert.True(t, ok)
+	assert.Equal(t, "/users/:id", m.Pattern)
+	assert.Equal(t, map[string]string{"id": "42"}, m.Params)
+
+	m, ok = trie.Lookup("/users/42/orders/7")
+	assert.True(t, ok)
+	assert.Equal(t, "order", m.Value)
+	assert.Equal(t, map[string]string{"id": "42", "order": "7"}, m.Params)
+
+	// backtracks from the static segment to the wildcard
+	m, ok = trie.Lookup("/users/me/orders/7")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"id": "me", "order": "7"}, m.Params)
+
+	m, ok = trie.Lookup("/static/css/site.css")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"file": "css/site.css"}, m.Params)
+
+	_, ok = trie.Lookup("/static")
+	assert.False(t, ok)
+	_, ok = trie.Lookup("/users/42/orders")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { trie.Insert("/a/*rest/b", "") })
+}
+
+func TestPathTrieLongestPrefix(t *testing.T) {
+	trie := NewPathTrie[int]()
+	trie.Insert("/", 0)
+	trie.Insert("/com.foo.Service", 1)
+	trie.Insert("/com.foo.Service/:method/v1", 2)
+
+	m, ok := trie.LongestPrefix("/com.foo.Service/Hello/v1/extra")
+	assert.True(t, ok)
+	assert.Equal(t, 2, m.Value)
+	assert.Equal(t, "Hello", m.Params["method"])
+
+	m, _ = trie.LongestPrefix("/com.foo.Service/Hello/v2")
+	assert.Equal(t, 1, m.Value)
+	m, _ = trie.LongestPrefix("/other")
+	assert.Equal(t, "/", m.Pattern)
+
+	v, ok := trie.Get("/com.foo.Service/:x/v1")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	assert.True(t, trie.Delete("/"))
+	assert.False(t, trie.Delete("/"))
+	assert.False(t, trie.Delete("/none"))
+	_, ok = trie.LongestPrefix("/other")
+	assert.False(t, ok)
+	assert.Equal(t, 2, trie.Len())
+}
@@ -0,0 +1,221 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxtuple implements generic pairs and triples, which are compared
+// lexicographically and encoded as JSON arrays.
+package gxtuple
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+import (
+	gxsort "github.com/dubbogo/gost/sort"
+)
+
+func compare[T gxsort.Ordered](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case b < a:
+		return 1
+	}
+
+	return 0
+}
+
+// unmarshalArray decodes the JSON array @data of exactly len(@elems) elements into @elems.
+func unmarshalArray(data []byte, elems ...interface{}) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return err
+	}
+	if len(raws) != len(elems) {
+		return fmt.Errorf("gxtuple: %d elements for a tuple of %d", len(raws), len(elems))
+	}
+	for i, raw := range raws {
+		if err := json.Unmarshal(raw, elems[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Pair is a tuple of two values.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// NewPair returns the pair of @a and @b.
+func NewPair[A, B any](a A, b B) Pair[A, B] {
+	return Pair[A, B]{First: a, Second: b}
+}
+
+// Values returns the elements of the pair.
+func (p Pair[A, B]) Values() (A, B) {
+	return p.First, p.Second
+}
+
+// Swap returns the pair with its elements swapped.
+func (p Pair[A, B]) Swap() Pair[B, A] {
+	return Pair[B, A]{First: p.Second, Second: p.First}
+}
+
+func (p Pair[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+}
+
+// MarshalJSON encodes the pair as an array of two elements.
+func (p Pair[A, B]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{p.First, p.Second})
+}
+
+// UnmarshalJSON decodes an array of two elements.
+func (p *Pair[A, B]) UnmarshalJSON(data []byte) error {
+	var q Pair[A, B]
+	if err := unmarshalArray(data, &q.First, &q.Second); err != nil {
+		return err
+	}
+	*p = q
+
+	return nil
+}
+
+// ComparePairs returns -1, 0 or 1 as @x is less than, equal to or greater than @y,
+// ordered by First and then by Second.
+func ComparePairs[A, B gxsort.Ordered](x, y Pair[A, B]) int {
+	if c := compare(x.First, y.First); c != 0 {
+		return c
+	}
+
+	return compare(x.Second, y.Second)
+}
+
+// LessPair reports whether @x is ordered before @y, e.g. for sort.Slice.
+func LessPair[A, B gxsort.Ordered](x, y Pair[A, B]) bool {
+	return ComparePairs(x, y) < 0
+}
+
+// Triple is a tuple of three values.
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// NewTriple returns the triple of @a, @b and @c.
+func NewTriple[A, B, C any](a A, b B, c C) Triple[A, B, C] {
+	return Triple[A, B, C]{First: a, Second: b, Third: c}
+}
+
+// Values returns the elements of the triple.
+func (t Triple[A, B, C]) Values() (A, B, C) {
+	return t.First, t.Second, t.Third
+}
+
+func (t Triple[A, B, C]) String() string {
+	return fmt.Sprintf("(%v, %v, %v)", t.First, t.Second, t.Third)
+}
+
+// MarshalJSON encodes the triple as an array of three elements.
+func (t Triple[A, B, C]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{t.First, t.Second, t.Third})
+}
+
+// UnmarshalJSON decodes an array of three elements.
+func (t *Triple[A, B, C]) UnmarshalJSON(data []byte) error {
+	var q Triple[A, B, C]
+	if err := unmarshalArray(data, &q.First, &q.Second, &q.Third); err != nil {
+		return err
+	}
+	*t = q
+
+	return nil
+}
+
+// CompareTriples returns -1, 0 or 1 as @x is less than, equal to or greater than @y,
+// ordered by First, then by Second and then by Third.
+func CompareTriples[A, B, C gxsort.Ordered](x, y Triple[A, B, C]) int {
+	if c := compare(x.First, y.First); c != 0 {
+		return c
+	}
+	if c := compare(x.Second, y.Second); c != 0 {
+		return c
+	}
+
+	return compare(x.Third, y.Third)
+}
+
+// LessTriple reports whether @x is ordered before @y.
+func LessTriple[A, B, C gxsort.Ordered](x, y Triple[A, B, C]) bool {
+	return CompareTriples(x, y) < 0
+}
+
+// Zip pairs up the elements of @as and @bs at the same index, up to the shorter one.
+func Zip[A, B any](as []A, bs []B) []Pair[A, B] {
+	n := len(as)
+	if len(bs) < n {
+		n = len(bs)
+	}
+	pairs := make([]Pair[A, B], n)
+	for i := range pairs {
+		pairs[i] = Pair[A, B]{First: as[i], Second: bs[i]}
+	}
+
+	return pairs
+}
+
+// Unzip splits @pairs into the slices of their first and second elements.
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	as, bs := make([]A, len(pairs)), make([]B, len(pairs))
+	for i, p := range pairs {
+		as[i], bs[i] = p.First, p.Second
+	}
+
+	return as, bs
+}
+
+// Zip3 makes triples of the elements of @as, @bs and @cs at the same index, up to the
+// shortest one.
+func Zip3[A, B, C any](as []A, bs []B, cs []C) []Triple[A, B, C] {
+	n := len(as)
+	if len(bs) < n {
+		n = len(bs)
+	}
+	if len(cs) < n {
+		n = len(cs)
+	}
+	triples := make([]Triple[A, B, C], n)
+	for i := range triples {
+		triples[i] = Triple[A, B, C]{First: as[i], Second: bs[i], Third: cs[i]}
+	}
+
+	return triples
+}
+
+// Unzip3 splits @triples into the slices of their elements.
+func Unzip3[A, B, C any](triples []Triple[A, B, C]) ([]A, []B, []C) {
+	as, bs, cs := make([]A, len(triples)), make([]B, len(triples)), make([]C, len(triples))
+	for i, t := range triples {
+		as[i], bs[i], cs[i] = t.First, t.Second, t.Third
+	}
+
+	return as, bs, cs
+}
@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtuple
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPair(t *testing.T) {
+	p := NewPair("a", 1)
+	a, b := p.Values()
+	assert.Equal(t, "a", a)
+	assert.Equal(t, 1, b)
+	assert.Equal(t, NewPair(1, "a"), p.Swap())
+	assert.Equal(t, "(a, 1)", p.String())
+
+	data, err := json.Marshal(p)
+	assert.Nil(t, err)
+	assert.Equal(t, `["a",1]`, string(data))
+	var q Pair[string, int]
+	assert.Nil(t, json.Unmarshal(data, &q))
+	assert.Equal(t, p, q)
+	assert.NotNil(t, json.Unmarshal([]byte(`["a"]`), &q))
+	assert.NotNil(t, json.Unmarshal([]byte(`["a","b"]`), &q))
+	assert.NotNil(t, json.Unmarshal([]byte(`{}`), &q))
+	assert.Equal(t, p, q)
+
+	pairs := []Pair[string, int]{{"b", 1}, {"a", 2}, {"a", 1}}
+	sort.Slice(pairs, func(i, j int) bool { return LessPair(pairs[i], pairs[j]) })
+	assert.Equal(t, []Pair[string, int]{{"a", 1}, {"a", 2}, {"b", 1}}, pairs)
+	assert.Equal(t, 0, ComparePairs(NewPair(1, 2), NewPair(1, 2)))
+	assert.Equal(t, 1, ComparePairs(NewPair(1, 3), NewPair(1, 2)))
+}
+
+func TestTriple(t *testing.T) {
+	tr := NewTriple("a", 1, true)
+	a, b, c := tr.Values()
+	assert.Equal(t, "a", a)
+	assert.Equal(t, 1, b)
+	assert.True(t, c)
+	assert.Equal(t, "(a, 1, true)", tr.String())
+
+	data, err := json.Marshal(tr)
+	assert.Nil(t, err)
+	assert.Equal(t, `["a",1,true]`, string(data))
+	var q Triple[string, int, bool]
+	assert.Nil(t, json.Unmarshal(data, &q))
+	assert.Equal(t, tr, q)
+	assert.NotNil(t, json.Unmarshal([]byte(`["a",1]`), &q))
+
+	assert.Equal(t, -1, CompareTriples(NewTriple(1, 2, 3), NewTriple(1, 2, 4)))
+	assert.Equal(t, 1, CompareTriples(NewTriple(1, 3, 0), NewTriple(1, 2, 4)))
+	assert.True(t, LessTriple(NewTriple(0, 9, 9), NewTriple(1, 0, 0)))
+}
+
+func TestZip(t *testing.T) {
+	pairs := Zip([]int{1, 2, 3}, []string{"a", "b"})
+	assert.Equal(t, []Pair[int, string]{{1, "a"}, {2, "b"}}, pairs)
+	as, bs := Unzip(pairs)
+	assert.Equal(t, []int{1, 2}, as)
+	assert.Equal(t, []string{"a", "b"}, bs)
+	assert.Empty(t, Zip[int, int](nil, []int{1}))
+
+	triples := Zip3([]int{1, 2}, []string{"a", "b", "c"}, []bool{true, false})
+	assert.Equal(t, []Triple[int, string, bool]{{1, "a", true}, {2, "b", false}}, triples)
+	xs, ys, zs := Unzip3(triples)
+	assert.Equal(t, []int{1, 2}, xs)
+	assert.Equal(t, []string{"a", "b"}, ys)
+	assert.Equal(t, []bool{true, false}, zs)
+}
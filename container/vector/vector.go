@@ -0,0 +1,271 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxvector implements a persistent vector on a bit-partitioned trie of 32-way
+// nodes. Append, Set and Slice return new versions copying only a path of the trie,
+// so the old versions stay valid and can be shared by the readers without a lock.
+// ref: https://hypirion.com/musings/understanding-persistent-vector-pt-1
+package gxvector
+
+const (
+	bits  = 5
+	width = 1 << bits
+	mask  = width - 1
+)
+
+type node[T any] struct {
+	children []*node[T] // nil for a leaf
+	values   []T        // values of a leaf
+}
+
+// Vector is an immutable vector of T. It is safe for concurrent use, and its
+// mutations return new versions sharing the most of the trie with it.
+type Vector[T any] struct {
+	root   *node[T]
+	tail   []T  // the last leaf, which is kept out of the trie to make Append cheap
+	cnt    int  // number of the values in the trie and the tail, including the ones before offset
+	shift  uint // bits of the index consumed by the levels above the leaves
+	offset int  // index of the first value, which is moved forward by Slice
+}
+
+// Empty returns an empty vector.
+func Empty[T any]() *Vector[T] {
+	return &Vector[T]{root: &node[T]{}, shift: bits}
+}
+
+// Of returns a vector of @values.
+func Of[T any](values ...T) *Vector[T] {
+	return Empty[T]().Append(values...)
+}
+
+// Len returns the number of values.
+func (v *Vector[T]) Len() int {
+	return v.cnt - v.offset
+}
+
+func (v *Vector[T]) tailOffset() int {
+	if v.cnt < width {
+		return 0
+	}
+
+	return ((v.cnt - 1) >> bits) << bits
+}
+
+// leaf returns the values of the leaf holding the absolute index @i.
+func (v *Vector[T]) leaf(i int) []T {
+	if i >= v.tailOffset() {
+		return v.tail
+	}
+
+	n := v.root
+	for level := v.shift; level > 0; level -= bits {
+		n = n.children[(i>>level)&mask]
+	}
+
+	return n.values
+}
+
+func (v *Vector[T]) checkIndex(i int) {
+	if i < 0 || i >= v.Len() {
+		panic("@i out of range")
+	}
+}
+
+// Get returns the value of index @i.
+func (v *Vector[T]) Get(i int) T {
+	v.checkIndex(i)
+	i += v.offset
+
+	return v.leaf(i)[i&mask]
+}
+
+// Set returns a new version in which index @i is @value.
+func (v *Vector[T]) Set(i int, value T) *Vector[T] {
+	v.checkIndex(i)
+	i += v.offset
+
+	nv := *v
+	if i >= v.tailOffset() {
+		nv.tail = append([]T(nil), v.tail...)
+		nv.tail[i&mask] = value
+		return &nv
+	}
+	nv.root = set(v.root, v.shift, i, value)
+
+	return &nv
+}
+
+func set[T any](n *node[T], level uint, i int, value T) *node[T] {
+	c := &node[T]{}
+	if level == 0 {
+		c.values = append([]T(nil), n.values...)
+		c.values[i&mask] = value
+		return c
+	}
+
+	c.children = append([]*node[T](nil), n.children...)
+	sub := (i >> level) & mask
+	c.children[sub] = set(n.children[sub], level-bits, i, value)
+
+	return c
+}
+
+// Append returns a new version with @values appended.
+func (v *Vector[T]) Append(values ...T) *Vector[T] {
+	if len(values) == 0 {
+		return v
+	}
+
+	nv := *v
+	owned := false // whether nv.tail is not shared with other versions
+	for _, value := range values {
+		if nv.cnt-nv.tailOffset() < width {
+			if !owned {
+				tail := make([]T, len(nv.tail), width)
+				copy(tail, nv.tail)
+				nv.tail, owned = tail, true
+			}
+			nv.tail = append(nv.tail, value)
+			nv.cnt++
+			continue
+		}
+
+		// the tail is full, push it into the trie
+		leaf := &node[T]{values: nv.tail}
+		if (nv.cnt >> bits) > (1 << nv.shift) {
+			nv.root = &node[T]{children: []*node[T]{nv.root, newPath(nv.shift, leaf)}}
+			nv.shift += bits
+		} else {
+			nv.root = nv.pushTail(nv.shift, nv.root, leaf)
+		}
+		nv.tail = make([]T, 1, width)
+		nv.tail[0] = value
+		owned = true
+		nv.cnt++
+	}
+
+	return &nv
+}
+
+func newPath[T any](level uint, leaf *node[T]) *node[T] {
+	if level == 0 {
+		return leaf
+	}
+
+	return &node[T]{children: []*node[T]{newPath(level-bits, leaf)}}
+}
+
+func (v *Vector[T]) pushTail(level uint, parent, leaf *node[T]) *node[T] {
+	c := &node[T]{children: append([]*node[T](nil), parent.children...)}
+	sub := ((v.cnt - 1) >> level) & mask
+
+	var child *node[T]
+	switch {
+	case level == bits:
+		child = leaf
+	case sub < len(parent.children):
+		child = v.pushTail(level-bits, parent.children[sub], leaf)
+	default:
+		child = newPath(level-bits, leaf)
+	}
+	if sub < len(c.children) {
+		c.children[sub] = child
+	} else {
+		c.children = append(c.children, child)
+	}
+
+	return c
+}
+
+// Slice returns a new version of the values of [@from, @to). It shares the trie with
+// @v, so the values before @from are not released until @v is.
+func (v *Vector[T]) Slice(from, to int) *Vector[T] {
+	if from < 0 || to > v.Len() || from > to {
+		panic("@from or @to out of range")
+	}
+	if from == to {
+		return Empty[T]()
+	}
+
+	nv := v.truncate(v.offset + to)
+	nv.offset = v.offset + from
+
+	return nv
+}
+
+// truncate returns a version of the first @n absolute values, n >= 1.
+func (v *Vector[T]) truncate(n int) *Vector[T] {
+	nv := *v
+	nv.cnt = n
+	if n > v.tailOffset() {
+		nv.tail = v.tail[: n-v.tailOffset() : n-v.tailOffset()]
+		return &nv
+	}
+
+	tailOffset := ((n - 1) >> bits) << bits
+	leaf := v.leaf(n - 1)
+	nv.tail = leaf[: n-tailOffset : n-tailOffset]
+
+	leaves := tailOffset >> bits
+	if leaves == 0 {
+		nv.root, nv.shift = &node[T]{}, bits
+		return &nv
+	}
+	nv.root = take(v.root, v.shift, leaves)
+	for nv.shift > bits && len(nv.root.children) == 1 {
+		nv.root = nv.root.children[0]
+		nv.shift -= bits
+	}
+
+	return &nv
+}
+
+// take returns a node of the first @leaves leaves of @n.
+func take[T any](n *node[T], level uint, leaves int) *node[T] {
+	capacity := 1 << (level - bits) // leaves under a child
+	children := (leaves + capacity - 1) / capacity
+	c := &node[T]{children: append([]*node[T](nil), n.children[:children]...)}
+	if rest := leaves - (children-1)*capacity; level > bits && rest < capacity {
+		c.children[children-1] = take(n.children[children-1], level-bits, rest)
+	}
+
+	return c
+}
+
+// Range calls @f with the values in order until @f returns false.
+func (v *Vector[T]) Range(f func(i int, value T) bool) {
+	for i := v.offset; i < v.cnt; {
+		values := v.leaf(i)
+		for j := i & mask; j < len(values) && i < v.cnt; j++ {
+			if !f(i-v.offset, values[j]) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Values returns a copy of the values.
+func (v *Vector[T]) Values() []T {
+	values := make([]T, 0, v.Len())
+	v.Range(func(_ int, value T) bool {
+		values = append(values, value)
+		return true
+	})
+
+	return values
+}
@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxvector
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func seq(from, to int) []int {
+	values := make([]int, 0, to-from)
+	for i := from; i < to; i++ {
+		values = append(values, i)
+	}
+
+	return values
+}
+
+func TestVectorAppendGet(t *testing.T) {
+	v := Empty[int]()
+	assert.Equal(t, 0, v.Len())
+	assert.Panics(t, func() { v.Get(0) })
+
+	versions := []*Vector[int]{v}
+	for i := 0; i < 40000; i++ {
+		v = v.Append(i)
+		if i%997 == 0 {
+			versions = append(versions, v)
+		}
+	}
+	assert.Equal(t, 40000, v.Len())
+	for i := 0; i < 40000; i += 7 {
+		assert.Equal(t, i, v.Get(i))
+	}
+	assert.Equal(t, seq(0, 40000), v.Values())
+
+	// the old versions are left intact
+	for _, old := range versions[1:] {
+		assert.Equal(t, seq(0, old.Len()), old.Values())
+	}
+
+	batch := Of(seq(0, 1100)...)
+	assert.Equal(t, seq(0, 1100), batch.Values())
+	assert.Equal(t, seq(0, 1105), batch.Append(seq(1100, 1105)...).Values())
+	assert.Equal(t, seq(0, 1100), batch.Values())
+}
+
+func TestVectorSet(t *testing.T) {
+	v := Of(seq(0, 2000)...)
+	w := v.Set(0, -1).Set(1999, -2).Set(1024, -3)
+	assert.Equal(t, -1, w.Get(0))
+	assert.Equal(t, -2, w.Get(1999))
+	assert.Equal(t, -3, w.Get(1024))
+	assert.Equal(t, 1024, v.Get(1024))
+	assert.Equal(t, seq(0, 2000), v.Values())
+	assert.Panics(t, func() { v.Set(2000, 0) })
+}
+
+func TestVectorSlice(t *testing.T) {
+	v := Of(seq(0, 5000)...)
+	for _, r := range [][2]int{{0, 5000}, {0, 1}, {10, 20}, {31, 33}, {0, 1024}, {1000, 1025}, {4990, 5000}, {33, 1057}, {7, 7}} {
+		s := v.Slice(r[0], r[1])
+		assert.Equal(t, r[1]-r[0], s.Len(), "%v", r)
+		assert.Equal(t, seq(r[0], r[1]), s.Values(), "%v", r)
+
+		// a slice grows and changes on its own
+		grown := s.Append(-1, -2)
+		assert.Equal(t, append(seq(r[0], r[1]), -1, -2), grown.Values(), "%v", r)
+		if s.Len() > 0 {
+			assert.Equal(t, -9, s.Set(0, -9).Get(0))
+		}
+	}
+	assert.Equal(t, seq(0, 5000), v.Values())
+
+	deep := Of(seq(0, 40000)...)
+	for _, to := range []int{33, 1024, 1025, 32768, 32800, 39999} {
+		s := deep.Slice(0, to)
+		assert.Equal(t, seq(0, to), s.Values(), "%d", to)
+		assert.Equal(t, seq(0, to+40), s.Append(seq(to, to+40)...).Values(), "%d", to)
+	}
+	assert.Equal(t, uint(bits), deep.Slice(0, 1024).shift)
+
+	nested := v.Slice(100, 4000).Slice(50, 60)
+	assert.Equal(t, seq(150, 160), nested.Values())
+	assert.Panics(t, func() { v.Slice(2, 1) })
+	assert.Panics(t, func() { v.Slice(0, 5001) })
+}
+
+func TestVectorRange(t *testing.T) {
+	v := Of(seq(0, 100)...).Slice(10, 90)
+	var got []int
+	v.Range(func(i, value int) bool {
+		assert.Equal(t, i+10, value)
+		got = append(got, value)
+		return len(got) < 5
+	})
+	assert.Equal(t, seq(10, 15), got)
+}
+
+func BenchmarkVectorAppend(b *testing.B) {
+	v := Empty[int]()
+	for i := 0; i < b.N; i++ {
+		v = v.Append(i)
+	}
+}
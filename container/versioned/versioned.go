@@ -0,0 +1,189 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxversioned keeps the recent versions of a value, such as a route or config
+// table, so that a bad push can be reverted instantly.
+package gxversioned
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrNoPreviousVersion is returned by Rollback if the current version is the oldest one kept.
+	ErrNoPreviousVersion = errors.New("versioned: no previous version")
+	// ErrVersionNotFound is returned by RollbackTo if the version is not kept.
+	ErrVersionNotFound = errors.New("versioned: version not found")
+)
+
+// Version is a published version of a value.
+type Version[T any] struct {
+	ID    uint64
+	Value T
+	At    time.Time // when it was published
+}
+
+type version[T any] struct {
+	Version[T]
+	pins int32
+}
+
+// Versioned holds a value and its last versions. A read is an atomic load, the
+// publishes and rollbacks are serialized.
+type Versioned[T any] struct {
+	lock    sync.Mutex
+	keep    int
+	history []*version[T] // the oldest first, the current is the last one
+	nextID  uint64
+	current atomic.Value // *version[T]
+}
+
+// New returns a value whose version 1 is @initial, and which keeps at most @keep
+// versions including the current one.
+func New[T any](keep int, initial T) *Versioned[T] {
+	if keep <= 0 {
+		panic("@keep <= 0")
+	}
+
+	v := &Versioned[T]{keep: keep}
+	v.Publish(initial)
+
+	return v
+}
+
+func (v *Versioned[T]) load() *version[T] {
+	return v.current.Load().(*version[T])
+}
+
+// Load returns the current value.
+func (v *Versioned[T]) Load() T {
+	return v.load().Value
+}
+
+// Current returns the current version.
+func (v *Versioned[T]) Current() Version[T] {
+	return v.load().Version
+}
+
+// Publish makes @value the current version, and returns its ID. The oldest version
+// is dropped if there are more than keep versions.
+func (v *Versioned[T]) Publish(value T) uint64 {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.nextID++
+	ver := &version[T]{Version: Version[T]{ID: v.nextID, Value: value, At: time.Now()}}
+	v.history = append(v.history, ver)
+	if len(v.history) > v.keep {
+		v.history[0] = nil
+		v.history = v.history[1:]
+	}
+	v.current.Store(ver)
+
+	return ver.ID
+}
+
+// Rollback drops the current version and makes the previous one current again.
+func (v *Versioned[T]) Rollback() (Version[T], error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if len(v.history) < 2 {
+		return Version[T]{}, ErrNoPreviousVersion
+	}
+
+	return v.rollback(len(v.history) - 2), nil
+}
+
+// RollbackTo drops the versions newer than @id and makes @id current again.
+func (v *Versioned[T]) RollbackTo(id uint64) (Version[T], error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	for i, ver := range v.history {
+		if ver.ID == id {
+			return v.rollback(i), nil
+		}
+	}
+
+	return Version[T]{}, ErrVersionNotFound
+}
+
+// rollback should be invoked with the lock held.
+func (v *Versioned[T]) rollback(i int) Version[T] {
+	for j := i + 1; j < len(v.history); j++ {
+		v.history[j] = nil
+	}
+	v.history = v.history[:i+1]
+	ver := v.history[i]
+	v.current.Store(ver)
+
+	return ver.Version
+}
+
+// History returns the kept versions, the current one first, and the number of readers
+// pinned to each of them.
+func (v *Versioned[T]) History() ([]Version[T], []int) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	var (
+		versions = make([]Version[T], 0, len(v.history))
+		pins     = make([]int, 0, len(v.history))
+	)
+	for i := len(v.history) - 1; i >= 0; i-- {
+		versions = append(versions, v.history[i].Version)
+		pins = append(pins, int(atomic.LoadInt32(&v.history[i].pins)))
+	}
+
+	return versions, pins
+}
+
+// Pin pins the reader to the current version, which it keeps seeing across publishes
+// and rollbacks until Release, e.g. for the whole handling of a request.
+func (v *Versioned[T]) Pin() *Pin[T] {
+	ver := v.load()
+	atomic.AddInt32(&ver.pins, 1)
+
+	return &Pin[T]{ver: ver}
+}
+
+// Pin is a reader pinned to a version.
+type Pin[T any] struct {
+	ver      *version[T]
+	released int32
+}
+
+// Version returns the pinned version.
+func (p *Pin[T]) Version() Version[T] {
+	return p.ver.Version
+}
+
+// Value returns the value of the pinned version.
+func (p *Pin[T]) Value() T {
+	return p.ver.Value
+}
+
+// Release unpins the reader. It is safe to be invoked more than once.
+func (p *Pin[T]) Release() {
+	if atomic.CompareAndSwapInt32(&p.released, 0, 1) {
+		atomic.AddInt32(&p.ver.pins, -1)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxversioned
+
+import (
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersioned(t *testing.T) {
+	v := New(3, "v1")
+	assert.Equal(t, "v1", v.Load())
+	assert.Equal(t, uint64(1), v.Current().ID)
+
+	_, err := v.Rollback()
+	assert.Equal(t, ErrNoPreviousVersion, err)
+
+	assert.Equal(t, uint64(2), v.Publish("v2"))
+	assert.Equal(t, uint64(3), v.Publish("v3"))
+	assert.Equal(t, uint64(4), v.Publish("v4"))
+	assert.Equal(t, "v4", v.Load())
+
+	versions, pins := v.History()
+	assert.Equal(t, 3, len(versions))
+	assert.Equal(t, []int{0, 0, 0}, pins)
+	assert.Equal(t, "v4", versions[0].Value)
+	assert.Equal(t, "v2", versions[2].Value)
+
+	_, err = v.RollbackTo(1)
+	assert.Equal(t, ErrVersionNotFound, err)
+
+	ver, err := v.Rollback()
+	assert.Nil(t, err)
+	assert.Equal(t, "v3", ver.Value)
+	assert.Equal(t, "v3", v.Load())
+
+	ver, err = v.RollbackTo(2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), ver.ID)
+	versions, _ = v.History()
+	assert.Equal(t, 1, len(versions))
+
+	// the IDs are never reused after rollbacks
+	assert.Equal(t, uint64(5), v.Publish("v5"))
+}
+
+func TestVersionedPin(t *testing.T) {
+	v := New(2, 1)
+	p := v.Pin()
+	v.Publish(2)
+	_, pins := v.History()
+	assert.Equal(t, []int{0, 1}, pins)
+
+	assert.Equal(t, 1, p.Value())
+	assert.Equal(t, uint64(1), p.Version().ID)
+	assert.Equal(t, 2, v.Load())
+
+	// still readable after falling out of the history
+	v.Publish(3)
+	assert.Equal(t, 1, p.Value())
+
+	p.Release()
+	p.Release()
+	v.Rollback()
+	_, pins = v.History()
+	assert.Equal(t, []int{0}, pins)
+}
+
+func TestVersionedConcurrent(t *testing.T) {
+	v := New(4, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v.Publish(i*100 + j)
+				if j%10 == 0 {
+					v.Rollback()
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				p := v.Pin()
+				_ = p.Value()
+				p.Release()
+				_ = v.Load()
+			}
+		}()
+	}
+	wg.Wait()
+
+	_, pins := v.History()
+	for _, n := range pins {
+		assert.Equal(t, 0, n)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxxorlist
+
+// Iterator iterates over a list from the front. Unlike the loop over Next, it panics
+// on the next move once the list is modified other than by Iterator.Remove, instead of
+// following the stale links silently.
+type Iterator[T any] struct {
+	list *XorList[T]
+	e    *Element[T]
+	prev *Element[T]
+	next *Element[T]
+	gen  uint64
+}
+
+// Iterator returns an iterator positioned before the front of list @l.
+func (l *XorList[T]) Iterator() *Iterator[T] {
+	return &Iterator[T]{list: l, next: l.front, gen: l.gen}
+}
+
+func (it *Iterator[T]) check() {
+	if it.gen != it.list.gen {
+		panic("list modified during iteration")
+	}
+}
+
+// Next moves to the next element, and returns false at the end of the list.
+func (it *Iterator[T]) Next() bool {
+	it.check()
+	if it.e != nil {
+		it.prev = it.e
+	}
+	it.e = it.next
+	if it.e == nil {
+		return false
+	}
+	it.next = it.e.Next(it.prev)
+
+	return true
+}
+
+// Element returns the current element.
+func (it *Iterator[T]) Element() *Element[T] {
+	return it.e
+}
+
+// Value returns the value of the current element.
+func (it *Iterator[T]) Value() T {
+	return it.e.Value
+}
+
+// Remove removes the current element, after which Next moves on to the element after it.
+func (it *Iterator[T]) Remove() T {
+	it.check()
+	if it.e == nil {
+		panic("no current element")
+	}
+
+	v := it.list.Remove(it.e, it.prev)
+	it.e = nil
+	it.gen = it.list.gen
+
+	return v
+}
+
+// Slice returns the values of list @l from the front.
+func (l *XorList[T]) Slice() []T {
+	values := make([]T, 0, l.len)
+	for it := l.Iterator(); it.Next(); {
+		values = append(values, it.Value())
+	}
+
+	return values
+}
+
+// ForEach calls @f with the values of list @l from the front until @f returns false.
+// @f must not modify the list.
+func (l *XorList[T]) ForEach(f func(v T) bool) {
+	for it := l.Iterator(); it.Next(); {
+		if !f(it.Value()) {
+			return
+		}
+	}
+}
+
+// Filter returns a new list of the values of list @l which satisfy @keep.
+func (l *XorList[T]) Filter(keep func(v T) bool) *XorList[T] {
+	filtered := New[T]()
+	for it := l.Iterator(); it.Next(); {
+		if keep(it.Value()) {
+			filtered.PushBack(it.Value())
+		}
+	}
+
+	return filtered
+}
+
+// RemoveFunc removes the elements whose values satisfy @match, and returns the number
+// of elements removed.
+func (l *XorList[T]) RemoveFunc(match func(v T) bool) int {
+	n := 0
+	for it := l.Iterator(); it.Next(); {
+		if match(it.Value()) {
+			it.Remove()
+			n++
+		}
+	}
+
+	return n
+}
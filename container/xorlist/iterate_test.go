@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxxorlist
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXorListIterator(t *testing.T) {
+	l := New[int]()
+	assert.Empty(t, l.Slice())
+	for i := 0; i < 6; i++ {
+		l.PushBack(i)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, l.Slice())
+
+	var seen []int
+	l.ForEach(func(v int) bool {
+		seen = append(seen, v)
+		return v < 2
+	})
+	assert.Equal(t, []int{0, 1, 2}, seen)
+
+	assert.Equal(t, []int{0, 2, 4}, l.Filter(func(v int) bool { return v%2 == 0 }).Slice())
+	assert.Equal(t, 6, l.Len())
+
+	// removing while iterating, including the front and the back
+	assert.Equal(t, 3, l.RemoveFunc(func(v int) bool { return v == 0 || v == 3 || v == 5 }))
+	assert.Equal(t, []int{1, 2, 4}, l.Slice())
+	assert.Equal(t, []int{1, 2, 4}, values(l))
+	assert.Equal(t, []int{4, 2, 1}, reversed(l))
+
+	it := l.Iterator()
+	assert.Panics(t, func() { it.Remove() })
+	for it.Next() {
+		it.Remove()
+	}
+	assert.Equal(t, 0, l.Len())
+	assert.Nil(t, l.Front())
+	assert.Nil(t, l.Back())
+}
+
+func TestXorListIteratorGuard(t *testing.T) {
+	l := New[int]()
+	e0 := l.PushBack(0)
+	l.PushBack(1)
+	l.PushBack(2)
+
+	it := l.Iterator()
+	assert.True(t, it.Next())
+	assert.Equal(t, e0, it.Element())
+	l.Remove(e0, nil)
+	assert.Panics(t, func() { it.Next() })
+
+	assert.Panics(t, func() {
+		l.ForEach(func(v int) bool {
+			l.PushBack(v)
+			return true
+		})
+	})
+}
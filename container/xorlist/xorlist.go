@@ -0,0 +1,358 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxxorlist implements a type safe doubly linked list whose elements keep
+// the xor of the ids of their neighbours instead of two pointers.
+//
+// The elements are addressed by ids of the list instead of by their addresses, so
+// xoring them hides nothing from the garbage collector. As a cost of the single
+// link, moving from an element needs one of its neighbours:
+//
+//	for e, prev := l.Front(), (*gxxorlist.Element[T])(nil); e != nil; e, prev = e.Next(prev), e {
+//		// do something with e.Value
+//	}
+package gxxorlist
+
+// Element is an element of XorList.
+type Element[T any] struct {
+	// The value stored with this element.
+	Value T
+
+	id   int // index of the element in list.elems, 0 means nil
+	link int // xor of the ids of the previous and the next element
+	list *XorList[T]
+}
+
+func (e *Element[T]) getID() int {
+	if e == nil {
+		return 0
+	}
+
+	return e.id
+}
+
+// Next returns the next element of @e, whose previous element is @prev.
+func (e *Element[T]) Next(prev *Element[T]) *Element[T] {
+	if e.list == nil {
+		return nil
+	}
+
+	return e.list.at(e.link ^ prev.getID())
+}
+
+// Prev returns the previous element of @e, whose next element is @next.
+func (e *Element[T]) Prev(next *Element[T]) *Element[T] {
+	if e.list == nil {
+		return nil
+	}
+
+	return e.list.at(e.link ^ next.getID())
+}
+
+// minPoolSize is the pool size kept regardless of the list length, see SetPooling.
+const minPoolSize = 64
+
+// XorList is a doubly linked list of T. The zero value is an empty list ready to use.
+// It is not goroutine safe.
+type XorList[T any] struct {
+	elems []*Element[T] // elems[0] is always nil
+	free  []int         // released ids
+	front *Element[T]
+	back  *Element[T]
+	len   int
+	gen   uint64 // bumped on every insertion and removal to catch them in iterators
+
+	pooling bool
+	pool    []*Element[T] // removed elements to reuse if pooling
+}
+
+// New returns an initialized list.
+func New[T any]() *XorList[T] {
+	return new(XorList[T]).Init()
+}
+
+// Init initializes or clears list @l.
+func (l *XorList[T]) Init() *XorList[T] {
+	for _, e := range l.elems {
+		if e != nil {
+			e.list = nil
+		}
+	}
+	l.elems = l.elems[:0]
+	l.free = l.free[:0]
+	l.front = nil
+	l.back = nil
+	l.len = 0
+	l.gen++
+
+	return l
+}
+
+func (l *XorList[T]) at(id int) *Element[T] {
+	if id == 0 {
+		return nil
+	}
+
+	return l.elems[id]
+}
+
+// Len returns the number of elements of list @l.
+func (l *XorList[T]) Len() int { return l.len }
+
+// Front returns the first element of list @l or nil.
+func (l *XorList[T]) Front() *Element[T] { return l.front }
+
+// Back returns the last element of list @l or nil.
+func (l *XorList[T]) Back() *Element[T] { return l.back }
+
+// SetPooling turns on or off reusing the removed elements for the later insertions,
+// which saves the allocations of the short-lived elements, e.g. of timers. Once it is
+// on, an element must not be used after it is removed. The pool keeps at most as many
+// elements as the list holds, or minPoolSize, so a burst of removals does not pin the
+// peak number of elements. Turning it off drops the pool.
+func (l *XorList[T]) SetPooling(on bool) {
+	l.pooling = on
+	if !on {
+		l.pool = nil
+	}
+}
+
+func (l *XorList[T]) alloc(v T) *Element[T] {
+	var e *Element[T]
+	if n := len(l.pool); n > 0 {
+		e = l.pool[n-1]
+		l.pool[n-1] = nil
+		l.pool = l.pool[:n-1]
+		e.Value = v
+	} else {
+		e = &Element[T]{Value: v}
+	}
+	l.adopt(e)
+
+	return e
+}
+
+// adopt gives @e an id of list @l.
+func (l *XorList[T]) adopt(e *Element[T]) {
+	if len(l.elems) == 0 {
+		l.elems = append(l.elems, nil)
+	}
+
+	e.list = l
+	if n := len(l.free); n > 0 {
+		e.id = l.free[n-1]
+		l.free = l.free[:n-1]
+		l.elems[e.id] = e
+	} else {
+		e.id = len(l.elems)
+		l.elems = append(l.elems, e)
+	}
+}
+
+// insertBetween inserts a new element of @v between the adjacent elements @a and @b.
+func (l *XorList[T]) insertBetween(v T, a, b *Element[T]) *Element[T] {
+	e := l.alloc(v)
+	l.link(e, a, b)
+
+	return e
+}
+
+// link links @e of list @l between the adjacent elements @a and @b.
+func (l *XorList[T]) link(e, a, b *Element[T]) {
+	e.link = a.getID() ^ b.getID()
+	if a != nil {
+		a.link ^= b.getID() ^ e.id
+	} else {
+		l.front = e
+	}
+	if b != nil {
+		b.link ^= a.getID() ^ e.id
+	} else {
+		l.back = e
+	}
+	l.len++
+	l.gen++
+}
+
+// unlink unlinks @e, whose previous element is @prev, from its neighbours and returns
+// them. @e keeps its id.
+func (l *XorList[T]) unlink(e, prev *Element[T]) (*Element[T], *Element[T]) {
+	if (prev == nil) != (l.front == e) {
+		panic("@prev is not the previous element of @e")
+	}
+
+	next := e.Next(prev)
+	if prev != nil {
+		prev.link ^= e.id ^ next.getID()
+	} else {
+		l.front = next
+	}
+	if next != nil {
+		next.link ^= e.id ^ prev.getID()
+	} else {
+		l.back = prev
+	}
+	e.link = 0
+	l.len--
+	l.gen++
+
+	return prev, next
+}
+
+// PushFront inserts a new element of @v at the front of list @l and returns it.
+func (l *XorList[T]) PushFront(v T) *Element[T] {
+	return l.insertBetween(v, nil, l.front)
+}
+
+// PushBack inserts a new element of @v at the back of list @l and returns it.
+func (l *XorList[T]) PushBack(v T) *Element[T] {
+	return l.insertBetween(v, l.back, nil)
+}
+
+// InsertAfter inserts a new element of @v right after @mark, whose previous element
+// is @prev, and returns it. It returns nil if @mark is not an element of list @l.
+func (l *XorList[T]) InsertAfter(v T, mark, prev *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+
+	return l.insertBetween(v, mark, mark.Next(prev))
+}
+
+// InsertBefore inserts a new element of @v right before @mark, whose next element
+// is @next, and returns it. It returns nil if @mark is not an element of list @l.
+func (l *XorList[T]) InsertBefore(v T, mark, next *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+
+	return l.insertBetween(v, mark.Prev(next), mark)
+}
+
+// Remove removes @e, whose previous element is @prev, from list @l if it is an element
+// of list @l, and returns @e.Value.
+func (l *XorList[T]) Remove(e, prev *Element[T]) T {
+	if e.list != l {
+		return e.Value
+	}
+
+	l.unlink(e, prev)
+	l.elems[e.id] = nil
+	l.free = append(l.free, e.id)
+	e.id, e.list = 0, nil
+
+	v := e.Value
+	if l.pooling {
+		limit := l.len
+		if limit < minPoolSize {
+			limit = minPoolSize
+		}
+		if n := len(l.pool); n < limit {
+			var zero T
+			e.Value = zero // drops the reference held by the pool
+			l.pool = append(l.pool, e)
+		} else if n > limit {
+			// trims the pool as the list shrinks
+			l.pool[n-1] = nil
+			l.pool = l.pool[:n-1]
+		}
+	}
+
+	return v
+}
+
+// MoveToFront moves @e, whose previous element is @prev, to the front of list @l
+// without reallocating it. It does nothing if @e is not an element of list @l.
+func (l *XorList[T]) MoveToFront(e, prev *Element[T]) {
+	if e.list != l || l.front == e {
+		return
+	}
+
+	l.unlink(e, prev)
+	l.link(e, nil, l.front)
+}
+
+// MoveToBack moves @e, whose previous element is @prev, to the back of list @l
+// without reallocating it. It does nothing if @e is not an element of list @l.
+func (l *XorList[T]) MoveToBack(e, prev *Element[T]) {
+	if e.list != l || l.back == e {
+		return
+	}
+
+	l.unlink(e, prev)
+	l.link(e, l.back, nil)
+}
+
+// SpliceAfter moves all the elements of list @other right after @mark, whose previous
+// element is @prev, and leaves @other empty. A nil @mark means the front of list @l.
+// The elements keep their addresses.
+func (l *XorList[T]) SpliceAfter(mark, prev *Element[T], other *XorList[T]) {
+	if other == l {
+		panic("@other is list @l")
+	}
+	if mark != nil && mark.list != l {
+		return
+	}
+
+	var next *Element[T]
+	if mark != nil {
+		next = mark.Next(prev)
+	} else {
+		next = l.front
+	}
+
+	// the links of @other are overwritten while moving, so collect the elements first
+	moved := make([]*Element[T], 0, other.len)
+	for e, ePrev := other.front, (*Element[T])(nil); e != nil; e, ePrev = e.Next(ePrev), e {
+		moved = append(moved, e)
+	}
+
+	a := mark
+	for _, e := range moved {
+		l.adopt(e)
+		l.link(e, a, next)
+		a = e
+	}
+
+	other.elems = other.elems[:0]
+	other.free = other.free[:0]
+	other.front = nil
+	other.back = nil
+	other.len = 0
+	other.gen++
+}
+
+// Find returns the first element whose value satisfies @match, and its previous element.
+func (l *XorList[T]) Find(match func(v T) bool) (e, prev *Element[T]) {
+	for e = l.front; e != nil; e, prev = e.Next(prev), e {
+		if match(e.Value) {
+			return e, prev
+		}
+	}
+
+	return nil, nil
+}
+
+// PushBackList inserts a copy of list @other at the back of list @l. The lists
+// @l and @other may be the same.
+func (l *XorList[T]) PushBackList(other *XorList[T]) {
+	var prev *Element[T]
+	for i, e := other.Len(), other.Front(); i > 0; i-- {
+		l.PushBack(e.Value)
+		e, prev = e.Next(prev), e
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxxorlist
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func values[T any](l *XorList[T]) []T {
+	var vs []T
+	for e, prev := l.Front(), (*Element[T])(nil); e != nil; e, prev = e.Next(prev), e {
+		vs = append(vs, e.Value)
+	}
+
+	return vs
+}
+
+func reversed[T any](l *XorList[T]) []T {
+	var vs []T
+	for e, next := l.Back(), (*Element[T])(nil); e != nil; e, next = e.Prev(next), e {
+		vs = append(vs, e.Value)
+	}
+
+	return vs
+}
+
+func TestXorList(t *testing.T) {
+	var l XorList[int]
+	assert.Equal(t, 0, l.Len())
+	assert.Nil(t, l.Front())
+
+	e2 := l.PushBack(2)
+	e1 := l.PushFront(1)
+	e4 := l.PushBack(4)
+	e3 := l.InsertAfter(3, e2, e1)
+	l.InsertBefore(0, e1, e2)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, values(&l))
+	assert.Equal(t, []int{4, 3, 2, 1, 0}, reversed(&l))
+	assert.Equal(t, 5, l.Len())
+
+	assert.Equal(t, 3, l.Remove(e3, e2))
+	assert.Equal(t, 4, l.Remove(e4, e2))
+	assert.Equal(t, []int{0, 1, 2}, values(&l))
+	assert.Equal(t, e2, l.Back())
+
+	// ids of the removed elements are reused
+	l.PushBack(5)
+	l.PushBack(6)
+	assert.Equal(t, []int{0, 1, 2, 5, 6}, values(&l))
+	assert.Equal(t, []int{6, 5, 2, 1, 0}, reversed(&l))
+
+	front := l.Front()
+	assert.Equal(t, 0, l.Remove(front, nil))
+	assert.Equal(t, e1, l.Front())
+	assert.Panics(t, func() { l.Remove(e2, nil) })
+
+	e, prev := l.Find(func(v int) bool { return v == 5 })
+	assert.Equal(t, 5, e.Value)
+	assert.Equal(t, 2, prev.Value)
+	e, _ = l.Find(func(v int) bool { return v == 7 })
+	assert.Nil(t, e)
+}
+
+func TestXorListForeignElement(t *testing.T) {
+	l1, l2 := New[string](), New[string]()
+	e := l1.PushBack("a")
+	assert.Nil(t, l2.InsertAfter("b", e, nil))
+	assert.Equal(t, "a", l2.Remove(e, nil))
+	assert.Equal(t, 1, l1.Len())
+
+	l1.Remove(e, nil)
+	assert.Nil(t, e.Next(nil))
+	assert.Equal(t, 0, l1.Len())
+}
+
+func TestXorListPushBackList(t *testing.T) {
+	l := New[int]()
+	l.PushBack(1)
+	l.PushBack(2)
+	l.PushBackList(l)
+	assert.Equal(t, []int{1, 2, 1, 2}, values(l))
+
+	l.Init()
+	assert.Equal(t, 0, l.Len())
+	assert.Nil(t, values(l))
+}
+
+func TestXorListMove(t *testing.T) {
+	l := New[int]()
+	e0 := l.PushBack(0)
+	e1 := l.PushBack(1)
+	e2 := l.PushBack(2)
+
+	l.MoveToFront(e2, e1)
+	assert.Equal(t, []int{2, 0, 1}, values(l))
+	assert.Equal(t, []int{1, 0, 2}, reversed(l))
+	l.MoveToFront(e2, nil)
+	assert.Equal(t, []int{2, 0, 1}, values(l))
+
+	l.MoveToBack(e2, nil)
+	assert.Equal(t, []int{0, 1, 2}, values(l))
+	l.MoveToBack(e0, nil)
+	assert.Equal(t, []int{1, 2, 0}, values(l))
+	assert.Equal(t, []int{0, 2, 1}, reversed(l))
+	assert.Equal(t, 3, l.Len())
+	assert.Panics(t, func() { l.MoveToBack(e2, nil) })
+
+	other := New[int]()
+	e9 := other.PushBack(9)
+	l.MoveToFront(e9, nil)
+	assert.Equal(t, []int{9}, values(other))
+	assert.Equal(t, e0, l.Back())
+}
+
+func TestXorListSpliceAfter(t *testing.T) {
+	l := New[int]()
+	e0 := l.PushBack(0)
+	e1 := l.PushBack(1)
+	l.PushBack(2)
+
+	other := New[int]()
+	o10 := other.PushBack(10)
+	other.PushBack(11)
+	l.SpliceAfter(e1, e0, other)
+	assert.Equal(t, []int{0, 1, 10, 11, 2}, values(l))
+	assert.Equal(t, []int{2, 11, 10, 1, 0}, reversed(l))
+	assert.Equal(t, 5, l.Len())
+	assert.Equal(t, 0, other.Len())
+	assert.Nil(t, other.Front())
+
+	// the moved elements belong to list @l
+	l.MoveToFront(o10, e1)
+	assert.Equal(t, []int{10, 0, 1, 11, 2}, values(l))
+
+	other.PushBack(20)
+	other.PushBack(21)
+	l.SpliceAfter(nil, nil, other)
+	assert.Equal(t, []int{20, 21, 10, 0, 1, 11, 2}, values(l))
+
+	other.PushBack(30)
+	l.SpliceAfter(l.Back(), l.Back().Prev(nil), other)
+	assert.Equal(t, []int{20, 21, 10, 0, 1, 11, 2, 30}, values(l))
+	assert.Equal(t, 30, l.Back().Value)
+
+	assert.Panics(t, func() { l.SpliceAfter(nil, nil, l) })
+}
+
+func TestXorListPooling(t *testing.T) {
+	l := New[*int]()
+	l.SetPooling(true)
+
+	v := 1
+	e := l.PushBack(&v)
+	assert.Equal(t, &v, l.Remove(e, nil))
+	assert.Nil(t, e.Value)
+
+	e2 := l.PushBack(&v)
+	assert.True(t, e == e2)
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Remove(l.PushFront(&v), nil)
+	})
+	assert.Equal(t, float64(0), allocs)
+	assert.Equal(t, 1, l.Len())
+
+	// the pool is bounded by the list length after a burst of removals
+	for i := 0; i < 1000; i++ {
+		l.PushBack(&v)
+	}
+	for l.Len() > 1 {
+		l.Remove(l.Back(), l.Back().Prev(nil))
+	}
+	assert.Equal(t, minPoolSize, len(l.pool))
+
+	l.Remove(e2, nil)
+	l.SetPooling(false)
+	assert.True(t, l.PushBack(&v) != e2)
+}
+
+func benchmarkXorListChurn(b *testing.B, pooling bool) {
+	l := New[int]()
+	l.SetPooling(pooling)
+	for i := 0; i < 1024; i++ {
+		l.PushBack(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Remove(l.Front(), nil)
+		l.PushBack(i)
+	}
+}
+
+func BenchmarkXorListChurn(b *testing.B) {
+	benchmarkXorListChurn(b, false)
+}
+
+func BenchmarkXorListChurnPooling(b *testing.B) {
+	benchmarkXorListChurn(b, true)
+}
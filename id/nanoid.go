@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxid generates short random IDs for human friendly resource identifiers.
+package gxid
+
+import (
+	"crypto/rand"
+	"math/bits"
+)
+
+const (
+	// DefaultAlphabet is the URL safe alphabet of NanoID.
+	DefaultAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// DefaultSize gives about the collision probability of UUID v4 with DefaultAlphabet.
+	DefaultSize = 21
+)
+
+var defaultGenerator = NewGenerator(DefaultAlphabet, DefaultSize)
+
+// NanoID returns a random ID of DefaultSize characters of DefaultAlphabet.
+func NanoID() string {
+	return defaultGenerator.Generate()
+}
+
+// Generator generates NanoID style random IDs of a fixed size from an alphabet. It is
+// safe for concurrent use.
+type Generator struct {
+	alphabet string
+	size     int
+	mask     byte // the smallest 2^n - 1 covering the alphabet
+	step     int  // random bytes read per batch
+}
+
+// NewGenerator returns a generator of @size characters from @alphabet, which should
+// consist of 2 to 256 distinct bytes.
+func NewGenerator(alphabet string, size int) *Generator {
+	checkAlphabet(alphabet)
+	if size <= 0 {
+		panic("@size <= 0")
+	}
+
+	mask := byte(1<<bits.Len(uint(len(alphabet)-1)) - 1)
+	// the bytes over the alphabet are rejected, so read some more up front
+	step := 1 + int(1.6*float64(int(mask)+1)*float64(size)/float64(len(alphabet)))
+
+	return &Generator{alphabet: alphabet, size: size, mask: mask, step: step}
+}
+
+func checkAlphabet(alphabet string) {
+	if len(alphabet) < 2 || len(alphabet) > 256 {
+		panic("the length of @alphabet is out of [2, 256]")
+	}
+
+	var seen [256]bool
+	for i := 0; i < len(alphabet); i++ {
+		if seen[alphabet[i]] {
+			panic("@alphabet has duplicate characters")
+		}
+		seen[alphabet[i]] = true
+	}
+}
+
+// Alphabet returns the alphabet of the IDs.
+func (g *Generator) Alphabet() string {
+	return g.alphabet
+}
+
+// Size returns the number of characters of the IDs.
+func (g *Generator) Size() int {
+	return g.size
+}
+
+// Generate returns a new ID.
+func (g *Generator) Generate() string {
+	id := make([]byte, g.size)
+	g.fill(id)
+
+	return string(id)
+}
+
+// fill fills @dst with random characters, picking the alphabet by masked random bytes
+// and dropping the ones out of the alphabet, so every character is equally likely.
+func (g *Generator) fill(dst []byte) {
+	var (
+		buf = make([]byte, g.step)
+		n   = 0
+	)
+	for n < len(dst) {
+		if _, err := rand.Read(buf); err != nil {
+			panic("gxid: crypto/rand failed: " + err.Error())
+		}
+		for _, b := range buf {
+			if idx := int(b & g.mask); idx < len(g.alphabet) {
+				dst[n] = g.alphabet[idx]
+				if n++; n == len(dst) {
+					return
+				}
+			}
+		}
+	}
+}
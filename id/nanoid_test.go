@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxid
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNanoID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := NanoID()
+		assert.Equal(t, DefaultSize, len(id))
+		for _, c := range id {
+			assert.True(t, strings.ContainsRune(DefaultAlphabet, c))
+		}
+		assert.False(t, seen[id])
+		seen[id] = true
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	g := NewGenerator("abc", 8)
+	assert.Equal(t, "abc", g.Alphabet())
+	assert.Equal(t, 8, g.Size())
+
+	counts := make(map[rune]int)
+	for i := 0; i < 3000; i++ {
+		for _, c := range g.Generate() {
+			counts[c]++
+		}
+	}
+	assert.Equal(t, 3, len(counts))
+	// every character is about equally likely
+	for _, n := range counts {
+		assert.InDelta(t, 8000, n, 600)
+	}
+
+	assert.Panics(t, func() { NewGenerator("a", 8) })
+	assert.Panics(t, func() { NewGenerator("abca", 8) })
+	assert.Panics(t, func() { NewGenerator("ab", 0) })
+}
+
+func TestGeneratorConcurrent(t *testing.T) {
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+		seen = make(map[string]bool)
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 250; j++ {
+				id := NanoID()
+				lock.Lock()
+				seen[id] = true
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, len(seen))
+}
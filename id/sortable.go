@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxid
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+const (
+	// SortableAlphabet is the base62 alphabet in the byte order.
+	SortableAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// the time prefix holds 48 bits of unix milliseconds, which last until the year 10889
+	timeBits = 48
+)
+
+// ErrInvalidID is returned when the time prefix of an ID can not be decoded.
+var ErrInvalidID = errors.New("gxid: invalid id")
+
+// SortableGenerator generates IDs prefixed by their creation time in milliseconds, so
+// the IDs sort by the creation time as plain strings. The IDs of the same millisecond
+// are in random order, that is, they are K-ordered. It is safe for concurrent use.
+type SortableGenerator struct {
+	random    *Generator
+	index     [256]int16 // the digit of every alphabet byte, -1 for the others
+	timeWidth int
+}
+
+// NewSortableGenerator returns a generator of IDs of a time prefix followed by @size
+// random characters from @alphabet. The alphabet is used in the byte order, so that
+// the string order of the IDs follows their time.
+func NewSortableGenerator(alphabet string, size int) *SortableGenerator {
+	checkAlphabet(alphabet)
+	sorted := []byte(alphabet)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	g := &SortableGenerator{
+		random:    NewGenerator(string(sorted), size),
+		timeWidth: int(math.Ceil(timeBits / math.Log2(float64(len(sorted))))),
+	}
+	for i := range g.index {
+		g.index[i] = -1
+	}
+	for i, b := range sorted {
+		g.index[b] = int16(i)
+	}
+
+	return g
+}
+
+// Size returns the number of characters of the IDs, including the time prefix.
+func (g *SortableGenerator) Size() int {
+	return g.timeWidth + g.random.size
+}
+
+// Generate returns a new ID of the current time of the gxtime source.
+func (g *SortableGenerator) Generate() string {
+	return g.GenerateAt(gxtime.Now())
+}
+
+// GenerateAt returns a new ID of @t, which should be after the unix epoch.
+func (g *SortableGenerator) GenerateAt(t time.Time) string {
+	var (
+		alphabet = g.random.alphabet
+		base     = uint64(len(alphabet))
+		ms       = uint64(t.UnixNano()/int64(time.Millisecond)) & (1<<timeBits - 1)
+		id       = make([]byte, g.Size())
+	)
+	for i := g.timeWidth - 1; i >= 0; i-- {
+		id[i] = alphabet[ms%base]
+		ms /= base
+	}
+	g.random.fill(id[g.timeWidth:])
+
+	return string(id)
+}
+
+// Time returns the creation time encoded in @id.
+func (g *SortableGenerator) Time(id string) (time.Time, error) {
+	if len(id) < g.timeWidth {
+		return time.Time{}, ErrInvalidID
+	}
+
+	var (
+		base = uint64(len(g.random.alphabet))
+		ms   uint64
+	)
+	for i := 0; i < g.timeWidth; i++ {
+		digit := g.index[id[i]]
+		if digit < 0 {
+			return time.Time{}, ErrInvalidID
+		}
+		ms = ms*base + uint64(digit)
+	}
+	if ms >= 1<<timeBits {
+		return time.Time{}, ErrInvalidID
+	}
+
+	return time.Unix(0, int64(ms)*int64(time.Millisecond)), nil
+}
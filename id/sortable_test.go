@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxid
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortableGenerator(t *testing.T) {
+	g := NewSortableGenerator(SortableAlphabet, 10)
+	assert.Equal(t, 9+10, g.Size())
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var ids []string
+	for i := 0; i < 100; i++ {
+		ids = append(ids, g.GenerateAt(base.Add(time.Duration(i)*time.Millisecond)))
+	}
+	assert.True(t, sort.StringsAreSorted(ids))
+
+	at, err := g.Time(ids[42])
+	assert.Nil(t, err)
+	assert.True(t, at.Equal(base.Add(42*time.Millisecond)), at)
+
+	now := time.Now()
+	at, err = g.Time(g.Generate())
+	assert.Nil(t, err)
+	assert.True(t, now.Sub(at) < time.Second)
+
+	_, err = g.Time("short")
+	assert.Equal(t, ErrInvalidID, err)
+	_, err = g.Time("!!!!!!!!!!")
+	assert.Equal(t, ErrInvalidID, err)
+	_, err = g.Time("zzzzzzzzzz")
+	assert.Equal(t, ErrInvalidID, err)
+}
+
+func TestSortableGeneratorUnsortedAlphabet(t *testing.T) {
+	// the alphabet is sorted by the generator
+	g := NewSortableGenerator("zyx0", 4)
+	assert.Equal(t, 24+4, g.Size())
+
+	base := time.Unix(1700000000, 0)
+	a := g.GenerateAt(base)
+	b := g.GenerateAt(base.Add(time.Millisecond))
+	assert.True(t, a < b, "%s %s", a, b)
+}
@@ -0,0 +1,255 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxlog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultCaptureLimit is the default number of records kept by a Capture.
+const DefaultCaptureLimit = 256
+
+// Record is a log record collected by a Capture.
+type Record struct {
+	Time    time.Time
+	Level   Level
+	Module  string
+	Message string
+}
+
+var levelColors = [...][]byte{NORMAL, NGreen, BMagenta, NRed, BRed}
+
+func printRecord(r Record) {
+	color := NORMAL
+	if r.Level >= DebugLevel && r.Level <= FatalLevel {
+		color = levelColors[r.Level]
+	}
+	format := "[%s] [%s] [%s] %s"
+	args := []interface{}{r.Time.Format("2006-01-02/15:04:05.000"), r.Level, r.Module, r.Message}
+	if r.Level >= WarnLevel {
+		CEPrintfln(color, format, args...)
+	} else {
+		CPrintfln(color, format, args...)
+	}
+}
+
+// CaptureOption configures a Capture.
+type CaptureOption func(*Capture)
+
+// WithCaptureLimit sets the number of records kept, beyond which the oldest are dropped.
+func WithCaptureLimit(limit int) CaptureOption {
+	return func(c *Capture) {
+		c.limit = limit
+	}
+}
+
+// WithCaptureThreshold makes Finish flush the records of a request slower than @threshold.
+func WithCaptureThreshold(threshold time.Duration) CaptureOption {
+	return func(c *Capture) {
+		c.threshold = threshold
+	}
+}
+
+// WithCaptureLevel sets the lowest level collected, DebugLevel by default, which is
+// independent of the module levels. The records below it are printed as usual.
+func WithCaptureLevel(level Level) CaptureOption {
+	return func(c *Capture) {
+		c.level = level
+	}
+}
+
+// WithCaptureSink replaces the output of the flushed records, which prints them by
+// default. @dropped is the number of records dropped over the limit.
+func WithCaptureSink(sink func(records []Record, dropped int)) CaptureOption {
+	return func(c *Capture) {
+		c.sink = sink
+	}
+}
+
+// Capture collects the log records of a request into a bounded buffer instead of
+// printing them, and flushes them only if the request turns out to be bad. It is
+// goroutine safe.
+type Capture struct {
+	limit     int
+	threshold time.Duration
+	level     Level
+	sink      func(records []Record, dropped int)
+	start     time.Time
+
+	lock     sync.Mutex
+	records  []Record // a ring of the latest records once it is full
+	head     int      // index of the oldest record in the full ring
+	dropped  int
+	finished bool
+}
+
+type captureKey struct{}
+
+// CaptureCtx returns a context carrying a new Capture, and the Capture. The *Ctx logs
+// of ModuleLogger with the context are collected by the Capture until it is finished.
+func CaptureCtx(ctx context.Context, opts ...CaptureOption) (context.Context, *Capture) {
+	c := &Capture{limit: DefaultCaptureLimit, start: time.Now()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.limit <= 0 {
+		panic("@limit <= 0")
+	}
+	if c.sink == nil {
+		c.sink = func(records []Record, dropped int) {
+			if dropped > 0 {
+				printRecord(Record{Time: time.Now(), Level: WarnLevel, Module: "gxlog", Message: fmt.Sprintf("%d captured records dropped", dropped)})
+			}
+			for _, r := range records {
+				printRecord(r)
+			}
+		}
+	}
+
+	return context.WithValue(ctx, captureKey{}, c), c
+}
+
+// CaptureFromCtx returns the Capture carried by @ctx, or nil.
+func CaptureFromCtx(ctx context.Context) *Capture {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(captureKey{}).(*Capture)
+
+	return c
+}
+
+// add collects a record, and returns false if the record is left to be printed as usual.
+func (c *Capture) add(level Level, module, message string) bool {
+	if level < c.level {
+		return false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.finished {
+		return false
+	}
+	r := Record{Time: time.Now(), Level: level, Module: module, Message: message}
+	if len(c.records) < c.limit {
+		c.records = append(c.records, r)
+		return true
+	}
+	c.records[c.head] = r
+	c.head = (c.head + 1) % c.limit
+	c.dropped++
+
+	return true
+}
+
+// Records returns a copy of the records collected, oldest first.
+func (c *Capture) Records() []Record {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.ordered()
+}
+
+// ordered should be invoked with the lock held.
+func (c *Capture) ordered() []Record {
+	records := make([]Record, 0, len(c.records))
+	records = append(records, c.records[c.head:]...)
+
+	return append(records, c.records[:c.head]...)
+}
+
+// Dropped returns the number of records dropped over the limit.
+func (c *Capture) Dropped() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.dropped
+}
+
+// Finish ends the capture. The records are flushed if @err is not nil or the request
+// took longer than the threshold, and discarded otherwise. It returns whether they
+// are flushed. The later logs with the context are printed as usual.
+func (c *Capture) Finish(err error) bool {
+	slow := c.threshold > 0 && time.Since(c.start) > c.threshold
+
+	return c.finish(err != nil || slow)
+}
+
+// Flush ends the capture and flushes the records regardless of the outcome.
+func (c *Capture) Flush() {
+	c.finish(true)
+}
+
+func (c *Capture) finish(flush bool) bool {
+	c.lock.Lock()
+	if c.finished {
+		c.lock.Unlock()
+		return false
+	}
+	c.finished = true
+	records, dropped := c.ordered(), c.dropped
+	c.records = nil
+	c.lock.Unlock()
+
+	if flush && len(records) > 0 {
+		c.sink(records, dropped)
+	}
+
+	return flush
+}
+
+func (l ModuleLogger) logCtx(ctx context.Context, level Level, format string, args []interface{}) {
+	if c := CaptureFromCtx(ctx); c != nil && c.add(level, l.name, fmt.Sprintf(format, args...)) {
+		return
+	}
+
+	switch level {
+	case DebugLevel:
+		l.Debug(format, args...)
+	case InfoLevel:
+		l.Info(format, args...)
+	case WarnLevel:
+		l.Warn(format, args...)
+	default:
+		l.Error(format, args...)
+	}
+}
+
+// DebugCtx is the same as Debug, except that the log is collected by the Capture of @ctx.
+func (l ModuleLogger) DebugCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logCtx(ctx, DebugLevel, format, args)
+}
+
+// InfoCtx is the same as Info, except that the log is collected by the Capture of @ctx.
+func (l ModuleLogger) InfoCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logCtx(ctx, InfoLevel, format, args)
+}
+
+// WarnCtx is the same as Warn, except that the log is collected by the Capture of @ctx.
+func (l ModuleLogger) WarnCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logCtx(ctx, WarnLevel, format, args)
+}
+
+// ErrorCtx is the same as Error, except that the log is collected by the Capture of @ctx.
+func (l ModuleLogger) ErrorCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logCtx(ctx, ErrorLevel, format, args)
+}
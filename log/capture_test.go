@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type sinkRecorder struct {
+	records []Record
+	dropped int
+	flushes int
+}
+
+func (s *sinkRecorder) sink(records []Record, dropped int) {
+	s.records, s.dropped = records, dropped
+	s.flushes++
+}
+
+func TestCaptureFlushOnError(t *testing.T) {
+	var s sinkRecorder
+	ctx, c := CaptureCtx(context.Background(), WithCaptureSink(s.sink), WithCaptureLimit(3))
+	assert.Equal(t, c, CaptureFromCtx(ctx))
+	assert.Nil(t, CaptureFromCtx(context.Background()))
+
+	l := GetModuleLogger("gost.test.capture")
+	// the debug logs are collected even though the module level is info
+	for i := 0; i < 5; i++ {
+		l.DebugCtx(ctx, "step %d", i)
+	}
+	l.ErrorCtx(ctx, "failed")
+	assert.Equal(t, 3, c.Dropped())
+	records := c.Records()
+	assert.Len(t, records, 3)
+	assert.Equal(t, "step 3", records[0].Message)
+	assert.Equal(t, "failed", records[2].Message)
+	assert.Equal(t, ErrorLevel, records[2].Level)
+	assert.Equal(t, "gost.test.capture", records[2].Module)
+
+	assert.True(t, c.Finish(errors.New("boom")))
+	assert.Equal(t, 1, s.flushes)
+	assert.Len(t, s.records, 3)
+	assert.Equal(t, 3, s.dropped)
+
+	// finished once, the later logs are printed as usual
+	assert.False(t, c.Finish(errors.New("again")))
+	l.InfoCtx(ctx, "after")
+	assert.Equal(t, 1, s.flushes)
+}
+
+func TestCaptureDiscard(t *testing.T) {
+	var s sinkRecorder
+	ctx, c := CaptureCtx(context.Background(), WithCaptureSink(s.sink), WithCaptureLevel(InfoLevel))
+	l := GetModuleLogger("gost.test.capture")
+	l.DebugCtx(ctx, "printed as usual")
+	l.InfoCtx(ctx, "ok")
+	l.WarnCtx(ctx, "retrying")
+	assert.Len(t, c.Records(), 2)
+
+	assert.False(t, c.Finish(nil))
+	assert.Equal(t, 0, s.flushes)
+
+	// a slow request is flushed
+	ctx, c = CaptureCtx(context.Background(), WithCaptureSink(s.sink), WithCaptureThreshold(time.Millisecond))
+	l.InfoCtx(ctx, "slow")
+	time.Sleep(5 * time.Millisecond)
+	assert.True(t, c.Finish(nil))
+	assert.Equal(t, 1, s.flushes)
+	assert.Equal(t, "slow", s.records[0].Message)
+
+	ctx, c = CaptureCtx(context.Background())
+	l.InfoCtx(ctx, "flushed to stdout")
+	c.Flush()
+
+	assert.Panics(t, func() { CaptureCtx(context.Background(), WithCaptureLimit(0)) })
+}
@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxlog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Level is the severity of a log.
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	FatalLevel
+)
+
+var levelNames = [...]string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+func (l Level) String() string {
+	if l < DebugLevel || l > FatalLevel {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+
+	return levelNames[l]
+}
+
+// ParseLevel parses a case insensitive level name such as "debug".
+func ParseLevel(name string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(n, name) {
+			return Level(i), nil
+		}
+	}
+
+	return InfoLevel, fmt.Errorf("unknown log level %q", name)
+}
+
+/////////////////////////////////////////
+// module level registry
+/////////////////////////////////////////
+
+var (
+	levelLock    sync.RWMutex
+	rootLevel    = InfoLevel
+	moduleLevels = make(map[string]Level)
+)
+
+// SetRootLevel sets the level of the modules without their own level or an ancestor's.
+func SetRootLevel(level Level) {
+	levelLock.Lock()
+	rootLevel = level
+	levelLock.Unlock()
+}
+
+// SetModuleLevel sets the level of module @name, such as "gost.time.wheel", which is
+// inherited by its descendants ("gost.time.wheel.*") without their own level. It can
+// be invoked at runtime, e.g. to turn on debug logs of just one module.
+func SetModuleLevel(name string, level Level) {
+	levelLock.Lock()
+	moduleLevels[name] = level
+	levelLock.Unlock()
+}
+
+// UnsetModuleLevel removes the level of module @name, which inherits the level of its
+// ancestors again.
+func UnsetModuleLevel(name string) {
+	levelLock.Lock()
+	delete(moduleLevels, name)
+	levelLock.Unlock()
+}
+
+// ModuleLevel returns the effective level of module @name: its own level, or the
+// level of its nearest ancestor, or the root level.
+func ModuleLevel(name string) Level {
+	levelLock.RLock()
+	defer levelLock.RUnlock()
+
+	for {
+		if level, ok := moduleLevels[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return rootLevel
+		}
+		name = name[:i]
+	}
+}
+
+// ModuleLevels returns a copy of the levels set by SetModuleLevel.
+func ModuleLevels() map[string]Level {
+	levelLock.RLock()
+	defer levelLock.RUnlock()
+
+	levels := make(map[string]Level, len(moduleLevels))
+	for name, level := range moduleLevels {
+		levels[name] = level
+	}
+
+	return levels
+}
+
+// ModuleLogger prints the colorful logs of a module whose level is enabled.
+type ModuleLogger struct {
+	name string
+}
+
+// GetModuleLogger returns the logger of module @name.
+func GetModuleLogger(name string) ModuleLogger {
+	return ModuleLogger{name: name}
+}
+
+// Name returns the module name of the logger.
+func (l ModuleLogger) Name() string {
+	return l.name
+}
+
+// Enabled reports whether the logs of @level are printed.
+func (l ModuleLogger) Enabled(level Level) bool {
+	return level >= ModuleLevel(l.name)
+}
+
+func (l ModuleLogger) prepend(args []interface{}) []interface{} {
+	return append([]interface{}{l.name}, args...)
+}
+
+func (l ModuleLogger) Debug(format string, args ...interface{}) {
+	if l.Enabled(DebugLevel) {
+		CPrintfln(NORMAL, "[%s] "+format, l.prepend(args)...)
+	}
+}
+
+func (l ModuleLogger) Info(format string, args ...interface{}) {
+	if l.Enabled(InfoLevel) {
+		CPrintfln(NGreen, "[%s] "+format, l.prepend(args)...)
+	}
+}
+
+func (l ModuleLogger) Warn(format string, args ...interface{}) {
+	if l.Enabled(WarnLevel) {
+		CEPrintfln(BMagenta, "[%s] "+format, l.prepend(args)...)
+	}
+}
+
+func (l ModuleLogger) Error(format string, args ...interface{}) {
+	if l.Enabled(ErrorLevel) {
+		CEPrintfln(NRed, "[%s] "+format, l.prepend(args)...)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxlog
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("warn")
+	assert.Nil(t, err)
+	assert.Equal(t, WarnLevel, level)
+	assert.Equal(t, "WARN", level.String())
+	_, err = ParseLevel("verbose")
+	assert.NotNil(t, err)
+	assert.Equal(t, "Level(9)", Level(9).String())
+}
+
+func TestModuleLevel(t *testing.T) {
+	defer func() {
+		SetRootLevel(InfoLevel)
+		UnsetModuleLevel("gost")
+		UnsetModuleLevel("gost.time.wheel")
+	}()
+
+	assert.Equal(t, InfoLevel, ModuleLevel("gost.time.wheel"))
+	SetModuleLevel("gost", ErrorLevel)
+	SetModuleLevel("gost.time.wheel", DebugLevel)
+	assert.Equal(t, DebugLevel, ModuleLevel("gost.time.wheel"))
+	assert.Equal(t, DebugLevel, ModuleLevel("gost.time.wheel.slot"))
+	assert.Equal(t, ErrorLevel, ModuleLevel("gost.net.pool"))
+	assert.Equal(t, InfoLevel, ModuleLevel("dubbo"))
+	assert.Len(t, ModuleLevels(), 2)
+
+	wheel, pool := GetModuleLogger("gost.time.wheel"), GetModuleLogger("gost.net.pool")
+	assert.Equal(t, "gost.time.wheel", wheel.Name())
+	assert.True(t, wheel.Enabled(DebugLevel))
+	assert.False(t, pool.Enabled(WarnLevel))
+	wheel.Debug("tick %d", 1)
+	pool.Info("discarded")
+
+	UnsetModuleLevel("gost.time.wheel")
+	assert.Equal(t, ErrorLevel, ModuleLevel("gost.time.wheel"))
+	SetRootLevel(WarnLevel)
+	assert.Equal(t, WarnLevel, ModuleLevel("dubbo"))
+}
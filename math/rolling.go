@@ -0,0 +1,235 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxmath
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// madScale makes the MAD a consistent estimator of the standard deviation of normal data.
+const madScale = 0.6745
+
+// RollingWindow keeps the latest samples of a sliding window of fixed size, and tracks
+// their mean and standard deviation in O(1). It is not goroutine safe.
+type RollingWindow struct {
+	samples []float64
+	next    int
+	full    bool
+	shift   float64 // the sums are of the samples minus it, to keep the precision of large samples with a small spread
+	sum     float64
+	sumSq   float64
+	adds    int // adds since the sums are recomputed, to wipe out the float error
+}
+
+// NewRollingWindow returns a window of @size samples.
+func NewRollingWindow(size int) *RollingWindow {
+	if size <= 0 {
+		panic("@size <= 0")
+	}
+
+	return &RollingWindow{samples: make([]float64, size)}
+}
+
+// Add adds sample @x, which pushes out the oldest one if the window is full.
+func (w *RollingWindow) Add(x float64) {
+	if w.Len() == 0 {
+		w.shift = x
+	}
+	if w.full {
+		old := w.samples[w.next] - w.shift
+		w.sum -= old
+		w.sumSq -= old * old
+	}
+	w.samples[w.next] = x
+	d := x - w.shift
+	w.sum += d
+	w.sumSq += d * d
+	if w.next++; w.next == len(w.samples) {
+		w.next, w.full = 0, true
+	}
+
+	if w.adds++; w.adds >= 16*len(w.samples) {
+		// shift by the current mean as the samples may have drifted far from the old shift
+		w.shift = w.Mean()
+		w.adds, w.sum, w.sumSq = 0, 0, 0
+		for _, s := range w.Samples() {
+			d := s - w.shift
+			w.sum += d
+			w.sumSq += d * d
+		}
+	}
+}
+
+// Len returns the number of samples in the window.
+func (w *RollingWindow) Len() int {
+	if w.full {
+		return len(w.samples)
+	}
+
+	return w.next
+}
+
+// Samples returns a copy of the samples from the oldest one.
+func (w *RollingWindow) Samples() []float64 {
+	if !w.full {
+		return append([]float64(nil), w.samples[:w.next]...)
+	}
+
+	samples := make([]float64, 0, len(w.samples))
+	samples = append(samples, w.samples[w.next:]...)
+
+	return append(samples, w.samples[:w.next]...)
+}
+
+// Mean returns the mean of the samples, or 0 if there is none.
+func (w *RollingWindow) Mean() float64 {
+	n := w.Len()
+	if n == 0 {
+		return 0
+	}
+
+	return w.shift + w.sum/float64(n)
+}
+
+// StdDev returns the population standard deviation of the samples.
+func (w *RollingWindow) StdDev() float64 {
+	n := w.Len()
+	if n == 0 {
+		return 0
+	}
+
+	mean := w.sum / float64(n)
+	variance := w.sumSq/float64(n) - mean*mean // invariant to the shift
+	if variance <= 0 {
+		return 0
+	}
+
+	return math.Sqrt(variance)
+}
+
+// ZScore returns how many standard deviations @x is away from the mean. It is 0 if
+// the standard deviation is 0.
+func (w *RollingWindow) ZScore(x float64) float64 {
+	stddev := w.StdDev()
+	if stddev == 0 {
+		return 0
+	}
+
+	return (x - w.Mean()) / stddev
+}
+
+// Median returns the median of the samples in O(n log n).
+func (w *RollingWindow) Median() float64 {
+	return median(w.Samples())
+}
+
+// MAD returns the median and the median absolute deviation of the samples in O(n log n).
+func (w *RollingWindow) MAD() (med, mad float64) {
+	samples := w.Samples()
+	med = median(samples)
+	for i, s := range samples {
+		samples[i] = math.Abs(s - med)
+	}
+
+	return med, median(samples)
+}
+
+// ModifiedZScore returns the robust z-score of @x by the median and the MAD, which
+// outliers of the window hardly affect. It is 0 if the MAD is 0.
+func (w *RollingWindow) ModifiedZScore(x float64) float64 {
+	med, mad := w.MAD()
+	if mad == 0 {
+		return 0
+	}
+
+	return madScale * (x - med) / mad
+}
+
+// median sorts @samples in place.
+func median(samples []float64) float64 {
+	n := len(samples)
+	if n == 0 {
+		return 0
+	}
+
+	sort.Float64s(samples)
+	if n%2 == 1 {
+		return samples[n/2]
+	}
+
+	return (samples[n/2-1] + samples[n/2]) / 2
+}
+
+// Anomaly is the verdict of AnomalyDetector.Observe on a sample.
+type Anomaly struct {
+	ZScore         float64
+	ModifiedZScore float64
+	ZAnomaly       bool // |ZScore| exceeds the z-score threshold
+	MADAnomaly     bool // |ModifiedZScore| exceeds the MAD threshold
+}
+
+// IsAnomaly reports whether either score flags the sample.
+func (a Anomaly) IsAnomaly() bool {
+	return a.ZAnomaly || a.MADAnomaly
+}
+
+// AnomalyDetector flags the samples far from the recent ones, such as a latency
+// spike, by both the z-score and the MAD based modified z-score over a sliding window.
+// It is goroutine safe.
+type AnomalyDetector struct {
+	lock         sync.Mutex
+	window       *RollingWindow
+	minSamples   int
+	zThreshold   float64
+	madThreshold float64
+}
+
+// NewAnomalyDetector returns a detector over the latest @size samples. The common
+// thresholds are 3 for @zThreshold and 3.5 for @madThreshold, and a non-positive one
+// turns off its check. No sample is flagged until the window holds @minSamples samples.
+func NewAnomalyDetector(size, minSamples int, zThreshold, madThreshold float64) *AnomalyDetector {
+	return &AnomalyDetector{
+		window:       NewRollingWindow(size),
+		minSamples:   minSamples,
+		zThreshold:   zThreshold,
+		madThreshold: madThreshold,
+	}
+}
+
+// Observe judges @x against the window and then adds it into the window.
+func (d *AnomalyDetector) Observe(x float64) Anomaly {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var a Anomaly
+	if d.window.Len() >= d.minSamples && d.window.Len() > 0 {
+		if d.zThreshold > 0 {
+			a.ZScore = d.window.ZScore(x)
+			a.ZAnomaly = math.Abs(a.ZScore) > d.zThreshold
+		}
+		if d.madThreshold > 0 {
+			a.ModifiedZScore = d.window.ModifiedZScore(x)
+			a.MADAnomaly = math.Abs(a.ModifiedZScore) > d.madThreshold
+		}
+	}
+	d.window.Add(x)
+
+	return a
+}
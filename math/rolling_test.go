@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxmath
+
+import (
+	"math"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingWindow(t *testing.T) {
+	w := NewRollingWindow(4)
+	assert.Equal(t, 0.0, w.Mean())
+	assert.Equal(t, 0.0, w.StdDev())
+
+	for _, x := range []float64{1, 2, 3, 4} {
+		w.Add(x)
+	}
+	assert.Equal(t, 4, w.Len())
+	assert.Equal(t, 2.5, w.Mean())
+	assert.True(t, DeltaCompareFloat64(math.Sqrt(1.25), w.StdDev(), 1e-9))
+	assert.Equal(t, 2.5, w.Median())
+
+	// 1 is pushed out
+	w.Add(10)
+	assert.Equal(t, []float64{2, 3, 4, 10}, w.Samples())
+	assert.Equal(t, 4.75, w.Mean())
+	med, mad := w.MAD()
+	assert.Equal(t, 3.5, med)
+	assert.Equal(t, 1.0, mad)
+	assert.True(t, DeltaCompareFloat64(madScale*6.5, w.ModifiedZScore(10), 1e-9))
+	assert.True(t, w.ZScore(10) > 1)
+
+	for i := 0; i < 1000; i++ {
+		w.Add(float64(i % 4))
+	}
+	assert.True(t, DeltaCompareFloat64(1.5, w.Mean(), 1e-9))
+}
+
+func TestRollingWindowLargeSamples(t *testing.T) {
+	// 1s latencies in nanoseconds with a spread of a few nanoseconds
+	w := NewRollingWindow(100)
+	for i := 0; i < 100; i++ {
+		w.Add(1e9 + float64(i%10))
+	}
+	stddev := math.Sqrt(99.0 / 12)
+	assert.True(t, DeltaCompareFloat64(1e9+4.5, w.Mean(), 1e-6))
+	assert.True(t, DeltaCompareFloat64(stddev, w.StdDev(), 1e-6))
+	assert.True(t, w.ZScore(1e9+100) > 3)
+
+	// the sums are recomputed while the samples drift
+	for i := 0; i < 5000; i++ {
+		w.Add(2e9 + float64(i%10))
+	}
+	assert.True(t, DeltaCompareFloat64(2e9+4.5, w.Mean(), 1e-6))
+	assert.True(t, DeltaCompareFloat64(stddev, w.StdDev(), 1e-6))
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	d := NewAnomalyDetector(50, 10, 3, 3.5)
+	assert.False(t, d.Observe(1000).IsAnomaly())
+
+	for i := 0; i < 50; i++ {
+		a := d.Observe(10 + float64(i%5))
+		assert.False(t, a.IsAnomaly(), "sample %d", i)
+	}
+
+	a := d.Observe(100)
+	assert.True(t, a.ZAnomaly)
+	assert.True(t, a.MADAnomaly)
+	assert.True(t, a.IsAnomaly())
+	assert.False(t, d.Observe(12).IsAnomaly())
+
+	madOnly := NewAnomalyDetector(50, 10, 0, 3.5)
+	for i := 0; i < 20; i++ {
+		madOnly.Observe(10 + float64(i%3))
+	}
+	a = madOnly.Observe(30)
+	assert.False(t, a.ZAnomaly)
+	assert.True(t, a.MADAnomaly)
+}
@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPauseInterval is how often a paused listener polls its saturation check.
+const DefaultPauseInterval = 10 * time.Millisecond
+
+// AcceptStats is a snapshot of the statistics of a RateLimitedListener.
+type AcceptStats struct {
+	Accepted  uint64 // connections accepted so far
+	Throttled uint64 // accepts delayed for want of a token
+	Paused    uint64 // accepts delayed by the backpressure
+}
+
+// AcceptOption configures a RateLimitedListener.
+type AcceptOption func(*RateLimitedListener)
+
+// WithSaturation pauses accepting while @saturated returns true, e.g. when the worker
+// pool downstream is full, and polls it every @interval. A non-positive @interval means
+// DefaultPauseInterval.
+func WithSaturation(saturated func() bool, interval time.Duration) AcceptOption {
+	return func(l *RateLimitedListener) {
+		if interval <= 0 {
+			interval = DefaultPauseInterval
+		}
+		l.saturated, l.pauseInterval = saturated, interval
+	}
+}
+
+// RateLimitedListener paces the accepts of a listener by a token bucket, and stops
+// accepting while the service is saturated. The connections kept waiting stay in the
+// backlog of the kernel, so a reconnect storm after a network blip is smoothed out
+// instead of being handed to the service at once.
+type RateLimitedListener struct {
+	accepted  uint64 // first for the 64-bit alignment on the 32-bit platforms
+	throttled uint64
+	pauses    uint64
+
+	net.Listener
+
+	rate          float64 // tokens per second, non-positive for no pacing
+	burst         float64
+	saturated     func() bool
+	pauseInterval time.Duration
+
+	lock    sync.Mutex
+	tokens  float64
+	last    time.Time
+	paused  bool
+	changed chan struct{} // closed and replaced on every Pause and Resume
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewRateLimitedListener wraps @l to accept @rate connections per second at most, with
+// bursts of @burst connections. A non-positive @rate means no pacing.
+func NewRateLimitedListener(l net.Listener, rate float64, burst int, opts ...AcceptOption) *RateLimitedListener {
+	if burst < 1 {
+		burst = 1
+	}
+
+	rl := &RateLimitedListener{
+		Listener: l,
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+		changed:  make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(rl)
+	}
+
+	return rl
+}
+
+// Accept waits until the listener is neither paused nor saturated and a token is
+// available, and then accepts a connection. It returns net.ErrClosed once the listener
+// is closed.
+func (l *RateLimitedListener) Accept() (net.Conn, error) {
+	if err := l.waitReady(); err != nil {
+		return nil, err
+	}
+	if err := l.waitToken(); err != nil {
+		return nil, err
+	}
+
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		atomic.AddUint64(&l.accepted, 1)
+	}
+
+	return conn, err
+}
+
+func (l *RateLimitedListener) waitReady() error {
+	var (
+		counted bool
+		poll    *time.Timer
+	)
+	defer func() {
+		if poll != nil {
+			poll.Stop()
+		}
+	}()
+
+	for {
+		l.lock.Lock()
+		paused, changed := l.paused, l.changed
+		l.lock.Unlock()
+		if !paused && (l.saturated == nil || !l.saturated()) {
+			return nil
+		}
+		if !counted {
+			counted = true
+			atomic.AddUint64(&l.pauses, 1)
+		}
+
+		var polled <-chan time.Time
+		if l.saturated != nil {
+			if poll == nil {
+				poll = time.NewTimer(l.pauseInterval)
+			} else {
+				poll.Reset(l.pauseInterval)
+			}
+			polled = poll.C
+		}
+		select {
+		case <-changed:
+			if poll != nil && !poll.Stop() {
+				<-poll.C
+			}
+		case <-polled:
+		case <-l.closed:
+			return net.ErrClosed
+		}
+	}
+}
+
+// waitToken takes a token, reserving a future one if the bucket is empty, so the
+// concurrent accepts are served in order.
+func (l *RateLimitedListener) waitToken() error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.lock.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	deficit := -l.tokens
+	l.lock.Unlock()
+	if deficit <= 0 {
+		return nil
+	}
+
+	atomic.AddUint64(&l.throttled, 1)
+	t := time.NewTimer(time.Duration(deficit / l.rate * float64(time.Second)))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-l.closed:
+		return net.ErrClosed
+	}
+}
+
+// Pause stops accepting until Resume, e.g. while the service drains.
+func (l *RateLimitedListener) Pause() {
+	l.setPaused(true)
+}
+
+// Resume undoes Pause.
+func (l *RateLimitedListener) Resume() {
+	l.setPaused(false)
+}
+
+func (l *RateLimitedListener) setPaused(paused bool) {
+	l.lock.Lock()
+	if l.paused != paused {
+		l.paused = paused
+		close(l.changed)
+		l.changed = make(chan struct{})
+	}
+	l.lock.Unlock()
+}
+
+// Stats returns a snapshot of the statistics of the listener.
+func (l *RateLimitedListener) Stats() AcceptStats {
+	return AcceptStats{
+		Accepted:  atomic.LoadUint64(&l.accepted),
+		Throttled: atomic.LoadUint64(&l.throttled),
+		Paused:    atomic.LoadUint64(&l.pauses),
+	}
+}
+
+// Close closes the listener, and wakes up the waiting accepts.
+func (l *RateLimitedListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return l.Listener.Close()
+}
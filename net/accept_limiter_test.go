@@ -0,0 +1,133 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func newLimitedListener(t *testing.T, rate float64, burst int, opts ...AcceptOption) *RateLimitedListener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	return NewRateLimitedListener(l, rate, burst, opts...)
+}
+
+func dial(t *testing.T, l net.Listener, n int) []net.Conn {
+	conns := make([]net.Conn, 0, n)
+	for i := 0; i < n; i++ {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		assert.Nil(t, err)
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+func closeAll(conns []net.Conn) {
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+func TestRateLimitedListenerPacing(t *testing.T) {
+	l := newLimitedListener(t, 100, 2)
+	defer l.Close()
+	defer closeAll(dial(t, l, 6))
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		conn, err := l.Accept()
+		assert.Nil(t, err)
+		conn.Close()
+	}
+	// 2 at once and 4 more at 100/s
+	assert.True(t, time.Since(start) >= 35*time.Millisecond)
+
+	stats := l.Stats()
+	assert.Equal(t, uint64(6), stats.Accepted)
+	assert.Equal(t, uint64(4), stats.Throttled)
+	assert.Equal(t, uint64(0), stats.Paused)
+}
+
+func TestRateLimitedListenerSaturation(t *testing.T) {
+	var saturated int32 = 1
+	l := newLimitedListener(t, 0, 1, WithSaturation(func() bool {
+		return atomic.LoadInt32(&saturated) == 1
+	}, time.Millisecond))
+	defer l.Close()
+	defer closeAll(dial(t, l, 1))
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := l.Accept()
+		assert.Nil(t, err)
+		accepted <- conn
+	}()
+
+	select {
+	case <-accepted:
+		t.Fatal("accepted while saturated")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	atomic.StoreInt32(&saturated, 0)
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(time.Second):
+		t.Fatal("not accepted after the saturation")
+	}
+	assert.Equal(t, uint64(1), l.Stats().Paused)
+}
+
+func TestRateLimitedListenerPause(t *testing.T) {
+	l := newLimitedListener(t, 0, 1)
+	defer closeAll(dial(t, l, 1))
+
+	l.Pause()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := l.Accept()
+		assert.Nil(t, err)
+		accepted <- conn
+	}()
+	select {
+	case <-accepted:
+		t.Fatal("accepted while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+	l.Resume()
+	(<-accepted).Close()
+
+	l.Pause()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := l.Accept()
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.Nil(t, l.Close())
+	assert.True(t, errors.Is(<-errs, net.ErrClosed))
+}
@@ -0,0 +1,296 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+const (
+	beaconVersion    = 1
+	maxBeaconPayload = 1024
+	beaconBufferSize = 64 * 1024
+
+	// the backoff of reading the listener conn after a failure
+	beaconMinBackoff = 5 * time.Millisecond
+	beaconMaxBackoff = time.Second
+)
+
+var (
+	beaconMagic = []byte("GXBC")
+
+	ErrInvalidBeacon = perrors.New("invalid beacon message")
+)
+
+// BeaconPeer is a peer discovered by a BeaconListener.
+type BeaconPeer struct {
+	ID     string
+	Meta   []byte       // the payload announced by the peer, such as its service address
+	Source *net.UDPAddr // where the latest announcement came from
+	Last   time.Time    // when the latest announcement was received
+}
+
+// Beacon announces a peer to a UDP multicast group periodically, for LAN-local
+// discovery without a registry.
+type Beacon struct {
+	conn  *net.UDPConn
+	msg   []byte
+	timer *gxtime.Timer
+	once  sync.Once
+}
+
+// NewBeacon announces @id with @meta to @group (such as "239.255.0.1:9999") every
+// @interval on the default gxtime wheel. @group can also be a unicast address.
+func NewBeacon(group string, id string, meta []byte, interval time.Duration) (*Beacon, error) {
+	msg, err := encodeBeacon(id, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", group)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+
+	b := &Beacon{conn: conn, msg: msg}
+	b.announce(nil)
+	b.timer = gxtime.GetDefaultWheel().AddTimer(b.announce, interval, nil)
+
+	return b, nil
+}
+
+func (b *Beacon) announce(interface{}) {
+	// a lost announcement is tolerated by the peer ttl of listeners
+	_, _ = b.conn.Write(b.msg)
+}
+
+// Close stops announcing.
+func (b *Beacon) Close() error {
+	var err error
+	b.once.Do(func() {
+		b.timer.Stop()
+		err = b.conn.Close()
+	})
+
+	return err
+}
+
+// BeaconListener discovers the peers announced to a UDP multicast group. A peer is
+// dropped from its table if it has not announced itself within the ttl.
+type BeaconListener struct {
+	conn     *net.UDPConn
+	ttl      time.Duration
+	onChange func(peer BeaconPeer, joined bool)
+	queue    *gxtime.TimeoutQueue[string]
+	done     chan struct{}
+	once     sync.Once
+
+	lock  sync.RWMutex
+	peers map[string]*BeaconPeer
+}
+
+// NewBeaconListener listens on @group and drops the peers silent for @ttl. @onChange,
+// which can be nil, is invoked when a peer joins or leaves.
+func NewBeaconListener(group string, ttl time.Duration, onChange func(peer BeaconPeer, joined bool)) (*BeaconListener, error) {
+	addr, err := net.ResolveUDPAddr("udp", group)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+
+	var conn *net.UDPConn
+	if addr.IP.IsMulticast() {
+		conn, err = net.ListenMulticastUDP("udp", nil, addr)
+	} else {
+		conn, err = net.ListenUDP("udp", addr)
+	}
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+
+	l := &BeaconListener{
+		conn:     conn,
+		ttl:      ttl,
+		onChange: onChange,
+		done:     make(chan struct{}),
+		peers:    make(map[string]*BeaconPeer),
+	}
+	l.queue = gxtime.NewTimeoutQueue(nil, ttl, l.expire)
+	go l.run()
+
+	return l, nil
+}
+
+// Addr returns the local address of the listener.
+func (l *BeaconListener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+// Peers returns the alive peers.
+func (l *BeaconListener) Peers() []BeaconPeer {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	peers := make([]BeaconPeer, 0, len(l.peers))
+	for _, p := range l.peers {
+		peers = append(peers, *p)
+	}
+
+	return peers
+}
+
+// Close stops listening. The peers are kept as they are.
+func (l *BeaconListener) Close() error {
+	var err error
+	l.once.Do(func() {
+		close(l.done)
+		l.queue.Stop()
+		err = l.conn.Close()
+	})
+
+	return err
+}
+
+func (l *BeaconListener) run() {
+	var (
+		buf     = make([]byte, beaconBufferSize)
+		backoff time.Duration
+	)
+	for {
+		n, src, err := l.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			// back off from a persistent failure instead of spinning on it
+			if backoff *= 2; backoff == 0 {
+				backoff = beaconMinBackoff
+			} else if backoff > beaconMaxBackoff {
+				backoff = beaconMaxBackoff
+			}
+			timer := time.NewTimer(backoff)
+			select {
+			case <-l.done:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			continue
+		}
+		backoff = 0
+
+		id, meta, err := decodeBeacon(buf[:n])
+		if err != nil {
+			continue
+		}
+		l.refresh(id, meta, src)
+	}
+}
+
+func (l *BeaconListener) refresh(id string, meta []byte, src *net.UDPAddr) {
+	l.lock.Lock()
+	p, ok := l.peers[id]
+	if !ok {
+		p = &BeaconPeer{ID: id}
+		l.peers[id] = p
+	}
+	p.Meta = meta
+	p.Source = src
+	p.Last = gxtime.Now()
+	peer := *p
+	l.queue.Add(id)
+	l.lock.Unlock()
+
+	if !ok && l.onChange != nil {
+		l.onChange(peer, true)
+	}
+}
+
+func (l *BeaconListener) expire(id string) {
+	l.lock.Lock()
+	p, ok := l.peers[id]
+	if ok && gxtime.Now().Sub(p.Last) < l.ttl {
+		// refreshed after it was evicted from the queue, which it is added into again
+		l.lock.Unlock()
+		return
+	}
+	delete(l.peers, id)
+	l.lock.Unlock()
+
+	if ok && l.onChange != nil {
+		l.onChange(*p, false)
+	}
+}
+
+// encodeBeacon encodes a beacon message as: magic | version | id length | id | meta length | meta
+func encodeBeacon(id string, meta []byte) ([]byte, error) {
+	if len(id) == 0 || len(id) > maxBeaconPayload || len(meta) > maxBeaconPayload {
+		return nil, perrors.Errorf("beacon id length %d or meta length %d is illegal", len(id), len(meta))
+	}
+
+	var buf bytes.Buffer
+	buf.Write(beaconMagic)
+	buf.WriteByte(beaconVersion)
+	_ = binary.Write(&buf, binary.BigEndian, uint16(len(id)))
+	buf.WriteString(id)
+	_ = binary.Write(&buf, binary.BigEndian, uint16(len(meta)))
+	buf.Write(meta)
+
+	return buf.Bytes(), nil
+}
+
+func decodeBeacon(msg []byte) (string, []byte, error) {
+	if len(msg) < len(beaconMagic)+1+2 || !bytes.Equal(msg[:len(beaconMagic)], beaconMagic) ||
+		msg[len(beaconMagic)] != beaconVersion {
+		return "", nil, ErrInvalidBeacon
+	}
+
+	msg = msg[len(beaconMagic)+1:]
+	idLen := int(binary.BigEndian.Uint16(msg))
+	msg = msg[2:]
+	if len(msg) < idLen+2 {
+		return "", nil, ErrInvalidBeacon
+	}
+	id := string(msg[:idLen])
+	msg = msg[idLen:]
+
+	metaLen := int(binary.BigEndian.Uint16(msg))
+	msg = msg[2:]
+	if len(msg) != metaLen || idLen == 0 {
+		return "", nil, ErrInvalidBeacon
+	}
+	meta := append([]byte(nil), msg...)
+
+	return id, meta, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeaconCodec(t *testing.T) {
+	msg, err := encodeBeacon("node-1", []byte("10.0.0.1:20000"))
+	assert.Nil(t, err)
+	id, meta, err := decodeBeacon(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, "node-1", id)
+	assert.Equal(t, []byte("10.0.0.1:20000"), meta)
+
+	_, _, err = decodeBeacon(msg[:len(msg)-1])
+	assert.Equal(t, ErrInvalidBeacon, err)
+	_, _, err = decodeBeacon([]byte("hello world"))
+	assert.Equal(t, ErrInvalidBeacon, err)
+	_, err = encodeBeacon("", nil)
+	assert.NotNil(t, err)
+}
+
+func TestBeacon(t *testing.T) {
+	var (
+		lock   sync.Mutex
+		events []bool
+	)
+	l, err := NewBeaconListener("127.0.0.1:0", 100*time.Millisecond, func(peer BeaconPeer, joined bool) {
+		lock.Lock()
+		events = append(events, joined)
+		lock.Unlock()
+	})
+	assert.Nil(t, err)
+	defer l.Close()
+
+	b, err := NewBeacon(l.Addr().String(), "node-1", []byte("meta"), 20*time.Millisecond)
+	assert.Nil(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	peers := l.Peers()
+	assert.Equal(t, 1, len(peers))
+	assert.Equal(t, "node-1", peers[0].ID)
+	assert.Equal(t, []byte("meta"), peers[0].Meta)
+
+	assert.Nil(t, b.Close())
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 0, len(l.Peers()))
+
+	lock.Lock()
+	assert.Equal(t, []bool{true, false}, events)
+	lock.Unlock()
+}
+
+func TestBeaconListenerRefreshAndErrors(t *testing.T) {
+	l, err := NewBeaconListener("127.0.0.1:0", time.Second, nil)
+	assert.Nil(t, err)
+	defer l.Close()
+
+	// a peer refreshed after its eviction from the queue is kept
+	l.refresh("node-1", nil, nil)
+	l.expire("node-1")
+	assert.Equal(t, 1, len(l.Peers()))
+
+	// the reads failing for a while back off, and the listener recovers afterwards
+	assert.Nil(t, l.conn.SetReadDeadline(time.Now()))
+	time.Sleep(50 * time.Millisecond)
+	assert.Nil(t, l.conn.SetReadDeadline(time.Time{}))
+
+	b, err := NewBeacon(l.Addr().String(), "node-2", nil, 20*time.Millisecond)
+	assert.Nil(t, err)
+	defer b.Close()
+	assert.Eventually(t, func() bool { return len(l.Peers()) == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestBeaconMulticast(t *testing.T) {
+	l, err := NewBeaconListener("239.255.77.77:19777", time.Second, nil)
+	if err != nil {
+		t.Skipf("multicast is not available: %v", err)
+	}
+	defer l.Close()
+
+	b, err := NewBeacon("239.255.77.77:19777", "node-2", nil, 20*time.Millisecond)
+	assert.Nil(t, err)
+	defer b.Close()
+
+	time.Sleep(100 * time.Millisecond)
+	t.Logf("multicast peers: %+v", l.Peers())
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"context"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+// HedgeFunc is one attempt of a hedged request. It should return asap when @ctx is done.
+type HedgeFunc func(ctx context.Context) (interface{}, error)
+
+type hedgeResult struct {
+	value interface{}
+	err   error
+}
+
+// Hedge runs @fs[0] at once, and starts the next attempt when the running ones have not
+// completed within @delay or when all of them have failed. The first successful result
+// is returned and the other attempts are cancelled. If every attempt fails, the error of
+// the last one is returned.
+// @delay is scheduled on the default gxtime wheel, so it should be less than its period.
+// A non-positive @delay starts all attempts at once.
+func Hedge(ctx context.Context, delay time.Duration, fs ...HedgeFunc) (interface{}, error) {
+	if len(fs) == 0 {
+		return nil, perrors.New("Hedge: no attempt")
+	}
+
+	wheel := gxtime.GetDefaultWheel()
+	if delay >= wheel.Period() {
+		return nil, perrors.Errorf("Hedge: delay %s is not less than the wheel period %s", delay, wheel.Period())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		started  int
+		finished int
+		err      error
+		backup   <-chan struct{}
+		results  = make(chan hedgeResult, len(fs))
+	)
+
+	launch := func() {
+		f := fs[started]
+		started++
+		go func() {
+			v, e := f(ctx)
+			results <- hedgeResult{value: v, err: e}
+		}()
+
+		backup = nil
+		if started < len(fs) && delay > 0 {
+			backup = wheel.After(delay)
+		}
+	}
+
+	launch()
+	for delay <= 0 && started < len(fs) {
+		launch()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-backup:
+			launch()
+
+		case r := <-results:
+			finished++
+			if r.err == nil {
+				return r.value, nil
+			}
+
+			err = r.err
+			if finished == len(fs) {
+				return nil, err
+			}
+			if finished == started {
+				launch()
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHedgeBackupWins(t *testing.T) {
+	var cancelled int32
+	primary := func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-ctx.Done():
+			atomic.StoreInt32(&cancelled, 1)
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return "primary", nil
+		}
+	}
+	backup := func(ctx context.Context) (interface{}, error) {
+		return "backup", nil
+	}
+
+	start := time.Now()
+	v, err := Hedge(context.Background(), 50*time.Millisecond, primary, backup)
+	assert.Nil(t, err)
+	assert.Equal(t, "backup", v)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cancelled))
+}
+
+func TestHedgePrimaryWins(t *testing.T) {
+	var backupCalled int32
+	primary := func(ctx context.Context) (interface{}, error) {
+		return 1, nil
+	}
+	backup := func(ctx context.Context) (interface{}, error) {
+		atomic.StoreInt32(&backupCalled, 1)
+		return 2, nil
+	}
+
+	v, err := Hedge(context.Background(), 100*time.Millisecond, primary, backup)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v)
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&backupCalled))
+}
+
+func TestHedgeFailures(t *testing.T) {
+	errFailed := errors.New("failed")
+	fail := func(ctx context.Context) (interface{}, error) {
+		return nil, errFailed
+	}
+
+	// the backup should be started right after the primary failed
+	start := time.Now()
+	_, err := Hedge(context.Background(), 30*time.Second, fail, fail)
+	assert.Equal(t, errFailed, err)
+	assert.True(t, time.Since(start) < time.Second)
+
+	_, err = Hedge(context.Background(), time.Hour, fail)
+	assert.NotNil(t, err)
+
+	_, err = Hedge(context.Background(), time.Millisecond)
+	assert.NotNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Hedge(ctx, 0, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	assert.Equal(t, context.Canceled, err)
+}
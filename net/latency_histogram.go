@@ -0,0 +1,251 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+const (
+	// every power of two range of microseconds is split into histogramSubBuckets
+	// linear buckets, which bounds the relative error of the quantiles by 1/16
+	histogramSubBits    = 4
+	histogramSubBuckets = 1 << histogramSubBits
+	histogramBuckets    = histogramSubBuckets * 40
+
+	// the counts are decayed in steps of 1/8 half-life instead of on every record
+	decaySteps = 8
+)
+
+// HistogramSnapshot is the decayed figures of a LatencyHistogram.
+type HistogramSnapshot struct {
+	Count float64 // decayed number of the samples
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// LatencyHistogram is a log-linear histogram of response times in the HDR style, whose
+// counts decay exponentially with @halfLife, so that its quantiles reflect the recent
+// samples. It is goroutine safe.
+type LatencyHistogram struct {
+	lock     sync.Mutex
+	halfLife time.Duration
+	last     time.Time // last time the counts are decayed
+	counts   [histogramBuckets]float64
+	total    float64
+	sum      float64 // decayed sum of the samples in microseconds
+}
+
+// NewLatencyHistogram returns a histogram whose counts halve every @halfLife. A
+// non-positive @halfLife means no decay.
+func NewLatencyHistogram(halfLife time.Duration) *LatencyHistogram {
+	return &LatencyHistogram{halfLife: halfLife, last: gxtime.Now()}
+}
+
+func histogramIndex(us uint64) int {
+	if us < histogramSubBuckets {
+		return int(us)
+	}
+
+	shift := bits.Len64(us) - histogramSubBits - 1
+	index := histogramSubBuckets + shift*histogramSubBuckets + int(us>>uint(shift)) - histogramSubBuckets
+	if index >= histogramBuckets {
+		index = histogramBuckets - 1
+	}
+
+	return index
+}
+
+// histogramValue returns the middle of the range of bucket @index in microseconds.
+func histogramValue(index int) float64 {
+	if index < histogramSubBuckets {
+		return float64(index)
+	}
+
+	shift := (index - histogramSubBuckets) / histogramSubBuckets
+	low := uint64(index-histogramSubBuckets*shift) << uint(shift)
+
+	return float64(low) + float64(uint64(1)<<uint(shift))/2
+}
+
+// decay should be invoked with the lock held.
+func (h *LatencyHistogram) decay(now time.Time) {
+	if h.halfLife <= 0 {
+		return
+	}
+	elapsed := now.Sub(h.last)
+	if elapsed < h.halfLife/decaySteps {
+		return
+	}
+
+	h.last = now
+	factor := math.Exp2(-float64(elapsed) / float64(h.halfLife))
+	for i := range h.counts {
+		h.counts[i] *= factor
+	}
+	h.total *= factor
+	h.sum *= factor
+}
+
+// Record records a response time of @d.
+func (h *LatencyHistogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	us := uint64(d / time.Microsecond)
+
+	h.lock.Lock()
+	h.decay(gxtime.Now())
+	h.counts[histogramIndex(us)]++
+	h.total++
+	h.sum += float64(us)
+	h.lock.Unlock()
+}
+
+// quantile should be invoked with the lock held.
+func (h *LatencyHistogram) quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+
+	rank := q * h.total
+	var seen float64
+	for i, c := range h.counts {
+		seen += c
+		if c > 0 && seen >= rank {
+			return time.Duration(histogramValue(i) * float64(time.Microsecond))
+		}
+	}
+
+	return time.Duration(histogramValue(histogramBuckets-1) * float64(time.Microsecond))
+}
+
+// Quantile returns the @q-th quantile of the response times, 0 <= @q <= 1.
+func (h *LatencyHistogram) Quantile(q float64) time.Duration {
+	if q < 0 || q > 1 {
+		panic("@q out of [0, 1]")
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.decay(gxtime.Now())
+	return h.quantile(q)
+}
+
+// Snapshot returns the decayed figures of the histogram.
+func (h *LatencyHistogram) Snapshot() HistogramSnapshot {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.decay(gxtime.Now())
+	s := HistogramSnapshot{
+		Count: h.total,
+		P50:   h.quantile(0.5),
+		P95:   h.quantile(0.95),
+		P99:   h.quantile(0.99),
+	}
+	if h.total > 0 {
+		s.Mean = time.Duration(h.sum / h.total * float64(time.Microsecond))
+	}
+
+	return s
+}
+
+// EndpointHistograms keeps a LatencyHistogram per endpoint, e.g. per address, whose
+// snapshots can feed a latency aware balancer or be exported. It is goroutine safe.
+type EndpointHistograms struct {
+	lock       sync.RWMutex
+	halfLife   time.Duration
+	histograms map[string]*LatencyHistogram
+}
+
+// NewEndpointHistograms returns an empty set of histograms decaying with @halfLife.
+func NewEndpointHistograms(halfLife time.Duration) *EndpointHistograms {
+	return &EndpointHistograms{halfLife: halfLife, histograms: make(map[string]*LatencyHistogram)}
+}
+
+// Histogram returns the histogram of @endpoint, which is created on first use.
+func (e *EndpointHistograms) Histogram(endpoint string) *LatencyHistogram {
+	e.lock.RLock()
+	h, ok := e.histograms[endpoint]
+	e.lock.RUnlock()
+	if ok {
+		return h
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if h, ok = e.histograms[endpoint]; !ok {
+		h = NewLatencyHistogram(e.halfLife)
+		e.histograms[endpoint] = h
+	}
+
+	return h
+}
+
+// Record records a response time of @d of @endpoint.
+func (e *EndpointHistograms) Record(endpoint string, d time.Duration) {
+	e.Histogram(endpoint).Record(d)
+}
+
+// Snapshot returns the figures of @endpoint, and false if it has no histogram.
+func (e *EndpointHistograms) Snapshot(endpoint string) (HistogramSnapshot, bool) {
+	e.lock.RLock()
+	h, ok := e.histograms[endpoint]
+	e.lock.RUnlock()
+	if !ok {
+		return HistogramSnapshot{}, false
+	}
+
+	return h.Snapshot(), true
+}
+
+// SnapshotAll returns the figures of all the endpoints.
+func (e *EndpointHistograms) SnapshotAll() map[string]HistogramSnapshot {
+	e.lock.RLock()
+	histograms := make(map[string]*LatencyHistogram, len(e.histograms))
+	for endpoint, h := range e.histograms {
+		histograms[endpoint] = h
+	}
+	e.lock.RUnlock()
+
+	snapshots := make(map[string]HistogramSnapshot, len(histograms))
+	for endpoint, h := range histograms {
+		snapshots[endpoint] = h.Snapshot()
+	}
+
+	return snapshots
+}
+
+// Remove drops the histogram of @endpoint, e.g. once it goes offline.
+func (e *EndpointHistograms) Remove(endpoint string) {
+	e.lock.Lock()
+	delete(e.histograms, endpoint)
+	e.lock.Unlock()
+}
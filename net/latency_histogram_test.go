@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+func TestHistogramIndex(t *testing.T) {
+	for _, us := range []uint64{0, 1, 15, 16, 31, 32, 100, 1000, 123456, 1 << 30} {
+		v := histogramValue(histogramIndex(us))
+		assert.InEpsilon(t, float64(us)+1, v+1, 1.0/16, "%d", us)
+	}
+	assert.Equal(t, histogramBuckets-1, histogramIndex(1<<63))
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	h := NewLatencyHistogram(0)
+	assert.Equal(t, HistogramSnapshot{}, h.Snapshot())
+
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	s := h.Snapshot()
+	assert.Equal(t, 1000.0, s.Count)
+	assert.InEpsilon(t, float64(500*time.Millisecond), float64(s.P50), 0.07)
+	assert.InEpsilon(t, float64(950*time.Millisecond), float64(s.P95), 0.07)
+	assert.InEpsilon(t, float64(990*time.Millisecond), float64(s.P99), 0.07)
+	assert.InEpsilon(t, float64(500*time.Millisecond), float64(s.Mean), 0.01)
+	assert.Equal(t, h.Quantile(0.5), s.P50)
+	assert.Panics(t, func() { h.Quantile(2) })
+}
+
+func TestLatencyHistogramDecay(t *testing.T) {
+	var (
+		lock sync.Mutex
+		now  = time.Unix(1000, 0)
+	)
+	gxtime.SetTimeSource(func() time.Time {
+		lock.Lock()
+		defer lock.Unlock()
+		return now
+	})
+	defer gxtime.SetTimeSource(nil)
+	advance := func(d time.Duration) {
+		lock.Lock()
+		now = now.Add(d)
+		lock.Unlock()
+	}
+
+	e := NewEndpointHistograms(time.Second)
+	for i := 0; i < 100; i++ {
+		e.Record("slow", 100*time.Millisecond)
+	}
+	assert.InEpsilon(t, float64(100*time.Millisecond), float64(e.Histogram("slow").Quantile(0.99)), 0.07)
+
+	// the endpoint recovers: the old samples fade after some half-lives
+	advance(10 * time.Second)
+	s, ok := e.Snapshot("slow")
+	assert.True(t, ok)
+	assert.InDelta(t, 100.0/1024, s.Count, 0.01)
+	for i := 0; i < 100; i++ {
+		e.Record("slow", time.Millisecond)
+	}
+	s, _ = e.Snapshot("slow")
+	assert.InEpsilon(t, float64(time.Millisecond), float64(s.P99), 0.07)
+
+	e.Record("fast", time.Microsecond)
+	all := e.SnapshotAll()
+	assert.Len(t, all, 2)
+	assert.Equal(t, time.Microsecond, all["fast"].P50)
+
+	e.Remove("slow")
+	_, ok = e.Snapshot("slow")
+	assert.False(t, ok)
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+const (
+	ipv4HeaderSize = 20
+	ipv6HeaderSize = 40
+	udpHeaderSize  = 8
+
+	maxProbeRounds = 8
+)
+
+// ErrPathMTUUnsupported is returned by the path MTU functions on the platforms without
+// IP_MTU or for a conn which is neither a connected TCP nor UDP socket.
+var ErrPathMTUUnsupported = perrors.New("path MTU is unsupported")
+
+// PathMTU returns the path MTU to the peer of @conn known by the kernel, which is the
+// MTU of the outgoing interface until the kernel learns a smaller one from the ICMP
+// "fragmentation needed" messages. It is only supported on Linux.
+func PathMTU(conn net.Conn) (int, error) {
+	return pathMTU(conn)
+}
+
+// MaxFrameSize returns the largest payload which can be written to @conn in a single
+// packet without fragmentation, which is the MSS of a TCP conn, or the path MTU minus
+// the IP and UDP headers of a UDP conn. A framed writer can chunk its frames by it.
+func MaxFrameSize(conn net.Conn) (int, error) {
+	switch c := conn.(type) {
+	case *net.TCPConn:
+		return maxSegment(c)
+	case *net.UDPConn:
+		mtu, err := pathMTU(c)
+		if err != nil {
+			return 0, err
+		}
+		return mtu - ipHeaderSize(c) - udpHeaderSize, nil
+	}
+
+	return 0, ErrPathMTUUnsupported
+}
+
+// ProbePathMTU discovers the path MTU to the UDP @address. It sends datagrams of the
+// known path MTU with the don't-fragment flag set, and waits @timeout for the ICMP
+// replies after every one, until the known path MTU stops shrinking. The routers which
+// drop the ICMP messages silently hide a smaller MTU from it.
+func ProbePathMTU(address string, timeout time.Duration) (int, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	udpConn := conn.(*net.UDPConn)
+	if err = setDontFragment(udpConn); err != nil {
+		return 0, err
+	}
+
+	mtu, err := pathMTU(udpConn)
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, mtu)
+	for round := 0; round < maxProbeRounds; round++ {
+		size := mtu - ipHeaderSize(udpConn) - udpHeaderSize
+		if _, err = udpConn.Write(buf[:size]); err != nil && !isProbeError(err) {
+			return 0, err
+		}
+		if err == nil {
+			// the ICMP replies arrive asynchronously, a read waits for them
+			udpConn.SetReadDeadline(time.Now().Add(timeout))
+			udpConn.Read(buf)
+		}
+
+		next, err := pathMTU(udpConn)
+		if err != nil {
+			return 0, err
+		}
+		if next >= mtu {
+			break
+		}
+		mtu = next
+	}
+
+	return mtu, nil
+}
+
+// isProbeError reports whether @err is caused by a probe over the path MTU, or by an
+// ICMP error of an earlier probe which does not end the probing.
+func isProbeError(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func ipHeaderSize(conn net.Conn) int {
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok && addr.IP.To4() == nil {
+		return ipv6HeaderSize
+	}
+
+	return ipv4HeaderSize
+}
@@ -0,0 +1,108 @@
+//go:build linux
+// +build linux
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+func isIPv6(conn net.Conn) bool {
+	switch addr := conn.LocalAddr().(type) {
+	case *net.TCPAddr:
+		return addr.IP.To4() == nil
+	case *net.UDPAddr:
+		return addr.IP.To4() == nil
+	}
+
+	return false
+}
+
+func getsockoptInt(conn net.Conn, level, opt int) (int, error) {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return 0, ErrPathMTUUnsupported
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		value  int
+		optErr error
+	)
+	err = rc.Control(func(fd uintptr) {
+		value, optErr = unix.GetsockoptInt(int(fd), level, opt)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if optErr != nil {
+		if optErr == unix.ENOTCONN || optErr == unix.ENOPROTOOPT || optErr == unix.EOPNOTSUPP {
+			return 0, ErrPathMTUUnsupported
+		}
+		return 0, os.NewSyscallError("getsockopt", optErr)
+	}
+
+	return value, nil
+}
+
+func pathMTU(conn net.Conn) (int, error) {
+	if isIPv6(conn) {
+		return getsockoptInt(conn, unix.IPPROTO_IPV6, unix.IPV6_MTU)
+	}
+
+	return getsockoptInt(conn, unix.IPPROTO_IP, unix.IP_MTU)
+}
+
+func maxSegment(conn *net.TCPConn) (int, error) {
+	return getsockoptInt(conn, unix.IPPROTO_TCP, unix.TCP_MAXSEG)
+}
+
+func setDontFragment(conn *net.UDPConn) error {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var optErr error
+	err = rc.Control(func(fd uintptr) {
+		if isIPv6(conn) {
+			optErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_MTU_DISCOVER, unix.IPV6_PMTUDISC_DO)
+		} else {
+			optErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_DO)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if optErr != nil {
+		return os.NewSyscallError("setsockopt", optErr)
+	}
+
+	return nil
+}
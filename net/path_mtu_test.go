@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"net"
+	"runtime"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxFrameSize(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+	go func() {
+		if c, err := l.Accept(); err == nil {
+			c.Close()
+		}
+	}()
+	tcpConn, err := net.Dial("tcp", l.Addr().String())
+	assert.Nil(t, err)
+	defer tcpConn.Close()
+
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer pc.Close()
+	udpConn, err := net.Dial("udp4", pc.LocalAddr().String())
+	assert.Nil(t, err)
+	defer udpConn.Close()
+
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	_, err = MaxFrameSize(c1)
+	assert.Equal(t, ErrPathMTUUnsupported, err)
+
+	if runtime.GOOS != "linux" {
+		_, err = MaxFrameSize(tcpConn)
+		assert.Equal(t, ErrPathMTUUnsupported, err)
+		return
+	}
+
+	mss, err := MaxFrameSize(tcpConn)
+	assert.Nil(t, err)
+	assert.True(t, mss > 0)
+
+	mtu, err := PathMTU(udpConn)
+	assert.Nil(t, err)
+	size, err := MaxFrameSize(udpConn)
+	assert.Nil(t, err)
+	assert.Equal(t, mtu-ipv4HeaderSize-udpHeaderSize, size)
+
+	// a datagram of the max frame size goes through without fragmentation
+	_, err = udpConn.Write(make([]byte, size))
+	assert.Nil(t, err)
+}
+
+func TestProbePathMTU(t *testing.T) {
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer pc.Close()
+
+	mtu, err := ProbePathMTU(pc.LocalAddr().String(), 10*time.Millisecond)
+	if runtime.GOOS != "linux" {
+		assert.Equal(t, ErrPathMTUUnsupported, err)
+		return
+	}
+	assert.Nil(t, err)
+	assert.True(t, mtu >= 576)
+
+	buf := make([]byte, mtu)
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, mtu-ipv4HeaderSize-udpHeaderSize, n)
+}
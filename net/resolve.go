@@ -0,0 +1,171 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+type resolveFunc func(ctx context.Context, host string) ([]net.IP, error)
+
+func lookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	return net.DefaultResolver.LookupIP(ctx, "ip", host)
+}
+
+// ResolveWatcher re-resolves a hostname periodically on the default gxtime wheel, and
+// reports the changes of its A/AAAA set, so that a pool can dial the added addresses
+// and drain the conns to the removed ones gradually when the backends rotate their IPs.
+// A failed resolution keeps the last set.
+type ResolveWatcher struct {
+	host     string
+	resolve  resolveFunc
+	onChange func(added, removed []net.IP)
+	timeout  time.Duration
+
+	lock      sync.RWMutex
+	ips       map[string]net.IP
+	err       error
+	resolving int32
+	timer     *gxtime.Timer
+}
+
+// NewResolveWatcher resolves @host and re-resolves it every @interval. @onChange is
+// invoked with the added and removed IPs once the set changes, but not for the first
+// resolution, whose failure is returned.
+func NewResolveWatcher(host string, interval time.Duration, onChange func(added, removed []net.IP)) (*ResolveWatcher, error) {
+	return newResolveWatcher(host, interval, lookupIP, onChange)
+}
+
+func newResolveWatcher(host string, interval time.Duration, resolve resolveFunc,
+	onChange func(added, removed []net.IP)) (*ResolveWatcher, error) {
+	w := &ResolveWatcher{
+		host:     host,
+		resolve:  resolve,
+		onChange: onChange,
+		timeout:  interval,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	ips, err := resolve(ctx, host)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	w.ips = ipSet(ips)
+	w.timer = gxtime.GetDefaultWheel().AddTimer(w.refresh, interval, nil)
+
+	return w, nil
+}
+
+func ipSet(ips []net.IP) map[string]net.IP {
+	set := make(map[string]net.IP, len(ips))
+	for _, ip := range ips {
+		set[ip.String()] = ip
+	}
+
+	return set
+}
+
+func (w *ResolveWatcher) refresh(interface{}) {
+	// skip the tick if the last resolution is still running
+	if !atomic.CompareAndSwapInt32(&w.resolving, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&w.resolving, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	ips, err := w.resolve(ctx, w.host)
+	cancel()
+	if err == nil && len(ips) == 0 {
+		err = &net.DNSError{Err: "no such host", Name: w.host, IsNotFound: true}
+	}
+
+	w.lock.Lock()
+	w.err = err
+	if err != nil {
+		w.lock.Unlock()
+		return
+	}
+
+	var (
+		added, removed []net.IP
+		set            = ipSet(ips)
+	)
+	for k, ip := range set {
+		if _, ok := w.ips[k]; !ok {
+			added = append(added, ip)
+		}
+	}
+	for k, ip := range w.ips {
+		if _, ok := set[k]; !ok {
+			removed = append(removed, ip)
+		}
+	}
+	w.ips = set
+	w.lock.Unlock()
+
+	if (len(added) != 0 || len(removed) != 0) && w.onChange != nil {
+		sortIPs(added)
+		sortIPs(removed)
+		w.onChange(added, removed)
+	}
+}
+
+func sortIPs(ips []net.IP) {
+	sort.Slice(ips, func(i, j int) bool { return ips[i].String() < ips[j].String() })
+}
+
+// Host returns the watched hostname.
+func (w *ResolveWatcher) Host() string {
+	return w.host
+}
+
+// IPs returns the current set of IPs, sorted by their string form.
+func (w *ResolveWatcher) IPs() []net.IP {
+	w.lock.RLock()
+	ips := make([]net.IP, 0, len(w.ips))
+	for _, ip := range w.ips {
+		ips = append(ips, ip)
+	}
+	w.lock.RUnlock()
+	sortIPs(ips)
+
+	return ips
+}
+
+// Err returns the error of the last resolution, which is nil if it succeeded.
+func (w *ResolveWatcher) Err() error {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.err
+}
+
+// Close stops re-resolving.
+func (w *ResolveWatcher) Close() {
+	w.timer.Stop()
+}
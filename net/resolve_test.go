@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResolver struct {
+	lock sync.Mutex
+	ips  []net.IP
+	err  error
+}
+
+func (r *fakeResolver) set(err error, ips ...string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.err = err
+	r.ips = nil
+	for _, ip := range ips {
+		r.ips = append(r.ips, net.ParseIP(ip))
+	}
+}
+
+func (r *fakeResolver) resolve(context.Context, string) ([]net.IP, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.ips, r.err
+}
+
+func TestResolveWatcher(t *testing.T) {
+	r := &fakeResolver{}
+	r.set(nil, "10.0.0.1", "10.0.0.2")
+
+	type change struct{ added, removed []net.IP }
+	changes := make(chan change, 4)
+	w, err := newResolveWatcher("backend", 20*time.Millisecond, r.resolve, func(added, removed []net.IP) {
+		changes <- change{added, removed}
+	})
+	assert.Nil(t, err)
+	defer w.Close()
+	assert.Equal(t, "backend", w.Host())
+	assert.Len(t, w.IPs(), 2)
+
+	r.set(nil, "10.0.0.2", "10.0.0.3")
+	select {
+	case c := <-changes:
+		assert.Equal(t, "10.0.0.3", c.added[0].String())
+		assert.Equal(t, "10.0.0.1", c.removed[0].String())
+	case <-time.After(time.Second):
+		t.Fatal("change is not reported")
+	}
+
+	// a failed resolution keeps the last set
+	r.set(errors.New("dns down"))
+	time.Sleep(60 * time.Millisecond)
+	assert.NotNil(t, w.Err())
+	ips := w.IPs()
+	assert.Len(t, ips, 2)
+	assert.Equal(t, "10.0.0.2", ips[0].String())
+	assert.Len(t, changes, 0)
+}
+
+func TestResolveWatcherLocalhost(t *testing.T) {
+	w, err := NewResolveWatcher("localhost", time.Second, nil)
+	if err != nil {
+		t.Skipf("localhost is not resolvable: %v", err)
+	}
+	defer w.Close()
+	assert.NotEmpty(t, w.IPs())
+
+	_, err = newResolveWatcher("backend", time.Second, (&fakeResolver{err: errors.New("dns down")}).resolve, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,40 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"io"
+	"net"
+	"os"
+)
+
+// SendFile writes @n bytes of @file starting at offset @off to @conn and returns the
+// number of bytes written. It uses sendfile(2) on linux when @conn is backed by a
+// file descriptor, and falls back to a plain copy otherwise. The file offset of
+// @file is not changed.
+func SendFile(conn net.Conn, file *os.File, off int64, n int64) (int64, error) {
+	if n <= 0 {
+		return 0, nil
+	}
+
+	return sendFile(conn, file, off, n)
+}
+
+func copyFile(conn net.Conn, file *os.File, off int64, n int64) (int64, error) {
+	return io.Copy(conn, io.NewSectionReader(file, off, n))
+}
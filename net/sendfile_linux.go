@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// maxSendfileSize is the largest chunk passed to a single sendfile(2) call
+const maxSendfileSize = 4 << 20
+
+func sendFile(conn net.Conn, file *os.File, off int64, n int64) (int64, error) {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return copyFile(conn, file, off, n)
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return copyFile(conn, file, off, n)
+	}
+
+	var (
+		written     int64
+		sendErr     error
+		unsupported bool
+		src         = int(file.Fd())
+	)
+	err = rc.Write(func(fd uintptr) bool {
+		for written < n {
+			chunk := n - written
+			if chunk > maxSendfileSize {
+				chunk = maxSendfileSize
+			}
+
+			offset := off + written
+			m, e := syscall.Sendfile(int(fd), src, &offset, int(chunk))
+			if m > 0 {
+				written += int64(m)
+			}
+
+			switch e {
+			case nil:
+				if m == 0 {
+					// EOF of @file
+					return true
+				}
+			case syscall.EINTR:
+			case syscall.EAGAIN:
+				// wait until @conn is writable
+				return false
+			case syscall.EINVAL, syscall.ENOSYS, syscall.EOPNOTSUPP:
+				unsupported = written == 0
+				sendErr = e
+				return true
+			default:
+				sendErr = e
+				return true
+			}
+		}
+		return true
+	})
+
+	if unsupported {
+		return copyFile(conn, file, off, n)
+	}
+	if err == nil && sendErr != nil {
+		err = os.NewSyscallError("sendfile", sendErr)
+	}
+
+	return written, err
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func newSendFileSource(t *testing.T, size int) (*os.File, []byte) {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	file, err := ioutil.TempFile("", "gost-sendfile")
+	assert.Nil(t, err)
+	_, err = file.Write(data)
+	assert.Nil(t, err)
+
+	return file, data
+}
+
+func TestSendFile(t *testing.T) {
+	file, data := newSendFileSource(t, 10<<20)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	l, err := ListenOnTCPRandomPort("127.0.0.1")
+	assert.Nil(t, err)
+	defer l.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		b, _ := ioutil.ReadAll(conn)
+		conn.Close()
+		received <- b
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.Nil(t, err)
+
+	off, n := int64(100), int64(len(data)-200)
+	written, err := SendFile(conn, file, off, n)
+	assert.Nil(t, err)
+	assert.Equal(t, n, written)
+	conn.Close()
+
+	assert.Equal(t, data[off:off+n], <-received)
+
+	// the file offset is not changed by SendFile
+	pos, err := file.Seek(0, io.SeekCurrent)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(data)), pos)
+}
+
+func TestSendFileFallback(t *testing.T) {
+	file, data := newSendFileSource(t, 4096)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	c1, c2 := net.Pipe()
+	received := make(chan []byte, 1)
+	go func() {
+		b, _ := ioutil.ReadAll(c2)
+		received <- b
+	}()
+
+	written, err := SendFile(c1, file, 10, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), written)
+	c1.Close()
+	assert.Equal(t, data[10:1010], <-received)
+
+	written, err = SendFile(c1, file, 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), written)
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// ErrTCPInfoUnsupported is returned by ReadTCPStats on the platforms without TCP_INFO
+// or for a conn which is not a TCP socket.
+var ErrTCPInfoUnsupported = perrors.New("TCP_INFO is unsupported")
+
+// TCPStats is the kernel statistics of a TCP connection read by TCP_INFO.
+type TCPStats struct {
+	RTT          time.Duration // smoothed round trip time
+	RTTVar       time.Duration // round trip time variance
+	Cwnd         uint32        // send congestion window, in segments
+	Retransmits  uint32        // retransmits of the unacknowledged segments
+	TotalRetrans uint32        // retransmits over the lifetime of the connection
+	Lost         uint32        // segments regarded as lost
+}
+
+// ReadTCPStats reads the TCP_INFO statistics of @conn. It is only supported on Linux.
+func ReadTCPStats(conn net.Conn) (TCPStats, error) {
+	return readTCPStats(conn)
+}
+
+// TCPStatsReader reads the statistics of the registered conns, on which latency aware
+// balancing can be based. It is goroutine safe.
+type TCPStatsReader struct {
+	lock  sync.RWMutex
+	conns map[string]net.Conn
+}
+
+// NewTCPStatsReader returns an empty TCPStatsReader.
+func NewTCPStatsReader() *TCPStatsReader {
+	return &TCPStatsReader{conns: make(map[string]net.Conn)}
+}
+
+// Register registers @conn by @key, which overrides the former conn of @key.
+func (r *TCPStatsReader) Register(key string, conn net.Conn) {
+	r.lock.Lock()
+	r.conns[key] = conn
+	r.lock.Unlock()
+}
+
+// Unregister removes the conn of @key.
+func (r *TCPStatsReader) Unregister(key string) {
+	r.lock.Lock()
+	delete(r.conns, key)
+	r.lock.Unlock()
+}
+
+// Read reads the statistics of the conn of @key.
+func (r *TCPStatsReader) Read(key string) (TCPStats, error) {
+	r.lock.RLock()
+	conn, ok := r.conns[key]
+	r.lock.RUnlock()
+	if !ok {
+		return TCPStats{}, perrors.Errorf("no conn registered by %s", key)
+	}
+
+	return readTCPStats(conn)
+}
+
+// ReadAll reads the statistics of all registered conns. The conns failed to read,
+// e.g. the closed ones, are left out.
+func (r *TCPStatsReader) ReadAll() map[string]TCPStats {
+	r.lock.RLock()
+	conns := make(map[string]net.Conn, len(r.conns))
+	for k, c := range r.conns {
+		conns[k] = c
+	}
+	r.lock.RUnlock()
+
+	stats := make(map[string]TCPStats, len(conns))
+	for k, c := range conns {
+		if s, err := readTCPStats(c); err == nil {
+			stats[k] = s
+		}
+	}
+
+	return stats
+}
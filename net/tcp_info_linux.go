@@ -0,0 +1,69 @@
+//go:build linux
+// +build linux
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+func readTCPStats(conn net.Conn) (TCPStats, error) {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return TCPStats{}, ErrTCPInfoUnsupported
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return TCPStats{}, err
+	}
+
+	var (
+		info    *unix.TCPInfo
+		infoErr error
+	)
+	err = rc.Control(func(fd uintptr) {
+		info, infoErr = unix.GetsockoptTCPInfo(int(fd), unix.IPPROTO_TCP, unix.TCP_INFO)
+	})
+	if err != nil {
+		return TCPStats{}, err
+	}
+	if infoErr != nil {
+		if infoErr == unix.EOPNOTSUPP || infoErr == unix.ENOPROTOOPT {
+			return TCPStats{}, ErrTCPInfoUnsupported
+		}
+		return TCPStats{}, os.NewSyscallError("getsockopt", infoErr)
+	}
+
+	return TCPStats{
+		RTT:          time.Duration(info.Rtt) * time.Microsecond,
+		RTTVar:       time.Duration(info.Rttvar) * time.Microsecond,
+		Cwnd:         info.Snd_cwnd,
+		Retransmits:  uint32(info.Retransmits),
+		TotalRetrans: info.Total_retrans,
+		Lost:         info.Lost,
+	}, nil
+}
@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxnet
+
+import (
+	"net"
+	"runtime"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCPStatsReader(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+	go func() {
+		c, err := l.Accept()
+		if err == nil {
+			defer c.Close()
+			buf := make([]byte, 4)
+			c.Read(buf)
+		}
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.Nil(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("ping"))
+	assert.Nil(t, err)
+
+	r := NewTCPStatsReader()
+	r.Register("peer", conn)
+	_, err = r.Read("absent")
+	assert.NotNil(t, err)
+
+	stats, err := r.Read("peer")
+	if runtime.GOOS != "linux" {
+		assert.Equal(t, ErrTCPInfoUnsupported, err)
+		assert.Empty(t, r.ReadAll())
+		return
+	}
+	assert.Nil(t, err)
+	assert.True(t, stats.Cwnd > 0)
+	assert.Len(t, r.ReadAll(), 1)
+
+	r.Unregister("peer")
+	assert.Empty(t, r.ReadAll())
+}
+
+func TestReadTCPStatsUnsupported(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	_, err := ReadTCPStats(c1)
+	assert.Equal(t, ErrTCPInfoUnsupported, err)
+}
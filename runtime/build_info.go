@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxruntime
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+)
+
+// Module is a module version the binary is built with.
+type Module struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Sum     string `json:"sum,omitempty"`
+	Replace string `json:"replace,omitempty"` // path@version of the replacement
+}
+
+// Build describes the running binary.
+type Build struct {
+	GoVersion  string            `json:"go_version"`
+	Path       string            `json:"path"`
+	Main       Module            `json:"main"`
+	Revision   string            `json:"revision,omitempty"`   // vcs.revision
+	Time       string            `json:"time,omitempty"`       // vcs.time, RFC3339
+	Modified   bool              `json:"modified,omitempty"`   // vcs.modified
+	Deps       []Module          `json:"deps,omitempty"`       // sorted by path
+	Components map[string]string `json:"components,omitempty"` // see RegisterComponentVersion
+}
+
+var componentVersions sync.Map // name -> version
+
+// RegisterComponentVersion records the version of a component, e.g. a protocol or a
+// plugin, which is not visible in the module list. A later register of the same
+// @name overrides the former one.
+func RegisterComponentVersion(name, version string) {
+	componentVersions.Store(name, version)
+}
+
+// BuildInfo aggregates the module versions, the VCS stamp and the registered component
+// versions of the running binary. Only the go version and the components are filled
+// if the binary is built without module support.
+func BuildInfo() Build {
+	b := Build{GoVersion: runtime.Version()}
+	componentVersions.Range(func(k, v interface{}) bool {
+		if b.Components == nil {
+			b.Components = make(map[string]string)
+		}
+		b.Components[k.(string)] = v.(string)
+		return true
+	})
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+
+	b.Path = info.Path
+	b.Main = newModule(&info.Main)
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Revision = s.Value
+		case "vcs.time":
+			b.Time = s.Value
+		case "vcs.modified":
+			b.Modified = s.Value == "true"
+		}
+	}
+	for _, dep := range info.Deps {
+		b.Deps = append(b.Deps, newModule(dep))
+	}
+	sort.Slice(b.Deps, func(i, j int) bool { return b.Deps[i].Path < b.Deps[j].Path })
+
+	return b
+}
+
+// ModuleVersion returns the version of module @path the binary is built with.
+func (b Build) ModuleVersion(path string) (string, bool) {
+	if b.Main.Path == path {
+		return b.Main.Version, true
+	}
+	i := sort.Search(len(b.Deps), func(i int) bool { return b.Deps[i].Path >= path })
+	if i < len(b.Deps) && b.Deps[i].Path == path {
+		return b.Deps[i].Version, true
+	}
+
+	return "", false
+}
+
+func newModule(m *debug.Module) Module {
+	mod := Module{
+		Path:    m.Path,
+		Version: m.Version,
+		Sum:     m.Sum,
+	}
+	if m.Replace != nil {
+		mod.Replace = m.Replace.Path + "@" + m.Replace.Version
+	}
+
+	return mod
+}
@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxruntime
+
+import (
+	"runtime"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInfo(t *testing.T) {
+	RegisterComponentVersion("dubbo", "v3.0.0")
+	RegisterComponentVersion("dubbo", "v3.0.1")
+
+	b := BuildInfo()
+	assert.Equal(t, runtime.Version(), b.GoVersion)
+	assert.Equal(t, "v3.0.1", b.Components["dubbo"])
+
+	for _, dep := range b.Deps {
+		v, ok := b.ModuleVersion(dep.Path)
+		assert.True(t, ok)
+		assert.Equal(t, dep.Version, v)
+	}
+	_, ok := b.ModuleVersion("example.com/absent")
+	assert.False(t, ok)
+	t.Logf("build info: %+v", b)
+}
@@ -0,0 +1,262 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxruntime
+
+import (
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+const defaultLatencyWindow = 1024
+
+// LatencyKind tells which runtime latency a LatencyAlert is about.
+type LatencyKind string
+
+const (
+	// SchedLatency is the delay from a heartbeat to its goroutine getting scheduled.
+	SchedLatency LatencyKind = "sched"
+	// GCPauseLatency is the stop-the-world pause of a GC cycle.
+	GCPauseLatency LatencyKind = "gc-pause"
+)
+
+// LatencyAlert is a latency sample over its threshold.
+type LatencyAlert struct {
+	Kind      LatencyKind
+	Latency   time.Duration
+	Threshold time.Duration
+	At        time.Time
+}
+
+// LatencyStats is the distribution of the samples in the window.
+type LatencyStats struct {
+	Samples int // samples in the window
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+// RuntimeLatency is a snapshot of a LatencyMonitor.
+type RuntimeLatency struct {
+	Sched   LatencyStats
+	GCPause LatencyStats
+	NumGC   int64 // GC cycles seen so far
+}
+
+// LatencyMonitorOption is an option of NewLatencyMonitor.
+type LatencyMonitorOption func(*LatencyMonitor)
+
+// WithLatencyWindow keeps the last @n samples of each latency for the percentiles. The
+// default is 1024.
+func WithLatencyWindow(n int) LatencyMonitorOption {
+	return func(m *LatencyMonitor) {
+		if n > 0 {
+			m.window = n
+		}
+	}
+}
+
+// WithSchedLatencyAlert invokes @f for every scheduling latency over @threshold.
+func WithSchedLatencyAlert(threshold time.Duration, f func(LatencyAlert)) LatencyMonitorOption {
+	return func(m *LatencyMonitor) {
+		m.schedThreshold, m.onSched = threshold, f
+	}
+}
+
+// WithGCPauseAlert invokes @f for every GC pause over @threshold.
+func WithGCPauseAlert(threshold time.Duration, f func(LatencyAlert)) LatencyMonitorOption {
+	return func(m *LatencyMonitor) {
+		m.gcThreshold, m.onGC = threshold, f
+	}
+}
+
+// LatencyMonitor samples the runtime latencies which requests suffer apart from their
+// own code, so that a latency regression can be attributed to the runtime. The default
+// gxtime wheel sends a heartbeat every interval to a monitor goroutine, whose wakeup
+// delay is the scheduling latency, and the goroutine also collects the pauses of the
+// GC cycles completed since the last heartbeat.
+type LatencyMonitor struct {
+	window         int
+	schedThreshold time.Duration
+	onSched        func(LatencyAlert)
+	gcThreshold    time.Duration
+	onGC           func(LatencyAlert)
+
+	beat  chan time.Time
+	done  chan struct{}
+	once  sync.Once
+	timer *gxtime.Timer
+
+	lock    sync.Mutex
+	sched   latencyRing
+	gcPause latencyRing
+	numGC   int64
+}
+
+// NewLatencyMonitor starts a monitor which sends a heartbeat every @interval.
+func NewLatencyMonitor(interval time.Duration, opts ...LatencyMonitorOption) *LatencyMonitor {
+	m := &LatencyMonitor{
+		window: defaultLatencyWindow,
+		beat:   make(chan time.Time, 1),
+		done:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.sched.init(m.window)
+	m.gcPause.init(m.window)
+
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+	m.numGC = gc.NumGC
+
+	go m.run(gc)
+	m.timer = gxtime.GetDefaultWheel().AddTimer(m.heartbeat, interval, nil)
+
+	return m
+}
+
+// heartbeat runs in a timer goroutine of the wheel.
+func (m *LatencyMonitor) heartbeat(interface{}) {
+	select {
+	case m.beat <- time.Now():
+	default:
+		// the monitor goroutine has not even picked up the last one
+	}
+}
+
+func (m *LatencyMonitor) run(gc debug.GCStats) {
+	for {
+		select {
+		case sent := <-m.beat:
+			now := time.Now()
+			m.record(SchedLatency, now.Sub(sent), now)
+			m.collectGC(&gc, now)
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// collectGC records the pauses of the GC cycles completed since the last call.
+func (m *LatencyMonitor) collectGC(gc *debug.GCStats, now time.Time) {
+	last := gc.NumGC
+	debug.ReadGCStats(gc)
+	n := int(gc.NumGC - last)
+	if n > len(gc.Pause) {
+		// only the recent pauses are kept by the runtime
+		n = len(gc.Pause)
+	}
+
+	m.lock.Lock()
+	m.numGC = gc.NumGC
+	m.lock.Unlock()
+	// gc.Pause is the most recent first
+	for i := n - 1; i >= 0; i-- {
+		m.record(GCPauseLatency, gc.Pause[i], now)
+	}
+}
+
+func (m *LatencyMonitor) record(kind LatencyKind, latency time.Duration, now time.Time) {
+	var (
+		threshold time.Duration
+		alert     func(LatencyAlert)
+	)
+
+	m.lock.Lock()
+	if kind == SchedLatency {
+		m.sched.add(latency)
+		threshold, alert = m.schedThreshold, m.onSched
+	} else {
+		m.gcPause.add(latency)
+		threshold, alert = m.gcThreshold, m.onGC
+	}
+	m.lock.Unlock()
+
+	if alert != nil && latency > threshold {
+		alert(LatencyAlert{Kind: kind, Latency: latency, Threshold: threshold, At: now})
+	}
+}
+
+// Stats returns the latency distributions of the window.
+func (m *LatencyMonitor) Stats() RuntimeLatency {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return RuntimeLatency{
+		Sched:   m.sched.stats(),
+		GCPause: m.gcPause.stats(),
+		NumGC:   m.numGC,
+	}
+}
+
+// Stop stops the heartbeats and the monitor goroutine.
+func (m *LatencyMonitor) Stop() {
+	m.once.Do(func() {
+		m.timer.Stop()
+		close(m.done)
+	})
+}
+
+// latencyRing keeps the last samples of a latency.
+type latencyRing struct {
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+func (r *latencyRing) init(n int) {
+	r.samples = make([]time.Duration, n)
+}
+
+func (r *latencyRing) add(d time.Duration) {
+	r.samples[r.next] = d
+	if r.next++; r.next == len(r.samples) {
+		r.next, r.full = 0, true
+	}
+}
+
+func (r *latencyRing) stats() LatencyStats {
+	n := r.next
+	if r.full {
+		n = len(r.samples)
+	}
+	if n == 0 {
+		return LatencyStats{}
+	}
+
+	sorted := append([]time.Duration(nil), r.samples[:n]...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := func(p float64) time.Duration {
+		return sorted[int(p*float64(n-1)+0.5)]
+	}
+
+	return LatencyStats{
+		Samples: n,
+		P50:     rank(0.5),
+		P90:     rank(0.9),
+		P99:     rank(0.99),
+		Max:     sorted[n-1],
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxruntime
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyMonitor(t *testing.T) {
+	alerts := make(chan LatencyAlert, 1024)
+	alert := func(a LatencyAlert) {
+		select {
+		case alerts <- a:
+		default:
+		}
+	}
+	m := NewLatencyMonitor(10*time.Millisecond, WithLatencyWindow(64),
+		WithSchedLatencyAlert(0, alert), WithGCPauseAlert(0, alert))
+	defer m.Stop()
+
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+		time.Sleep(30 * time.Millisecond)
+	}
+	var stats RuntimeLatency
+	for i := 0; i < 100; i++ {
+		if stats = m.Stats(); stats.GCPause.Samples >= 3 && stats.Sched.Samples >= 5 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, stats.Sched.Samples >= 5, stats.Sched.Samples)
+	assert.True(t, stats.GCPause.Samples >= 3, stats.GCPause.Samples)
+	assert.True(t, stats.NumGC >= 3)
+	assert.True(t, stats.Sched.P50 <= stats.Sched.P99 && stats.Sched.P99 <= stats.Sched.Max)
+	assert.True(t, stats.GCPause.Max > 0)
+
+	kinds := make(map[LatencyKind]bool)
+	for len(alerts) > 0 {
+		kinds[(<-alerts).Kind] = true
+	}
+	assert.True(t, kinds[SchedLatency])
+	assert.True(t, kinds[GCPauseLatency])
+
+	m.Stop()
+	m.Stop()
+}
+
+func TestLatencyRing(t *testing.T) {
+	var r latencyRing
+	r.init(4)
+	assert.Equal(t, LatencyStats{}, r.stats())
+
+	for i := 1; i <= 6; i++ {
+		r.add(time.Duration(i))
+	}
+	stats := r.stats()
+	assert.Equal(t, 4, stats.Samples)
+	assert.Equal(t, time.Duration(6), stats.Max)
+	assert.Equal(t, time.Duration(5), stats.P50)
+	assert.Equal(t, time.Duration(6), stats.P99)
+}
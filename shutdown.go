@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gost is the entry of the process wide operations over the gost subsystems.
+package gost
+
+import (
+	"context"
+	"time"
+)
+
+import (
+	gxsync "github.com/dubbogo/gost/sync"
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+// DefaultShutdownTimeout is the time given to each built-in component by ShutdownAll.
+const DefaultShutdownTimeout = 5 * time.Second
+
+var shutdown gxsync.Cleanup
+
+func init() {
+	// the bottom layer, as everything may schedule timers on it
+	shutdown.AddWithTimeout("default timer wheel", DefaultShutdownTimeout, func(context.Context) error {
+		gxtime.StopDefaultWheel()
+		return nil
+	})
+}
+
+// RegisterShutdown adds the component @name shut down by @f in ShutdownAll, which is
+// given at most @timeout, a non-positive @timeout means no limit but the one of
+// ShutdownAll. The components are shut down in the reverse order of the registration,
+// so a component should be registered after the ones it depends on.
+func RegisterShutdown(name string, timeout time.Duration, f gxsync.CleanupFunc) {
+	shutdown.AddWithTimeout(name, timeout, f)
+}
+
+// RegisterTaskPool adds @pool closed in ShutdownAll, which waits for its running tasks
+// at most @timeout.
+func RegisterTaskPool(name string, timeout time.Duration, pool gxsync.GenericTaskPool) {
+	RegisterShutdown(name, timeout, func(context.Context) error {
+		if !pool.IsClosed() {
+			pool.Close()
+		}
+		return nil
+	})
+}
+
+// ShutdownAll shuts down the registered components and then the default singletons of
+// gost, e.g. the default timer wheel, in the dependency order. A component which fails
+// or runs out of its timeout does not stop the rest, but once @ctx is done the rest are
+// skipped. The errors are aggregated into a *gxsync.CleanupError. Only the first call
+// shuts anything down.
+func ShutdownAll(ctx context.Context) error {
+	return shutdown.Run(ctx)
+}
@@ -0,0 +1,82 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gost
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxsync "github.com/dubbogo/gost/sync"
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+func TestShutdownAll(t *testing.T) {
+	wheel := gxtime.GetDefaultWheel()
+	pool := gxsync.NewTaskPoolSimple(1)
+
+	var (
+		lock  sync.Mutex
+		order []string
+	)
+	record := func(name string) {
+		lock.Lock()
+		order = append(order, name)
+		lock.Unlock()
+	}
+	RegisterShutdown("cache", 0, func(context.Context) error {
+		record("cache")
+		return nil
+	})
+	RegisterTaskPool("workers", time.Second, pool)
+	RegisterShutdown("server", 10*time.Millisecond, func(ctx context.Context) error {
+		record("server")
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	RegisterShutdown("broken", 0, func(context.Context) error {
+		record("broken")
+		return errors.New("broken")
+	})
+
+	err := ShutdownAll(context.Background())
+	var cerr *gxsync.CleanupError
+	assert.True(t, errors.As(err, &cerr))
+	assert.Len(t, cerr.Errs, 2)
+	assert.True(t, errors.Is(cerr.Errs[1], context.DeadlineExceeded))
+	lock.Lock()
+	assert.Equal(t, []string{"broken", "server", "cache"}, order)
+	lock.Unlock()
+	assert.True(t, pool.IsClosed())
+
+	select {
+	case <-wheel.After(20 * time.Millisecond):
+		t.Fatal("the default wheel still ticks")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Nil(t, ShutdownAll(context.Background()))
+}
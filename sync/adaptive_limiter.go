@@ -0,0 +1,194 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+import (
+	gxslidingwindow "github.com/dubbogo/gost/container/slidingwindow"
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+// ErrLimitExceeded is returned by AdaptiveLimiter.TryAcquire when the limit is reached.
+var ErrLimitExceeded = errors.New("gxsync: concurrency limit exceeded")
+
+const (
+	adaptiveLimiterBuckets    = 10
+	adaptiveLimiterMinSamples = 10   // samples in the window to adjust the limit
+	adaptiveLimiterSmoothing  = 0.2  // weight of a new limit
+	adaptiveLimiterLongWeight = 0.05 // weight of the short RTT in the long one
+	adaptiveLimiterBackoff    = 0.9  // cut of the limit by a dropped request
+)
+
+// AdaptiveLimiter limits the inflight requests by a limit adjusted to the latencies,
+// for the adaptive load shedding of providers, ref: the gradient limit of Netflix's
+// concurrency-limits. The average RTT of a sliding window is compared with a long-term
+// RTT approaching the no-load one: the limit shrinks by their gradient as the requests
+// start queueing, and grows by a queue of sqrt(limit) while the RTT holds. A request
+// dropped by overload such as a timeout cuts the limit at once. The time is read by
+// gxtime.Now. It is goroutine safe.
+type AdaptiveLimiter struct {
+	minLimit int
+	maxLimit int
+	interval time.Duration // between two adjustments
+	window   *gxslidingwindow.Window
+
+	lock       sync.Mutex
+	limit      float64
+	inflight   int
+	longRTT    float64 // in nanoseconds, 0 before the first adjustment
+	lastUpdate time.Time
+	changed    chan struct{} // closed and replaced on every release and adjustment
+}
+
+// NewAdaptiveLimiter returns a limiter starting at @initial inflight requests, which
+// is adjusted within [@minLimit, @maxLimit] by the RTTs of the last @window.
+func NewAdaptiveLimiter(initial, minLimit, maxLimit int, window time.Duration) *AdaptiveLimiter {
+	if minLimit < 1 || maxLimit < minLimit || initial < minLimit || initial > maxLimit {
+		panic("illegal limits, which should satisfy 1 <= @minLimit <= @initial <= @maxLimit")
+	}
+	if window < adaptiveLimiterBuckets {
+		panic("@window too short")
+	}
+
+	span := window / adaptiveLimiterBuckets
+	return &AdaptiveLimiter{
+		minLimit:   minLimit,
+		maxLimit:   maxLimit,
+		interval:   span,
+		window:     gxslidingwindow.New(adaptiveLimiterBuckets, span, gxslidingwindow.WithSamples(0)),
+		limit:      float64(initial),
+		lastUpdate: gxtime.Now(),
+		changed:    make(chan struct{}),
+	}
+}
+
+// broadcast should be invoked with the lock held.
+func (l *AdaptiveLimiter) broadcast() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// Acquire waits for room under the limit until @ctx is done. On success, the returned
+// func must be invoked once the request completes, with false if the request was
+// dropped by overload, e.g. timed out, which is not taken as an RTT sample.
+func (l *AdaptiveLimiter) Acquire(ctx context.Context) (func(ok bool), error) {
+	l.lock.Lock()
+	for l.inflight >= int(l.limit) {
+		changed := l.changed
+		l.lock.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		l.lock.Lock()
+	}
+	l.inflight++
+	l.lock.Unlock()
+
+	return l.releaser(), nil
+}
+
+// TryAcquire is Acquire rejecting the request with ErrLimitExceeded instead of waiting.
+func (l *AdaptiveLimiter) TryAcquire() (func(ok bool), error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.inflight >= int(l.limit) {
+		return nil, ErrLimitExceeded
+	}
+	l.inflight++
+
+	return l.releaser(), nil
+}
+
+func (l *AdaptiveLimiter) releaser() func(ok bool) {
+	start := gxtime.Now()
+	var once sync.Once
+
+	return func(ok bool) {
+		once.Do(func() { l.release(gxtime.Now().Sub(start), ok) })
+	}
+}
+
+func (l *AdaptiveLimiter) release(rtt time.Duration, ok bool) {
+	if ok {
+		l.window.Add(float64(rtt))
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.inflight--
+	if !ok {
+		l.setLimit(l.limit * adaptiveLimiterBackoff)
+	} else if now := gxtime.Now(); now.Sub(l.lastUpdate) >= l.interval && l.window.Count() >= adaptiveLimiterMinSamples {
+		l.lastUpdate = now
+		l.adjust(l.window.Avg())
+	}
+	l.broadcast()
+}
+
+// adjust moves the limit by the @shortRTT of the window. It should be invoked with the
+// lock held.
+func (l *AdaptiveLimiter) adjust(shortRTT float64) {
+	if shortRTT <= 0 {
+		return
+	}
+	if l.longRTT == 0 {
+		l.longRTT = shortRTT
+	} else {
+		l.longRTT = l.longRTT*(1-adaptiveLimiterLongWeight) + shortRTT*adaptiveLimiterLongWeight
+	}
+	// the long RTT catches up faster once the load is gone
+	if l.longRTT > shortRTT*2 {
+		l.longRTT *= 0.95
+	}
+
+	gradient := math.Max(0.5, math.Min(1, l.longRTT/shortRTT))
+	queue := math.Sqrt(l.limit)
+	l.setLimit(l.limit*(1-adaptiveLimiterSmoothing) + (l.limit*gradient+queue)*adaptiveLimiterSmoothing)
+}
+
+// setLimit should be invoked with the lock held.
+func (l *AdaptiveLimiter) setLimit(limit float64) {
+	l.limit = math.Max(float64(l.minLimit), math.Min(float64(l.maxLimit), limit))
+}
+
+// Limit returns the current limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return int(l.limit)
+}
+
+// Inflight returns the number of requests acquired and not released.
+func (l *AdaptiveLimiter) Inflight() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.inflight
+}
@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+type limiterClock struct {
+	now int64
+}
+
+func (c *limiterClock) Now() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.now))
+}
+
+func (c *limiterClock) Advance(d time.Duration) {
+	atomic.AddInt64(&c.now, int64(d))
+}
+
+// serve runs @n requests of @rtt one by one under @l.
+func serve(t *testing.T, l *AdaptiveLimiter, clock *limiterClock, n int, rtt time.Duration) {
+	for i := 0; i < n; i++ {
+		release, err := l.TryAcquire()
+		assert.Nil(t, err)
+		clock.Advance(rtt)
+		release(true)
+	}
+}
+
+func TestAdaptiveLimiterGradient(t *testing.T) {
+	clock := &limiterClock{now: time.Unix(1000, 0).UnixNano()}
+	gxtime.SetTimeSource(clock.Now)
+	defer gxtime.SetTimeSource(nil)
+
+	l := NewAdaptiveLimiter(20, 5, 100, time.Second)
+	assert.Equal(t, 20, l.Limit())
+
+	// the limit grows while the RTT holds
+	serve(t, l, clock, 500, 10*time.Millisecond)
+	grown := l.Limit()
+	assert.True(t, grown > 20, "limit %d", grown)
+
+	// and shrinks once the requests start queueing
+	serve(t, l, clock, 40, 50*time.Millisecond)
+	assert.True(t, l.Limit() < grown, "limit %d of %d", l.Limit(), grown)
+	assert.Equal(t, 0, l.Inflight())
+}
+
+func TestAdaptiveLimiterAcquire(t *testing.T) {
+	l := NewAdaptiveLimiter(2, 1, 10, time.Second)
+
+	r1, err := l.Acquire(context.Background())
+	assert.Nil(t, err)
+	r2, err := l.TryAcquire()
+	assert.Nil(t, err)
+	_, err = l.TryAcquire()
+	assert.Equal(t, ErrLimitExceeded, err)
+	assert.Equal(t, 2, l.Inflight())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		release, err := l.Acquire(context.Background())
+		assert.Nil(t, err)
+		release(true)
+		close(acquired)
+	}()
+	r1(true)
+	r1(true) // released once only
+	<-acquired
+	assert.Equal(t, 1, l.Inflight())
+
+	// a dropped request cuts the limit
+	r2(false)
+	assert.Equal(t, 1, l.Limit())
+	assert.Equal(t, 0, l.Inflight())
+}
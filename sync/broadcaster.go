@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"sync"
+)
+
+// Broadcaster is a condition variable with channel semantics: the waiters get a
+// channel closed by the next Broadcast, which can be selected along with a context or
+// other channels unlike sync.Cond. Every Broadcast starts a new epoch, so a waiter
+// which checks the state at an epoch and then waits for the one after it never misses
+// a Broadcast in between:
+//
+//	for {
+//		epoch := b.Epoch()
+//		if ready() {
+//			break
+//		}
+//		select {
+//		case <-b.WaitEpoch(epoch):
+//		case <-ctx.Done():
+//			return ctx.Err()
+//		}
+//	}
+//
+// Its zero value is ready to use.
+type Broadcaster struct {
+	lock  sync.Mutex
+	epoch uint64
+	ch    chan struct{} // closed by the Broadcast ending the epoch, created lazily
+}
+
+// Epoch returns the current epoch, which is the number of Broadcasts so far.
+func (b *Broadcaster) Epoch() uint64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.epoch
+}
+
+// Wait returns a channel closed by the next Broadcast.
+func (b *Broadcaster) Wait() <-chan struct{} {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.chanLocked()
+}
+
+// WaitEpoch returns a channel closed once the epoch is over @epoch, which is closed
+// already if a Broadcast has happened since @epoch.
+func (b *Broadcaster) WaitEpoch(epoch uint64) <-chan struct{} {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.epoch > epoch {
+		return closedDone
+	}
+	return b.chanLocked()
+}
+
+func (b *Broadcaster) chanLocked() chan struct{} {
+	if b.ch == nil {
+		b.ch = make(chan struct{})
+	}
+	return b.ch
+}
+
+// Broadcast wakes up all waiters and starts a new epoch.
+func (b *Broadcaster) Broadcast() {
+	b.lock.Lock()
+	b.epoch++
+	if b.ch != nil {
+		close(b.ch)
+		b.ch = nil
+	}
+	b.lock.Unlock()
+}
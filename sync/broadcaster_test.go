@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroadcaster(t *testing.T) {
+	var b Broadcaster
+	assert.Equal(t, uint64(0), b.Epoch())
+
+	var (
+		wg    sync.WaitGroup
+		woken int32
+	)
+	for i := 0; i < 3; i++ {
+		c := b.Wait()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-c
+			atomic.AddInt32(&woken, 1)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&woken))
+	b.Broadcast()
+	wg.Wait()
+	assert.Equal(t, int32(3), woken)
+	assert.Equal(t, uint64(1), b.Epoch())
+
+	select {
+	case <-b.Wait():
+		t.Fatal("the new epoch is over")
+	default:
+	}
+}
+
+func TestBroadcasterWaitEpoch(t *testing.T) {
+	var (
+		b     Broadcaster
+		ready int32
+	)
+
+	epoch := b.Epoch()
+	// the broadcast between the check and the wait is not lost
+	atomic.StoreInt32(&ready, 1)
+	b.Broadcast()
+	select {
+	case <-b.WaitEpoch(epoch):
+	default:
+		t.Fatal("missed the broadcast")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			epoch := b.Epoch()
+			if atomic.LoadInt32(&ready) == 2 {
+				return
+			}
+			<-b.WaitEpoch(epoch)
+		}
+	}()
+	for i := 0; i < 3; i++ {
+		b.Broadcast()
+	}
+	atomic.StoreInt32(&ready, 2)
+	b.Broadcast()
+	<-done
+}
@@ -0,0 +1,226 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CancelToken is a cooperative cancellation signal. Canceling a token cancels all of
+// its child tokens, and fires the callbacks registered on them with the reason. A
+// callback is an entry of a map instead of a goroutine blocked on a channel, so a
+// token fans out to thousands of listeners cheaply.
+//
+// CancelToken implements context.Context, whose Err is context.Canceled once the token
+// is canceled, so it can be passed to the APIs taking a context. It is goroutine safe.
+type CancelToken struct {
+	pool     GenericTaskPool
+	canceled int32
+
+	lock      sync.Mutex
+	parent    *CancelToken
+	children  map[*CancelToken]struct{}
+	callbacks map[uint64]func(reason error)
+	nextID    uint64
+	done      chan struct{} // created lazily by Done
+	reason    error
+}
+
+var closedDone = make(chan struct{})
+
+func init() {
+	close(closedDone)
+}
+
+// NewCancelToken returns a root token, whose callbacks are executed on @pool. A nil
+// @pool executes them in the goroutine which cancels the token.
+func NewCancelToken(pool GenericTaskPool) *CancelToken {
+	return &CancelToken{pool: pool}
+}
+
+// CancelTokenFromContext returns a root token which is canceled with ctx.Err() once
+// @ctx is done.
+func CancelTokenFromContext(ctx context.Context, pool GenericTaskPool) *CancelToken {
+	t := NewCancelToken(pool)
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				t.Cancel(ctx.Err())
+			case <-t.Done():
+			}
+		}()
+	}
+
+	return t
+}
+
+// Child returns a child token, which is canceled together with @t but can also be
+// canceled alone. The child of a canceled token is born canceled.
+func (t *CancelToken) Child() *CancelToken {
+	child := &CancelToken{pool: t.pool, parent: t}
+
+	t.lock.Lock()
+	if t.reason != nil {
+		reason := t.reason
+		t.lock.Unlock()
+		child.Cancel(reason)
+		return child
+	}
+	if t.children == nil {
+		t.children = make(map[*CancelToken]struct{})
+	}
+	t.children[child] = struct{}{}
+	t.lock.Unlock()
+
+	return child
+}
+
+// Register registers @f to be called with the reason once @t is canceled, and returns
+// the func to unregister it, which returns false if @f has been fired or unregistered.
+// @f is fired at once if @t has been canceled.
+func (t *CancelToken) Register(f func(reason error)) (unregister func() bool) {
+	t.lock.Lock()
+	if t.reason != nil {
+		reason := t.reason
+		t.lock.Unlock()
+		t.fire([]func(error){f}, reason)
+		return func() bool { return false }
+	}
+	if t.callbacks == nil {
+		t.callbacks = make(map[uint64]func(error))
+	}
+	t.nextID++
+	id := t.nextID
+	t.callbacks[id] = f
+	t.lock.Unlock()
+
+	return func() bool {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+
+		_, ok := t.callbacks[id]
+		delete(t.callbacks, id)
+		return ok
+	}
+}
+
+// Cancel cancels @t and its descendants with @reason, a nil one means context.Canceled.
+// It returns false if @t has been canceled.
+func (t *CancelToken) Cancel(reason error) bool {
+	if reason == nil {
+		reason = context.Canceled
+	}
+
+	t.lock.Lock()
+	if t.reason != nil {
+		t.lock.Unlock()
+		return false
+	}
+	t.reason = reason
+	atomic.StoreInt32(&t.canceled, 1)
+	if t.done != nil {
+		close(t.done)
+	}
+	children, callbacks := t.children, t.callbacks
+	t.children, t.callbacks = nil, nil
+	parent := t.parent
+	t.parent = nil
+	t.lock.Unlock()
+
+	if parent != nil {
+		parent.lock.Lock()
+		delete(parent.children, t)
+		parent.lock.Unlock()
+	}
+	for child := range children {
+		child.lock.Lock()
+		child.parent = nil
+		child.lock.Unlock()
+		child.Cancel(reason)
+	}
+
+	fs := make([]func(error), 0, len(callbacks))
+	for _, f := range callbacks {
+		fs = append(fs, f)
+	}
+	t.fire(fs, reason)
+
+	return true
+}
+
+func (t *CancelToken) fire(fs []func(error), reason error) {
+	for _, f := range fs {
+		f := f
+		if t.pool == nil {
+			f(reason)
+			continue
+		}
+		t.pool.AddTaskAlways(func() { f(reason) })
+	}
+}
+
+// IsCanceled reports whether @t has been canceled. It is a single atomic load.
+func (t *CancelToken) IsCanceled() bool {
+	return atomic.LoadInt32(&t.canceled) == 1
+}
+
+// Reason returns the reason of the cancellation, or nil if @t is not canceled.
+func (t *CancelToken) Reason() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.reason
+}
+
+// Done returns a channel closed once @t is canceled.
+func (t *CancelToken) Done() <-chan struct{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.done == nil {
+		if t.reason != nil {
+			return closedDone
+		}
+		t.done = make(chan struct{})
+	}
+
+	return t.done
+}
+
+// Err returns context.Canceled once @t is canceled, see Reason for the reason.
+func (t *CancelToken) Err() error {
+	if t.IsCanceled() {
+		return context.Canceled
+	}
+
+	return nil
+}
+
+// Deadline returns no deadline.
+func (t *CancelToken) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Value returns nil, a token carries no values.
+func (t *CancelToken) Value(key interface{}) interface{} {
+	return nil
+}
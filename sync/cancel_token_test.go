@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelTokenTree(t *testing.T) {
+	root := NewCancelToken(nil)
+	child := root.Child()
+	grandchild := child.Child()
+	sibling := root.Child()
+
+	var fired []string
+	child.Register(func(reason error) { fired = append(fired, "child") })
+	unregister := grandchild.Register(func(reason error) { fired = append(fired, "grandchild") })
+	assert.True(t, unregister())
+	assert.False(t, unregister())
+
+	errShutdown := errors.New("shutdown")
+	assert.True(t, child.Cancel(errShutdown))
+	assert.False(t, child.Cancel(nil))
+	assert.Equal(t, []string{"child"}, fired)
+	assert.True(t, grandchild.IsCanceled())
+	assert.Equal(t, errShutdown, grandchild.Reason())
+	assert.False(t, root.IsCanceled())
+	assert.False(t, sibling.IsCanceled())
+	assert.Len(t, root.children, 1, "the canceled child is removed")
+
+	root.Cancel(nil)
+	assert.Equal(t, context.Canceled, sibling.Reason())
+	assert.Equal(t, errShutdown, child.Reason())
+
+	// late children and callbacks are canceled at once
+	late := root.Child()
+	assert.True(t, late.IsCanceled())
+	var reason error
+	assert.False(t, late.Register(func(r error) { reason = r })())
+	assert.Equal(t, context.Canceled, reason)
+}
+
+func TestCancelTokenContext(t *testing.T) {
+	token := NewCancelToken(nil)
+	var ctx context.Context = token
+	assert.Nil(t, ctx.Err())
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+
+	sub, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	token.Cancel(errors.New("stop"))
+	select {
+	case <-sub.Done():
+	case <-time.After(time.Second):
+		t.Fatal("the derived context is not canceled")
+	}
+	assert.Equal(t, context.Canceled, ctx.Err())
+	<-token.Done()
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	from := CancelTokenFromContext(parent, nil)
+	cancelParent()
+	<-from.Done()
+	assert.Equal(t, context.Canceled, from.Reason())
+}
+
+func TestCancelTokenPool(t *testing.T) {
+	pool := NewTaskPoolSimple(4)
+	defer pool.Close()
+
+	token := NewCancelToken(pool)
+	var (
+		wg    sync.WaitGroup
+		count int32
+	)
+	for i := 0; i < 1000; i++ {
+		wg.Add(1)
+		token.Child().Register(func(reason error) {
+			atomic.AddInt32(&count, 1)
+			wg.Done()
+		})
+	}
+	token.Cancel(nil)
+	wg.Wait()
+	assert.Equal(t, int32(1000), atomic.LoadInt32(&count))
+}
@@ -0,0 +1,143 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CleanupFunc releases one resource. It should return asap when @ctx is done.
+type CleanupFunc func(ctx context.Context) error
+
+type cleanupStep struct {
+	name    string
+	timeout time.Duration
+	f       CleanupFunc
+}
+
+// Cleanup is a cancellable stack of cleanup funcs. It standardizes the teardown of
+// partially-initialized resource sets:
+//
+//	var c gxsync.Cleanup
+//	defer c.Run(ctx)
+//	// ... c.Add() after every resource is created successfully
+//	c.Release() // every resource is ready, keep them
+//
+// Its zero value is ready to use and it is goroutine safe.
+type Cleanup struct {
+	lock  sync.Mutex
+	steps []cleanupStep
+}
+
+// Add pushes @f named @name onto the stack.
+func (c *Cleanup) Add(name string, f CleanupFunc) {
+	c.AddWithTimeout(name, 0, f)
+}
+
+// AddWithTimeout pushes @f named @name onto the stack, and @f is given at most
+// @timeout to finish. A non-positive @timeout means no limit.
+func (c *Cleanup) AddWithTimeout(name string, timeout time.Duration, f CleanupFunc) {
+	c.lock.Lock()
+	c.steps = append(c.steps, cleanupStep{name: name, timeout: timeout, f: f})
+	c.lock.Unlock()
+}
+
+// Len returns the number of pending cleanup funcs.
+func (c *Cleanup) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.steps)
+}
+
+// Release drops all pending cleanup funcs without running them.
+func (c *Cleanup) Release() {
+	c.lock.Lock()
+	c.steps = nil
+	c.lock.Unlock()
+}
+
+// Run pops and runs all pending cleanup funcs in LIFO order. Once @ctx is done, the
+// remaining funcs are skipped. The errors of all failed, timed out or skipped funcs
+// are aggregated into a *CleanupError.
+func (c *Cleanup) Run(ctx context.Context) error {
+	c.lock.Lock()
+	steps := c.steps
+	c.steps = nil
+	c.lock.Unlock()
+
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("cleanup %s skipped: %w", step.name, err))
+			continue
+		}
+		if err := step.run(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("cleanup %s: %w", step.name, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &CleanupError{Errs: errs}
+}
+
+func (s cleanupStep) run(ctx context.Context) error {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- s.f(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CleanupError aggregates the errors of a Cleanup.Run, in the order they happened.
+type CleanupError struct {
+	Errs []error
+}
+
+func (e *CleanupError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanup(t *testing.T) {
+	var (
+		c     Cleanup
+		lock  sync.Mutex
+		order []string
+		errDB = errors.New("db close error")
+	)
+	push := func(name string) {
+		lock.Lock()
+		order = append(order, name)
+		lock.Unlock()
+	}
+	c.Add("listener", func(ctx context.Context) error {
+		push("listener")
+		return nil
+	})
+	c.Add("pool", func(ctx context.Context) error {
+		push("pool")
+		return errDB
+	})
+	c.AddWithTimeout("wheel", 20*time.Millisecond, func(ctx context.Context) error {
+		push("wheel")
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	c.Add("panic", func(ctx context.Context) error {
+		panic("boom")
+	})
+	assert.Equal(t, 4, c.Len())
+
+	err := c.Run(context.Background())
+	lock.Lock()
+	assert.Equal(t, []string{"wheel", "pool", "listener"}, order)
+	lock.Unlock()
+	ce, ok := err.(*CleanupError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, len(ce.Errs))
+	assert.Contains(t, ce.Errs[0].Error(), "panic")
+	assert.True(t, errors.Is(ce.Errs[1], context.DeadlineExceeded))
+	assert.True(t, errors.Is(ce.Errs[2], errDB))
+	assert.Equal(t, 0, c.Len())
+	assert.Nil(t, c.Run(context.Background()))
+}
+
+func TestCleanupReleaseAndCancel(t *testing.T) {
+	var c Cleanup
+	called := false
+	c.Add("res", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	c.Release()
+	assert.Nil(t, c.Run(context.Background()))
+	assert.False(t, called)
+
+	c.Add("res", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := c.Run(ctx)
+	assert.False(t, called)
+	assert.True(t, errors.Is(err.(*CleanupError).Errs[0], context.Canceled))
+}
@@ -0,0 +1,315 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+var (
+	// ErrTaskPoolClosed is returned by the submissions to a closed pool.
+	ErrTaskPoolClosed = errors.New("task pool closed")
+	// ErrTaskPoolFull is returned by a submission which finds no room in time.
+	ErrTaskPoolFull = errors.New("task pool full")
+)
+
+/////////////////////////////////////////
+// Dynamic Task Pool
+/////////////////////////////////////////
+
+// DynamicTaskPool runs tasks on the workers between a min and a max number. A worker
+// is started for a task if the others are busy and the max is not reached, otherwise
+// the task waits in a bounded queue. The workers idle for a while are reaped down to
+// the min, by the default gxtime wheel instead of a timer per worker. It is a bounded
+// alternative to starting a goroutine per task, e.g. in the callbacks of timers.
+type DynamicTaskPool struct {
+	minWorkers  int
+	maxWorkers  int
+	idleTimeout time.Duration
+	queue       chan task
+
+	lock    sync.Mutex
+	workers int
+	idle    int
+	closed  bool
+	done    chan struct{}
+	senders sync.WaitGroup // submissions waiting for room in the queue
+	wg      sync.WaitGroup // workers
+}
+
+// NewDynamicTaskPool returns a pool of @minWorkers to @maxWorkers workers, with a queue
+// of @queueLen tasks. A worker idle for @idleTimeout exits if there are more than
+// @minWorkers workers.
+func NewDynamicTaskPool(minWorkers, maxWorkers, queueLen int, idleTimeout time.Duration) *DynamicTaskPool {
+	if minWorkers < 0 || maxWorkers < 1 || minWorkers > maxWorkers {
+		panic(fmt.Sprintf("illegal worker numbers [%d, %d]", minWorkers, maxWorkers))
+	}
+	if queueLen < 0 {
+		queueLen = 0
+	}
+	if idleTimeout <= 0 {
+		panic("@idleTimeout <= 0")
+	}
+
+	p := &DynamicTaskPool{
+		minWorkers:  minWorkers,
+		maxWorkers:  maxWorkers,
+		idleTimeout: idleTimeout,
+		queue:       make(chan task, queueLen),
+		done:        make(chan struct{}),
+	}
+	p.lock.Lock()
+	for i := 0; i < minWorkers; i++ {
+		p.spawn(nil)
+	}
+	p.lock.Unlock()
+
+	return p
+}
+
+// spawn starts a worker running @first at first. It should be invoked with the lock held.
+func (p *DynamicTaskPool) spawn(first task) {
+	p.workers++
+	p.wg.Add(1)
+	go p.worker(first)
+}
+
+func (p *DynamicTaskPool) worker(t task) {
+	defer p.wg.Done()
+
+	for {
+		if t != nil {
+			runTask(t)
+		}
+
+		var ok bool
+		if t, ok = p.next(); !ok {
+			return
+		}
+	}
+}
+
+func runTask(t task) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "%s goroutine panic: %v\n%s\n",
+				time.Now(), r, string(debug.Stack()))
+		}
+	}()
+	t()
+}
+
+// next waits for a task, and returns false once the worker should exit.
+func (p *DynamicTaskPool) next() (task, bool) {
+	wheel := gxtime.GetDefaultWheel()
+	wait := p.idleTimeout
+	if limit := wheel.Period() - wheel.Span(); wait > limit {
+		wait = limit
+	}
+
+	p.lock.Lock()
+	p.idle++
+	p.lock.Unlock()
+
+	idleSince := time.Now()
+	for {
+		select {
+		case t, ok := <-p.queue:
+			p.lock.Lock()
+			p.idle--
+			if !ok {
+				p.workers--
+			}
+			p.lock.Unlock()
+			return t, ok
+
+		case <-wheel.After(wait):
+			p.lock.Lock()
+			// the queue is checked with the lock held, as the submissions enqueue with it
+			if time.Since(idleSince) >= p.idleTimeout && p.workers > p.minWorkers && len(p.queue) == 0 {
+				p.idle--
+				p.workers--
+				p.lock.Unlock()
+				return nil, false
+			}
+			p.lock.Unlock()
+		}
+	}
+}
+
+// Submit runs @t on a worker, waiting for room in the queue at most @timeout. It
+// returns ErrTaskPoolFull if there is still no room, and a non-positive @timeout means
+// no wait. It returns ErrTaskPoolClosed once the pool is closed.
+func (p *DynamicTaskPool) Submit(t task, timeout time.Duration) error {
+	if timeout <= 0 {
+		return p.submit(t, closedDone, poolFullErr)
+	}
+
+	expired := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() { close(expired) })
+	defer timer.Stop()
+
+	return p.submit(t, expired, poolFullErr)
+}
+
+// SubmitContext runs @t on a worker, waiting for room in the queue until @ctx is done.
+func (p *DynamicTaskPool) SubmitContext(ctx context.Context, t task) error {
+	return p.submit(t, ctx.Done(), ctx.Err)
+}
+
+func poolFullErr() error {
+	return ErrTaskPoolFull
+}
+
+// submit runs @t, or waits for room in the queue until @expired is closed, a nil
+// @expired means no limit.
+func (p *DynamicTaskPool) submit(t task, expired <-chan struct{}, expiredErr func() error) error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return ErrTaskPoolClosed
+	}
+	// the queued tasks are on the way to the idle workers
+	if p.idle <= len(p.queue) && p.workers < p.maxWorkers {
+		p.spawn(t)
+		p.lock.Unlock()
+		return nil
+	}
+	select {
+	case p.queue <- t:
+		p.lock.Unlock()
+		return nil
+	default:
+	}
+	p.senders.Add(1)
+	p.lock.Unlock()
+	defer p.senders.Done()
+
+	select {
+	case p.queue <- t:
+		// the workers may be reaped since the lock was released
+		p.lock.Lock()
+		if p.workers == 0 {
+			p.spawn(nil)
+		}
+		p.lock.Unlock()
+		return nil
+	case <-p.done:
+		return ErrTaskPoolClosed
+	case <-expired:
+		return expiredErr()
+	}
+}
+
+// AddTask runs @t, waiting for room in the queue until the pool is closed, after which
+// it returns false.
+func (p *DynamicTaskPool) AddTask(t task) bool {
+	return p.submit(t, nil, nil) == nil
+}
+
+// AddTaskAlways runs @t, or runs it in a new goroutine if the pool is full.
+func (p *DynamicTaskPool) AddTaskAlways(t task) {
+	if p.Submit(t, 0) == ErrTaskPoolFull {
+		goSafely(t)
+	}
+}
+
+// AddTaskBalance is AddTaskAlways, as the pool has a single queue.
+func (p *DynamicTaskPool) AddTaskBalance(t task) {
+	p.AddTaskAlways(t)
+}
+
+// Workers returns the number of workers.
+func (p *DynamicTaskPool) Workers() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.workers
+}
+
+// IdleWorkers returns the number of workers waiting for tasks.
+func (p *DynamicTaskPool) IdleWorkers() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.idle
+}
+
+// QueueLen returns the number of tasks waiting in the queue.
+func (p *DynamicTaskPool) QueueLen() int {
+	return len(p.queue)
+}
+
+// stop rejects the later submissions, and closes the queue once the waiting ones quit.
+func (p *DynamicTaskPool) stop() {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.done)
+	p.lock.Unlock()
+
+	p.senders.Wait()
+	close(p.queue)
+}
+
+// Close rejects the later submissions, and waits for the workers to run the queued
+// tasks and exit.
+func (p *DynamicTaskPool) Close() {
+	p.stop()
+	p.wg.Wait()
+}
+
+// Shutdown is Close waiting until @ctx is done, after which it returns ctx.Err() and
+// the workers still go on with the queued tasks.
+func (p *DynamicTaskPool) Shutdown(ctx context.Context) error {
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsClosed reports whether the pool is closed.
+func (p *DynamicTaskPool) IsClosed() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
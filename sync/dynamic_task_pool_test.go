@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicTaskPoolGrowAndReap(t *testing.T) {
+	p := NewDynamicTaskPool(1, 4, 2, 50*time.Millisecond)
+	defer p.Close()
+	assert.Equal(t, 1, p.Workers())
+
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		started.Add(1)
+		assert.Nil(t, p.Submit(func() {
+			started.Done()
+			<-release
+		}, 0))
+	}
+	started.Wait()
+	assert.Equal(t, 4, p.Workers())
+
+	// the max workers are busy, so the tasks queue up and then overflow
+	var ran int32
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, p.Submit(func() { atomic.AddInt32(&ran, 1) }, 0))
+	}
+	assert.Equal(t, 2, p.QueueLen())
+	assert.Equal(t, ErrTaskPoolFull, p.Submit(func() {}, 10*time.Millisecond))
+
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- p.Submit(func() { atomic.AddInt32(&ran, 1) }, time.Second)
+	}()
+	close(release)
+	assert.Nil(t, <-submitted)
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&ran) == 3 }, time.Second, time.Millisecond)
+	// the idle workers are reaped down to the min
+	assert.Eventually(t, func() bool { return p.Workers() == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, p.IdleWorkers())
+}
+
+func TestDynamicTaskPoolClose(t *testing.T) {
+	p := NewDynamicTaskPool(0, 1, 8, time.Second)
+	assert.Equal(t, 0, p.Workers())
+
+	var ran int32
+	block := make(chan struct{})
+	assert.True(t, p.AddTask(func() { <-block }))
+	for i := 0; i < 8; i++ {
+		p.AddTaskAlways(func() { atomic.AddInt32(&ran, 1) })
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.SubmitContext(ctx, func() {}))
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel2()
+	assert.Equal(t, context.DeadlineExceeded, p.Shutdown(ctx2))
+	assert.True(t, p.IsClosed())
+	assert.Equal(t, ErrTaskPoolClosed, p.Submit(func() {}, 0))
+	assert.False(t, p.AddTask(func() {}))
+
+	// the queued tasks still run after the close
+	close(block)
+	p.Close()
+	assert.Equal(t, int32(8), atomic.LoadInt32(&ran))
+	assert.Equal(t, 0, p.Workers())
+}
+
+func TestDynamicTaskPoolPanic(t *testing.T) {
+	p := NewDynamicTaskPool(1, 1, 1, time.Second)
+	defer p.Close()
+
+	done := make(chan struct{})
+	assert.Nil(t, p.Submit(func() { panic("boom") }, time.Second))
+	assert.Nil(t, p.Submit(func() { close(done) }, time.Second))
+	<-done
+	assert.Equal(t, 1, p.Workers())
+
+	assert.Panics(t, func() { NewDynamicTaskPool(2, 1, 0, time.Second) })
+	var _ GenericTaskPool = p
+}
@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	var g Group
+	var n int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			atomic.AddInt32(&n, 1)
+			return nil
+		})
+	}
+	assert.Nil(t, g.Wait())
+	assert.Equal(t, int32(10), n)
+}
+
+func TestGroupCancel(t *testing.T) {
+	g, ctx := NewGroup(context.Background())
+	errFirst := errors.New("first")
+
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	g.Go(func() error { return errFirst })
+	assert.Equal(t, errFirst, g.Wait())
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestGroupLimit(t *testing.T) {
+	var (
+		g           Group
+		running     int32
+		maxRunning  int32
+		release     = make(chan struct{})
+		blockedDone = make(chan struct{})
+	)
+	g.SetLimit(2)
+
+	task := func() error {
+		cur := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&maxRunning)
+			if cur <= old || atomic.CompareAndSwapInt32(&maxRunning, old, cur) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+	g.Go(task)
+	g.Go(task)
+	assert.False(t, g.TryGo(task))
+	assert.Panics(t, func() { g.SetLimit(3) })
+
+	go func() {
+		g.Go(task) // waits for a slot
+		close(blockedDone)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case <-blockedDone:
+		t.Fatal("Go ignored the limit")
+	default:
+	}
+
+	close(release)
+	<-blockedDone
+	assert.Nil(t, g.Wait())
+	assert.Equal(t, int32(2), maxRunning)
+	assert.True(t, g.TryGo(func() error { return nil }))
+	assert.Nil(t, g.Wait())
+}
+
+func TestGroupPanic(t *testing.T) {
+	g, ctx := NewGroup(context.Background())
+	errCause := errors.New("cause")
+	g.Go(func() error { panic(errCause) })
+
+	err := g.Wait()
+	var perr *PanicError
+	assert.True(t, errors.As(err, &perr))
+	assert.True(t, errors.Is(err, errCause))
+	assert.True(t, strings.Contains(err.Error(), "group_test.go"))
+	assert.NotNil(t, ctx.Err())
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+type idempotencyEntry[V any] struct {
+	done  chan struct{} // closed once the operation finishes
+	value V
+	err   error
+	timer *gxtime.Timer // expires the finished entry
+}
+
+// IdempotencyGuard runs an operation at most once per key within a TTL, for the
+// exactly-once-ish handling of retried requests. A duplicate of a running operation
+// joins it, and a duplicate of a succeeded one gets the cached result until the key
+// expires on the default wheel. A failed operation is not cached, so that its retry
+// runs again. It is goroutine safe.
+type IdempotencyGuard[K comparable, V any] struct {
+	ttl     time.Duration
+	lock    sync.Mutex
+	entries map[K]*idempotencyEntry[V]
+}
+
+// NewIdempotencyGuard returns a guard which keeps the results for @ttl after the
+// operations succeed.
+func NewIdempotencyGuard[K comparable, V any](ttl time.Duration) *IdempotencyGuard[K, V] {
+	if ttl <= 0 {
+		panic("@ttl <= 0")
+	}
+
+	return &IdempotencyGuard[K, V]{ttl: ttl, entries: make(map[K]*idempotencyEntry[V])}
+}
+
+// Do runs @f for @key unless it is running or has succeeded within the TTL, in which
+// case it waits for and returns that result. @duplicate reports whether the result
+// comes from another call. Waiting for a running call ends once @ctx is done, which
+// leaves that call running.
+func (g *IdempotencyGuard[K, V]) Do(ctx context.Context, key K, f func(ctx context.Context) (V, error)) (value V, duplicate bool, err error) {
+	g.lock.Lock()
+	if e, ok := g.entries[key]; ok {
+		g.lock.Unlock()
+		select {
+		case <-e.done:
+			return e.value, true, e.err
+		case <-ctx.Done():
+			var zero V
+			return zero, true, ctx.Err()
+		}
+	}
+	e := &idempotencyEntry[V]{done: make(chan struct{})}
+	g.entries[key] = e
+	g.lock.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			g.finish(key, e, value, errIdempotencyPanic)
+			panic(r)
+		}
+		g.finish(key, e, value, err)
+	}()
+	value, err = f(ctx)
+
+	return value, false, err
+}
+
+var errIdempotencyPanic = errors.New("gxsync: idempotent operation panicked")
+
+func (g *IdempotencyGuard[K, V]) finish(key K, e *idempotencyEntry[V], value V, err error) {
+	g.lock.Lock()
+	e.value, e.err = value, err
+	if err != nil {
+		delete(g.entries, key)
+	} else {
+		e.timer = gxtime.GetDefaultWheel().AddTimerInline(func(interface{}) {
+			g.expire(key, e)
+		}, g.ttl, 1, nil)
+	}
+	g.lock.Unlock()
+
+	close(e.done)
+}
+
+func (g *IdempotencyGuard[K, V]) expire(key K, e *idempotencyEntry[V]) {
+	g.lock.Lock()
+	if g.entries[key] == e {
+		delete(g.entries, key)
+	}
+	g.lock.Unlock()
+}
+
+// Forget drops the result of @key, so that the next Do of @key runs again. The calls
+// joining a running operation still get its result.
+func (g *IdempotencyGuard[K, V]) Forget(key K) {
+	var timer *gxtime.Timer
+	g.lock.Lock()
+	if e, ok := g.entries[key]; ok {
+		timer = e.timer
+		delete(g.entries, key)
+	}
+	g.lock.Unlock()
+
+	if timer != nil {
+		timer.Stop()
+	}
+}
+
+// Len returns the number of the keys running or cached.
+func (g *IdempotencyGuard[K, V]) Len() int {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return len(g.entries)
+}
@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyGuardJoin(t *testing.T) {
+	g := NewIdempotencyGuard[string, int](time.Minute)
+
+	var (
+		runs    int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+		dups    int32
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, dup, err := g.Do(context.Background(), "op-1", func(ctx context.Context) (int, error) {
+				atomic.AddInt32(&runs, 1)
+				<-release
+				return 42, nil
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 42, v)
+			if dup {
+				atomic.AddInt32(&dups, 1)
+			}
+		}()
+	}
+	for g.Len() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+	assert.Equal(t, int32(9), atomic.LoadInt32(&dups))
+
+	// the result is cached
+	v, dup, err := g.Do(context.Background(), "op-1", func(ctx context.Context) (int, error) { return 0, nil })
+	assert.Nil(t, err)
+	assert.True(t, dup)
+	assert.Equal(t, 42, v)
+
+	g.Forget("op-1")
+	v, dup, _ = g.Do(context.Background(), "op-1", func(ctx context.Context) (int, error) { return 7, nil })
+	assert.False(t, dup)
+	assert.Equal(t, 7, v)
+}
+
+func TestIdempotencyGuardFailure(t *testing.T) {
+	g := NewIdempotencyGuard[string, int](time.Minute)
+	errFail := errors.New("fail")
+
+	_, dup, err := g.Do(context.Background(), "op", func(ctx context.Context) (int, error) { return 0, errFail })
+	assert.Equal(t, errFail, err)
+	assert.False(t, dup)
+	assert.Equal(t, 0, g.Len(), "a failure is not cached")
+
+	assert.Panics(t, func() {
+		g.Do(context.Background(), "op", func(ctx context.Context) (int, error) { panic("boom") })
+	})
+	assert.Equal(t, 0, g.Len())
+
+	// a waiter gives up with its context
+	release := make(chan struct{})
+	go g.Do(context.Background(), "slow", func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	for g.Len() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, dup, err = g.Do(ctx, "slow", func(ctx context.Context) (int, error) { return 2, nil })
+	assert.True(t, dup)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	close(release)
+}
+
+func TestIdempotencyGuardExpire(t *testing.T) {
+	g := NewIdempotencyGuard[int, string](50 * time.Millisecond)
+	_, _, err := g.Do(context.Background(), 1, func(ctx context.Context) (string, error) { return "a", nil })
+	assert.Nil(t, err)
+	assert.Equal(t, 1, g.Len())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for g.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, g.Len())
+
+	v, dup, _ := g.Do(context.Background(), 1, func(ctx context.Context) (string, error) { return "b", nil })
+	assert.False(t, dup)
+	assert.Equal(t, "b", v)
+}
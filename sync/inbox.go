@@ -0,0 +1,181 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+var (
+	// ErrInboxClosed is returned by the operations on a closed Inbox.
+	ErrInboxClosed = errors.New("inbox closed")
+	// ErrRequestTimeout is returned by Inbox.Request when no response arrives in time.
+	ErrRequestTimeout = errors.New("inbox request timeout")
+)
+
+// Message is a message of an Inbox. @ID is the correlation id of a request, which
+// is zero for a message put by Send.
+type Message[T any] struct {
+	ID   uint64
+	Body T
+}
+
+// IsRequest reports whether the sender waits for a response of the message.
+func (m Message[T]) IsRequest() bool {
+	return m.ID != 0
+}
+
+// Inbox is a typed message channel of an actor style module, which supports both
+// one-way messages and request/response pairs correlated by ids.
+type Inbox[T any] struct {
+	ch     chan Message[T]
+	done   chan struct{}
+	once   sync.Once
+	lock   sync.Mutex
+	seq    uint64
+	calls  map[uint64]chan T
+	closed bool
+}
+
+// NewInbox returns an Inbox buffering @size messages.
+func NewInbox[T any](size int) *Inbox[T] {
+	return &Inbox[T]{
+		ch:    make(chan Message[T], size),
+		done:  make(chan struct{}),
+		calls: make(map[uint64]chan T),
+	}
+}
+
+// Receive returns the channel on which the messages arrive. It is never closed, so
+// the receiver should select on Done as well.
+func (in *Inbox[T]) Receive() <-chan Message[T] {
+	return in.ch
+}
+
+// Done returns a channel which is closed once the Inbox is closed.
+func (in *Inbox[T]) Done() <-chan struct{} {
+	return in.done
+}
+
+// put puts @msg, failing with ErrRequestTimeout once @expired is closed if it is not nil.
+func (in *Inbox[T]) put(ctx context.Context, msg Message[T], expired <-chan struct{}) error {
+	select {
+	case <-in.done:
+		return ErrInboxClosed
+	default:
+	}
+
+	select {
+	case in.ch <- msg:
+		return nil
+	case <-expired:
+		return ErrRequestTimeout
+	case <-in.done:
+		return ErrInboxClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Send puts a one-way message of @body, blocking until there is room, @ctx is done
+// or the Inbox is closed.
+func (in *Inbox[T]) Send(ctx context.Context, body T) error {
+	return in.put(ctx, Message[T]{Body: body}, nil)
+}
+
+// Request puts a request of @body and waits for its response. The whole request, both
+// the wait for room in the Inbox and the wait for the response, is bounded by @ctx and
+// by @timeout, which is scheduled on the default wheel. A non-positive @timeout means
+// no timeout besides @ctx.
+func (in *Inbox[T]) Request(ctx context.Context, body T, timeout time.Duration) (T, error) {
+	var zero T
+
+	in.lock.Lock()
+	if in.closed {
+		in.lock.Unlock()
+		return zero, ErrInboxClosed
+	}
+	in.seq++
+	id := in.seq
+	reply := make(chan T, 1)
+	in.calls[id] = reply
+	in.lock.Unlock()
+
+	defer func() {
+		in.lock.Lock()
+		delete(in.calls, id)
+		in.lock.Unlock()
+	}()
+
+	var expired <-chan struct{}
+	if timeout > 0 {
+		deadline := gxtime.NewDeadline(gxtime.Now().Add(timeout))
+		defer deadline.Stop()
+		expired = deadline.Done()
+	}
+
+	if err := in.put(ctx, Message[T]{ID: id, Body: body}, expired); err != nil {
+		return zero, err
+	}
+
+	select {
+	case resp := <-reply:
+		return resp, nil
+	case <-expired:
+		return zero, ErrRequestTimeout
+	case <-in.done:
+		return zero, ErrInboxClosed
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Respond delivers @body as the response of request @id. It returns false if the
+// request is not waiting any longer, e.g. it has timed out.
+func (in *Inbox[T]) Respond(id uint64, body T) bool {
+	in.lock.Lock()
+	reply, ok := in.calls[id]
+	if ok {
+		delete(in.calls, id)
+	}
+	in.lock.Unlock()
+
+	if ok {
+		reply <- body
+	}
+
+	return ok
+}
+
+// Close closes the Inbox. The pending requests fail with ErrInboxClosed, and the
+// messages left in the buffer can still be received.
+func (in *Inbox[T]) Close() {
+	in.once.Do(func() {
+		in.lock.Lock()
+		in.closed = true
+		in.lock.Unlock()
+		close(in.done)
+	})
+}
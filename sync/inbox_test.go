@@ -0,0 +1,87 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInbox(t *testing.T) {
+	in := NewInbox[string](4)
+	defer in.Close()
+
+	go func() {
+		for {
+			select {
+			case msg := <-in.Receive():
+				if msg.IsRequest() {
+					in.Respond(msg.ID, "re: "+msg.Body)
+				}
+			case <-in.Done():
+				return
+			}
+		}
+	}()
+
+	assert.Nil(t, in.Send(context.Background(), "hello"))
+	resp, err := in.Request(context.Background(), "ping", time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "re: ping", resp)
+}
+
+func TestInboxRequestTimeout(t *testing.T) {
+	in := NewInbox[int](1)
+	_, err := in.Request(context.Background(), 1, 30*time.Millisecond)
+	assert.Equal(t, ErrRequestTimeout, err)
+
+	msg := <-in.Receive()
+	assert.True(t, msg.IsRequest())
+	assert.False(t, in.Respond(msg.ID, 2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	_, err = in.Request(ctx, 1, 0)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// the request above is left in the inbox, so the timeout bounds the wait for room
+	start := time.Now()
+	_, err = in.Request(context.Background(), 1, 30*time.Millisecond)
+	assert.Equal(t, ErrRequestTimeout, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestInboxClose(t *testing.T) {
+	in := NewInbox[int](0)
+	errs := make(chan error, 1)
+	go func() {
+		_, err := in.Request(context.Background(), 1, 0)
+		errs <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	in.Close()
+	assert.Equal(t, ErrInboxClosed, <-errs)
+	assert.Equal(t, ErrInboxClosed, in.Send(context.Background(), 1))
+	_, err := in.Request(context.Background(), 1, 0)
+	assert.Equal(t, ErrInboxClosed, err)
+}
@@ -19,6 +19,7 @@ package gxsync
 
 import (
 	"fmt"
+	"time"
 )
 
 const (
@@ -35,6 +36,9 @@ type TaskPoolOptions struct {
 	tQLen      int // task queue length. buffer size per queue
 	tQNumber   int // task queue number. number of queue
 	tQPoolSize int // task pool size. number of workers
+
+	provenance    bool          // record the call site of every task
+	slowThreshold time.Duration // report tasks running longer than it
 }
 
 func (o *TaskPoolOptions) validate() {
@@ -77,3 +81,18 @@ func WithTaskPoolTaskQueueNumber(number int) TaskPoolOption {
 		o.tQNumber = number
 	}
 }
+
+// WithTaskPoolTaskProvenance records the call stack of the submitter of every task,
+// which will be included in its panic and slow task reports.
+func WithTaskPoolTaskProvenance() TaskPoolOption {
+	return func(o *TaskPoolOptions) {
+		o.provenance = true
+	}
+}
+
+// WithTaskPoolSlowTaskThreshold reports the tasks whose running time exceeds @threshold
+func WithTaskPoolSlowTaskThreshold(threshold time.Duration) TaskPoolOption {
+	return func(o *TaskPoolOptions) {
+		o.slowThreshold = threshold
+	}
+}
@@ -0,0 +1,268 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+// ErrPoolClosed is returned by Pool.Get after the pool is closed.
+var ErrPoolClosed = errors.New("gxsync: pool closed")
+
+// PoolConfig configures a Pool of the resources of T.
+type PoolConfig[T any] struct {
+	// Dial opens a new resource. It is required.
+	Dial func(ctx context.Context) (T, error)
+	// Validate checks an idle resource on borrow, a failed one is closed. It is optional.
+	Validate func(v T) error
+	// Close closes a resource dropped by the pool. It is optional.
+	Close func(v T) error
+
+	// MaxIdle is the max number of the idle resources, 0 keeps no idle one.
+	MaxIdle int
+	// MaxActive is the max number of the open resources, the idle ones included.
+	// A non-positive one is unlimited.
+	MaxActive int
+	// IdleTimeout closes the resources idle for longer than it. A non-positive one
+	// keeps them until the pool is closed.
+	IdleTimeout time.Duration
+}
+
+type idleResource[T any] struct {
+	v     T
+	since time.Time
+}
+
+// PoolStats is a snapshot of the counters of a Pool.
+type PoolStats struct {
+	Active  int // the open resources, the idle ones included
+	Idle    int
+	Waiting int // the Gets waiting for MaxActive
+}
+
+// Pool keeps the resources of T such as TCP connections, gRPC clients or DB handles
+// for reuse, which are opened, checked and closed by the hooks of its PoolConfig. The
+// most recently returned idle resource is borrowed first, so that the others can time
+// out, and the idle ones are swept by the default wheel. It is goroutine safe.
+type Pool[T any] struct {
+	config PoolConfig[T]
+
+	lock    sync.Mutex
+	idle    []idleResource[T] // from the oldest to the newest
+	active  int
+	waiting int
+	closed  bool
+	changed chan struct{} // closed and replaced whenever a resource is returned or closed
+	timer   *gxtime.Timer
+}
+
+// NewPool returns a pool of @config.
+func NewPool[T any](config PoolConfig[T]) *Pool[T] {
+	if config.Dial == nil {
+		panic("@config.Dial is nil")
+	}
+	if config.MaxIdle < 0 {
+		panic("@config.MaxIdle < 0")
+	}
+
+	p := &Pool[T]{config: config, changed: make(chan struct{})}
+	if config.IdleTimeout > 0 {
+		wheel := gxtime.GetDefaultWheel()
+		interval := config.IdleTimeout / 2
+		if span := wheel.Span(); interval < span {
+			interval = span
+		}
+		p.timer = wheel.AddTimer(func(interface{}) { p.sweep() }, interval, nil)
+	}
+
+	return p
+}
+
+// broadcast should be invoked with the lock held.
+func (p *Pool[T]) broadcast() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+func (p *Pool[T]) close(v T) error {
+	if p.config.Close == nil {
+		return nil
+	}
+
+	return p.config.Close(v)
+}
+
+// expired should be invoked with the lock held.
+func (p *Pool[T]) expired(r idleResource[T], now time.Time) bool {
+	return p.config.IdleTimeout > 0 && now.Sub(r.since) >= p.config.IdleTimeout
+}
+
+// Get borrows an idle resource which passes the validation, or dials a new one if
+// there is none. It waits for a resource returned or closed until @ctx is done once
+// MaxActive is reached. The resource must be given back by Put or Discard.
+func (p *Pool[T]) Get(ctx context.Context) (T, error) {
+	var zero T
+
+	p.lock.Lock()
+	for {
+		if p.closed {
+			p.lock.Unlock()
+			return zero, ErrPoolClosed
+		}
+
+		if n := len(p.idle); n > 0 {
+			r := p.idle[n-1]
+			p.idle[n-1] = idleResource[T]{}
+			p.idle = p.idle[:n-1]
+			stale := p.expired(r, gxtime.Now())
+			p.lock.Unlock()
+
+			if !stale && (p.config.Validate == nil || p.config.Validate(r.v) == nil) {
+				return r.v, nil
+			}
+			p.Discard(r.v)
+			p.lock.Lock()
+			continue
+		}
+
+		if p.config.MaxActive <= 0 || p.active < p.config.MaxActive {
+			p.active++
+			p.lock.Unlock()
+
+			v, err := p.config.Dial(ctx)
+			if err != nil {
+				p.lock.Lock()
+				p.active--
+				p.broadcast()
+				p.lock.Unlock()
+				return zero, err
+			}
+			return v, nil
+		}
+
+		changed := p.changed
+		p.waiting++
+		p.lock.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			p.lock.Lock()
+			p.waiting--
+			p.lock.Unlock()
+			return zero, ctx.Err()
+		}
+		p.lock.Lock()
+		p.waiting--
+	}
+}
+
+// Put returns @v borrowed by Get to the pool, which closes it if the pool is closed
+// or MaxIdle is reached.
+func (p *Pool[T]) Put(v T) error {
+	p.lock.Lock()
+	if p.closed || len(p.idle) >= p.config.MaxIdle {
+		p.active--
+		p.broadcast()
+		p.lock.Unlock()
+		return p.close(v)
+	}
+	p.idle = append(p.idle, idleResource[T]{v: v, since: gxtime.Now()})
+	p.broadcast()
+	p.lock.Unlock()
+
+	return nil
+}
+
+// Discard closes @v borrowed by Get instead of returning it, e.g. after an I/O error.
+func (p *Pool[T]) Discard(v T) error {
+	p.lock.Lock()
+	p.active--
+	p.broadcast()
+	p.lock.Unlock()
+
+	return p.close(v)
+}
+
+// sweep closes the idle resources timed out.
+func (p *Pool[T]) sweep() {
+	now := gxtime.Now()
+
+	p.lock.Lock()
+	n := 0
+	for n < len(p.idle) && p.expired(p.idle[n], now) {
+		n++
+	}
+	if n == 0 {
+		p.lock.Unlock()
+		return
+	}
+	stale := make([]idleResource[T], n)
+	copy(stale, p.idle)
+	p.idle = append(p.idle[:0], p.idle[n:]...)
+	p.active -= n
+	p.broadcast()
+	p.lock.Unlock()
+
+	for _, r := range stale {
+		p.close(r.v)
+	}
+}
+
+// Stats returns the counters of the pool.
+func (p *Pool[T]) Stats() PoolStats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return PoolStats{Active: p.active, Idle: len(p.idle), Waiting: p.waiting}
+}
+
+// Close closes the idle resources and fails the Gets with ErrPoolClosed, the waiting
+// ones included. The borrowed resources are closed once they are returned. It returns
+// the first error of closing the idle resources.
+func (p *Pool[T]) Close() error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil
+	}
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	p.active -= len(idle)
+	p.broadcast()
+	p.lock.Unlock()
+
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	var first error
+	for _, r := range idle {
+		if err := p.close(r.v); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
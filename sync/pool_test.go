@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type poolConn struct {
+	id     int
+	broken bool
+	closed bool
+}
+
+type poolDialer struct {
+	lock  sync.Mutex
+	dials int
+	err   error
+}
+
+func (d *poolDialer) config(maxIdle, maxActive int, idleTimeout time.Duration) PoolConfig[*poolConn] {
+	return PoolConfig[*poolConn]{
+		Dial: func(context.Context) (*poolConn, error) {
+			d.lock.Lock()
+			defer d.lock.Unlock()
+			if d.err != nil {
+				return nil, d.err
+			}
+			d.dials++
+			return &poolConn{id: d.dials}, nil
+		},
+		Validate: func(c *poolConn) error {
+			if c.broken {
+				return errors.New("broken")
+			}
+			return nil
+		},
+		Close: func(c *poolConn) error {
+			d.lock.Lock()
+			c.closed = true
+			d.lock.Unlock()
+			return nil
+		},
+		MaxIdle:     maxIdle,
+		MaxActive:   maxActive,
+		IdleTimeout: idleTimeout,
+	}
+}
+
+func TestPoolReuse(t *testing.T) {
+	d := &poolDialer{}
+	p := NewPool(d.config(1, 0, 0))
+	defer p.Close()
+
+	c1, err := p.Get(context.Background())
+	assert.Nil(t, err)
+	c2, err := p.Get(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, PoolStats{Active: 2}, p.Stats())
+
+	assert.Nil(t, p.Put(c1))
+	// over MaxIdle
+	assert.Nil(t, p.Put(c2))
+	assert.True(t, c2.closed)
+	assert.Equal(t, PoolStats{Active: 1, Idle: 1}, p.Stats())
+
+	c, err := p.Get(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, c1, c)
+
+	// a broken one fails the validation on borrow
+	c.broken = true
+	assert.Nil(t, p.Put(c))
+	c, err = p.Get(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, c.id)
+	assert.True(t, c1.closed)
+
+	assert.Nil(t, p.Discard(c))
+	assert.True(t, c.closed)
+	assert.Equal(t, PoolStats{}, p.Stats())
+
+	d.err = errors.New("refused")
+	_, err = p.Get(context.Background())
+	assert.Equal(t, d.err, err)
+	assert.Equal(t, PoolStats{}, p.Stats())
+}
+
+func TestPoolMaxActive(t *testing.T) {
+	d := &poolDialer{}
+	p := NewPool(d.config(1, 1, 0))
+
+	c, err := p.Get(context.Background())
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = p.Get(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	got := make(chan *poolConn)
+	go func() {
+		c, err := p.Get(context.Background())
+		assert.Nil(t, err)
+		got <- c
+	}()
+	for p.Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Nil(t, p.Put(c))
+	assert.Equal(t, c, <-got)
+
+	// Close fails the waiting ones and closes the later returned resources
+	failed := make(chan error)
+	go func() {
+		_, err := p.Get(context.Background())
+		failed <- err
+	}()
+	for p.Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Nil(t, p.Close())
+	assert.Equal(t, ErrPoolClosed, <-failed)
+	assert.Nil(t, p.Put(c))
+	assert.True(t, c.closed)
+	assert.Equal(t, PoolStats{}, p.Stats())
+}
+
+func TestPoolIdleTimeout(t *testing.T) {
+	d := &poolDialer{}
+	p := NewPool(d.config(2, 0, 50*time.Millisecond))
+	defer p.Close()
+
+	c1, _ := p.Get(context.Background())
+	c2, _ := p.Get(context.Background())
+	assert.Nil(t, p.Put(c1))
+	assert.Nil(t, p.Put(c2))
+	assert.Equal(t, 2, p.Stats().Idle)
+
+	assert.Eventually(t, func() bool {
+		return p.Stats() == PoolStats{}
+	}, 2*time.Second, 10*time.Millisecond)
+	d.lock.Lock()
+	assert.True(t, c1.closed && c2.closed)
+	d.lock.Unlock()
+}
@@ -0,0 +1,119 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// LeakyBucket paces the events evenly at @rate per second without bursts, e.g. to
+// smooth the calls to a fragile backend. An event early for its slot waits in a queue
+// of at most @queue events. It is lock-free like TokenBucket. The time is read by
+// gxtime.Now.
+type LeakyBucket struct {
+	next     int64 // unix nanoseconds of the next free slot
+	interval int64 // nanoseconds per event
+	maxQueue int64 // nanoseconds an event may wait for its slot
+}
+
+// NewLeakyBucket returns a bucket letting @rate events per second through, with @queue
+// events waiting at most. A non-positive @queue means no waiting.
+func NewLeakyBucket(rate float64, queue int) *LeakyBucket {
+	if queue < 0 {
+		queue = 0
+	}
+
+	b := &LeakyBucket{interval: interval(rate)}
+	b.maxQueue = int64(queue) * b.interval
+
+	return b
+}
+
+// Rate returns the events let through per second.
+func (b *LeakyBucket) Rate() float64 {
+	return float64(time.Second) / float64(b.interval)
+}
+
+// take takes the next slot if it comes within @maxDelay, and returns the delay and
+// the end of the slot.
+func (b *LeakyBucket) take(maxDelay int64) (delay, end int64, ok bool) {
+	if maxDelay > b.maxQueue {
+		maxDelay = b.maxQueue
+	}
+
+	for {
+		t := now()
+		old := atomic.LoadInt64(&b.next)
+		slot := old
+		if slot < t {
+			slot = t
+		}
+		delay = slot - t
+		if delay > maxDelay {
+			return 0, 0, false
+		}
+		end = slot + b.interval
+		if atomic.CompareAndSwapInt64(&b.next, old, end) {
+			return delay, end, true
+		}
+	}
+}
+
+// Allow takes the slot of now if it is free.
+func (b *LeakyBucket) Allow() bool {
+	_, _, ok := b.take(0)
+	return ok
+}
+
+// Reserve takes the next slot, and returns how long to wait for it. It returns false
+// if the queue is full.
+func (b *LeakyBucket) Reserve() (time.Duration, bool) {
+	delay, _, ok := b.take(b.maxQueue)
+	return time.Duration(delay), ok
+}
+
+// WaitCtx waits for the next slot until @ctx is done. It returns ErrQueueFull at once
+// if the queue is full. If @ctx is done first, the slot reserved is given back unless
+// a later slot has been taken, which keeps the events evenly paced.
+func (b *LeakyBucket) WaitCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	maxDelay := b.maxQueue
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		maxDelay = int64(time.Until(deadline))
+	}
+	delay, end, ok := b.take(maxDelay)
+	if !ok {
+		if hasDeadline && maxDelay < b.maxQueue {
+			return context.DeadlineExceeded
+		}
+		return ErrQueueFull
+	}
+
+	if err := wait(ctx, time.Duration(delay)); err != nil {
+		atomic.CompareAndSwapInt64(&b.next, end, end-b.interval)
+		return err
+	}
+
+	return nil
+}
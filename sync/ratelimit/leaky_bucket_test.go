@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+func TestLeakyBucket(t *testing.T) {
+	clock := newFakeClock()
+	defer gxtime.SetTimeSource(nil)
+
+	b := NewLeakyBucket(10, 2)
+	assert.Equal(t, float64(10), b.Rate())
+	assert.True(t, b.Allow())
+	// no burst
+	assert.False(t, b.Allow())
+
+	delay, ok := b.Reserve()
+	assert.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, delay)
+	delay, ok = b.Reserve()
+	assert.True(t, ok)
+	assert.Equal(t, 200*time.Millisecond, delay)
+	_, ok = b.Reserve()
+	assert.False(t, ok)
+	assert.Equal(t, ErrQueueFull, b.WaitCtx(context.Background()))
+
+	clock.Advance(300 * time.Millisecond)
+	assert.True(t, b.Allow())
+	nb := NewLeakyBucket(1, 0)
+	assert.True(t, nb.Allow())
+	_, ok = nb.Reserve()
+	assert.False(t, ok)
+}
+
+func TestLeakyBucketWaitCtx(t *testing.T) {
+	b := NewLeakyBucket(200, 10)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, b.WaitCtx(ctx))
+	}
+	// the first one at once and the others 5ms apart
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, b.WaitCtx(canceled))
+}
+
+func TestLeakyBucketWaitCtxGiveBack(t *testing.T) {
+	clock := newFakeClock()
+	defer gxtime.SetTimeSource(nil)
+	start := clock.Now().UnixNano()
+
+	b := NewLeakyBucket(10, 10)
+	assert.True(t, b.Allow())
+	waitCanceled := func(later func()) {
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error)
+		next := atomic.LoadInt64(&b.next)
+		go func() { errs <- b.WaitCtx(ctx) }()
+		for atomic.LoadInt64(&b.next) == next {
+			time.Sleep(time.Millisecond)
+		}
+		later()
+		cancel()
+		assert.Equal(t, context.Canceled, <-errs)
+	}
+
+	// the slot at the tail is given back
+	waitCanceled(func() {})
+	assert.Equal(t, start+int64(100*time.Millisecond), atomic.LoadInt64(&b.next))
+
+	// the slot followed by a later one is not, or two events would share an interval
+	waitCanceled(func() {
+		delay, ok := b.Reserve()
+		assert.True(t, ok)
+		assert.Equal(t, 200*time.Millisecond, delay)
+	})
+	delay, ok := b.Reserve()
+	assert.True(t, ok)
+	assert.Equal(t, 300*time.Millisecond, delay)
+}
@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxratelimit implements lock-free token bucket and leaky bucket rate limiters.
+package gxratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+var (
+	// ErrExceedsBurst is returned by a wait for more tokens than the burst of a bucket.
+	ErrExceedsBurst = errors.New("gxratelimit: tokens exceed the burst")
+	// ErrQueueFull is returned by a wait on a leaky bucket whose queue is full.
+	ErrQueueFull = errors.New("gxratelimit: queue full")
+)
+
+// interval returns the nanoseconds between two events at @rate per second.
+func interval(rate float64) int64 {
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		panic("@rate should be a positive number")
+	}
+	ns := int64(float64(time.Second) / rate)
+	if ns < 1 {
+		ns = 1
+	}
+
+	return ns
+}
+
+func now() int64 {
+	return gxtime.Now().UnixNano()
+}
+
+// wait waits @d until @ctx is done. The long waits are scheduled by an inline timer of
+// the default gxtime wheel, which counts in the time since the last tick so that they
+// never end early, and the ones shorter than a few spans by a runtime timer.
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	wheel := gxtime.GetDefaultWheel()
+	if d >= 4*wheel.Span() {
+		done := make(chan struct{})
+		t := wheel.AddTimerInline(func(interface{}) { close(done) }, d, 1, nil)
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	for _, d := range []time.Duration{
+		5 * time.Millisecond, 45 * time.Millisecond, 47 * time.Millisecond, 63 * time.Millisecond,
+	} {
+		for i := 0; i < 3; i++ {
+			// start at different phases of the wheel ticks
+			time.Sleep(3 * time.Millisecond)
+			start := time.Now()
+			assert.Nil(t, wait(context.Background(), d))
+			assert.True(t, time.Since(start) >= d, "wait %s ends after %s", d, time.Since(start))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, wait(ctx, time.Second))
+}
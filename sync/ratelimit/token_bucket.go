@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// TokenBucket is a lock-free token bucket, which refills @rate tokens per second up to
+// @burst. It keeps the theoretical arrival time of the next token in nanoseconds as in
+// GCRA instead of a token count, so taking tokens is a single CAS. The time is read by
+// gxtime.Now.
+type TokenBucket struct {
+	tat      int64 // unix nanoseconds when the bucket is full again
+	interval int64 // nanoseconds per token
+	burst    int64
+}
+
+// NewTokenBucket returns a full bucket of @burst tokens refilled at @rate per second.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		panic("@burst < 1")
+	}
+
+	return &TokenBucket{interval: interval(rate), burst: int64(burst)}
+}
+
+// Rate returns the tokens refilled per second.
+func (b *TokenBucket) Rate() float64 {
+	return float64(time.Second) / float64(b.interval)
+}
+
+// Burst returns the capacity of the bucket.
+func (b *TokenBucket) Burst() int {
+	return int(b.burst)
+}
+
+// Tokens returns the tokens available now.
+func (b *TokenBucket) Tokens() int {
+	debt := atomic.LoadInt64(&b.tat) - now()
+	if debt <= 0 {
+		return int(b.burst)
+	}
+	if tokens := b.burst - (debt+b.interval-1)/b.interval; tokens > 0 {
+		return int(tokens)
+	}
+
+	return 0
+}
+
+// take takes @n tokens if they are available within @maxDelay, and returns the delay
+// and the new theoretical arrival time.
+func (b *TokenBucket) take(n int64, maxDelay int64) (delay, newTat int64, ok bool) {
+	if n > b.burst {
+		return 0, 0, false
+	}
+
+	for {
+		t := now()
+		old := atomic.LoadInt64(&b.tat)
+		tat := old
+		if tat < t {
+			tat = t
+		}
+		newTat = tat + n*b.interval
+		// the tokens are available once the bucket has room for them
+		delay = newTat - b.burst*b.interval - t
+		if delay > maxDelay {
+			return 0, 0, false
+		}
+		if atomic.CompareAndSwapInt64(&b.tat, old, newTat) {
+			if delay < 0 {
+				delay = 0
+			}
+			return delay, newTat, true
+		}
+	}
+}
+
+// Allow takes a token if it is available now.
+func (b *TokenBucket) Allow() bool {
+	return b.AllowN(1)
+}
+
+// AllowN takes @n tokens if they are available now.
+func (b *TokenBucket) AllowN(n int) bool {
+	_, _, ok := b.take(int64(n), 0)
+	return ok
+}
+
+// Reserve takes @n tokens ahead, and returns how long to wait before they can be used.
+// It returns false if @n exceeds the burst.
+func (b *TokenBucket) Reserve(n int) (time.Duration, bool) {
+	delay, _, ok := b.take(int64(n), int64(1<<62))
+	return time.Duration(delay), ok
+}
+
+// WaitCtx waits for @n tokens until @ctx is done. If @ctx is done first, the tokens
+// reserved are given back unless later tokens have been taken.
+func (b *TokenBucket) WaitCtx(ctx context.Context, n int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	maxDelay := int64(1 << 62)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxDelay = int64(time.Until(deadline))
+	}
+	delay, tat, ok := b.take(int64(n), maxDelay)
+	if !ok {
+		if int64(n) > b.burst {
+			return ErrExceedsBurst
+		}
+		return context.DeadlineExceeded
+	}
+
+	if err := wait(ctx, time.Duration(delay)); err != nil {
+		atomic.CompareAndSwapInt64(&b.tat, tat, tat-int64(n)*b.interval)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+type fakeClock struct {
+	now int64
+}
+
+func (c *fakeClock) Now() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.now))
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	atomic.AddInt64(&c.now, int64(d))
+}
+
+func newFakeClock() *fakeClock {
+	c := &fakeClock{now: time.Unix(1000, 0).UnixNano()}
+	gxtime.SetTimeSource(c.Now)
+	return c
+}
+
+func TestTokenBucket(t *testing.T) {
+	clock := newFakeClock()
+	defer gxtime.SetTimeSource(nil)
+
+	b := NewTokenBucket(10, 3)
+	assert.Equal(t, float64(10), b.Rate())
+	assert.Equal(t, 3, b.Burst())
+	assert.Equal(t, 3, b.Tokens())
+
+	assert.True(t, b.AllowN(2))
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+	assert.Equal(t, 0, b.Tokens())
+
+	clock.Advance(100 * time.Millisecond)
+	assert.Equal(t, 1, b.Tokens())
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+
+	// refills up to the burst only
+	clock.Advance(time.Hour)
+	assert.Equal(t, 3, b.Tokens())
+	assert.False(t, b.AllowN(4))
+
+	delay, ok := b.Reserve(3)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+	delay, ok = b.Reserve(2)
+	assert.True(t, ok)
+	assert.Equal(t, 200*time.Millisecond, delay)
+	_, ok = b.Reserve(4)
+	assert.False(t, ok)
+}
+
+func TestTokenBucketConcurrent(t *testing.T) {
+	newFakeClock()
+	defer gxtime.SetTimeSource(nil)
+
+	b := NewTokenBucket(1, 100)
+	var (
+		wg      sync.WaitGroup
+		allowed int32
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if b.Allow() {
+					atomic.AddInt32(&allowed, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(100), allowed)
+}
+
+func TestTokenBucketWaitCtx(t *testing.T) {
+	b := NewTokenBucket(100, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.Nil(t, b.WaitCtx(ctx, 1))
+	}
+	assert.True(t, time.Since(start) >= 25*time.Millisecond)
+	assert.Equal(t, ErrExceedsBurst, b.WaitCtx(ctx, 2))
+
+	// a wait beyond the deadline fails at once and takes nothing
+	short, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	b2 := NewTokenBucket(1, 1)
+	assert.True(t, b2.Allow())
+	assert.Equal(t, context.DeadlineExceeded, b2.WaitCtx(short, 1))
+
+	canceled, cancel2 := context.WithCancel(ctx)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel2()
+	}()
+	assert.Equal(t, context.Canceled, b2.WaitCtx(canceled, 1))
+	delay, _ := b2.Reserve(1)
+	assert.True(t, delay <= time.Second)
+}
+
+func TestTokenBucketWaitCtxGiveBack(t *testing.T) {
+	clock := newFakeClock()
+	defer gxtime.SetTimeSource(nil)
+	start := clock.Now().UnixNano()
+
+	b := NewTokenBucket(10, 1)
+	assert.True(t, b.Allow())
+	waitCanceled := func(later func()) {
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error)
+		tat := atomic.LoadInt64(&b.tat)
+		go func() { errs <- b.WaitCtx(ctx, 1) }()
+		for atomic.LoadInt64(&b.tat) == tat {
+			time.Sleep(time.Millisecond)
+		}
+		later()
+		cancel()
+		assert.Equal(t, context.Canceled, <-errs)
+	}
+
+	// the tokens at the tail are given back
+	waitCanceled(func() {})
+	assert.Equal(t, start+int64(100*time.Millisecond), atomic.LoadInt64(&b.tat))
+
+	// the tokens followed by later ones are not
+	waitCanceled(func() {
+		delay, ok := b.Reserve(1)
+		assert.True(t, ok)
+		assert.Equal(t, 200*time.Millisecond, delay)
+	})
+	delay, ok := b.Reserve(1)
+	assert.True(t, ok)
+	assert.Equal(t, 300*time.Millisecond, delay)
+}
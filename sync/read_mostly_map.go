@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+type pendingEntry[V any] struct {
+	value   V
+	deleted bool
+}
+
+// ReadMostlyMap is a map for ultra read heavy access. Reads are served lock free
+// from an immutable snapshot, while writes are buffered and promoted into a new
+// snapshot every interval by the default wheel. A read missing the snapshot falls
+// back to the write buffer, so a new key is visible at once, but an update or a
+// delete of a key in the snapshot is visible only after the next promotion.
+type ReadMostlyMap[K comparable, V any] struct {
+	snapshot atomic.Value // map[K]V, never modified once stored
+
+	lock    sync.Mutex
+	pending map[K]pendingEntry[V]
+	timer   *gxtime.Timer
+}
+
+// NewReadMostlyMap returns a ReadMostlyMap which promotes buffered writes every @interval.
+func NewReadMostlyMap[K comparable, V any](interval time.Duration) *ReadMostlyMap[K, V] {
+	m := &ReadMostlyMap[K, V]{
+		pending: make(map[K]pendingEntry[V]),
+	}
+	m.snapshot.Store(make(map[K]V))
+	m.timer = gxtime.GetDefaultWheel().AddTimer(func(interface{}) { m.Promote() }, interval, nil)
+
+	return m
+}
+
+func (m *ReadMostlyMap[K, V]) load() map[K]V {
+	return m.snapshot.Load().(map[K]V)
+}
+
+// Load returns the value of @key.
+func (m *ReadMostlyMap[K, V]) Load(key K) (V, bool) {
+	if v, ok := m.load()[key]; ok {
+		return v, true
+	}
+
+	m.lock.Lock()
+	e, ok := m.pending[key]
+	m.lock.Unlock()
+	if !ok || e.deleted {
+		var zero V
+		return zero, false
+	}
+
+	return e.value, true
+}
+
+// Store buffers @key with @value.
+func (m *ReadMostlyMap[K, V]) Store(key K, value V) {
+	m.lock.Lock()
+	m.pending[key] = pendingEntry[V]{value: value}
+	m.lock.Unlock()
+}
+
+// Delete buffers the delete of @key.
+func (m *ReadMostlyMap[K, V]) Delete(key K) {
+	m.lock.Lock()
+	m.pending[key] = pendingEntry[V]{deleted: true}
+	m.lock.Unlock()
+}
+
+// Len returns the size of the snapshot.
+func (m *ReadMostlyMap[K, V]) Len() int {
+	return len(m.load())
+}
+
+// Range calls @f for every pair of the snapshot until it returns false.
+func (m *ReadMostlyMap[K, V]) Range(f func(key K, value V) bool) {
+	for k, v := range m.load() {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+// Promote applies the buffered writes to a copy of the snapshot and publishes it.
+// It is invoked by the wheel every interval, and can be invoked directly to make
+// the writes visible at once.
+func (m *ReadMostlyMap[K, V]) Promote() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if len(m.pending) == 0 {
+		return
+	}
+
+	old := m.load()
+	snapshot := make(map[K]V, len(old)+len(m.pending))
+	for k, v := range old {
+		snapshot[k] = v
+	}
+	for k, e := range m.pending {
+		if e.deleted {
+			delete(snapshot, k)
+		} else {
+			snapshot[k] = e.value
+		}
+	}
+	m.snapshot.Store(snapshot)
+	m.pending = make(map[K]pendingEntry[V])
+}
+
+// Stop promotes the buffered writes and stops the periodic promotion. Later writes
+// are visible only after an explicit Promote.
+func (m *ReadMostlyMap[K, V]) Stop() {
+	m.timer.Stop()
+	m.Promote()
+}
@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadMostlyMap(t *testing.T) {
+	m := NewReadMostlyMap[string, int](time.Hour)
+	defer m.Stop()
+
+	m.Store("a", 1)
+	v, ok := m.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 0, m.Len())
+
+	m.Promote()
+	assert.Equal(t, 1, m.Len())
+
+	m.Store("a", 2)
+	m.Delete("b")
+	v, _ = m.Load("a")
+	assert.Equal(t, 1, v)
+	m.Promote()
+	v, _ = m.Load("a")
+	assert.Equal(t, 2, v)
+
+	m.Delete("a")
+	m.Promote()
+	_, ok = m.Load("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestReadMostlyMapPeriodicPromotion(t *testing.T) {
+	m := NewReadMostlyMap[string, int](20 * time.Millisecond)
+	defer m.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Store(strconv.Itoa(i*100+j), j)
+				m.Load(strconv.Itoa(j))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 400, m.Len())
+	n := 0
+	m.Range(func(string, int) bool {
+		n++
+		return n < 10
+	})
+	assert.Equal(t, 10, n)
+}
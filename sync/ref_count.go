@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"sync/atomic"
+)
+
+// RefCount shares a resource such as a connection or a mmap buffer among goroutines,
+// and finalizes it exactly once after the last reference is released. Once finalized,
+// it can not be acquired again. It is goroutine safe.
+type RefCount[T any] struct {
+	refs     int64
+	value    T
+	finalize func(T)
+}
+
+// NewRefCount returns a RefCount of @value holding one reference for the caller.
+// @finalize is invoked with @value once the references drop to zero, it can be nil.
+func NewRefCount[T any](value T, finalize func(T)) *RefCount[T] {
+	return &RefCount[T]{refs: 1, value: value, finalize: finalize}
+}
+
+// Acquire takes a reference and returns the resource. It returns false if the
+// resource has been finalized.
+func (r *RefCount[T]) Acquire() (T, bool) {
+	for {
+		refs := atomic.LoadInt64(&r.refs)
+		if refs <= 0 {
+			var zero T
+			return zero, false
+		}
+		if atomic.CompareAndSwapInt64(&r.refs, refs, refs+1) {
+			return r.value, true
+		}
+	}
+}
+
+// Release drops a reference, and finalizes the resource if it is the last one, in
+// which case it returns true. Releasing more than acquired panics.
+func (r *RefCount[T]) Release() bool {
+	refs := atomic.AddInt64(&r.refs, -1)
+	switch {
+	case refs > 0:
+		return false
+	case refs < 0:
+		panic("gxsync: RefCount released more than acquired")
+	}
+
+	if r.finalize != nil {
+		r.finalize(r.value)
+	}
+	return true
+}
+
+// Refs returns the number of references, which is 0 once finalized.
+func (r *RefCount[T]) Refs() int64 {
+	if refs := atomic.LoadInt64(&r.refs); refs > 0 {
+		return refs
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefCount(t *testing.T) {
+	var finalized []string
+	r := NewRefCount("conn", func(v string) { finalized = append(finalized, v) })
+	assert.Equal(t, int64(1), r.Refs())
+
+	v, ok := r.Acquire()
+	assert.True(t, ok)
+	assert.Equal(t, "conn", v)
+	assert.Equal(t, int64(2), r.Refs())
+
+	assert.False(t, r.Release())
+	assert.Empty(t, finalized)
+	assert.True(t, r.Release())
+	assert.Equal(t, []string{"conn"}, finalized)
+	assert.Equal(t, int64(0), r.Refs())
+
+	_, ok = r.Acquire()
+	assert.False(t, ok)
+	assert.Panics(t, func() { r.Release() })
+	assert.Equal(t, []string{"conn"}, finalized)
+}
+
+func TestRefCountConcurrent(t *testing.T) {
+	var finalized int32
+	r := NewRefCount(1, func(int) { atomic.AddInt32(&finalized, 1) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if _, ok := r.Acquire(); ok {
+					r.Release()
+				}
+			}
+		}()
+	}
+	r.Release()
+	wg.Wait()
+
+	assert.Equal(t, int32(1), finalized)
+	assert.Equal(t, int64(0), r.Refs())
+}
@@ -0,0 +1,210 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+// SagaStep is a step of a Saga. @Compensate undoes @Action after a later step fails,
+// and can be nil for a step which needs no undo. Both are retried by @Retry, whose
+// zero value means a single attempt.
+type SagaStep struct {
+	Name       string
+	Action     func(ctx context.Context) error
+	Compensate func(ctx context.Context) error
+	Retry      gxtime.RetryPolicy
+}
+
+// SagaSnapshot is the progress of a Saga, which can be persisted to resume the saga
+// by RestoreSaga after a crash.
+type SagaSnapshot struct {
+	Steps        []string `json:"steps"`                  // names of all steps, to check the restored saga
+	Completed    int      `json:"completed"`              // number of the completed actions
+	Compensating bool     `json:"compensating,omitempty"` // an action has failed
+	Compensated  int      `json:"compensated,omitempty"`  // number of the compensated steps, from the last completed one
+	Failed       string   `json:"failed,omitempty"`       // name of the failed step
+	Err          string   `json:"err,omitempty"`          // error of the failed step
+}
+
+// Done reports whether the saga has nothing left to do.
+func (s SagaSnapshot) Done() bool {
+	if s.Compensating {
+		return s.Compensated == s.Completed
+	}
+
+	return s.Completed == len(s.Steps)
+}
+
+// SagaError is returned by Saga.Run when a step fails. If @Compensated is true, all
+// completed steps are compensated, otherwise @CompensateErr tells why not and the
+// saga can be run again to continue compensating.
+type SagaError struct {
+	Step          string
+	Err           error
+	Compensated   bool
+	CompensateErr error
+}
+
+func (e *SagaError) Error() string {
+	if e.Compensated {
+		return fmt.Sprintf("saga step %s: %v, compensated", e.Step, e.Err)
+	}
+
+	return fmt.Sprintf("saga step %s: %v, compensation failed: %v", e.Step, e.Err, e.CompensateErr)
+}
+
+func (e *SagaError) Unwrap() error {
+	return e.Err
+}
+
+// Saga runs its steps in order. Once a step fails, it compensates the completed
+// steps in the reverse order. Run should not be invoked concurrently.
+type Saga struct {
+	lock   sync.Mutex
+	steps  []SagaStep
+	state  SagaSnapshot
+	hook   func(SagaSnapshot)
+	failed error
+}
+
+// NewSaga returns a Saga of @steps.
+func NewSaga(steps ...SagaStep) *Saga {
+	s := &Saga{steps: steps}
+	for _, step := range steps {
+		s.state.Steps = append(s.state.Steps, step.Name)
+	}
+
+	return s
+}
+
+// RestoreSaga returns a Saga of @steps resuming from @snapshot. It fails if the step
+// names differ from the ones of the snapshot.
+func RestoreSaga(snapshot SagaSnapshot, steps ...SagaStep) (*Saga, error) {
+	s := NewSaga(steps...)
+	if len(snapshot.Steps) != len(steps) {
+		return nil, errors.New("saga steps mismatch the snapshot")
+	}
+	for i, name := range snapshot.Steps {
+		if steps[i].Name != name {
+			return nil, fmt.Errorf("saga step %d is %s, but %s in the snapshot", i, steps[i].Name, name)
+		}
+	}
+	if snapshot.Completed < 0 || snapshot.Completed > len(steps) ||
+		snapshot.Compensated < 0 || snapshot.Compensated > snapshot.Completed {
+		return nil, errors.New("invalid saga snapshot")
+	}
+
+	s.state = snapshot
+	s.state.Steps = append([]string(nil), snapshot.Steps...)
+	if snapshot.Compensating {
+		s.failed = errors.New(snapshot.Err)
+	}
+
+	return s, nil
+}
+
+// OnSnapshot sets @hook, which is invoked with the new snapshot after every progress,
+// e.g. to persist it.
+func (s *Saga) OnSnapshot(hook func(SagaSnapshot)) {
+	s.lock.Lock()
+	s.hook = hook
+	s.lock.Unlock()
+}
+
+// Snapshot returns the current progress.
+func (s *Saga) Snapshot() SagaSnapshot {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.snapshot()
+}
+
+// snapshot should be invoked with the lock held.
+func (s *Saga) snapshot() SagaSnapshot {
+	snapshot := s.state
+	snapshot.Steps = append([]string(nil), s.state.Steps...)
+
+	return snapshot
+}
+
+// update applies @f to the state and notifies the hook.
+func (s *Saga) update(f func(state *SagaSnapshot)) {
+	s.lock.Lock()
+	f(&s.state)
+	hook, snapshot := s.hook, s.snapshot()
+	s.lock.Unlock()
+
+	if hook != nil {
+		hook(snapshot)
+	}
+}
+
+// Run runs the remaining steps, or continues compensating if a step has failed.
+// It returns nil once all steps complete, or a *SagaError.
+func (s *Saga) Run(ctx context.Context) error {
+	state := s.Snapshot()
+	if !state.Compensating {
+		for i := state.Completed; i < len(s.steps); i++ {
+			step := s.steps[i]
+			if err := runSagaStep(ctx, step.Retry, step.Action); err != nil {
+				s.failed = err
+				s.update(func(state *SagaSnapshot) {
+					state.Compensating = true
+					state.Failed = step.Name
+					state.Err = err.Error()
+				})
+				break
+			}
+			s.update(func(state *SagaSnapshot) { state.Completed++ })
+		}
+		if state = s.Snapshot(); !state.Compensating {
+			return nil
+		}
+	}
+
+	sagaErr := &SagaError{Step: state.Failed, Err: s.failed}
+	for i := state.Completed - state.Compensated - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if step.Compensate != nil {
+			if err := runSagaStep(ctx, step.Retry, step.Compensate); err != nil {
+				sagaErr.CompensateErr = fmt.Errorf("compensate %s: %w", step.Name, err)
+				return sagaErr
+			}
+		}
+		s.update(func(state *SagaSnapshot) { state.Compensated++ })
+	}
+	sagaErr.Compensated = true
+
+	return sagaErr
+}
+
+func runSagaStep(ctx context.Context, policy gxtime.RetryPolicy, f func(ctx context.Context) error) error {
+	if policy == (gxtime.RetryPolicy{}) {
+		return f(ctx)
+	}
+
+	return gxtime.Retry(ctx, policy, f)
+}
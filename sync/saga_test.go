@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+type sagaRecorder struct {
+	log []string
+}
+
+func (r *sagaRecorder) step(name string, fail *int) SagaStep {
+	return SagaStep{
+		Name: name,
+		Action: func(context.Context) error {
+			if fail != nil && *fail > 0 {
+				*fail--
+				r.log = append(r.log, "fail "+name)
+				return errors.New(name + " failed")
+			}
+			r.log = append(r.log, "do "+name)
+			return nil
+		},
+		Compensate: func(context.Context) error {
+			r.log = append(r.log, "undo "+name)
+			return nil
+		},
+		Retry: gxtime.FixedBackoff(time.Millisecond, 3),
+	}
+}
+
+func TestSaga(t *testing.T) {
+	r := &sagaRecorder{}
+	flaky := 2
+	s := NewSaga(r.step("a", nil), r.step("b", &flaky))
+	var snapshots []SagaSnapshot
+	s.OnSnapshot(func(snapshot SagaSnapshot) { snapshots = append(snapshots, snapshot) })
+
+	assert.Nil(t, s.Run(context.Background()))
+	assert.Equal(t, []string{"do a", "fail b", "fail b", "do b"}, r.log)
+	assert.Len(t, snapshots, 2)
+	assert.True(t, s.Snapshot().Done())
+}
+
+func TestSagaCompensation(t *testing.T) {
+	r := &sagaRecorder{}
+	broken := 10
+	s := NewSaga(r.step("a", nil), r.step("b", nil), r.step("c", &broken))
+
+	err := s.Run(context.Background())
+	var sagaErr *SagaError
+	assert.True(t, errors.As(err, &sagaErr))
+	assert.Equal(t, "c", sagaErr.Step)
+	assert.True(t, sagaErr.Compensated)
+	assert.Equal(t, []string{"do a", "do b", "fail c", "fail c", "fail c", "undo b", "undo a"}, r.log)
+
+	snapshot := s.Snapshot()
+	assert.True(t, snapshot.Compensating)
+	assert.True(t, snapshot.Done())
+	assert.Equal(t, 2, snapshot.Compensated)
+}
+
+func TestSagaResume(t *testing.T) {
+	r := &sagaRecorder{}
+	broken := 10
+	steps := []SagaStep{r.step("a", nil), r.step("b", nil), r.step("c", &broken)}
+	undoB := 1
+	steps[1].Compensate = func(context.Context) error {
+		if undoB > 0 {
+			undoB--
+			return errors.New("undo b failed")
+		}
+		r.log = append(r.log, "undo b")
+		return nil
+	}
+	steps[1].Retry = gxtime.RetryPolicy{MaxAttempts: 1}
+
+	s := NewSaga(steps...)
+	err := s.Run(context.Background())
+	var sagaErr *SagaError
+	assert.True(t, errors.As(err, &sagaErr))
+	assert.False(t, sagaErr.Compensated)
+	assert.NotNil(t, sagaErr.CompensateErr)
+
+	// persist and resume
+	data, err := json.Marshal(s.Snapshot())
+	assert.Nil(t, err)
+	var snapshot SagaSnapshot
+	assert.Nil(t, json.Unmarshal(data, &snapshot))
+	assert.False(t, snapshot.Done())
+
+	_, err = RestoreSaga(snapshot, steps[:2]...)
+	assert.NotNil(t, err)
+	resumed, err := RestoreSaga(snapshot, steps...)
+	assert.Nil(t, err)
+	err = resumed.Run(context.Background())
+	assert.True(t, errors.As(err, &sagaErr))
+	assert.True(t, sagaErr.Compensated)
+	assert.Equal(t, "c failed", sagaErr.Err.Error())
+	assert.Equal(t, []string{"do a", "do b", "fail c", "fail c", "fail c", "undo b", "undo a"}, r.log)
+}
@@ -0,0 +1,177 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type semaphoreWaiter struct {
+	n     int64
+	ready chan struct{} // closed once the weight is acquired
+}
+
+// Semaphore is a weighted semaphore, e.g. to limit the connections or the inflight
+// requests by their costs. The waiters are served in FIFO order, so that a heavy one
+// is not starved by the light ones coming after it, and they wait on channels instead
+// of spinning. It is goroutine safe.
+type Semaphore struct {
+	size    int64
+	lock    sync.Mutex
+	cur     int64      // acquired weight
+	waiters *list.List // of *semaphoreWaiter
+}
+
+// NewSemaphore returns a semaphore of the total weight @size.
+func NewSemaphore(size int64) *Semaphore {
+	if size <= 0 {
+		panic("@size <= 0")
+	}
+
+	return &Semaphore{size: size, waiters: list.New()}
+}
+
+// Size returns the total weight.
+func (s *Semaphore) Size() int64 {
+	return s.size
+}
+
+// Available returns the weight not acquired.
+func (s *Semaphore) Available() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.size - s.cur
+}
+
+// Acquire acquires the weight @n, blocking until it is available.
+func (s *Semaphore) Acquire(n int64) {
+	_ = s.acquire(nil, n, nil)
+}
+
+// AcquireCtx acquires the weight @n, blocking until it is available or @ctx is done,
+// in which case it returns ctx.Err() with nothing acquired.
+func (s *Semaphore) AcquireCtx(ctx context.Context, n int64) error {
+	return s.acquire(ctx.Done(), n, ctx.Err)
+}
+
+// TryAcquire acquires the weight @n only if it is available at once, and reports
+// whether it is acquired.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	return s.acquire(closedDone, n, nil) == nil
+}
+
+// TryAcquireFor acquires the weight @n, waiting at most @timeout, and reports whether
+// it is acquired.
+func (s *Semaphore) TryAcquireFor(n int64, timeout time.Duration) bool {
+	if timeout <= 0 {
+		return s.TryAcquire(n)
+	}
+
+	expired := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() { close(expired) })
+	defer timer.Stop()
+
+	return s.acquire(expired, n, nil) == nil
+}
+
+var errSemaphoreExpired = errors.New("gxsync: semaphore acquisition expired")
+
+// acquire waits for the weight @n until @expired is closed, a nil @expired means no
+// limit. @expiredErr returns the error then, a nil one means errSemaphoreExpired.
+func (s *Semaphore) acquire(expired <-chan struct{}, n int64, expiredErr func() error) error {
+	if n > s.size {
+		panic(fmt.Sprintf("gxsync: acquire %d over the semaphore size %d", n, s.size))
+	}
+
+	s.lock.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.lock.Unlock()
+		return nil
+	}
+	if expired == closedDone {
+		s.lock.Unlock()
+		return errSemaphoreExpired
+	}
+	w := &semaphoreWaiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.lock.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-expired:
+	}
+
+	s.lock.Lock()
+	select {
+	case <-w.ready:
+		// acquired meanwhile, so give it back
+		s.cur -= n
+		s.notify()
+	default:
+		front := s.waiters.Front() == elem
+		s.waiters.Remove(elem)
+		if front {
+			// the waiters behind may fit now
+			s.notify()
+		}
+	}
+	s.lock.Unlock()
+
+	if expiredErr == nil {
+		return errSemaphoreExpired
+	}
+	return expiredErr()
+}
+
+// notify wakes up the waiters in order while their weights fit. It should be invoked
+// with the lock held.
+func (s *Semaphore) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(*semaphoreWaiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
+
+// Release releases the weight @n.
+func (s *Semaphore) Release(n int64) {
+	s.lock.Lock()
+	s.cur -= n
+	if s.cur < 0 {
+		s.lock.Unlock()
+		panic("gxsync: semaphore released more than acquired")
+	}
+	s.notify()
+	s.lock.Unlock()
+}
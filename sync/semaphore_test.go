@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemaphore(t *testing.T) {
+	s := NewSemaphore(10)
+	assert.Equal(t, int64(10), s.Size())
+
+	s.Acquire(6)
+	assert.True(t, s.TryAcquire(4))
+	assert.False(t, s.TryAcquire(1))
+	assert.Equal(t, int64(0), s.Available())
+	assert.False(t, s.TryAcquireFor(1, 10*time.Millisecond))
+
+	acquired := make(chan struct{})
+	go func() {
+		s.Acquire(5)
+		close(acquired)
+	}()
+	s.Release(4)
+	select {
+	case <-acquired:
+		t.Fatal("acquired 5 of 4")
+	case <-time.After(10 * time.Millisecond):
+	}
+	s.Release(1)
+	<-acquired
+	assert.Equal(t, int64(0), s.Available())
+
+	s.Release(10)
+	assert.Equal(t, int64(10), s.Available())
+	assert.Panics(t, func() { s.Release(1) })
+	assert.Panics(t, func() { s.Acquire(11) })
+}
+
+func TestSemaphoreFIFO(t *testing.T) {
+	s := NewSemaphore(4)
+	s.Acquire(3)
+
+	heavy := make(chan struct{})
+	go func() {
+		s.Acquire(4)
+		close(heavy)
+	}()
+	assert.Eventually(t, func() bool {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return s.waiters.Len() == 1
+	}, time.Second, time.Millisecond)
+
+	// a light one does not overtake the heavy waiter
+	assert.False(t, s.TryAcquire(1))
+	s.Release(3)
+	<-heavy
+	s.Release(4)
+}
+
+func TestSemaphoreAcquireCtx(t *testing.T) {
+	s := NewSemaphore(4)
+	s.Acquire(3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, s.AcquireCtx(ctx, 4))
+	assert.Equal(t, int64(1), s.Available())
+
+	// the cancelled head waiter lets the ones behind it in
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	headErr := make(chan error, 1)
+	go func() { headErr <- s.AcquireCtx(ctx2, 4) }()
+	assert.Eventually(t, func() bool {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return s.waiters.Len() == 1
+	}, time.Second, time.Millisecond)
+
+	light := make(chan error, 1)
+	go func() { light <- s.AcquireCtx(context.Background(), 1) }()
+	assert.Eventually(t, func() bool {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return s.waiters.Len() == 2
+	}, time.Second, time.Millisecond)
+
+	cancel2()
+	assert.Equal(t, context.Canceled, <-headErr)
+	assert.Nil(t, <-light)
+	assert.Equal(t, int64(0), s.Available())
+}
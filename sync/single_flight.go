@@ -0,0 +1,176 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+// SingleFlightResult is the result of SingleFlight.DoChan.
+type SingleFlightResult[V any] struct {
+	Value  V
+	Err    error
+	Shared bool // whether the result comes from another call
+}
+
+type singleFlightCall[V any] struct {
+	done  chan struct{} // closed once the call finishes
+	value V
+	err   error
+	timer *gxtime.Timer // expires the cached result
+}
+
+// SingleFlight runs one call per key at a time, which the concurrent identical calls
+// join, e.g. for the registry lookups. Optionally, a result is cached for a short TTL
+// on the default wheel, so that the calls right after it share it too. It is
+// goroutine safe and its zero value is not usable.
+type SingleFlight[K comparable, V any] struct {
+	ttl           time.Duration
+	forgetOnError bool
+
+	lock  sync.Mutex
+	calls map[K]*singleFlightCall[V]
+}
+
+// NewSingleFlight returns a SingleFlight which caches the results for @ttl, a
+// non-positive @ttl means no caching. The errors are not cached if @forgetOnError,
+// so that a failed call is retried at once. A panic is never cached.
+func NewSingleFlight[K comparable, V any](ttl time.Duration, forgetOnError bool) *SingleFlight[K, V] {
+	return &SingleFlight[K, V]{
+		ttl:           ttl,
+		forgetOnError: forgetOnError,
+		calls:         make(map[K]*singleFlightCall[V]),
+	}
+}
+
+// Do runs @f for @key unless a call of @key is running or cached, in which case it
+// returns that result and @shared is true. If @f panics, the panic is passed on to the
+// caller of Do running it, and the calls joining it get a *PanicError.
+func (s *SingleFlight[K, V]) Do(key K, f func() (V, error)) (value V, err error, shared bool) {
+	c, leader := s.join(key)
+	if !leader {
+		<-c.done
+		return c.value, c.err, true
+	}
+
+	s.run(key, c, f)
+	return c.value, c.err, false
+}
+
+// DoChan is Do returning a channel of its result instead of waiting for it.
+func (s *SingleFlight[K, V]) DoChan(key K, f func() (V, error)) <-chan SingleFlightResult[V] {
+	ch := make(chan SingleFlightResult[V], 1)
+	c, leader := s.join(key)
+	go func() {
+		if leader {
+			defer func() {
+				if r := recover(); r != nil {
+					ch <- SingleFlightResult[V]{Value: c.value, Err: c.err}
+				}
+			}()
+			s.run(key, c, f)
+		} else {
+			<-c.done
+		}
+		ch <- SingleFlightResult[V]{Value: c.value, Err: c.err, Shared: !leader}
+	}()
+
+	return ch
+}
+
+// join returns the call of @key, and whether the caller should run it.
+func (s *SingleFlight[K, V]) join(key K) (*singleFlightCall[V], bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if c, ok := s.calls[key]; ok {
+		return c, false
+	}
+	c := &singleFlightCall[V]{done: make(chan struct{})}
+	s.calls[key] = c
+
+	return c, true
+}
+
+func (s *SingleFlight[K, V]) run(key K, c *singleFlightCall[V], f func() (V, error)) {
+	normal := false
+	defer func() {
+		if !normal {
+			r := recover()
+			c.err = &PanicError{Value: r, Stack: debug.Stack()}
+			s.finish(key, c, false)
+			panic(r)
+		}
+		s.finish(key, c, s.ttl > 0 && (c.err == nil || !s.forgetOnError))
+	}()
+
+	c.value, c.err = f()
+	normal = true
+}
+
+func (s *SingleFlight[K, V]) finish(key K, c *singleFlightCall[V], cache bool) {
+	s.lock.Lock()
+	if cache {
+		c.timer = gxtime.GetDefaultWheel().AddTimerInline(func(interface{}) {
+			s.expire(key, c)
+		}, s.ttl, 1, nil)
+	} else if s.calls[key] == c {
+		delete(s.calls, key)
+	}
+	s.lock.Unlock()
+
+	close(c.done)
+}
+
+func (s *SingleFlight[K, V]) expire(key K, c *singleFlightCall[V]) {
+	s.lock.Lock()
+	if s.calls[key] == c {
+		delete(s.calls, key)
+	}
+	s.lock.Unlock()
+}
+
+// Forget drops the call of @key, so that the next Do of @key runs again. The calls
+// joining a running call still get its result.
+func (s *SingleFlight[K, V]) Forget(key K) {
+	var timer *gxtime.Timer
+	s.lock.Lock()
+	if c, ok := s.calls[key]; ok {
+		timer = c.timer
+		delete(s.calls, key)
+	}
+	s.lock.Unlock()
+
+	if timer != nil {
+		timer.Stop()
+	}
+}
+
+// Len returns the number of the keys running or cached.
+func (s *SingleFlight[K, V]) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.calls)
+}
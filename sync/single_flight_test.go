@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleFlight(t *testing.T) {
+	s := NewSingleFlight[string, int](0, false)
+
+	var (
+		calls   int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+		shared  int32
+	)
+	f := func() (int, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return 42, nil
+	}
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, sh := s.Do("key", f)
+			assert.Nil(t, err)
+			assert.Equal(t, 42, v)
+			if sh {
+				atomic.AddInt32(&shared, 1)
+			}
+		}()
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond) // lets the others join
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+	assert.Equal(t, int32(4), shared)
+	// no caching without a TTL
+	assert.Equal(t, 0, s.Len())
+	s.Do("key", f)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestSingleFlightTTL(t *testing.T) {
+	var calls int32
+	errLookup := errors.New("lookup")
+	fail := func() (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return 0, errLookup
+	}
+
+	s := NewSingleFlight[string, int](time.Second, true)
+	v, err, shared := s.Do("a", func() (int, error) { return 1, nil })
+	assert.Equal(t, 1, v)
+	assert.Nil(t, err)
+	assert.False(t, shared)
+	v, _, shared = s.Do("a", func() (int, error) { return 2, nil })
+	assert.Equal(t, 1, v)
+	assert.True(t, shared)
+
+	s.Forget("a")
+	v, _, _ = s.Do("a", func() (int, error) { return 2, nil })
+	assert.Equal(t, 2, v)
+
+	_, err, _ = s.Do("b", fail)
+	assert.Equal(t, errLookup, err)
+	s.Do("b", fail)
+	assert.Equal(t, int32(2), calls)
+
+	// the errors are cached too without forgetOnError
+	s2 := NewSingleFlight[string, int](time.Second, false)
+	s2.Do("b", fail)
+	_, err, shared = s2.Do("b", fail)
+	assert.Equal(t, errLookup, err)
+	assert.True(t, shared)
+	assert.Equal(t, int32(3), calls)
+
+	s3 := NewSingleFlight[string, int](20*time.Millisecond, false)
+	s3.Do("c", func() (int, error) { return 1, nil })
+	assert.Equal(t, 1, s3.Len())
+	assert.Eventually(t, func() bool { return s3.Len() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestSingleFlightPanic(t *testing.T) {
+	s := NewSingleFlight[string, int](time.Second, false)
+	release := make(chan struct{})
+
+	joined := make(chan error, 1)
+	go func() {
+		defer func() { recover() }()
+		s.Do("p", func() (int, error) {
+			go func() {
+				_, err, _ := s.Do("p", func() (int, error) { return 0, nil })
+				joined <- err
+			}()
+			<-release
+			panic("boom")
+		})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	var perr *PanicError
+	assert.True(t, errors.As(<-joined, &perr))
+	assert.Equal(t, "boom", perr.Value)
+	assert.Eventually(t, func() bool { return s.Len() == 0 }, time.Second, time.Millisecond)
+
+	res := <-s.DoChan("q", func() (int, error) { panic("boom") })
+	assert.True(t, errors.As(res.Err, &perr))
+	res = <-s.DoChan("r", func() (int, error) { return 7, nil })
+	assert.Equal(t, 7, res.Value)
+	assert.False(t, res.Shared)
+	res = <-s.DoChan("r", func() (int, error) { return 8, nil })
+	assert.Equal(t, 7, res.Value)
+	assert.True(t, res.Shared)
+}
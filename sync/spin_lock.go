@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// spins of a SpinLock before yielding the processor
+	spinLockSpins = 16
+	// TryLock attempts of an AdaptiveMutex before parking on the mutex
+	adaptiveMutexSpins = 4
+)
+
+// SpinLock is a mutex which spins instead of parking the goroutine, which beats
+// sync.Mutex only for the critical sections of a few dozen nanoseconds, e.g. in the
+// buffer pools and the ID generators. It yields the processor by runtime.Gosched after
+// a few spins, so a long holder does not burn a whole processor. The zero value is an
+// unlocked SpinLock.
+type SpinLock struct {
+	state uint32
+}
+
+// Lock spins until the lock is acquired.
+func (l *SpinLock) Lock() {
+	for spins := 0; !l.TryLock(); spins++ {
+		if spins >= spinLockSpins {
+			runtime.Gosched()
+			spins = 0
+		}
+	}
+}
+
+// TryLock acquires the lock if it is free, and reports whether it is acquired.
+func (l *SpinLock) TryLock() bool {
+	return atomic.LoadUint32(&l.state) == 0 && atomic.CompareAndSwapUint32(&l.state, 0, 1)
+}
+
+// Unlock releases the lock. Unlocking an unlocked SpinLock panics.
+func (l *SpinLock) Unlock() {
+	if !atomic.CompareAndSwapUint32(&l.state, 1, 0) {
+		panic("gxsync: unlock of unlocked SpinLock")
+	}
+}
+
+// AdaptiveMutex is a sync.Mutex which retries TryLock a few times, yielding the
+// processor in between, before it parks on the mutex. It saves the park and wake-up
+// of the short critical sections, and keeps the fairness of sync.Mutex for the long
+// ones. The zero value is an unlocked AdaptiveMutex.
+type AdaptiveMutex struct {
+	mu sync.Mutex
+}
+
+// Lock acquires the lock.
+func (m *AdaptiveMutex) Lock() {
+	for i := 0; i < adaptiveMutexSpins; i++ {
+		if m.mu.TryLock() {
+			return
+		}
+		runtime.Gosched()
+	}
+	m.mu.Lock()
+}
+
+// TryLock acquires the lock if it is free, and reports whether it is acquired.
+func (m *AdaptiveMutex) TryLock() bool {
+	return m.mu.TryLock()
+}
+
+// Unlock releases the lock.
+func (m *AdaptiveMutex) Unlock() {
+	m.mu.Unlock()
+}
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func testLocker(t *testing.T, l sync.Locker) {
+	var (
+		wg sync.WaitGroup
+		n  int
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				l.Lock()
+				n++
+				l.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 8000, n)
+}
+
+func TestSpinLock(t *testing.T) {
+	var l SpinLock
+	assert.True(t, l.TryLock())
+	assert.False(t, l.TryLock())
+	l.Unlock()
+	assert.Panics(t, func() { l.Unlock() })
+
+	testLocker(t, &l)
+}
+
+func TestAdaptiveMutex(t *testing.T) {
+	var m AdaptiveMutex
+	assert.True(t, m.TryLock())
+	assert.False(t, m.TryLock())
+	m.Unlock()
+
+	testLocker(t, &m)
+}
+
+func benchmarkLocker(b *testing.B, l sync.Locker) {
+	var n int
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Lock()
+			n++
+			l.Unlock()
+		}
+	})
+}
+
+func BenchmarkSpinLock(b *testing.B) {
+	benchmarkLocker(b, &SpinLock{})
+}
+
+func BenchmarkAdaptiveMutex(b *testing.B) {
+	benchmarkLocker(b, &AdaptiveMutex{})
+}
+
+func BenchmarkMutex(b *testing.B) {
+	benchmarkLocker(b, &sync.Mutex{})
+}
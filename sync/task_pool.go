@@ -151,6 +151,7 @@ func (p *TaskPool) run(id int, q chan task) error {
 
 // return false when the pool is stop
 func (p *TaskPool) AddTask(t task) (ok bool) {
+	t = p.wrap(t)
 	idx := atomic.AddUint32(&p.idx, 1)
 	id := idx % uint32(p.tQNumber)
 
@@ -164,6 +165,7 @@ func (p *TaskPool) AddTask(t task) (ok bool) {
 }
 
 func (p *TaskPool) AddTaskAlways(t task) {
+	t = p.wrap(t)
 	id := atomic.AddUint32(&p.idx, 1) % uint32(p.tQNumber)
 
 	select {
@@ -176,6 +178,7 @@ func (p *TaskPool) AddTaskAlways(t task) {
 
 // do it immediately when no idle queue
 func (p *TaskPool) AddTaskBalance(t task) {
+	t = p.wrap(t)
 	length := len(p.qArray)
 
 	// try len/2 times to lookup idle queue
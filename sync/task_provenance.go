@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const (
+	provenanceDepth = 16
+)
+
+// taskProvenance is the call stack of the submitter of a task
+type taskProvenance []uintptr
+
+// callerProvenance records the call stack of the submitter which invokes an
+// AddTask* method, which in turn invokes wrap.
+func callerProvenance() taskProvenance {
+	pcs := make([]uintptr, provenanceDepth)
+	return taskProvenance(pcs[:runtime.Callers(4, pcs)])
+}
+
+func (tp taskProvenance) String() string {
+	if len(tp) == 0 {
+		return "unknown"
+	}
+
+	var (
+		b      strings.Builder
+		frames = runtime.CallersFrames(tp)
+	)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+
+	return b.String()
+}
+
+// wrap decorates @t with provenance and slow task reports if they are enabled.
+func (p *TaskPool) wrap(t task) task {
+	if !p.provenance && p.slowThreshold <= 0 {
+		return t
+	}
+
+	var tp taskProvenance
+	if p.provenance {
+		tp = callerProvenance()
+	}
+
+	return func() {
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Fprintf(os.Stderr, "%s goroutine panic: %v\n%s\nsubmitted by:\n%s\n",
+					time.Now(), r, string(debug.Stack()), tp)
+				return
+			}
+
+			if cost := time.Since(start); p.slowThreshold > 0 && cost > p.slowThreshold {
+				log.Printf("gost/TaskPool slow task costs %s, submitted by:\n%s", cost, tp)
+			}
+		}()
+		t()
+	}
+}
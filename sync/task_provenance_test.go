@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func addTask() taskProvenance {
+	return wrap()
+}
+
+func wrap() taskProvenance {
+	return callerProvenance()
+}
+
+func TestTaskProvenance(t *testing.T) {
+	tp := addTask()
+	assert.True(t, strings.HasPrefix(tp.String(), "github.com/dubbogo/gost/sync.TestTaskProvenance"), tp.String())
+	assert.Equal(t, "unknown", taskProvenance(nil).String())
+}
+
+func TestTaskPoolProvenance(t *testing.T) {
+	// the slow task reports go to the log, and the panic ones to stderr
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	r, w, err := os.Pipe()
+	assert.Nil(t, err)
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+	printed := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		printed <- string(b)
+	}()
+
+	tp := NewTaskPool(
+		WithTaskPoolTaskPoolSize(2),
+		WithTaskPoolTaskProvenance(),
+		WithTaskPoolSlowTaskThreshold(time.Millisecond),
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	tp.AddTask(func() {
+		defer wg.Done()
+		time.Sleep(2 * time.Millisecond)
+	})
+	tp.AddTaskAlways(func() {
+		defer wg.Done()
+		panic("provenance")
+	})
+	tp.AddTaskBalance(func() {
+		wg.Done()
+	})
+	wg.Wait()
+	// the reports are written once the tasks return, and Close waits for them
+	tp.Close()
+	os.Stderr = stderr
+	w.Close()
+
+	submitter := "github.com/dubbogo/gost/sync.TestTaskPoolProvenance\n\t"
+	slow := logged.String()
+	assert.Contains(t, slow, "slow task costs")
+	assert.Contains(t, slow, "submitted by:\n"+submitter)
+	assert.Contains(t, slow, "task_provenance_test.go:")
+
+	panicked := <-printed
+	assert.Contains(t, panicked, "goroutine panic: provenance")
+	assert.Contains(t, panicked, "submitted by:\n"+submitter)
+	assert.Contains(t, panicked, "task_provenance_test.go:")
+}
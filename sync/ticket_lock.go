@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+type ticketWaiter struct {
+	ready   chan struct{}
+	granted bool // protected by the lock of TicketLock
+}
+
+// TicketLock is a fair mutex, which is acquired strictly in the order of the Lock
+// calls. Unlike sync.Mutex, a new comer never barges in ahead of the waiters, so it
+// suits the critical sections whose order matters, such as sequence number assignment.
+// The lock is handed off to the next waiter directly on Unlock. The zero value is an
+// unlocked TicketLock.
+type TicketLock struct {
+	lock    sync.Mutex
+	locked  bool
+	waiters list.List // *ticketWaiter, the earliest first
+}
+
+// Lock waits for the lock.
+func (l *TicketLock) Lock() {
+	_ = l.LockContext(context.Background())
+}
+
+// LockContext waits for the lock until @ctx is done, in which case it gives up its
+// turn and returns the error of @ctx.
+func (l *TicketLock) LockContext(ctx context.Context) error {
+	l.lock.Lock()
+	if !l.locked && l.waiters.Len() == 0 {
+		l.locked = true
+		l.lock.Unlock()
+		return nil
+	}
+	w := &ticketWaiter{ready: make(chan struct{})}
+	e := l.waiters.PushBack(w)
+	l.lock.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.lock.Lock()
+	if w.granted {
+		// handed off meanwhile, then pass it on
+		l.unlock()
+	} else {
+		l.waiters.Remove(e)
+	}
+	l.lock.Unlock()
+
+	return ctx.Err()
+}
+
+// TryLock acquires the lock if it is free and nobody is waiting.
+func (l *TicketLock) TryLock() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.locked || l.waiters.Len() > 0 {
+		return false
+	}
+	l.locked = true
+
+	return true
+}
+
+// Unlock releases the lock to the earliest waiter. It panics if the lock is not locked.
+func (l *TicketLock) Unlock() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if !l.locked {
+		panic("gxsync: unlock of unlocked TicketLock")
+	}
+	l.unlock()
+}
+
+// unlock should be invoked with l.lock held.
+func (l *TicketLock) unlock() {
+	e := l.waiters.Front()
+	if e == nil {
+		l.locked = false
+		return
+	}
+
+	w := l.waiters.Remove(e).(*ticketWaiter)
+	w.granted = true
+	close(w.ready)
+}
+
+// Waiters returns the number of the goroutines waiting for the lock.
+func (l *TicketLock) Waiters() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.waiters.Len()
+}
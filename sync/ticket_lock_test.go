@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func waitTicketWaiters(l *TicketLock, n int) {
+	for l.Waiters() != n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTicketLockFIFO(t *testing.T) {
+	var (
+		l     TicketLock
+		order = make(chan int, 10)
+		done  = make(chan struct{})
+	)
+	l.Lock()
+	assert.False(t, l.TryLock())
+
+	for i := 0; i < 10; i++ {
+		go func(i int) {
+			l.Lock()
+			order <- i
+			l.Unlock()
+			done <- struct{}{}
+		}(i)
+		// queue the goroutines in order
+		waitTicketWaiters(&l, i+1)
+	}
+	l.Unlock()
+
+	for i := 0; i < 10; i++ {
+		<-done
+		assert.Equal(t, i, <-order)
+	}
+	assert.True(t, l.TryLock())
+	l.Unlock()
+	assert.Panics(t, func() { l.Unlock() })
+}
+
+func TestTicketLockContext(t *testing.T) {
+	var l TicketLock
+	assert.Nil(t, l.LockContext(context.Background()))
+
+	got := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() { errs <- l.LockContext(ctx) }()
+	waitTicketWaiters(&l, 1)
+	go func() {
+		l.Lock()
+		close(got)
+	}()
+	waitTicketWaiters(&l, 2)
+
+	// the canceled waiter gives up its turn to the next one
+	cancel()
+	assert.Equal(t, context.Canceled, <-errs)
+	assert.Equal(t, 1, l.Waiters())
+	l.Unlock()
+	<-got
+	l.Unlock()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	l.Lock()
+	assert.Equal(t, context.DeadlineExceeded, l.LockContext(ctx))
+	l.Unlock()
+	assert.True(t, l.TryLock())
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Value is a typed atomic.Value for the read-mostly data such as the config snapshots
+// and the route tables. The values are held by pointers swapped atomically, so there
+// is no lock nor interface{} boxing, and a loaded value must be treated as immutable.
+// The zero value holds the zero T.
+type Value[T any] struct {
+	p unsafe.Pointer // *T, nil for the zero T
+}
+
+// NewValue returns a Value holding @v.
+func NewValue[T any](v T) *Value[T] {
+	return &Value[T]{p: unsafe.Pointer(&v)}
+}
+
+// Load returns the current value.
+func (v *Value[T]) Load() T {
+	if p := (*T)(atomic.LoadPointer(&v.p)); p != nil {
+		return *p
+	}
+
+	var zero T
+	return zero
+}
+
+// Store replaces the value with @x.
+func (v *Value[T]) Store(x T) {
+	atomic.StorePointer(&v.p, unsafe.Pointer(&x))
+}
+
+// Swap replaces the value with @x, and returns the old one.
+func (v *Value[T]) Swap(x T) T {
+	if p := (*T)(atomic.SwapPointer(&v.p, unsafe.Pointer(&x))); p != nil {
+		return *p
+	}
+
+	var zero T
+	return zero
+}
+
+// Update replaces the value with @f of it by CAS, and returns the new value. @f is
+// retried on the value stored by a concurrent update, so it should be a pure function
+// which builds a new value instead of modifying the old one in place.
+func (v *Value[T]) Update(f func(old T) T) T {
+	for {
+		p := atomic.LoadPointer(&v.p)
+		var old T
+		if p != nil {
+			old = *(*T)(p)
+		}
+		x := f(old)
+		if atomic.CompareAndSwapPointer(&v.p, p, unsafe.Pointer(&x)) {
+			return x
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type routeTable struct {
+	version int
+	routes  map[string]string
+}
+
+func TestValue(t *testing.T) {
+	var v Value[string]
+	assert.Equal(t, "", v.Load())
+	v.Store("a")
+	assert.Equal(t, "a", v.Load())
+	assert.Equal(t, "a", v.Swap("b"))
+	assert.Equal(t, "b", v.Load())
+	assert.Equal(t, "bc", v.Update(func(old string) string { return old + "c" }))
+
+	var empty Value[int]
+	assert.Equal(t, 0, empty.Swap(1))
+	assert.Equal(t, 1, NewValue(1).Load())
+}
+
+func TestValueUpdateConcurrent(t *testing.T) {
+	v := NewValue(routeTable{routes: map[string]string{}})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				v.Update(func(old routeTable) routeTable {
+					routes := make(map[string]string, len(old.routes)+1)
+					for k, r := range old.routes {
+						routes[k] = r
+					}
+					routes["svc"] = "host"
+					return routeTable{version: old.version + 1, routes: routes}
+				})
+				_ = v.Load().routes["svc"]
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 800, v.Load().version)
+}
@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNegativeWaitGroupCounter is returned by an Add or Done of WaitGroupTimeout which
+// would make its counter negative.
+var ErrNegativeWaitGroupCounter = errors.New("gxsync: negative WaitGroup counter")
+
+// WaitGroupTimeout is a WaitGroup whose waits can be bounded by a timeout or a context,
+// e.g. on the shutdown paths, and whose counter refuses to go negative with an error
+// instead of a panic. Its zero value is ready to use.
+type WaitGroupTimeout struct {
+	lock  sync.Mutex
+	count int
+	zero  chan struct{} // closed once the counter drops to 0, nil while it is 0
+}
+
+// Add adds @delta to the counter. It returns ErrNegativeWaitGroupCounter leaving the
+// counter unchanged if the counter would be negative.
+func (wg *WaitGroupTimeout) Add(delta int) error {
+	wg.lock.Lock()
+	defer wg.lock.Unlock()
+
+	count := wg.count + delta
+	if count < 0 {
+		return ErrNegativeWaitGroupCounter
+	}
+	switch {
+	case wg.count == 0 && count > 0:
+		wg.zero = make(chan struct{})
+	case wg.count > 0 && count == 0:
+		close(wg.zero)
+		wg.zero = nil
+	}
+	wg.count = count
+
+	return nil
+}
+
+// Done decrements the counter by one.
+func (wg *WaitGroupTimeout) Done() error {
+	return wg.Add(-1)
+}
+
+// Count returns the counter.
+func (wg *WaitGroupTimeout) Count() int {
+	wg.lock.Lock()
+	defer wg.lock.Unlock()
+
+	return wg.count
+}
+
+func (wg *WaitGroupTimeout) zeroChan() <-chan struct{} {
+	wg.lock.Lock()
+	defer wg.lock.Unlock()
+
+	if wg.zero == nil {
+		return closedDone
+	}
+	return wg.zero
+}
+
+// Wait blocks until the counter is 0.
+func (wg *WaitGroupTimeout) Wait() {
+	<-wg.zeroChan()
+}
+
+// WaitTimeout waits at most @timeout for the counter to be 0, and reports whether it is.
+func (wg *WaitGroupTimeout) WaitTimeout(timeout time.Duration) bool {
+	zero := wg.zeroChan()
+	if timeout <= 0 {
+		select {
+		case <-zero:
+			return true
+		default:
+			return false
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-zero:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// WaitCtx waits for the counter to be 0 until @ctx is done, in which case it returns
+// ctx.Err().
+func (wg *WaitGroupTimeout) WaitCtx(ctx context.Context) error {
+	select {
+	case <-wg.zeroChan():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
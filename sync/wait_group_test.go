@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxsync
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitGroupTimeout(t *testing.T) {
+	var wg WaitGroupTimeout
+	assert.True(t, wg.WaitTimeout(0))
+	wg.Wait()
+
+	assert.Nil(t, wg.Add(2))
+	assert.Equal(t, 2, wg.Count())
+	assert.False(t, wg.WaitTimeout(10*time.Millisecond))
+	assert.False(t, wg.WaitTimeout(0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, wg.WaitCtx(ctx))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		wg.Done()
+		wg.Done()
+	}()
+	assert.True(t, wg.WaitTimeout(time.Second))
+	assert.Nil(t, wg.WaitCtx(context.Background()))
+
+	// the counter can be reused
+	assert.Nil(t, wg.Add(1))
+	assert.False(t, wg.WaitTimeout(0))
+	assert.Nil(t, wg.Done())
+	wg.Wait()
+}
+
+func TestWaitGroupTimeoutNegative(t *testing.T) {
+	var wg WaitGroupTimeout
+	assert.Equal(t, ErrNegativeWaitGroupCounter, wg.Done())
+	assert.Nil(t, wg.Add(1))
+	assert.Equal(t, ErrNegativeWaitGroupCounter, wg.Add(-2))
+	assert.Equal(t, 1, wg.Count())
+	assert.Nil(t, wg.Done())
+	assert.Equal(t, ErrNegativeWaitGroupCounter, wg.Done())
+	assert.Equal(t, 0, wg.Count())
+}
@@ -0,0 +1,190 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"time"
+)
+
+const (
+	defaultTuneWindow = time.Second
+
+	// the timers expired per tick, below which the ticks are mostly wasted
+	sparseTimerDensity = 0.05
+	// the timers expired per tick, above which a finer tick pays off
+	denseTimerDensity = 0.5
+)
+
+// TuneDecision is a tick interval change made by the auto-tune mode.
+type TuneDecision struct {
+	At      time.Time
+	From    time.Duration
+	To      time.Duration
+	Reason  string
+	Density float64       // timers expired per tick in the evaluated window
+	Latency time.Duration // average fire latency in the evaluated window
+}
+
+// autoTune samples the wheel stats of a window and adjusts the span within
+// [min, max]. Its fields are protected by the wheel lock.
+type autoTune struct {
+	min, max time.Duration
+	window   time.Duration // wall time between two evaluations
+	start    time.Time
+	ticks    int
+	fired    uint64
+	latency  time.Duration
+	dropped  uint64
+}
+
+func newAutoTune(min, max time.Duration, stats WheelStats, now time.Time) *autoTune {
+	if min <= 0 || max < min {
+		panic("illegal auto-tune bounds")
+	}
+
+	a := &autoTune{min: min, max: max, window: defaultTuneWindow}
+	a.reset(stats, now)
+
+	return a
+}
+
+func (a *autoTune) reset(stats WheelStats, now time.Time) {
+	a.start = now
+	a.ticks = 0
+	a.fired = stats.Fired
+	a.latency = stats.FireLatency
+	a.dropped = stats.DroppedTicks
+}
+
+// decide returns the span for the next window and why, which is @span itself if it
+// should be kept. The span doubles if the wheel loop drops ticks or lags more than
+// two spans behind, as a finer tick only costs more CPU then, or if the timers are
+// too sparse to deserve the ticks. It halves if the timers are dense and fire in time.
+func (a *autoTune) decide(span time.Duration, stats WheelStats) (time.Duration, string, float64, time.Duration) {
+	var (
+		fired   = stats.Fired - a.fired
+		dropped = stats.DroppedTicks - a.dropped
+		density = float64(fired) / float64(a.ticks)
+		latency time.Duration
+	)
+	if fired > 0 {
+		latency = (stats.FireLatency - a.latency) / time.Duration(fired)
+	}
+
+	next, reason := span, ""
+	switch {
+	case dropped > uint64(a.ticks/10):
+		next, reason = span*2, "dropped ticks"
+	case latency > span*2:
+		next, reason = span*2, "fire latency over two spans"
+	case density < sparseTimerDensity:
+		next, reason = span*2, "sparse timers"
+	case density >= denseTimerDensity:
+		next, reason = span/2, "dense timers"
+	}
+
+	if next > a.max {
+		next = a.max
+	}
+	if next < a.min {
+		next = a.min
+	}
+	if next != span && reason == "" {
+		reason = "span out of bounds"
+	}
+
+	return next, reason, density, latency
+}
+
+// tune evaluates the last window at the end of a tick, and re-slots the wheel if the
+// span changes. It should be invoked with the wheel lock held.
+func (w *Wheel) tune() {
+	a := w.autoTune
+	if a == nil {
+		return
+	}
+	if a.ticks++; w.now.Sub(a.start) < a.window {
+		return
+	}
+
+	span, reason, density, latency := a.decide(w.span, w.stats)
+	if span != w.span {
+		decision := TuneDecision{
+			At:      w.now,
+			From:    w.span,
+			To:      span,
+			Reason:  reason,
+			Density: density,
+			Latency: latency,
+		}
+		w.respan(span)
+		w.stats.Retunes++
+		w.stats.LastTune = decision
+		w.logger.Info("gost/time wheel tick %v -> %v: %s, density %.3f, latency %v",
+			decision.From, decision.To, reason, density, latency)
+	}
+	a.reset(w.stats, w.now)
+}
+
+// respan changes the span to @span and re-slots every pending timer by its expected
+// fire time. The pending After channels are turned into one-shot inline timers, as the
+// slots of a coarser ring can not hold them apart. It should be invoked with the wheel
+// lock held, right after a tick.
+func (w *Wheel) respan(span time.Duration) {
+	var pending []*Timer
+	for k := 0; k < len(w.ring); k++ {
+		pos := (w.index + k) % len(w.ring)
+		if c := w.ring[pos]; c != nil {
+			w.ring[pos] = nil
+			t := w.newTimer(closeAfter, w.span, 1, c)
+			t.inline = true
+			t.expect = w.last.Add(time.Duration(k+1) * w.span)
+			pending = append(pending, t)
+			w.stats.Timers++
+		}
+		for _, t := range w.timers[pos] {
+			if !t.stop {
+				pending = append(pending, t)
+			}
+		}
+		w.timers[pos] = nil
+	}
+
+	w.span = span
+	w.period = span * time.Duration(len(w.ring))
+	w.stats.Span = span
+	if w.check != nil {
+		w.check.rescan(span)
+	}
+	if w.ticker != nil {
+		w.ticker.Reset(span)
+		// drop a tick of the old interval buffered meanwhile, which would fire the re-slotted timers early
+		select {
+		case <-w.ticker.C:
+		default:
+		}
+	}
+
+	for _, t := range pending {
+		w.schedule(t, w.now, t.expect.Sub(w.now), 0)
+	}
+}
+
+func closeAfter(arg interface{}) {
+	close(arg.(chan struct{}))
+}
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoTuneDecide(t *testing.T) {
+	span := 10 * time.Millisecond
+	a := newAutoTune(5*time.Millisecond, 40*time.Millisecond, WheelStats{}, time.Now())
+
+	cases := []struct {
+		stats  WheelStats
+		span   time.Duration
+		reason string
+	}{
+		{WheelStats{Fired: 0}, 20 * time.Millisecond, "sparse timers"},
+		{WheelStats{Fired: 100, FireLatency: 100 * 5 * time.Millisecond}, 5 * time.Millisecond, "dense timers"},
+		{WheelStats{Fired: 100, FireLatency: 100 * 30 * time.Millisecond}, 20 * time.Millisecond, "fire latency over two spans"},
+		{WheelStats{Fired: 100, DroppedTicks: 50}, 20 * time.Millisecond, "dropped ticks"},
+		{WheelStats{Fired: 20}, span, ""},
+	}
+	for _, c := range cases {
+		a.reset(WheelStats{}, time.Now())
+		a.ticks = 100
+		next, reason, _, _ := a.decide(span, c.stats)
+		assert.Equal(t, c.span, next, c.reason)
+		assert.Equal(t, c.reason, reason)
+	}
+
+	// clamped into the bounds
+	a.ticks = 100
+	next, _, _, _ := a.decide(40*time.Millisecond, WheelStats{})
+	assert.Equal(t, 40*time.Millisecond, next)
+	next, reason, _, _ := a.decide(80*time.Millisecond, WheelStats{Fired: 20})
+	assert.Equal(t, 40*time.Millisecond, next)
+	assert.Equal(t, "span out of bounds", reason)
+
+	assert.Panics(t, func() { newAutoTune(0, time.Second, WheelStats{}, time.Now()) })
+	assert.Panics(t, func() { newAutoTune(time.Second, time.Millisecond, WheelStats{}, time.Now()) })
+}
+
+func newTunedWheel(span, min, max time.Duration) *Wheel {
+	w := NewWheel(span, 100, WithWheelAutoTune(min, max), WithWheelLogger(NopLogger{}))
+	w.Lock()
+	w.autoTune.window = 100 * time.Millisecond
+	w.Unlock()
+
+	return w
+}
+
+func TestWheelAutoTuneSparse(t *testing.T) {
+	wheel := newTunedWheel(TimeMillisecondDuration(10), TimeMillisecondDuration(5), TimeMillisecondDuration(40))
+	defer wheel.Stop()
+
+	var (
+		cnt   int64
+		start = time.Now()
+		after = wheel.After(TimeMillisecondDuration(600))
+	)
+	wheel.AddTimerTimes(func(interface{}) {
+		atomic.AddInt64(&cnt, 1)
+	}, TimeMillisecondDuration(300), 2, nil)
+
+	for i := 0; i < 100 && wheel.Span() != TimeMillisecondDuration(40); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats := wheel.Stats()
+	assert.Equal(t, TimeMillisecondDuration(40), stats.Span)
+	assert.Equal(t, TimeMillisecondDuration(4000), wheel.Period())
+	assert.True(t, stats.Retunes >= 2)
+	assert.Equal(t, "sparse timers", stats.LastTune.Reason)
+	assert.Equal(t, TimeMillisecondDuration(40), stats.LastTune.To)
+
+	// the re-slotted channel and timer neither fire early nor get lost
+	<-after
+	assert.True(t, time.Since(start) >= TimeMillisecondDuration(590), time.Since(start))
+	for i := 0; i < 100 && atomic.LoadInt64(&cnt) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(2), atomic.LoadInt64(&cnt))
+}
+
+func TestWheelAutoTuneDense(t *testing.T) {
+	wheel := newTunedWheel(TimeMillisecondDuration(20), TimeMillisecondDuration(5), TimeMillisecondDuration(40))
+	defer wheel.Stop()
+
+	for i := 0; i < 20; i++ {
+		timer := wheel.AddTimer(func(interface{}) {}, TimeMillisecondDuration(5), nil)
+		defer timer.Stop()
+	}
+
+	for i := 0; i < 100 && wheel.Span() != TimeMillisecondDuration(5); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats := wheel.Stats()
+	assert.Equal(t, TimeMillisecondDuration(5), stats.Span)
+	assert.Equal(t, "dense timers", stats.LastTune.Reason)
+	assert.Equal(t, 20, stats.Timers)
+}
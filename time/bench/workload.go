@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxbench provides a reproducible timer workload generator, so that
+// different gxtime wheel configurations can be compared on the same hardware.
+package gxbench
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+const (
+	maxSamples = 1 << 16
+)
+
+// Workload describes the timer load put on a wheel.
+type Workload struct {
+	Timers   int           // number of concurrent timers
+	Period   time.Duration // base period of every timer
+	Jitter   time.Duration // every timer's period is Period + rand[0, Jitter)
+	Churn    float64       // probability of adding and stopping an extra timer on each fire
+	Duration time.Duration // how long the workload runs
+	Seed     int64         // seed of the workload, the same seed generates the same periods
+}
+
+// Report is the result of a workload.
+type Report struct {
+	Fired   int64 // fired callbacks
+	Churned int64 // timers added and stopped before expiring
+	Samples int   // number of sampled fire errors
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+type runner struct {
+	wheel *gxtime.Wheel
+	load  Workload
+
+	// lock guards the fields below, so that no fire still running writes them
+	// once the runner is stopped
+	lock    sync.Mutex
+	stopped bool
+	fired   int64
+	churned int64
+	sampled int64
+	samples []time.Duration
+	rand    *rand.Rand
+}
+
+type timerArg struct {
+	period time.Duration
+	expect time.Time
+}
+
+// Run puts @load on @wheel and reports the distribution of the fire errors,
+// which are the differences between the actual and the expected fire time.
+func Run(wheel *gxtime.Wheel, load Workload) Report {
+	if load.Period <= 0 {
+		panic("@Period <= 0")
+	}
+
+	r := &runner{
+		wheel:   wheel,
+		load:    load,
+		samples: make([]time.Duration, maxSamples),
+		rand:    rand.New(rand.NewSource(load.Seed)),
+	}
+
+	for i := 0; i < load.Timers; i++ {
+		period := load.Period
+		if load.Jitter > 0 {
+			period += time.Duration(r.rand.Int63n(int64(load.Jitter)))
+		}
+		r.add(period)
+	}
+
+	time.Sleep(load.Duration)
+	r.lock.Lock()
+	r.stopped = true
+	r.lock.Unlock()
+
+	return r.report()
+}
+
+func (r *runner) add(period time.Duration) {
+	r.wheel.AddTimerTimes(r.fire, period, 1, &timerArg{period: period, expect: time.Now().Add(period)})
+}
+
+func (r *runner) fire(arg interface{}) {
+	a := arg.(*timerArg)
+	delay := time.Since(a.expect)
+
+	r.lock.Lock()
+	if r.stopped {
+		r.lock.Unlock()
+		return
+	}
+	r.fired++
+	if r.sampled < maxSamples {
+		r.samples[r.sampled] = delay
+	}
+	r.sampled++
+	churn := r.load.Churn > 0 && r.rand.Float64() < r.load.Churn
+	if churn {
+		r.churned++
+	}
+	r.lock.Unlock()
+
+	if churn {
+		r.wheel.AddTimerTimes(r.fire, a.period, 1, a).Stop()
+	}
+	r.add(a.period)
+}
+
+// report should be invoked once the runner is stopped.
+func (r *runner) report() Report {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	n := r.sampled
+	if n > maxSamples {
+		n = maxSamples
+	}
+
+	samples := make([]time.Duration, n)
+	copy(samples, r.samples[:n])
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	rp := Report{
+		Fired:   r.fired,
+		Churned: r.churned,
+		Samples: len(samples),
+	}
+	if len(samples) > 0 {
+		rp.P50 = percentile(samples, 0.50)
+		rp.P90 = percentile(samples, 0.90)
+		rp.P99 = percentile(samples, 0.99)
+		rp.Max = samples[len(samples)-1]
+	}
+
+	return rp
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	return sorted[int(float64(len(sorted)-1)*p)]
+}
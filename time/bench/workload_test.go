@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbench
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+func TestRun(t *testing.T) {
+	wheel := gxtime.NewWheel(10*time.Millisecond, 100)
+	defer wheel.Stop()
+
+	rp := Run(wheel, Workload{
+		Timers:   100,
+		Period:   20 * time.Millisecond,
+		Jitter:   10 * time.Millisecond,
+		Churn:    0.5,
+		Duration: 300 * time.Millisecond,
+		Seed:     1,
+	})
+	t.Logf("report: %+v", rp)
+	assert.True(t, rp.Fired > 100)
+	assert.True(t, rp.Churned > 0)
+	assert.True(t, rp.P50 <= rp.P99)
+	assert.True(t, rp.P99 <= rp.Max)
+}
+
+func BenchmarkWheelAddStop(b *testing.B) {
+	for _, n := range []int{1000, 100000, 1000000} {
+		b.Run(fmt.Sprintf("timers-%d", n), func(b *testing.B) {
+			if n > 100000 && testing.Short() {
+				b.Skip("skip 1M timers in short mode")
+			}
+
+			wheel := gxtime.NewWheel(10*time.Millisecond, 1000)
+			defer wheel.Stop()
+			for i := 0; i < n; i++ {
+				wheel.AddTimerTimes(func(interface{}) {}, time.Hour, 1, nil)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				wheel.AddTimerTimes(func(interface{}) {}, time.Minute, 1, nil).Stop()
+			}
+		})
+	}
+}
+
+func BenchmarkWorkload(b *testing.B) {
+	for _, churn := range []float64{0, 0.1, 1} {
+		b.Run(fmt.Sprintf("churn-%v", churn), func(b *testing.B) {
+			wheel := gxtime.NewWheel(10*time.Millisecond, 1000)
+			defer wheel.Stop()
+
+			var rp Report
+			for i := 0; i < b.N; i++ {
+				rp = Run(wheel, Workload{
+					Timers:   10000,
+					Period:   50 * time.Millisecond,
+					Jitter:   50 * time.Millisecond,
+					Churn:    churn,
+					Duration: 500 * time.Millisecond,
+					Seed:     int64(i),
+				})
+			}
+			b.ReportMetric(float64(rp.P50.Microseconds()), "p50-us")
+			b.ReportMetric(float64(rp.P99.Microseconds()), "p99-us")
+			b.ReportMetric(float64(rp.Fired), "fired")
+		})
+	}
+}
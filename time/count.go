@@ -33,14 +33,14 @@ type CountWatch struct {
 func (w *CountWatch) Start() {
 	var t time.Time
 	if t.Equal(w.start) {
-		w.start = time.Now()
+		w.start = Now()
 	}
 }
 
 func (w *CountWatch) Reset() {
-	w.start = time.Now()
+	w.start = Now()
 }
 
 func (w *CountWatch) Count() int64 {
-	return time.Since(w.start).Nanoseconds()
+	return Now().Sub(w.start).Nanoseconds()
 }
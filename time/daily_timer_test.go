@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextDailyTime(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	after := time.Date(2020, 3, 7, 9, 0, 0, 0, loc)
+	next := nextDailyTime(after, 8, 0, 0, loc)
+	assert.Equal(t, time.Date(2020, 3, 8, 8, 0, 0, 0, loc), next)
+	// 2020-03-08 is 23 hours long in New York
+	assert.Equal(t, 23*time.Hour, next.Sub(time.Date(2020, 3, 7, 8, 0, 0, 0, loc)))
+
+	next = nextDailyTime(next, 8, 0, 0, loc)
+	assert.Equal(t, time.Date(2020, 3, 9, 8, 0, 0, 0, loc), next)
+
+	// 2020-11-01 is 25 hours long in New York
+	next = nextDailyTime(time.Date(2020, 10, 31, 8, 0, 0, 0, loc), 8, 0, 0, loc)
+	assert.Equal(t, 25*time.Hour, next.Sub(time.Date(2020, 10, 31, 8, 0, 0, 0, loc)))
+
+	next = nextDailyTime(time.Date(2020, 10, 31, 7, 0, 0, 0, loc), 8, 0, 0, loc)
+	assert.Equal(t, time.Date(2020, 10, 31, 8, 0, 0, 0, loc), next)
+}
+
+func TestWheelAddDailyTimer(t *testing.T) {
+	loc := time.UTC
+	SetTimeSource(func() time.Time {
+		return time.Date(2020, 12, 10, 7, 59, 59, int(950*time.Millisecond), loc)
+	})
+	defer SetTimeSource(nil)
+
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	var cnt int64
+	timer := wheel.AddDailyTimer(8, 0, 0, loc, func(interface{}) {
+		atomic.AddInt64(&cnt, 1)
+	})
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&cnt))
+	assert.Equal(t, 1, wheel.Stats().Timers)
+	assert.True(t, timer.Stop())
+
+	assert.Panics(t, func() {
+		wheel.AddDailyTimer(24, 0, 0, loc, func(interface{}) {})
+	})
+}
@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync"
+	"time"
+)
+
+// Deadline is a per-request deadline driven by the default wheel, so that no runtime
+// timer is allocated for it. The channel returned by Done is closed once the deadline
+// passes. It is goroutine safe.
+type Deadline struct {
+	lock     sync.Mutex
+	deadline time.Time
+	done     chan struct{}
+	timer    *Timer
+	expired  bool
+	stopped  bool
+}
+
+// NewDeadline returns a Deadline which expires at @t. It is expired at once if @t has passed.
+func NewDeadline(t time.Time) *Deadline {
+	d := &Deadline{
+		deadline: t,
+		done:     make(chan struct{}),
+	}
+	d.lock.Lock()
+	d.arm(Now())
+	d.lock.Unlock()
+
+	return d
+}
+
+// arm should be invoked with the lock held.
+func (d *Deadline) arm(now time.Time) {
+	if !now.Before(d.deadline) {
+		d.expired = true
+		close(d.done)
+		return
+	}
+	d.timer = GetDefaultWheel().AddTimerInline(d.check, d.deadline.Sub(now), 1, nil)
+}
+
+// check runs in the wheel goroutine. An extended deadline is armed again here instead
+// of moving the timer in Extend.
+func (d *Deadline) check(interface{}) {
+	d.lock.Lock()
+	if !d.stopped && !d.expired {
+		d.arm(Now())
+	}
+	d.lock.Unlock()
+}
+
+// Done returns a channel which is closed when the deadline passes.
+func (d *Deadline) Done() <-chan struct{} {
+	return d.done
+}
+
+// Extend postpones the deadline by @delta. It returns false if the deadline has
+// expired or been stopped.
+func (d *Deadline) Extend(delta time.Duration) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.expired || d.stopped {
+		return false
+	}
+	d.deadline = d.deadline.Add(delta)
+
+	return true
+}
+
+// Expired reports whether the deadline has passed. It may report true up to one
+// wheel span before Done is closed.
+func (d *Deadline) Expired() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.expired || !Now().Before(d.deadline)
+}
+
+// Time returns the current deadline.
+func (d *Deadline) Time() time.Time {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.deadline
+}
+
+// Stop releases the wheel timer of a deadline which is no longer needed, e.g. when
+// the request finishes in time. Done will never be closed after it.
+func (d *Deadline) Stop() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.stopped = true
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
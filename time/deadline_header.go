@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+const (
+	// DeadlineRemainingKey is the attachment key of the remaining time of the deadline, in microseconds.
+	DeadlineRemainingKey = "gost-deadline-remaining"
+	// DeadlineSentKey is the attachment key of the sender wall clock, in unix nanoseconds,
+	// which gives the receiver a clock skew hint.
+	DeadlineSentKey = "gost-deadline-sent"
+)
+
+// EncodeDeadline puts the remaining time until @deadline into @attachments. The remaining
+// time instead of the absolute deadline is carried, so that the deadline survives hops
+// between processes whose clocks are skewed. A passed deadline is encoded as zero remaining.
+func EncodeDeadline(attachments map[string]string, deadline time.Time) {
+	now := Now()
+	remaining := deadline.Sub(now)
+	if remaining < 0 {
+		remaining = 0
+	}
+	attachments[DeadlineRemainingKey] = strconv.FormatInt(int64(remaining/time.Microsecond), 10)
+	attachments[DeadlineSentKey] = strconv.FormatInt(now.UnixNano(), 10)
+}
+
+// EncodeContextDeadline puts the deadline of @ctx into @attachments. It returns false
+// if @ctx has no deadline.
+func EncodeContextDeadline(ctx context.Context, attachments map[string]string) bool {
+	deadline, ok := ctx.Deadline()
+	if ok {
+		EncodeDeadline(attachments, deadline)
+	}
+
+	return ok
+}
+
+// DecodeDeadline returns the local deadline carried by @attachments. @skew is the local
+// clock minus the sender clock, which includes the transport latency; it is zero if the
+// sender clock is absent. @ok is false if there is no valid remaining time.
+func DecodeDeadline(attachments map[string]string) (deadline time.Time, skew time.Duration, ok bool) {
+	remaining, err := strconv.ParseInt(attachments[DeadlineRemainingKey], 10, 64)
+	if err != nil || remaining < 0 {
+		return time.Time{}, 0, false
+	}
+
+	now := Now()
+	if sent, err := strconv.ParseInt(attachments[DeadlineSentKey], 10, 64); err == nil {
+		skew = now.Sub(time.Unix(0, sent))
+	}
+
+	return now.Add(time.Duration(remaining) * time.Microsecond), skew, true
+}
+
+// DecodeContextDeadline returns a child context of @ctx with the deadline carried by
+// @attachments. It returns @ctx itself and a no-op cancel if there is no deadline.
+func DecodeContextDeadline(ctx context.Context, attachments map[string]string) (context.Context, context.CancelFunc) {
+	deadline, _, ok := DecodeDeadline(attachments)
+	if !ok {
+		return ctx, func() {}
+	}
+
+	return context.WithDeadline(ctx, deadline)
+}
@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadlineHeader(t *testing.T) {
+	attachments := make(map[string]string)
+	deadline := Now().Add(time.Second)
+	EncodeDeadline(attachments, deadline)
+
+	got, skew, ok := DecodeDeadline(attachments)
+	assert.True(t, ok)
+	assert.True(t, got.Sub(deadline) < 100*time.Millisecond)
+	assert.True(t, skew >= 0 && skew < 100*time.Millisecond)
+
+	// a sender clock one hour ahead
+	attachments[DeadlineSentKey] = strconv.FormatInt(Now().Add(time.Hour).UnixNano(), 10)
+	got, skew, ok = DecodeDeadline(attachments)
+	assert.True(t, ok)
+	assert.True(t, got.Sub(deadline) < 100*time.Millisecond)
+	assert.True(t, skew < -59*time.Minute)
+
+	EncodeDeadline(attachments, Now().Add(-time.Second))
+	assert.Equal(t, "0", attachments[DeadlineRemainingKey])
+
+	_, _, ok = DecodeDeadline(map[string]string{DeadlineRemainingKey: "x"})
+	assert.False(t, ok)
+}
+
+func TestContextDeadlineHeader(t *testing.T) {
+	attachments := make(map[string]string)
+	assert.False(t, EncodeContextDeadline(context.Background(), attachments))
+	ctx, cancel := DecodeContextDeadline(context.Background(), attachments)
+	cancel()
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+
+	parent, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.True(t, EncodeContextDeadline(parent, attachments))
+	ctx, cancel = DecodeContextDeadline(context.Background(), attachments)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	parentDeadline, _ := parent.Deadline()
+	assert.True(t, deadline.Sub(parentDeadline) < 100*time.Millisecond)
+}
@@ -0,0 +1,226 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// FirstOf waits for the earliest of @deadlines on a single timer of the default wheel,
+// and returns its index, or -1 if @ctx is done before it. A passed deadline returns at once.
+func FirstOf(ctx context.Context, deadlines ...time.Time) int {
+	first := -1
+	for i, d := range deadlines {
+		if first < 0 || d.Before(deadlines[first]) {
+			first = i
+		}
+	}
+	if first < 0 {
+		<-ctx.Done()
+		return -1
+	}
+
+	wait := deadlines[first].Sub(Now())
+	if wait <= 0 {
+		return first
+	}
+
+	expired := make(chan struct{})
+	t := GetDefaultWheel().AddTimerInline(func(interface{}) { close(expired) }, wait, 1, nil)
+	select {
+	case <-expired:
+		return first
+	case <-ctx.Done():
+		t.Stop()
+		return -1
+	}
+}
+
+type deadlineEntry[K comparable] struct {
+	key      K
+	deadline time.Time
+	index    int
+}
+
+type deadlineHeap[K comparable] []*deadlineEntry[K]
+
+func (h deadlineHeap[K]) Len() int           { return len(h) }
+func (h deadlineHeap[K]) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+
+func (h deadlineHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *deadlineHeap[K]) Push(x interface{}) {
+	e := x.(*deadlineEntry[K])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *deadlineHeap[K]) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+
+	return e
+}
+
+// DeadlineSet holds many keyed deadlines, such as session expirations, and waits for
+// whichever comes first on a single wheel timer armed for the earliest one, instead of
+// a timer per deadline. It is goroutine safe.
+type DeadlineSet[K comparable] struct {
+	lock    sync.Mutex
+	wheel   *Wheel
+	heap    deadlineHeap[K]
+	entries map[K]*deadlineEntry[K]
+	timer   *Timer
+	armed   time.Time     // the deadline the timer is armed for
+	wake    chan struct{} // closed when the timer fires
+}
+
+// NewDeadlineSet returns an empty set driven by @wheel. A nil @wheel means the default wheel.
+func NewDeadlineSet[K comparable](wheel *Wheel) *DeadlineSet[K] {
+	if wheel == nil {
+		wheel = GetDefaultWheel()
+	}
+
+	return &DeadlineSet[K]{
+		wheel:   wheel,
+		entries: make(map[K]*deadlineEntry[K]),
+		wake:    make(chan struct{}),
+	}
+}
+
+// Set sets the deadline of @key, adding @key if it is absent.
+func (s *DeadlineSet[K]) Set(key K, deadline time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		e.deadline = deadline
+		heap.Fix(&s.heap, e.index)
+	} else {
+		e = &deadlineEntry[K]{key: key, deadline: deadline}
+		s.entries[key] = e
+		heap.Push(&s.heap, e)
+	}
+	if s.timer != nil && deadline.Before(s.armed) {
+		// an earlier deadline, wake the waiters up to arm it
+		s.fire()
+	}
+}
+
+// Remove removes @key, and returns false if it is absent.
+func (s *DeadlineSet[K]) Remove(key K) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e, ok := s.entries[key]
+	if ok {
+		heap.Remove(&s.heap, e.index)
+		delete(s.entries, key)
+	}
+
+	return ok
+}
+
+// Len returns the number of deadlines.
+func (s *DeadlineSet[K]) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.heap)
+}
+
+// Next returns the earliest deadline and its key.
+func (s *DeadlineSet[K]) Next() (K, time.Time, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.heap) == 0 {
+		var zero K
+		return zero, time.Time{}, false
+	}
+
+	return s.heap[0].key, s.heap[0].deadline, true
+}
+
+// WaitNext waits for the earliest deadline to pass, removes it and returns its key,
+// or returns the error of @ctx if it is done first. The deadlines set or removed while
+// waiting are taken into account. The precision is the wheel span.
+func (s *DeadlineSet[K]) WaitNext(ctx context.Context) (K, time.Time, error) {
+	for {
+		s.lock.Lock()
+		if len(s.heap) > 0 {
+			e := s.heap[0]
+			wait := e.deadline.Sub(Now())
+			if wait <= 0 {
+				heap.Pop(&s.heap)
+				delete(s.entries, e.key)
+				s.lock.Unlock()
+				return e.key, e.deadline, nil
+			}
+			s.arm(e.deadline, wait)
+		}
+		wake := s.wake
+		s.lock.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			var zero K
+			return zero, time.Time{}, ctx.Err()
+		}
+	}
+}
+
+// arm makes sure the timer fires at @deadline, which is @wait later. It should be
+// invoked with the lock held.
+func (s *DeadlineSet[K]) arm(deadline time.Time, wait time.Duration) {
+	if s.timer != nil {
+		if !deadline.Before(s.armed) {
+			return
+		}
+		s.timer.Stop()
+	}
+	s.armed = deadline
+	s.timer = s.wheel.AddTimerInline(s.expire, wait, 1, nil)
+}
+
+// expire runs in the wheel goroutine.
+func (s *DeadlineSet[K]) expire(interface{}) {
+	s.lock.Lock()
+	s.fire()
+	s.lock.Unlock()
+}
+
+// fire drops the timer and wakes the waiters up. It should be invoked with the lock held.
+func (s *DeadlineSet[K]) fire() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	close(s.wake)
+	s.wake = make(chan struct{})
+}
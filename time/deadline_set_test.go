@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstOf(t *testing.T) {
+	now := time.Now()
+	start := time.Now()
+	idx := FirstOf(context.Background(), now.Add(200*time.Millisecond), now.Add(50*time.Millisecond), now.Add(time.Second))
+	assert.Equal(t, 1, idx)
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+
+	assert.Equal(t, 0, FirstOf(context.Background(), now.Add(-time.Second), now))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, -1, FirstOf(ctx, now.Add(time.Second)))
+	assert.Equal(t, -1, FirstOf(ctx))
+}
+
+func TestDeadlineSet(t *testing.T) {
+	s := NewDeadlineSet[string](nil)
+	now := time.Now()
+	s.Set("b", now.Add(100*time.Millisecond))
+	s.Set("a", now.Add(50*time.Millisecond))
+	s.Set("c", now.Add(150*time.Millisecond))
+	assert.Equal(t, 3, s.Len())
+
+	key, _, ok := s.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "a", key)
+
+	var order []string
+	for i := 0; i < 3; i++ {
+		key, deadline, err := s.WaitNext(context.Background())
+		assert.Nil(t, err)
+		assert.False(t, time.Now().Add(5*time.Millisecond).Before(deadline))
+		order = append(order, key)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, order)
+	assert.Equal(t, 0, s.Len())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err := s.WaitNext(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestDeadlineSetChanges(t *testing.T) {
+	s := NewDeadlineSet[int](nil)
+	now := time.Now()
+	s.Set(1, now.Add(time.Second))
+	s.Set(2, now.Add(2*time.Second))
+
+	type result struct {
+		key int
+		at  time.Duration
+	}
+	results := make(chan result, 2)
+	go func() {
+		for i := 0; i < 2; i++ {
+			key, _, err := s.WaitNext(context.Background())
+			assert.Nil(t, err)
+			results <- result{key, time.Since(now)}
+		}
+	}()
+
+	// an earlier deadline set while waiting re-arms the timer
+	time.Sleep(20 * time.Millisecond)
+	s.Set(2, now.Add(60*time.Millisecond))
+	r := <-results
+	assert.Equal(t, 2, r.key)
+	assert.True(t, r.at < 500*time.Millisecond, r.at)
+
+	// a removed deadline never comes
+	assert.True(t, s.Remove(1))
+	assert.False(t, s.Remove(1))
+	s.Set(3, time.Now().Add(30*time.Millisecond))
+	r = <-results
+	assert.Equal(t, 3, r.key)
+	_, _, ok := s.Next()
+	assert.False(t, ok)
+}
@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadline(t *testing.T) {
+	start := time.Now()
+	d := NewDeadline(Now().Add(50 * time.Millisecond))
+	assert.False(t, d.Expired())
+	assert.True(t, d.Extend(50*time.Millisecond))
+
+	select {
+	case <-d.Done():
+	case <-time.After(time.Second):
+		t.Fatal("deadline is not done")
+	}
+	assert.True(t, time.Since(start) >= 90*time.Millisecond)
+	assert.True(t, d.Expired())
+	assert.False(t, d.Extend(time.Second))
+}
+
+func TestDeadlinePassed(t *testing.T) {
+	d := NewDeadline(Now().Add(-time.Second))
+	assert.True(t, d.Expired())
+	select {
+	case <-d.Done():
+	default:
+		t.Fatal("passed deadline is not done")
+	}
+}
+
+func TestDeadlineStop(t *testing.T) {
+	d := NewDeadline(Now().Add(30 * time.Millisecond))
+	d.Stop()
+	assert.False(t, d.Extend(time.Second))
+	select {
+	case <-d.Done():
+		t.Fatal("stopped deadline is done")
+	case <-time.After(60 * time.Millisecond):
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"time"
+)
+
+const (
+	// DriftHistorySize is the number of samples kept by the drift history of a wheel.
+	DriftHistorySize = 60
+	// driftSampleInterval is the wheel time between two samples of the drift history.
+	driftSampleInterval = time.Second
+)
+
+// DriftSample is the drift of a wheel at a moment.
+type DriftSample struct {
+	At    time.Time     // Now() of the sample
+	Drift time.Duration // Now() minus wheel time
+}
+
+// drift tracks the wheel time, which starts at Now() the wheel is created and advances
+// a span on every tick, so it falls behind Now(), the wall time by default, whenever
+// the ticks are dropped or handled late. Its fields are protected by the wheel lock.
+type drift struct {
+	wheelTime  time.Time
+	nextSample time.Time
+	history    [DriftHistorySize]DriftSample
+	samples    int // samples recorded so far, the newest one is history[(samples - 1) % size]
+}
+
+func newDrift(start time.Time) drift {
+	return drift{wheelTime: start, nextSample: start.Add(driftSampleInterval)}
+}
+
+// advanceDrift moves the wheel time forward by a tick of @span, and samples the drift
+// once per second of the wheel time. It should be invoked with the wheel lock held.
+func (w *Wheel) advanceDrift(span time.Duration) {
+	d := &w.drift
+	d.wheelTime = d.wheelTime.Add(span)
+	if d.wheelTime.Before(d.nextSample) {
+		return
+	}
+
+	now := Now()
+	d.history[d.samples%DriftHistorySize] = DriftSample{At: now, Drift: now.Sub(d.wheelTime)}
+	d.samples++
+	d.nextSample = d.nextSample.Add(driftSampleInterval)
+}
+
+// Drift returns how far the wheel time lags behind Now(). The wheel time moves
+// a span per tick handled, so a drift growing beyond a few spans means the wheel loop
+// is starved, e.g. by the GC or by the callbacks run on it, and its timers fire late.
+func (w *Wheel) Drift() time.Duration {
+	w.RLock()
+	wheelTime := w.drift.wheelTime
+	w.RUnlock()
+
+	return Now().Sub(wheelTime)
+}
+
+// DriftHistory returns the drift sampled once per second of the wheel time, the oldest
+// first, for DriftHistorySize seconds at most.
+func (w *Wheel) DriftHistory() []DriftSample {
+	w.RLock()
+	defer w.RUnlock()
+
+	d := &w.drift
+	n := d.samples
+	if n > DriftHistorySize {
+		n = DriftHistorySize
+	}
+	history := make([]DriftSample, 0, n)
+	for i := d.samples - n; i < d.samples; i++ {
+		history = append(history, d.history[i%DriftHistorySize])
+	}
+
+	return history
+}
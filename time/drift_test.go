@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWheelDrift(t *testing.T) {
+	w := NewWheel(time.Millisecond, 100)
+	defer w.Stop()
+
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, w.Drift() < 20*time.Millisecond)
+
+	// a starved wheel loop drops the ticks
+	w.Lock()
+	time.Sleep(100 * time.Millisecond)
+	w.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	assert.True(t, w.Drift() >= 50*time.Millisecond)
+}
+
+func TestWheelDriftHistory(t *testing.T) {
+	w := NewWheel(time.Second, 10)
+	w.Stop()
+	assert.Empty(t, w.DriftHistory())
+
+	start := w.drift.wheelTime
+	w.Lock()
+	for i := 0; i < DriftHistorySize+5; i++ {
+		w.advanceDrift(time.Second)
+	}
+	w.Unlock()
+
+	history := w.DriftHistory()
+	assert.Len(t, history, DriftHistorySize)
+	for i := 1; i < len(history); i++ {
+		assert.False(t, history[i].At.Before(history[i-1].At))
+		assert.True(t, history[i].Drift < history[i-1].Drift)
+	}
+	// the wheel time runs ahead here, a second per sample
+	wheelTime := start.Add((DriftHistorySize + 5) * time.Second)
+	assert.Equal(t, history[len(history)-1].At.Sub(wheelTime), history[len(history)-1].Drift)
+}
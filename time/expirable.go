@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync"
+	"time"
+)
+
+// Expirable holds a value with a deadline. If an eviction callback is given, it
+// is invoked by the default wheel once the value expires without being refreshed.
+// It is goroutine safe.
+type Expirable[T any] struct {
+	lock     sync.Mutex
+	value    T
+	deadline time.Time
+	timer    *Timer
+	onEvict  func(T)
+	stopped  bool
+}
+
+// NewExpirable returns an Expirable of @value which expires after @ttl.
+// @onEvict can be nil.
+func NewExpirable[T any](value T, ttl time.Duration, onEvict func(T)) *Expirable[T] {
+	e := &Expirable[T]{
+		value:   value,
+		onEvict: onEvict,
+	}
+	e.lock.Lock()
+	e.reset(ttl)
+	e.lock.Unlock()
+
+	return e
+}
+
+// reset should be invoked with the lock held.
+func (e *Expirable[T]) reset(ttl time.Duration) {
+	e.deadline = Now().Add(ttl)
+	if e.onEvict == nil || e.stopped {
+		return
+	}
+
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	if ttl <= 0 {
+		ttl = time.Nanosecond
+	}
+	e.timer = GetDefaultWheel().AddTimerTimes(e.evict, ttl, 1, nil)
+}
+
+func (e *Expirable[T]) evict(interface{}) {
+	e.lock.Lock()
+	if e.stopped {
+		e.lock.Unlock()
+		return
+	}
+	if now := Now(); now.Before(e.deadline) {
+		// the wheel fires at most one span earlier
+		e.timer = GetDefaultWheel().AddTimerTimes(e.evict, e.deadline.Sub(now), 1, nil)
+		e.lock.Unlock()
+		return
+	}
+	e.stopped = true
+	value := e.value
+	e.lock.Unlock()
+
+	e.onEvict(value)
+}
+
+// Get returns the value and whether it is still alive.
+func (e *Expirable[T]) Get() (T, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.value, Now().Before(e.deadline)
+}
+
+// Value returns the value whether it has expired or not.
+func (e *Expirable[T]) Value() T {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.value
+}
+
+// Deadline returns the expiring time of the value.
+func (e *Expirable[T]) Deadline() time.Time {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.deadline
+}
+
+// IsExpired reports whether the deadline has passed.
+func (e *Expirable[T]) IsExpired() bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return !Now().Before(e.deadline)
+}
+
+// Refresh extends the deadline to @d from now. It does nothing if the value
+// has been evicted or the Expirable has been stopped.
+func (e *Expirable[T]) Refresh(d time.Duration) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.stopped {
+		return
+	}
+	e.reset(d)
+}
+
+// Stop cancels the eviction callback. The deadline is kept.
+func (e *Expirable[T]) Stop() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.stopped = true
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+}
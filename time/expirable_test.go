@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpirable(t *testing.T) {
+	e := NewExpirable("lease", 50*time.Millisecond, nil)
+	v, ok := e.Get()
+	assert.True(t, ok)
+	assert.Equal(t, "lease", v)
+	assert.False(t, e.IsExpired())
+
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, e.IsExpired())
+	_, ok = e.Get()
+	assert.False(t, ok)
+	assert.Equal(t, "lease", e.Value())
+
+	e.Refresh(time.Second)
+	assert.False(t, e.IsExpired())
+	assert.True(t, e.Deadline().After(Now()))
+}
+
+func TestExpirableEviction(t *testing.T) {
+	evicted := make(chan int, 2)
+	e := NewExpirable(1, 50*time.Millisecond, func(v int) {
+		evicted <- v
+	})
+
+	time.Sleep(30 * time.Millisecond)
+	e.Refresh(80 * time.Millisecond)
+	select {
+	case <-evicted:
+		t.Fatal("refreshed value is evicted")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	select {
+	case v := <-evicted:
+		assert.Equal(t, 1, v)
+	case <-time.After(time.Second):
+		t.Fatal("value is not evicted")
+	}
+
+	e = NewExpirable(2, 30*time.Millisecond, func(v int) {
+		evicted <- v
+	})
+	e.Stop()
+	select {
+	case <-evicted:
+		t.Fatal("stopped value is evicted")
+	case <-time.After(80 * time.Millisecond):
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync/atomic"
+)
+
+// CommonLogLayout is the time layout of the NCSA common log format.
+const CommonLogLayout = "02/Jan/2006:15:04:05 -0700"
+
+// TimeFormatter caches the current time formatted by a layout of second precision,
+// e.g. time.RFC3339 or CommonLogLayout. The string is refreshed by the wheel tick
+// once the second changes, so loggers and access logs can read it on every line
+// without invoking time.Format.
+type TimeFormatter struct {
+	layout string
+	unix   int64        // second of @value, only accessed in the wheel goroutine
+	value  atomic.Value // string
+	timer  *Timer
+}
+
+// NewTimeFormatter returns a TimeFormatter of @layout refreshed by @wheel. A nil @wheel
+// means the default wheel.
+func NewTimeFormatter(wheel *Wheel, layout string) *TimeFormatter {
+	if wheel == nil {
+		wheel = GetDefaultWheel()
+	}
+
+	f := &TimeFormatter{layout: layout}
+	now := Now()
+	f.unix = now.Unix()
+	f.value.Store(now.Format(layout))
+
+	f.timer = wheel.AddTimerInline(f.refresh, wheel.Span(), -1, nil)
+
+	return f
+}
+
+func (f *TimeFormatter) refresh(interface{}) {
+	now := Now()
+	if unix := now.Unix(); unix != f.unix {
+		f.unix = unix
+		f.value.Store(now.Format(f.layout))
+	}
+}
+
+// String returns the cached formatted time, which lags behind the wall clock by
+// at most one wheel span.
+func (f *TimeFormatter) String() string {
+	return f.value.Load().(string)
+}
+
+// Layout returns the layout of the formatter.
+func (f *TimeFormatter) Layout() string {
+	return f.layout
+}
+
+// Stop stops refreshing. String keeps returning the last formatted time.
+func (f *TimeFormatter) Stop() {
+	f.timer.Stop()
+}
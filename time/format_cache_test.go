@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeFormatter(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	f := NewTimeFormatter(wheel, CommonLogLayout)
+	defer f.Stop()
+	assert.Equal(t, CommonLogLayout, f.Layout())
+
+	first, err := time.Parse(CommonLogLayout, f.String())
+	assert.Nil(t, err)
+	assert.True(t, time.Since(first) < 2*time.Second)
+
+	time.Sleep(1100 * time.Millisecond)
+	next, err := time.Parse(CommonLogLayout, f.String())
+	assert.Nil(t, err)
+	assert.True(t, next.After(first))
+}
+
+func TestUnixNanoConversion(t *testing.T) {
+	now := time.Now()
+	assert.True(t, now.Equal(UnixNano2Time(Time2UnixNano(now))))
+}
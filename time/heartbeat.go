@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync"
+	"time"
+)
+
+// HeartbeatFunc is invoked when @peer has missed @misses heartbeats in a row.
+type HeartbeatFunc func(peer string, misses int)
+
+type heartbeatPeer struct {
+	interval  time.Duration
+	threshold int
+	last      time.Time
+	reported  int // misses reported since the last heartbeat
+}
+
+// HeartbeatManager tracks the heartbeats of many peers by a single wheel timer,
+// instead of a ticker goroutine per peer.
+type HeartbeatManager struct {
+	lock   sync.Mutex
+	timer  *Timer
+	peers  map[string]*heartbeatPeer
+	onMiss HeartbeatFunc
+}
+
+// NewHeartbeatManager checks all peers on @wheel every @check and invokes @onMiss for
+// the peers which have missed their heartbeat threshold. A nil @wheel means the default wheel.
+func NewHeartbeatManager(wheel *Wheel, check time.Duration, onMiss HeartbeatFunc) *HeartbeatManager {
+	if onMiss == nil {
+		panic("@onMiss is nil")
+	}
+	if wheel == nil {
+		wheel = GetDefaultWheel()
+	}
+
+	m := &HeartbeatManager{
+		peers:  make(map[string]*heartbeatPeer),
+		onMiss: onMiss,
+	}
+	m.timer = wheel.addTimer(m.check, check, -1, nil)
+
+	return m
+}
+
+// Register starts tracking @peer which should beat every @interval. @onMiss is
+// invoked once @peer misses @threshold heartbeats, and again on every further miss.
+// Registering an existing peer resets it.
+func (m *HeartbeatManager) Register(peer string, interval time.Duration, threshold int) {
+	if interval <= 0 {
+		panic("@interval <= 0")
+	}
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	m.lock.Lock()
+	m.peers[peer] = &heartbeatPeer{
+		interval:  interval,
+		threshold: threshold,
+		last:      Now(),
+	}
+	m.lock.Unlock()
+}
+
+// Unregister stops tracking @peer.
+func (m *HeartbeatManager) Unregister(peer string) {
+	m.lock.Lock()
+	delete(m.peers, peer)
+	m.lock.Unlock()
+}
+
+// Beat records a heartbeat of @peer. It returns false if @peer is not registered.
+func (m *HeartbeatManager) Beat(peer string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	p, ok := m.peers[peer]
+	if !ok {
+		return false
+	}
+	p.last = Now()
+	p.reported = 0
+
+	return true
+}
+
+// Len returns the number of registered peers.
+func (m *HeartbeatManager) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return len(m.peers)
+}
+
+// Stop stops checking the peers.
+func (m *HeartbeatManager) Stop() {
+	m.timer.Stop()
+}
+
+type heartbeatMiss struct {
+	peer   string
+	misses int
+}
+
+func (m *HeartbeatManager) check(interface{}) {
+	var (
+		now    = Now()
+		misses []heartbeatMiss
+	)
+
+	m.lock.Lock()
+	for peer, p := range m.peers {
+		n := int(now.Sub(p.last) / p.interval)
+		if n >= p.threshold && n > p.reported {
+			p.reported = n
+			misses = append(misses, heartbeatMiss{peer: peer, misses: n})
+		}
+	}
+	m.lock.Unlock()
+
+	for _, miss := range misses {
+		m.onMiss(miss.peer, miss.misses)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeatManager(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	var (
+		lock   sync.Mutex
+		missed = make(map[string]int)
+	)
+	m := NewHeartbeatManager(wheel, 10*time.Millisecond, func(peer string, misses int) {
+		lock.Lock()
+		missed[peer] = misses
+		lock.Unlock()
+	})
+	defer m.Stop()
+
+	m.Register("alive", 20*time.Millisecond, 2)
+	m.Register("dead", 20*time.Millisecond, 2)
+	m.Register("gone", 20*time.Millisecond, 2)
+	m.Unregister("gone")
+	assert.Equal(t, 2, m.Len())
+	assert.False(t, m.Beat("gone"))
+
+	for i := 0; i < 15; i++ {
+		time.Sleep(10 * time.Millisecond)
+		assert.True(t, m.Beat("alive"))
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, 0, missed["alive"])
+	assert.True(t, missed["dead"] >= 5, missed["dead"])
+	assert.Equal(t, 0, missed["gone"])
+}
@@ -0,0 +1,267 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sort"
+	"time"
+)
+
+// Interval is the half-open time interval [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of @i, which is 0 for an empty interval.
+func (i Interval) Duration() time.Duration {
+	if i.IsEmpty() {
+		return 0
+	}
+
+	return i.End.Sub(i.Start)
+}
+
+// IsEmpty reports whether @i contains no instant.
+func (i Interval) IsEmpty() bool {
+	return !i.End.After(i.Start)
+}
+
+// Contains reports whether @t is in @i.
+func (i Interval) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+// Overlaps reports whether @i and @o share any instant.
+func (i Interval) Overlaps(o Interval) bool {
+	return !i.IsEmpty() && !o.IsEmpty() && i.Start.Before(o.End) && o.Start.Before(i.End)
+}
+
+// Intersect returns the common part of @i and @o, and false if there is none.
+func (i Interval) Intersect(o Interval) (Interval, bool) {
+	if !i.Overlaps(o) {
+		return Interval{}, false
+	}
+
+	r := i
+	if o.Start.After(r.Start) {
+		r.Start = o.Start
+	}
+	if o.End.Before(r.End) {
+		r.End = o.End
+	}
+
+	return r, true
+}
+
+// MergeIntervals returns the union of @intervals as sorted disjoint intervals. The
+// overlapping and adjacent intervals are merged, and the empty ones are dropped.
+func MergeIntervals(intervals ...Interval) []Interval {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, i := range intervals {
+		if !i.IsEmpty() {
+			sorted = append(sorted, i)
+		}
+	}
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].Start.Before(sorted[b].Start) })
+
+	merged := sorted[:0]
+	for _, i := range sorted {
+		if n := len(merged); n > 0 && !i.Start.After(merged[n-1].End) {
+			if i.End.After(merged[n-1].End) {
+				merged[n-1].End = i.End
+			}
+			continue
+		}
+		merged = append(merged, i)
+	}
+
+	return merged
+}
+
+// IntersectIntervals returns the instants in both @a and @b as sorted disjoint intervals.
+func IntersectIntervals(a, b []Interval) []Interval {
+	a, b = MergeIntervals(a...), MergeIntervals(b...)
+
+	var r []Interval
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		if common, ok := a[i].Intersect(b[j]); ok {
+			r = append(r, common)
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return r
+}
+
+// SubtractIntervals returns the instants in @from but not in @busy as sorted disjoint
+// intervals.
+func SubtractIntervals(from, busy []Interval) []Interval {
+	from, busy = MergeIntervals(from...), MergeIntervals(busy...)
+
+	var r []Interval
+	j := 0
+	for _, i := range from {
+		for j < len(busy) && !busy[j].End.After(i.Start) {
+			j++
+		}
+		for k := j; k < len(busy) && busy[k].Start.Before(i.End); k++ {
+			if busy[k].Start.After(i.Start) {
+				r = append(r, Interval{Start: i.Start, End: busy[k].Start})
+			}
+			if busy[k].End.After(i.Start) {
+				i.Start = busy[k].End
+			}
+		}
+		if !i.IsEmpty() {
+			r = append(r, i)
+		}
+	}
+
+	return r
+}
+
+// clockRange is a range of the wall clock time of a day, in offsets from the midnight.
+type clockRange struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// WeeklyHours is a weekly mask of wall clock time ranges, such as business hours or
+// maintenance windows, in a location. The zero value is an empty mask of time.Local.
+type WeeklyHours struct {
+	loc  *time.Location
+	days [7][]clockRange
+}
+
+// NewWeeklyHours returns an empty mask in @loc, a nil @loc means time.Local.
+func NewWeeklyHours(loc *time.Location) *WeeklyHours {
+	return &WeeklyHours{loc: loc}
+}
+
+func (h *WeeklyHours) location() *time.Location {
+	if h.loc == nil {
+		return time.Local
+	}
+
+	return h.loc
+}
+
+// Add adds the wall clock range [@start, @end) of @days to the mask, both offsets from
+// the midnight. An @end not after @start means the range ends on the next day, e.g.
+// 22h to 2h. It returns @h for chaining.
+func (h *WeeklyHours) Add(start, end time.Duration, days ...time.Weekday) *WeeklyHours {
+	if start < 0 || start >= 24*time.Hour || end < 0 || end > 24*time.Hour {
+		panic("@start or @end out of the day")
+	}
+	if end <= start {
+		end += 24 * time.Hour
+	}
+
+	for _, day := range days {
+		h.days[day] = append(h.days[day], clockRange{start: start, end: end})
+	}
+
+	return h
+}
+
+// AddWeekdays adds [@start, @end) of Monday to Friday to the mask.
+func (h *WeeklyHours) AddWeekdays(start, end time.Duration) *WeeklyHours {
+	return h.Add(start, end, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+}
+
+// clockTime returns the time of @offset from the midnight of @year-@month-@day by the
+// wall clock, which keeps the wall clock time across DST transitions.
+func clockTime(year int, month time.Month, day int, offset time.Duration, loc *time.Location) time.Time {
+	hour := int(offset / time.Hour)
+	min := int(offset % time.Hour / time.Minute)
+	nsec := int(offset % time.Minute)
+
+	return time.Date(year, month, day, hour, min, 0, nsec, loc)
+}
+
+// Windows returns the ranges of the mask within @within as sorted disjoint intervals.
+func (h *WeeklyHours) Windows(within Interval) []Interval {
+	if within.IsEmpty() {
+		return nil
+	}
+
+	var (
+		loc     = h.location()
+		windows []Interval
+	)
+	// a range can start on the day before @within and end in it
+	first := within.Start.In(loc).AddDate(0, 0, -1)
+	year, month, day := first.Date()
+	for d := 0; ; d++ {
+		midnight := time.Date(year, month, day+d, 0, 0, 0, 0, loc)
+		if !midnight.Before(within.End) {
+			break
+		}
+		for _, r := range h.days[midnight.Weekday()] {
+			w := Interval{
+				Start: clockTime(year, month, day+d, r.start, loc),
+				End:   clockTime(year, month, day+d, r.end, loc),
+			}
+			if common, ok := w.Intersect(within); ok {
+				windows = append(windows, common)
+			}
+		}
+	}
+
+	return MergeIntervals(windows...)
+}
+
+// Contains reports whether @t is in a range of the mask.
+func (h *WeeklyHours) Contains(t time.Time) bool {
+	return len(h.Windows(Interval{Start: t, End: t.Add(1)})) > 0
+}
+
+// Next returns the first instant at or after @t in a range of the mask, looking ahead at
+// most @horizon, and false if there is none.
+func (h *WeeklyHours) Next(t time.Time, horizon time.Duration) (time.Time, bool) {
+	windows := h.Windows(Interval{Start: t, End: t.Add(horizon)})
+	if len(windows) == 0 {
+		return time.Time{}, false
+	}
+
+	return windows[0].Start, true
+}
+
+// FreeSlots returns the parts of @within which are in the ranges of @mask and not in
+// @busy, and are at least @min long. A nil @mask allows all of @within.
+func FreeSlots(within Interval, mask *WeeklyHours, busy []Interval, min time.Duration) []Interval {
+	allowed := []Interval{within}
+	if mask != nil {
+		allowed = mask.Windows(within)
+	}
+
+	free := SubtractIntervals(allowed, busy)
+	slots := free[:0]
+	for _, slot := range free {
+		if slot.Duration() >= min {
+			slots = append(slots, slot)
+		}
+	}
+
+	return slots
+}
@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+var intervalBase = time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC) // a Monday
+
+func iv(start, end int) Interval {
+	return Interval{Start: intervalBase.Add(time.Duration(start) * time.Hour), End: intervalBase.Add(time.Duration(end) * time.Hour)}
+}
+
+func TestInterval(t *testing.T) {
+	i := iv(1, 3)
+	assert.Equal(t, 2*time.Hour, i.Duration())
+	assert.True(t, i.Contains(i.Start))
+	assert.False(t, i.Contains(i.End))
+	assert.True(t, iv(3, 1).IsEmpty())
+	assert.Equal(t, time.Duration(0), iv(3, 1).Duration())
+
+	assert.True(t, i.Overlaps(iv(2, 5)))
+	assert.False(t, i.Overlaps(iv(3, 5)))
+	common, ok := i.Intersect(iv(2, 5))
+	assert.True(t, ok)
+	assert.Equal(t, iv(2, 3), common)
+	_, ok = i.Intersect(iv(5, 6))
+	assert.False(t, ok)
+}
+
+func TestIntervalSets(t *testing.T) {
+	assert.Equal(t, []Interval{iv(0, 4), iv(5, 6)}, MergeIntervals(iv(5, 6), iv(2, 4), iv(0, 2), iv(1, 3), iv(7, 7)))
+	assert.Empty(t, MergeIntervals())
+
+	a := []Interval{iv(0, 4), iv(6, 10)}
+	b := []Interval{iv(2, 7), iv(9, 12)}
+	assert.Equal(t, []Interval{iv(2, 4), iv(6, 7), iv(9, 10)}, IntersectIntervals(a, b))
+	assert.Equal(t, []Interval{iv(0, 2), iv(7, 9)}, SubtractIntervals(a, b))
+	assert.Equal(t, []Interval{iv(4, 6), iv(10, 12)}, SubtractIntervals(b, a))
+	assert.Equal(t, []Interval{iv(0, 1), iv(2, 3), iv(5, 10)}, SubtractIntervals([]Interval{iv(0, 10)}, []Interval{iv(1, 2), iv(3, 5)}))
+	assert.Empty(t, SubtractIntervals([]Interval{iv(1, 2)}, []Interval{iv(0, 4)}))
+}
+
+func TestWeeklyHours(t *testing.T) {
+	h := NewWeeklyHours(time.UTC).AddWeekdays(9*time.Hour, 17*time.Hour)
+	// Monday 0:00 to Wednesday 0:00
+	windows := h.Windows(iv(0, 48))
+	assert.Equal(t, []Interval{iv(9, 17), iv(33, 41)}, windows)
+	assert.True(t, h.Contains(intervalBase.Add(10*time.Hour)))
+	assert.False(t, h.Contains(intervalBase.Add(17*time.Hour)))
+
+	// Saturday and Sunday are empty
+	next, ok := h.Next(intervalBase.Add(5*24*time.Hour), 7*24*time.Hour)
+	assert.True(t, ok)
+	assert.Equal(t, intervalBase.Add(7*24*time.Hour+9*time.Hour), next)
+	_, ok = h.Next(intervalBase.Add(5*24*time.Hour), 24*time.Hour)
+	assert.False(t, ok)
+
+	// a range across the midnight, which starts on the day before
+	night := NewWeeklyHours(time.UTC).Add(22*time.Hour, 2*time.Hour, time.Sunday)
+	assert.Equal(t, []Interval{iv(0, 2)}, night.Windows(iv(0, 24)))
+}
+
+func TestWeeklyHoursDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+	// the clocks go forward at 2:00 of 2024-03-10, a Sunday
+	h := NewWeeklyHours(loc).Add(1*time.Hour, 4*time.Hour, time.Sunday)
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, loc)
+	windows := h.Windows(Interval{Start: day, End: day.AddDate(0, 0, 1)})
+	assert.Len(t, windows, 1)
+	assert.Equal(t, 1, windows[0].Start.Hour())
+	assert.Equal(t, 4, windows[0].End.Hour())
+	assert.Equal(t, 2*time.Hour, windows[0].Duration())
+}
+
+func TestFreeSlots(t *testing.T) {
+	h := NewWeeklyHours(time.UTC).AddWeekdays(9*time.Hour, 17*time.Hour)
+	busy := []Interval{iv(10, 12), iv(12, 13), iv(16, 18), iv(33, 40)}
+
+	slots := FreeSlots(iv(0, 48), h, busy, time.Hour)
+	assert.Equal(t, []Interval{iv(9, 10), iv(13, 16), iv(40, 41)}, slots)
+
+	slots = FreeSlots(iv(0, 48), h, busy, 2*time.Hour)
+	assert.Equal(t, []Interval{iv(13, 16)}, slots)
+
+	assert.Equal(t, []Interval{iv(0, 10), iv(13, 16)}, FreeSlots(iv(0, 16), nil, busy, 0))
+}
@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Logger is the logger of a Wheel, through which the wheel reports its internal
+// errors such as timer callback panics. It can be an adapter of the structured
+// logger of the application.
+type Logger interface {
+	Debug(format string, args ...interface{})
+	Info(format string, args ...interface{})
+	Warn(format string, args ...interface{})
+	Error(format string, args ...interface{})
+}
+
+// stderrLogger is the default logger, which prints the warnings and errors to stderr.
+type stderrLogger struct{}
+
+func (stderrLogger) Debug(string, ...interface{}) {}
+
+func (stderrLogger) Info(string, ...interface{}) {}
+
+func (stderrLogger) Warn(format string, args ...interface{}) {
+	stderrPrint("WARN", format, args...)
+}
+
+func (stderrLogger) Error(format string, args ...interface{}) {
+	stderrPrint("ERROR", format, args...)
+}
+
+func stderrPrint(level string, format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s [%s] %s\n", time.Now().Format(time.RFC3339), level, fmt.Sprintf(format, args...))
+}
+
+// NopLogger discards everything, which silences a wheel.
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...interface{}) {}
+
+func (NopLogger) Info(string, ...interface{}) {}
+
+func (NopLogger) Warn(string, ...interface{}) {}
+
+func (NopLogger) Error(string, ...interface{}) {}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxmetrics exposes the statistics of gxtime wheels as prometheus collectors.
+// It is a standalone module, so gost itself does not depend on prometheus.
+package gxmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+const (
+	namespace = "gost"
+	subsystem = "timer_wheel"
+)
+
+type wheelCollector struct {
+	wheel *gxtime.Wheel
+
+	timers       *prometheus.Desc
+	fired        *prometheus.Desc
+	fireLatency  *prometheus.Desc
+	batch        *prometheus.Desc
+	droppedTicks *prometheus.Desc
+}
+
+// NewWheelCollector returns a collector of @wheel. @name is exported as the "wheel"
+// label to tell different wheels apart. Register it by prometheus.MustRegister.
+func NewWheelCollector(name string, wheel *gxtime.Wheel) prometheus.Collector {
+	labels := prometheus.Labels{"wheel": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, metric), help, nil, labels)
+	}
+
+	return &wheelCollector{
+		wheel:        wheel,
+		timers:       desc("active_timers", "Number of callback timers waiting to fire."),
+		fired:        desc("fired_total", "Number of fired timer callbacks."),
+		fireLatency:  desc("fire_latency_seconds_total", "Accumulated delay of fired callbacks against their expected fire time."),
+		batch:        desc("queue_depth", "Number of callbacks expired on the last tick."),
+		droppedTicks: desc("dropped_ticks_total", "Number of ticks dropped because the wheel loop lagged behind."),
+	}
+}
+
+func (c *wheelCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.timers
+	ch <- c.fired
+	ch <- c.fireLatency
+	ch <- c.batch
+	ch <- c.droppedTicks
+}
+
+func (c *wheelCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.wheel.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.timers, prometheus.GaugeValue, float64(stats.Timers))
+	ch <- prometheus.MustNewConstMetric(c.fired, prometheus.CounterValue, float64(stats.Fired))
+	ch <- prometheus.MustNewConstMetric(c.fireLatency, prometheus.CounterValue, stats.FireLatency.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.batch, prometheus.GaugeValue, float64(stats.Batch))
+	ch <- prometheus.MustNewConstMetric(c.droppedTicks, prometheus.CounterValue, float64(stats.DroppedTicks))
+}
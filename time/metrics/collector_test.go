@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxmetrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	gxtime "github.com/dubbogo/gost/time"
+)
+
+func TestWheelCollector(t *testing.T) {
+	wheel := gxtime.NewWheel(10*time.Millisecond, 10)
+	defer wheel.Stop()
+	wheel.AddTimerTimes(func(interface{}) {}, time.Hour, 1, nil)
+
+	c := NewWheelCollector("default", wheel)
+	reg := prometheus.NewPedanticRegistry()
+	assert.Nil(t, reg.Register(c))
+
+	expected := `
+# HELP gost_timer_wheel_active_timers Number of callback timers waiting to fire.
+# TYPE gost_timer_wheel_active_timers gauge
+gost_timer_wheel_active_timers{wheel="default"} 1
+`
+	assert.Nil(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "gost_timer_wheel_active_timers"))
+	assert.Equal(t, 5, testutil.CollectAndCount(c))
+}
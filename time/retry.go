@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RetryPolicy decides how many times and how long to wait between the attempts of Retry.
+type RetryPolicy struct {
+	MaxAttempts int           // max attempts including the first one, 0 means unlimited
+	Initial     time.Duration // wait before the second attempt
+	Max         time.Duration // upper bound of the wait, 0 means unbounded
+	Multiplier  float64       // growth factor of the wait, a value not greater than 1 means a fixed wait
+	Jitter      float64       // random fraction in [0, 1] added to or subtracted from the wait
+}
+
+// FixedBackoff returns a policy which waits @wait between at most @attempts attempts.
+func FixedBackoff(wait time.Duration, attempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: attempts,
+		Initial:     wait,
+	}
+}
+
+// ExponentialBackoff returns a policy which doubles the wait from @initial to @max
+// with 20% jitter between at most @attempts attempts.
+func ExponentialBackoff(initial, max time.Duration, attempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: attempts,
+		Initial:     initial,
+		Max:         max,
+		Multiplier:  2,
+		Jitter:      0.2,
+	}
+}
+
+var (
+	retryRandLock sync.Mutex
+	retryRand     = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// Backoff returns the wait after the @attempt-th attempt, which starts from 1.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	wait := float64(p.Initial)
+	if p.Multiplier > 1 {
+		for i := 1; i < attempt; i++ {
+			wait *= p.Multiplier
+			if p.Max > 0 && wait >= float64(p.Max) {
+				break
+			}
+		}
+	}
+	if p.Max > 0 && wait > float64(p.Max) {
+		wait = float64(p.Max)
+	}
+	if p.Jitter > 0 {
+		retryRandLock.Lock()
+		r := retryRand.Float64()
+		retryRandLock.Unlock()
+		wait += wait * p.Jitter * (2*r - 1)
+	}
+
+	return time.Duration(wait)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps @err to make Retry return it at once without any further attempt.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// Retry invokes @fn until it succeeds, returns a Permanent error, or the attempts of
+// @policy run out. The waits are scheduled on the default wheel instead of time.Sleep,
+// so a large number of concurrent retries share the wheel goroutine instead of parking
+// a runtime timer each. It returns the last error of @fn, also when @ctx is done
+// during a wait.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	var (
+		err       error
+		permanent *permanentError
+	)
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return err
+		}
+		if !retryWait(ctx, policy.Backoff(attempt)) {
+			return err
+		}
+	}
+}
+
+// retryWait returns false if @ctx is done before @d passes.
+func retryWait(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	done := make(chan struct{})
+	t := GetDefaultWheel().AddTimerInline(func(interface{}) { close(done) }, d, 1, nil)
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		t.Stop()
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := FixedBackoff(10*time.Millisecond, 3)
+	assert.Equal(t, 10*time.Millisecond, p.Backoff(1))
+	assert.Equal(t, 10*time.Millisecond, p.Backoff(5))
+
+	p = ExponentialBackoff(10*time.Millisecond, 100*time.Millisecond, 0)
+	p.Jitter = 0
+	assert.Equal(t, 10*time.Millisecond, p.Backoff(1))
+	assert.Equal(t, 40*time.Millisecond, p.Backoff(3))
+	assert.Equal(t, 100*time.Millisecond, p.Backoff(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.Backoff(1)
+		assert.True(t, d >= 5*time.Millisecond && d <= 15*time.Millisecond)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	errFail := errors.New("fail")
+	n := 0
+	err := Retry(context.Background(), FixedBackoff(10*time.Millisecond, 5), func(context.Context) error {
+		n++
+		if n < 3 {
+			return errFail
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+
+	n = 0
+	err = Retry(context.Background(), FixedBackoff(10*time.Millisecond, 2), func(context.Context) error {
+		n++
+		return errFail
+	})
+	assert.Equal(t, errFail, err)
+	assert.Equal(t, 2, n)
+
+	n = 0
+	err = Retry(context.Background(), FixedBackoff(10*time.Millisecond, 0), func(context.Context) error {
+		n++
+		return Permanent(errFail)
+	})
+	assert.Equal(t, errFail, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestRetryCanceled(t *testing.T) {
+	errFail := errors.New("fail")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := Retry(ctx, FixedBackoff(time.Second, 0), func(context.Context) error {
+		return errFail
+	})
+	assert.Equal(t, errFail, err)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+}
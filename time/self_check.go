@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"time"
+)
+
+// selfCheck tracks the logical deadline of every timer of a wheel apart from the
+// timers themselves, and checks the fire time of every expired timer against it.
+// Its fields are protected by the wheel lock.
+type selfCheck struct {
+	tolerance time.Duration
+	deadlines map[*Timer]time.Time
+	scanTicks int // ticks between two scans of the timers which should have fired
+	ticks     int
+}
+
+func newSelfCheck(tolerance time.Duration, span time.Duration) *selfCheck {
+	c := &selfCheck{
+		tolerance: tolerance,
+		deadlines: make(map[*Timer]time.Time),
+	}
+	c.rescan(span)
+
+	return c
+}
+
+// rescan keeps the scans once per second for a new @span.
+func (c *selfCheck) rescan(span time.Duration) {
+	c.scanTicks = int(time.Second / span)
+	if c.scanTicks < 1 {
+		c.scanTicks = 1
+	}
+}
+
+// track records the deadline of the scheduled @t. It should be invoked with the wheel lock held.
+func (w *Wheel) track(t *Timer, deadline time.Time) {
+	if w.check != nil {
+		w.check.deadlines[t] = deadline
+	}
+}
+
+// untrack forgets the stopped @t. It should be invoked with the wheel lock held.
+func (w *Wheel) untrack(t *Timer) {
+	if w.check != nil {
+		delete(w.check.deadlines, t)
+	}
+}
+
+// checkFire checks that the expired @t fires within [deadline - tolerance, deadline +
+// span + tolerance]. The lower bound is loose because a late tick of the ticker is
+// followed by an earlier one. It should be invoked with the wheel lock held.
+func (w *Wheel) checkFire(t *Timer) {
+	if w.check == nil {
+		return
+	}
+
+	deadline, ok := w.check.deadlines[t]
+	if !ok {
+		w.violate(t, -1, "untracked timer fired", 0)
+		return
+	}
+	delete(w.check.deadlines, t)
+
+	diff := w.now.Sub(deadline)
+	if diff < -w.check.tolerance {
+		w.violate(t, -1, "timer fired early", diff)
+	} else if diff > w.span+w.check.tolerance {
+		w.violate(t, -1, "timer fired late", diff)
+	}
+}
+
+// scanLost reports the timers which should have fired but are still waiting, e.g.
+// lost by a wrong round or slot. It should be invoked with the wheel lock held.
+func (w *Wheel) scanLost() {
+	if w.check == nil {
+		return
+	}
+	if w.check.ticks++; w.check.ticks < w.check.scanTicks {
+		return
+	}
+	w.check.ticks = 0
+
+	for t, deadline := range w.check.deadlines {
+		if diff := w.now.Sub(deadline); diff > w.span+w.check.tolerance {
+			delete(w.check.deadlines, t)
+			w.violate(t, w.slotOf(t), "timer not fired", diff)
+		}
+	}
+}
+
+// slotOf returns the slot holding @t, or -1 if it is absent.
+func (w *Wheel) slotOf(t *Timer) int {
+	for i, slot := range w.timers {
+		for _, s := range slot {
+			if s == t {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func (w *Wheel) violate(t *Timer, slot int, what string, diff time.Duration) {
+	w.stats.SelfCheckViolations++
+	w.logger.Error("gost/time self-check: %s, error %v, timer{period %v, expect %v, rounds %d, times %d, slot %d}, "+
+		"wheel{span %v, slots %d, index %d, last tick %v, now %v}",
+		what, diff, t.period, t.expect, t.rounds, t.times, slot,
+		w.span, len(w.ring), w.index, w.last, w.now)
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWheelSelfCheck(t *testing.T) {
+	logger := &recordLogger{errs: make(chan string, 16)}
+	wheel := NewWheel(TimeMillisecondDuration(10), 10, WithWheelSelfCheck(50*time.Millisecond), WithWheelLogger(logger))
+	defer wheel.Stop()
+
+	var cnt int64
+	for i := 1; i <= 5; i++ {
+		wheel.AddTimerTimes(func(interface{}) {
+			atomic.AddInt64(&cnt, 1)
+		}, time.Duration(i*15)*time.Millisecond, 2, nil)
+	}
+	stopped := wheel.AddTimer(func(interface{}) {}, time.Second, nil)
+	assert.True(t, stopped.Stop())
+
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, int64(10), atomic.LoadInt64(&cnt))
+	assert.Equal(t, uint64(0), wheel.Stats().SelfCheckViolations)
+	wheel.RLock()
+	assert.Empty(t, wheel.check.deadlines)
+	wheel.RUnlock()
+}
+
+func TestWheelSelfCheckLostTimer(t *testing.T) {
+	logger := &recordLogger{errs: make(chan string, 16)}
+	wheel := NewWheel(TimeMillisecondDuration(10), 10, WithWheelSelfCheck(10*time.Millisecond), WithWheelLogger(logger))
+	defer wheel.Stop()
+
+	timer := wheel.AddTimerTimes(func(interface{}) {}, 20*time.Millisecond, 1, nil)
+	// simulate a cascade bug which puts the timer several rounds off
+	wheel.Lock()
+	timer.rounds = 1000
+	wheel.Unlock()
+
+	select {
+	case msg := <-logger.errs:
+		assert.True(t, strings.Contains(msg, "timer not fired"), msg)
+	case <-time.After(2 * time.Second):
+		t.Fatal("lost timer is not reported")
+	}
+	assert.Equal(t, uint64(1), wheel.Stats().SelfCheckViolations)
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type timeSourceFunc func() time.Time
+
+var timeSource atomic.Value
+
+func init() {
+	timeSource.Store(timeSourceFunc(time.Now))
+}
+
+// SetTimeSource replaces the clock used by the whole package, including Now(),
+// CountWatch, GetEndtime, and the time every Wheel records on its ticks and schedules
+// its timers by. A wheel still ticks by the wall clock unless it is created
+// WithWheelManualTick, in which case Advance ticks it by the time source, so that the
+// triggers and the cascades of its timers follow the time source too. It is designed
+// for simulation environments and unit tests. A nil @f restores the default time.Now.
+func SetTimeSource(f func() time.Time) {
+	if f == nil {
+		f = time.Now
+	}
+	timeSource.Store(timeSourceFunc(f))
+}
+
+// Now returns the current time of the package time source.
+func Now() time.Time {
+	return timeSource.Load().(timeSourceFunc)()
+}
@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTimeSource(t *testing.T) {
+	fixed := time.Date(2020, 12, 10, 8, 0, 0, 0, time.Local)
+	SetTimeSource(func() time.Time { return fixed })
+	defer SetTimeSource(nil)
+
+	assert.Equal(t, fixed, Now())
+	assert.Equal(t, time.Date(2020, 12, 10, 23, 59, 59, 0, time.Local), GetEndtime("day"))
+
+	var cw CountWatch
+	cw.Start()
+	assert.Equal(t, int64(0), cw.Count())
+
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+	<-wheel.After(TimeMillisecondDuration(20))
+	assert.Equal(t, fixed, wheel.Now())
+
+	SetTimeSource(nil)
+	assert.True(t, time.Since(Now()) < time.Second)
+}
+
+type fakeClock struct {
+	now int64
+}
+
+func (c *fakeClock) Now() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.now))
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	atomic.AddInt64(&c.now, int64(d))
+}
+
+func TestSetTimeSourceManualTick(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 12, 10, 8, 0, 0, 0, time.UTC).UnixNano()}
+	SetTimeSource(clock.Now)
+	defer SetTimeSource(nil)
+	start := clock.Now()
+
+	wheel := NewWheel(TimeMillisecondDuration(10), 10, WithWheelManualTick())
+	defer wheel.Stop()
+	assert.Panics(t, func() { GetDefaultWheel().Advance() })
+
+	var once, periodic, cascaded []time.Duration
+	at := func(fires *[]time.Duration) TimerFunc {
+		return func(interface{}) { *fires = append(*fires, wheel.Now().Sub(start)) }
+	}
+	wheel.AddTimerInline(at(&once), TimeMillisecondDuration(35), 1, nil)
+	wheel.AddTimerInline(at(&periodic), TimeMillisecondDuration(20), 3, nil)
+	// longer than the ring period of 100ms
+	wheel.AddTimerInline(at(&cascaded), TimeMillisecondDuration(250), 1, nil)
+	after := wheel.After(TimeMillisecondDuration(50))
+
+	clock.Advance(TimeMillisecondDuration(35))
+	assert.Equal(t, 3, wheel.Advance())
+	assert.Empty(t, once)
+	clock.Advance(TimeMillisecondDuration(5))
+	assert.Equal(t, 1, wheel.Advance())
+	assert.Equal(t, []time.Duration{TimeMillisecondDuration(40)}, once)
+	select {
+	case <-after:
+		t.Fatal("After fired early")
+	default:
+	}
+
+	// a timer added between two ticks counts in the time since the last tick
+	clock.Advance(TimeMillisecondDuration(5))
+	assert.Equal(t, 0, wheel.Advance())
+	var late []time.Duration
+	wheel.AddTimerInline(at(&late), TimeMillisecondDuration(10), 1, nil)
+
+	clock.Advance(TimeMillisecondDuration(300))
+	assert.Equal(t, 30, wheel.Advance())
+	<-after
+	assert.Equal(t, []time.Duration{TimeMillisecondDuration(60)}, late)
+	assert.Equal(t, []time.Duration{
+		TimeMillisecondDuration(20), TimeMillisecondDuration(40), TimeMillisecondDuration(60),
+	}, periodic)
+	assert.Equal(t, []time.Duration{TimeMillisecondDuration(250)}, cascaded)
+	// 345ms by the clock, and 340ms of the last tick
+	assert.Equal(t, TimeMillisecondDuration(5), wheel.Drift())
+}
+
+func TestSetTimeSourceJump(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+	defer SetTimeSource(nil)
+
+	fired := func() (TimerFunc, chan struct{}) {
+		c := make(chan struct{})
+		return func(interface{}) { close(c) }, c
+	}
+	waitFire := func(c chan struct{}) time.Duration {
+		start := time.Now()
+		select {
+		case <-c:
+		case <-time.After(time.Second):
+			t.Fatal("the timer did not fire in time")
+		}
+		return time.Since(start)
+	}
+
+	// the time source jumps forward between two ticks
+	<-wheel.After(TimeMillisecondDuration(10))
+	SetTimeSource(func() time.Time { return time.Now().Add(time.Second) })
+	f, c := fired()
+	wheel.AddTimerTimes(f, TimeMillisecondDuration(50), 1, nil)
+	assert.True(t, waitFire(c) < TimeMillisecondDuration(500))
+
+	// the real clock is restored from a time source long in the past
+	fixed := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	SetTimeSource(func() time.Time { return fixed })
+	<-wheel.After(TimeMillisecondDuration(10))
+	SetTimeSource(nil)
+	f, c = fired()
+	wheel.AddTimerTimes(f, TimeMillisecondDuration(50), 1, nil)
+	assert.True(t, waitFire(c) < TimeMillisecondDuration(500))
+}
@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"os"
+	"time"
+)
+
+const (
+	stampVersion = 1
+	stampLen     = 1 + 8 + 8 + 8
+)
+
+var (
+	// monoBase is the reference point of the monotonic readings of this process
+	monoBase = time.Now()
+	// monoEpoch identifies the monotonic clock of this process
+	monoEpoch = newMonoEpoch()
+
+	ErrInvalidStamp = errors.New("gxtime: invalid stamp data")
+)
+
+func newMonoEpoch() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return uint64(monoBase.UnixNano()) ^ uint64(os.Getpid())
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Stamp captures a wall clock reading and a monotonic clock reading together.
+// Unlike time.Time, its monotonic reading survives serialization, so two stamps
+// taken by the same process are always compared by the monotonic clock, which
+// is immune to wall clock adjustments. Stamps of different processes fall back
+// to be compared by the wall clock.
+type Stamp struct {
+	wall  int64 // unix nanoseconds
+	mono  int64 // nanoseconds since monoBase
+	epoch uint64
+}
+
+// NewStamp takes a stamp now. Its wall reading comes from the package time source.
+func NewStamp() Stamp {
+	return Stamp{
+		wall:  Now().UnixNano(),
+		mono:  int64(time.Since(monoBase)),
+		epoch: monoEpoch,
+	}
+}
+
+// Wall returns the wall clock reading of the stamp.
+func (s Stamp) Wall() time.Time {
+	return UnixNano2Time(s.wall)
+}
+
+// IsZero reports whether the stamp has never been taken.
+func (s Stamp) IsZero() bool {
+	return s.epoch == 0 && s.wall == 0 && s.mono == 0
+}
+
+// Local reports whether the stamp was taken by the current process,
+// that is, whether its monotonic reading is comparable.
+func (s Stamp) Local() bool {
+	return s.epoch == monoEpoch
+}
+
+// Sub returns the duration s-u, by the monotonic readings if both stamps
+// share the same monotonic clock, or by the wall readings otherwise.
+func (s Stamp) Sub(u Stamp) time.Duration {
+	if s.epoch == u.epoch {
+		return time.Duration(s.mono - u.mono)
+	}
+	return time.Duration(s.wall - u.wall)
+}
+
+// Compare returns -1, 0 or +1 if s is respectively before, at the same time or after u.
+func (s Stamp) Compare(u Stamp) int {
+	d := s.Sub(u)
+	switch {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
+
+// Before reports whether s is before u.
+func (s Stamp) Before(u Stamp) bool {
+	return s.Compare(u) < 0
+}
+
+// After reports whether s is after u.
+func (s Stamp) After(u Stamp) bool {
+	return s.Compare(u) > 0
+}
+
+// SinceStamp returns the time elapsed since @s.
+func SinceStamp(s Stamp) time.Duration {
+	return NewStamp().Sub(s)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s Stamp) MarshalBinary() ([]byte, error) {
+	b := make([]byte, stampLen)
+	b[0] = stampVersion
+	binary.BigEndian.PutUint64(b[1:], uint64(s.wall))
+	binary.BigEndian.PutUint64(b[9:], uint64(s.mono))
+	binary.BigEndian.PutUint64(b[17:], s.epoch)
+
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *Stamp) UnmarshalBinary(data []byte) error {
+	if len(data) != stampLen || data[0] != stampVersion {
+		return ErrInvalidStamp
+	}
+
+	s.wall = int64(binary.BigEndian.Uint64(data[1:]))
+	s.mono = int64(binary.BigEndian.Uint64(data[9:]))
+	s.epoch = binary.BigEndian.Uint64(data[17:])
+
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStamp(t *testing.T) {
+	s1 := NewStamp()
+	assert.False(t, s1.IsZero())
+	assert.True(t, s1.Local())
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, SinceStamp(s1) >= 10*time.Millisecond)
+
+	// a wall clock jump backward does not affect local comparison
+	SetTimeSource(func() time.Time { return time.Now().Add(-time.Hour) })
+	s2 := NewStamp()
+	SetTimeSource(nil)
+	assert.True(t, s2.Wall().Before(s1.Wall()))
+	assert.True(t, s1.Before(s2))
+	assert.True(t, s2.After(s1))
+	assert.Equal(t, 0, s1.Compare(s1))
+
+	data, err := s1.MarshalBinary()
+	assert.Nil(t, err)
+	var s3 Stamp
+	assert.Nil(t, s3.UnmarshalBinary(data))
+	assert.Equal(t, s1, s3)
+	assert.Equal(t, ErrInvalidStamp, s3.UnmarshalBinary(data[1:]))
+
+	// stamps of another process are compared by the wall clock
+	remote := s1
+	remote.epoch++
+	assert.False(t, remote.Local())
+	assert.True(t, s2.Before(remote))
+}
@@ -0,0 +1,47 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"time"
+)
+
+// WheelStats is a snapshot of the statistics of a Wheel.
+type WheelStats struct {
+	Timers       int           // callback timers waiting to fire
+	Fired        uint64        // callbacks fired so far
+	FireLatency  time.Duration // accumulated delay of all fired callbacks against their expected fire time
+	Batch        int           // number of callbacks expired on the last tick
+	Deferred     int           // callbacks carried over to the last tick by the batch budget
+	DroppedTicks uint64        // ticks dropped by the ticker because the wheel loop lagged behind
+
+	SelfCheckViolations uint64 // fire time violations caught by the self-check mode
+
+	Span     time.Duration // current tick interval, which only changes in the auto-tune mode
+	Retunes  uint64        // span changes made by the auto-tune mode
+	LastTune TuneDecision  // the latest span change made by the auto-tune mode
+}
+
+// Stats returns a snapshot of the statistics of the wheel.
+func (w *Wheel) Stats() WheelStats {
+	w.RLock()
+	stats := w.stats
+	w.RUnlock()
+
+	return stats
+}
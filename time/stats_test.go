@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWheelStats(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	wheel.AddTimerTimes(func(interface{}) {}, TimeMillisecondDuration(10), 2, nil)
+	timer := wheel.AddTimerTimes(func(interface{}) {}, time.Hour, 1, nil)
+	assert.Equal(t, 2, wheel.Stats().Timers)
+
+	time.Sleep(100 * time.Millisecond)
+	stats := wheel.Stats()
+	assert.Equal(t, 1, stats.Timers)
+	assert.Equal(t, uint64(2), stats.Fired)
+	assert.True(t, stats.FireLatency < 100*time.Millisecond)
+
+	timer.Stop()
+	assert.Equal(t, 0, wheel.Stats().Timers)
+}
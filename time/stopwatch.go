@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"time"
+)
+
+/////////////////////////////////////////
+// stopwatch
+/////////////////////////////////////////
+
+// Stopwatch measures elapsed time by the cached time of a Wheel instead of
+// invoking time.Now() on every reading, so its precision is the wheel span.
+// It is not goroutine safe.
+type Stopwatch struct {
+	wheel    *Wheel
+	start    time.Time
+	lapStart time.Time
+	elapsed  time.Duration // accumulated elapsed time of the stopped runs
+	running  bool
+	laps     []time.Duration
+}
+
+// NewStopwatch returns a stopwatch reading @wheel. A nil @wheel means the default wheel.
+func NewStopwatch(wheel *Wheel) *Stopwatch {
+	if wheel == nil {
+		wheel = GetDefaultWheel()
+	}
+
+	return &Stopwatch{wheel: wheel}
+}
+
+// Start starts or resumes the stopwatch.
+func (s *Stopwatch) Start() {
+	if s.running {
+		return
+	}
+
+	now := s.wheel.Now()
+	s.start = now
+	s.lapStart = now
+	s.running = true
+}
+
+// Stop pauses the stopwatch and returns its elapsed time.
+func (s *Stopwatch) Stop() time.Duration {
+	if s.running {
+		s.elapsed += s.wheel.Now().Sub(s.start)
+		s.running = false
+	}
+
+	return s.elapsed
+}
+
+// Lap records and returns the time elapsed since the last lap or the start.
+func (s *Stopwatch) Lap() time.Duration {
+	if !s.running {
+		return 0
+	}
+
+	now := s.wheel.Now()
+	lap := now.Sub(s.lapStart)
+	s.lapStart = now
+	s.laps = append(s.laps, lap)
+
+	return lap
+}
+
+// Laps returns all recorded laps.
+func (s *Stopwatch) Laps() []time.Duration {
+	return s.laps
+}
+
+// Elapsed returns the total elapsed time, including the current run.
+func (s *Stopwatch) Elapsed() time.Duration {
+	if s.running {
+		return s.elapsed + s.wheel.Now().Sub(s.start)
+	}
+
+	return s.elapsed
+}
+
+// Reset stops the stopwatch and clears its elapsed time and laps.
+func (s *Stopwatch) Reset() {
+	s.elapsed = 0
+	s.running = false
+	s.laps = nil
+}
+
+// CostTime returns the running time of @f measured by the default wheel.
+func CostTime(f func()) time.Duration {
+	wheel := GetDefaultWheel()
+	start := wheel.Now()
+	f()
+
+	return wheel.Now().Sub(start)
+}
@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopwatch(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	sw := NewStopwatch(wheel)
+	assert.Equal(t, time.Duration(0), sw.Elapsed())
+	assert.Equal(t, time.Duration(0), sw.Lap())
+
+	sw.Start()
+	time.Sleep(50 * time.Millisecond)
+	lap := sw.Lap()
+	assert.True(t, lap >= 30*time.Millisecond, lap)
+	time.Sleep(30 * time.Millisecond)
+	sw.Lap()
+	assert.Equal(t, 2, len(sw.Laps()))
+
+	elapsed := sw.Stop()
+	assert.True(t, elapsed >= 60*time.Millisecond, elapsed)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, elapsed, sw.Elapsed())
+
+	sw.Start()
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, sw.Elapsed() > elapsed)
+
+	sw.Reset()
+	assert.Equal(t, time.Duration(0), sw.Elapsed())
+	assert.Nil(t, sw.Laps())
+}
+
+func TestCostTime(t *testing.T) {
+	cost := CostTime(func() {
+		time.Sleep(50 * time.Millisecond)
+	})
+	assert.True(t, cost >= 30*time.Millisecond, cost)
+	assert.True(t, cost < 200*time.Millisecond, cost)
+}
@@ -99,7 +99,7 @@ func Time2UnixNano(t time.Time) int64 {
 }
 
 func GetEndtime(format string) time.Time {
-	timeNow := time.Now()
+	timeNow := Now()
 	switch format {
 	case "day":
 		year, month, _ := timeNow.Date()
@@ -0,0 +1,160 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"container/list"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+type timeoutEntry[K comparable] struct {
+	id   K
+	last time.Time
+}
+
+// TimeoutQueue tracks many objects sharing one idle timeout, such as idle connections.
+// The objects are kept in the order of their last touch, so Touch is O(1) and a single
+// wheel timer only has to look at the front of the queue to evict the idle ones.
+type TimeoutQueue[K comparable] struct {
+	lock    sync.Mutex
+	timeout time.Duration
+	queue   *list.List // the least recently touched entry is at the front
+	entries map[K]*list.Element
+	onEvict func(id K)
+	timer   *Timer
+	logger  Logger
+}
+
+// NewTimeoutQueue returns a queue whose entries are evicted by @wheel if they are not
+// touched within @timeout. @onEvict is invoked for every evicted entry, in a goroutine
+// of the ticks which evict any. The precision of the eviction is the wheel span. A nil
+// @wheel means the default wheel.
+func NewTimeoutQueue[K comparable](wheel *Wheel, timeout time.Duration, onEvict func(id K)) *TimeoutQueue[K] {
+	if timeout <= 0 {
+		panic("@timeout <= 0")
+	}
+	if wheel == nil {
+		wheel = GetDefaultWheel()
+	}
+
+	q := &TimeoutQueue[K]{
+		timeout: timeout,
+		queue:   list.New(),
+		entries: make(map[K]*list.Element),
+		onEvict: onEvict,
+		logger:  wheel.logger,
+	}
+	q.timer = wheel.AddTimerInline(q.evict, wheel.Span(), -1, nil)
+
+	return q
+}
+
+// Add inserts @id into the queue, or touches it if it exists.
+func (q *TimeoutQueue[K]) Add(id K) {
+	now := Now()
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if e, ok := q.entries[id]; ok {
+		e.Value.(*timeoutEntry[K]).last = now
+		q.queue.MoveToBack(e)
+		return
+	}
+	q.entries[id] = q.queue.PushBack(&timeoutEntry[K]{id: id, last: now})
+}
+
+// Touch resets the idle time of @id. It returns false if @id is not in the queue.
+func (q *TimeoutQueue[K]) Touch(id K) bool {
+	now := Now()
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	e, ok := q.entries[id]
+	if !ok {
+		return false
+	}
+	e.Value.(*timeoutEntry[K]).last = now
+	q.queue.MoveToBack(e)
+
+	return true
+}
+
+// Remove deletes @id from the queue without invoking the eviction callback.
+func (q *TimeoutQueue[K]) Remove(id K) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	e, ok := q.entries[id]
+	if ok {
+		q.queue.Remove(e)
+		delete(q.entries, id)
+	}
+
+	return ok
+}
+
+// Len returns the number of entries in the queue.
+func (q *TimeoutQueue[K]) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return len(q.entries)
+}
+
+// Stop stops evicting entries.
+func (q *TimeoutQueue[K]) Stop() {
+	q.timer.Stop()
+}
+
+// evict runs in the wheel goroutine on every span, so it only scans the front of the
+// queue, and leaves the callbacks, which may block, to a new goroutine.
+func (q *TimeoutQueue[K]) evict(interface{}) {
+	var (
+		evicted  []K
+		deadline = Now().Add(-q.timeout)
+	)
+
+	q.lock.Lock()
+	for e := q.queue.Front(); e != nil; e = q.queue.Front() {
+		entry := e.Value.(*timeoutEntry[K])
+		if entry.last.After(deadline) {
+			break
+		}
+		q.queue.Remove(e)
+		delete(q.entries, entry.id)
+		evicted = append(evicted, entry.id)
+	}
+	q.lock.Unlock()
+
+	if q.onEvict != nil && len(evicted) > 0 {
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					q.logger.Error("gost/time timeout queue callback panic: %v\n%s", r, string(debug.Stack()))
+				}
+			}()
+			for _, id := range evicted {
+				q.onEvict(id)
+			}
+		}()
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutQueue(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	var (
+		lock    sync.Mutex
+		evicted []int
+	)
+	q := NewTimeoutQueue(wheel, 50*time.Millisecond, func(id int) {
+		lock.Lock()
+		evicted = append(evicted, id)
+		lock.Unlock()
+	})
+	defer q.Stop()
+
+	for i := 1; i <= 4; i++ {
+		q.Add(i)
+	}
+	assert.True(t, q.Remove(4))
+	assert.False(t, q.Remove(4))
+	assert.False(t, q.Touch(4))
+	assert.Equal(t, 3, q.Len())
+
+	for i := 0; i < 10; i++ {
+		time.Sleep(10 * time.Millisecond)
+		assert.True(t, q.Touch(1))
+		if i == 2 {
+			q.Add(2)
+		}
+	}
+
+	lock.Lock()
+	assert.Equal(t, []int{3, 2}, evicted)
+	lock.Unlock()
+	assert.Equal(t, 1, q.Len())
+}
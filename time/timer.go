@@ -0,0 +1,266 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// TimerFunc is the callback of a timer added to a Wheel.
+type TimerFunc func(arg interface{})
+
+// Timer is a callback timer driven by a Wheel. All of its fields are protected by the wheel lock.
+type Timer struct {
+	w      *Wheel
+	f      TimerFunc
+	arg    interface{}
+	period time.Duration
+	next   func(after time.Time) time.Time // computes the next fire time instead of @period if it is not nil
+	expect time.Time                       // expected time of the next fire
+	times  int                             // remaining fire times, negative means unlimited
+	rounds int                             // remaining ring rounds before the timer expires
+	stop   bool
+	inline bool // run in the wheel goroutine
+}
+
+// AddTimer adds a timer which invokes @f with @arg every @period until it is stopped.
+func (w *Wheel) AddTimer(f TimerFunc, period time.Duration, arg interface{}) *Timer {
+	return w.addTimer(f, period, -1, arg)
+}
+
+// AddTimerTimes adds a timer which invokes @f with @arg every @period, and removes
+// itself after @count fires. @period can be longer than the period of the ring.
+func (w *Wheel) AddTimerTimes(f TimerFunc, period time.Duration, count int, arg interface{}) *Timer {
+	if count < 1 {
+		panic("@count < 1")
+	}
+
+	return w.addTimer(f, period, count, arg)
+}
+
+// AddTimerInline is the same as AddTimerTimes, except that @f runs directly in the
+// wheel goroutine instead of a new goroutine, which gives microsecond-scale fire latency,
+// and that a negative @count means firing until it is stopped as AddTimer.
+// It is designed for extremely cheap callbacks such as counter bumps: @f MUST NOT block,
+// or it delays every timer of the wheel.
+func (w *Wheel) AddTimerInline(f TimerFunc, period time.Duration, count int, arg interface{}) *Timer {
+	if count == 0 {
+		panic("@count == 0")
+	}
+
+	t := w.newTimer(f, period, count, arg)
+	t.inline = true
+	w.startTimer(t)
+
+	return t
+}
+
+func (w *Wheel) addTimer(f TimerFunc, period time.Duration, times int, arg interface{}) *Timer {
+	t := w.newTimer(f, period, times, arg)
+	w.startTimer(t)
+
+	return t
+}
+
+func (w *Wheel) newTimer(f TimerFunc, period time.Duration, times int, arg interface{}) *Timer {
+	if f == nil {
+		panic("@f is nil")
+	}
+	if period <= 0 {
+		panic("@period <= 0")
+	}
+
+	return &Timer{
+		w:      w,
+		f:      f,
+		arg:    arg,
+		period: period,
+		times:  times,
+	}
+}
+
+func (w *Wheel) startTimer(t *Timer) {
+	w.Lock()
+	w.schedule(t, Now(), t.period, w.phase())
+	w.stats.Timers++
+	w.Unlock()
+}
+
+// AddDailyTimer adds a timer which invokes @f with a nil arg every day at @hour:@min:@sec
+// of @loc. Instead of a fixed 24h period, the next fire time is recomputed by the wall
+// clock after every fire, so the timer keeps its wall clock time across DST transitions.
+// A nil @loc means time.Local.
+func (w *Wheel) AddDailyTimer(hour, min, sec int, loc *time.Location, f TimerFunc) *Timer {
+	if hour < 0 || hour > 23 || min < 0 || min > 59 || sec < 0 || sec > 59 {
+		panic(fmt.Sprintf("illegal daily time %02d:%02d:%02d", hour, min, sec))
+	}
+	if f == nil {
+		panic("@f is nil")
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+
+	t := &Timer{
+		w:     w,
+		f:     f,
+		times: -1,
+		next: func(after time.Time) time.Time {
+			return nextDailyTime(after, hour, min, sec, loc)
+		},
+	}
+
+	w.Lock()
+	now := Now()
+	w.schedule(t, now, t.next(now).Sub(now), w.phase())
+	w.stats.Timers++
+	w.Unlock()
+
+	return t
+}
+
+// nextDailyTime returns the first @hour:@min:@sec of @loc after @after.
+func nextDailyTime(after time.Time, hour, min, sec int, loc *time.Location) time.Time {
+	after = after.In(loc)
+	year, month, day := after.Date()
+	next := time.Date(year, month, day, hour, min, sec, 0, loc)
+	for !next.After(after) {
+		day++
+		next = time.Date(year, month, day, hour, min, sec, 0, loc)
+	}
+
+	return next
+}
+
+// nextPeriod returns the duration from @now to the next fire of @t.
+func (t *Timer) nextPeriod(now time.Time) time.Duration {
+	if t.next == nil {
+		return t.period
+	}
+
+	// never fire twice for the same expected time if the wheel fires a little early
+	after := t.expect
+	if now.After(after) {
+		after = now
+	}
+
+	return t.next(after).Sub(now)
+}
+
+// phase returns the time elapsed since the last tick. A wheel ticked by the wall clock
+// takes it from the wall clock, bounded by [0, w.span), so that a jump of the time
+// source between two ticks does not leak into the timers. It should be invoked with
+// the wheel lock held.
+func (w *Wheel) phase() time.Duration {
+	if w.manualTick {
+		// the ticks due are handled by the next Advance, so the phase is not bounded by the span
+		if phase := Now().Sub(w.last); phase > 0 {
+			return phase
+		}
+		return 0
+	}
+
+	phase := time.Since(w.lastTick)
+	if phase < 0 {
+		return 0
+	}
+	if phase >= w.span {
+		return w.span - 1
+	}
+
+	return phase
+}
+
+// schedule puts @t into the slot which will be expired after @d from @now.
+// @phase is the time elapsed since the last tick, the k-th slot from w.index
+// expires at w.last + (k+1)*w.span, so it is counted in to avoid firing earlier
+// than @d. It should be invoked with the wheel lock held.
+func (w *Wheel) schedule(t *Timer, now time.Time, d, phase time.Duration) {
+	ticks := int((d + phase + w.span - 1) / w.span)
+	if ticks < 1 {
+		ticks = 1
+	}
+
+	t.expect = now.Add(d)
+	w.track(t, t.expect)
+	t.rounds = (ticks - 1) / len(w.ring)
+	pos := (w.index + (ticks-1)%len(w.ring)) % len(w.ring)
+	w.timers[pos] = append(w.timers[pos], t)
+}
+
+// expire picks out the expired timers of slot @pos. It should be invoked with the wheel lock held.
+func (w *Wheel) expire(pos int) []*Timer {
+	var (
+		expired []*Timer
+		slot    = w.timers[pos]
+	)
+
+	w.timers[pos] = nil
+	for _, t := range slot {
+		if t.stop {
+			continue
+		}
+		if t.rounds > 0 {
+			t.rounds--
+			w.timers[pos] = append(w.timers[pos], t)
+			continue
+		}
+
+		if t.times > 0 {
+			t.times--
+		}
+		expired = append(expired, t)
+	}
+
+	return expired
+}
+
+// Stop prevents the timer from firing again. It returns false if the timer
+// has already been stopped or has fired all its times.
+func (t *Timer) Stop() bool {
+	t.w.Lock()
+	defer t.w.Unlock()
+
+	if t.stop || t.times == 0 {
+		return false
+	}
+	t.stop = true
+	t.w.stats.Timers--
+	t.w.untrack(t)
+
+	return true
+}
+
+func (t *Timer) fire() {
+	if t.inline {
+		t.run()
+		return
+	}
+	go t.run()
+}
+
+func (t *Timer) run() {
+	defer func() {
+		if r := recover(); r != nil {
+			t.w.logger.Error("gost/time timer callback panic: %v\n%s", r, string(debug.Stack()))
+		}
+	}()
+	t.f(t.arg)
+}
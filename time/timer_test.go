@@ -0,0 +1,136 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWheelAddTimerTimes(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	var (
+		cnt  int64
+		args = make(chan interface{}, 8)
+	)
+	wheel.AddTimerTimes(func(arg interface{}) {
+		atomic.AddInt64(&cnt, 1)
+		args <- arg
+	}, TimeMillisecondDuration(20), 3, "retry")
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int64(3), atomic.LoadInt64(&cnt))
+	assert.Equal(t, "retry", <-args)
+
+	assert.Panics(t, func() {
+		wheel.AddTimerTimes(func(interface{}) {}, time.Second, 0, nil)
+	})
+}
+
+func TestWheelTimerLongPeriod(t *testing.T) {
+	// the ring's life period is 100ms
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	fired := make(chan time.Time, 1)
+	start := time.Now()
+	timer := wheel.AddTimerTimes(func(interface{}) {
+		fired <- time.Now()
+	}, TimeMillisecondDuration(250), 1, nil)
+
+	select {
+	case at := <-fired:
+		cost := at.Sub(start)
+		assert.True(t, cost >= 230*time.Millisecond, cost)
+		assert.True(t, cost < 400*time.Millisecond, cost)
+	case <-time.After(time.Second):
+		t.Fatal("timer is not fired")
+	}
+	assert.False(t, timer.Stop())
+}
+
+func TestWheelTimerStop(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	var cnt int64
+	timer := wheel.AddTimerTimes(func(interface{}) {
+		atomic.AddInt64(&cnt, 1)
+	}, TimeMillisecondDuration(20), 100, nil)
+
+	time.Sleep(70 * time.Millisecond)
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+	// wait for the callback which may be running
+	time.Sleep(5 * time.Millisecond)
+	fired := atomic.LoadInt64(&cnt)
+	assert.True(t, fired > 0)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, fired, atomic.LoadInt64(&cnt))
+}
+
+func TestWheelAddTimerInline(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	var cnt int64
+	wheel.AddTimerInline(func(interface{}) {
+		panic("inline callback panic")
+	}, TimeMillisecondDuration(10), 1, nil)
+	wheel.AddTimerInline(func(arg interface{}) {
+		atomic.AddInt64(&cnt, int64(arg.(int)))
+	}, TimeMillisecondDuration(20), 3, 2)
+
+	time.Sleep(150 * time.Millisecond)
+	// a panic of an inline callback does not break the wheel loop
+	assert.Equal(t, int64(6), atomic.LoadInt64(&cnt))
+	assert.Equal(t, 0, wheel.Stats().Timers)
+
+	// a negative count fires until the timer is stopped
+	var unlimited int64
+	timer := wheel.AddTimerInline(func(interface{}) {
+		atomic.AddInt64(&unlimited, 1)
+	}, TimeMillisecondDuration(10), -1, nil)
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, timer.Stop())
+	assert.True(t, atomic.LoadInt64(&unlimited) >= 5)
+	assert.Equal(t, 0, wheel.Stats().Timers)
+	assert.Panics(t, func() { wheel.AddTimerInline(func(interface{}) {}, TimeMillisecondDuration(10), 0, nil) })
+}
+
+func TestWheelAddTimer(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10)
+	defer wheel.Stop()
+
+	var cnt int64
+	timer := wheel.AddTimer(func(interface{}) {
+		atomic.AddInt64(&cnt, 1)
+	}, TimeMillisecondDuration(10), nil)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, timer.Stop())
+	assert.True(t, atomic.LoadInt64(&cnt) >= 5)
+}
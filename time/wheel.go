@@ -21,68 +21,242 @@ package gxtime
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+const (
+	defaultWheelSpan    = 10 * time.Millisecond
+	defaultWheelBuckets = 6000
+)
+
+var (
+	defaultWheel        *Wheel
+	defaultWheelOnce    sync.Once
+	defaultWheelCreated uint32
+)
+
+// GetDefaultWheel returns the process wide wheel whose span is 10ms and whose
+// life period is one minute. It is created on first use and runs until StopDefaultWheel.
+func GetDefaultWheel() *Wheel {
+	defaultWheelOnce.Do(func() {
+		defaultWheel = NewWheel(defaultWheelSpan, defaultWheelBuckets)
+		atomic.StoreUint32(&defaultWheelCreated, 1)
+	})
+
+	return defaultWheel
+}
+
+// StopDefaultWheel stops the default wheel if it has been created. It is meant for the
+// process shutdown, as the timers of the default wheel never fire after it.
+func StopDefaultWheel() {
+	if atomic.LoadUint32(&defaultWheelCreated) == 1 {
+		defaultWheel.Stop()
+	}
+}
+
 type Wheel struct {
 	sync.RWMutex
-	span   time.Duration
-	period time.Duration
-	ticker *time.Ticker
-	index  int
-	ring   []chan struct{}
-	once   sync.Once
-	now    time.Time
+	WheelOptions
+
+	span     time.Duration
+	period   time.Duration
+	ticker   *time.Ticker // nil if the wheel is ticked manually
+	index    int
+	ring     []chan struct{}
+	timers   [][]*Timer
+	once     sync.Once
+	now      time.Time
+	last     time.Time  // time of the last tick by the time source
+	lastTick time.Time  // wall time of the last tick read from the ticker
+	tickLock sync.Mutex // serializes the Advance calls of a manual wheel
+	pending  []*Timer   // expired timers deferred by the batch budget, accessed by the ticking goroutine
+	stats    WheelStats
+	drift    drift
+	check    *selfCheck // nil unless the self-check mode is on
+
+	autoTune *autoTune // nil unless the auto-tune mode is on
 }
 
-func NewWheel(span time.Duration, buckets int) *Wheel {
+func NewWheel(span time.Duration, buckets int, opts ...WheelOption) *Wheel {
 	var (
-		w *Wheel
+		w     *Wheel
+		wOpts WheelOptions
 	)
 
+	for _, opt := range opts {
+		opt(&wOpts)
+	}
+
 	if span == 0 {
 		panic("@span == 0")
 	}
 	if buckets == 0 {
 		panic("@bucket == 0")
 	}
+	if wOpts.logger == nil {
+		wOpts.logger = stderrLogger{}
+	}
 
 	w = &Wheel{
-		span:   span,
-		period: span * (time.Duration(buckets)),
-		ticker: time.NewTicker(span),
-		index:  0,
-		ring:   make([]chan struct{}, buckets),
-		now:    time.Now(),
+		WheelOptions: wOpts,
+		span:         span,
+		period:       span * (time.Duration(buckets)),
+		index:        0,
+		ring:         make([]chan struct{}, buckets),
+		timers:       make([][]*Timer, buckets),
+		now:          Now(),
+		lastTick:     time.Now(),
+	}
+	w.last = w.now
+	w.drift = newDrift(w.last)
+	w.stats.Span = span
+
+	if wOpts.selfCheck {
+		w.check = newSelfCheck(wOpts.tolerance, span)
+	}
+	if wOpts.tuneMax != 0 {
+		w.autoTune = newAutoTune(wOpts.tuneMin, wOpts.tuneMax, w.stats, w.now)
+	}
+
+	if !wOpts.manualTick {
+		w.ticker = time.NewTicker(span)
+		go w.run()
 	}
 
-	go func() {
-		var notify chan struct{}
-		for t := range w.ticker.C {
-			w.Lock()
-			w.now = t
+	return w
+}
 
-			notify = w.ring[w.index]
-			w.ring[w.index] = nil
-			w.index = (w.index + 1) % len(w.ring)
+func (w *Wheel) run() {
+	for tick := range w.ticker.C {
+		w.tick(tick)
+	}
+}
+
+// Advance handles the ticks due by Now() of the package time source since the last
+// one, and returns the number of them. Every tick is handled at its own time, stamped
+// to Now() of the wheel and its timers. The inline callbacks run in the caller. It
+// panics unless the wheel is created WithWheelManualTick.
+func (w *Wheel) Advance() int {
+	if !w.manualTick {
+		panic("Advance of a wheel without WithWheelManualTick")
+	}
 
-			w.Unlock()
+	w.tickLock.Lock()
+	defer w.tickLock.Unlock()
 
-			if notify != nil {
-				close(notify)
-			}
+	n := 0
+	for {
+		w.RLock()
+		next := w.last.Add(w.span)
+		w.RUnlock()
+		if Now().Before(next) {
+			return n
 		}
-	}()
+		w.tick(next)
+		n++
+	}
+}
 
-	return w
+// tick handles a tick at @tick, which is the wall time of the ticker, or the time
+// source time of a manual tick.
+func (w *Wheel) tick(tick time.Time) {
+	w.Lock()
+	if w.manualTick {
+		w.now = tick
+		w.last = tick
+	} else {
+		w.now = Now()
+		if elapsed := tick.Sub(w.lastTick); elapsed > w.span*3/2 {
+			w.stats.DroppedTicks += uint64(elapsed/w.span) - 1
+		}
+		w.lastTick = tick
+		// the tick time in the time source, which is the wall clock by default
+		w.last = w.now.Add(-time.Since(tick))
+	}
+	w.pending = unstopped(w.pending)
+	w.stats.Deferred = len(w.pending)
+	w.advanceDrift(w.span)
+
+	notify := w.ring[w.index]
+	w.ring[w.index] = nil
+	expired := w.expire(w.index)
+	w.index = (w.index + 1) % len(w.ring)
+	for _, t := range expired {
+		w.stats.Fired++
+		w.checkFire(t)
+		if late := w.now.Sub(t.expect); late > 0 {
+			w.stats.FireLatency += late
+		}
+		if t.times != 0 {
+			// rescheduled at the tick, so the phase is zero
+			w.schedule(t, w.now, t.nextPeriod(w.now), 0)
+		} else {
+			w.stats.Timers--
+		}
+	}
+	w.stats.Batch = len(expired)
+	w.scanLost()
+	w.tune()
+
+	w.Unlock()
+
+	if notify != nil {
+		close(notify)
+	}
+	w.pending = w.dispatch(append(w.pending, expired...))
+}
+
+// dispatch fires @expired in order, and returns the remainder once the batch budget is used up.
+func (w *Wheel) dispatch(expired []*Timer) []*Timer {
+	if w.batchBudget <= 0 {
+		for _, t := range expired {
+			t.fire()
+		}
+		return nil
+	}
+
+	start := time.Now()
+	for i, t := range expired {
+		if i > 0 && i%budgetCheckInterval == 0 && time.Since(start) > w.batchBudget {
+			return append([]*Timer(nil), expired[i:]...)
+		}
+		t.fire()
+	}
+
+	return nil
+}
+
+// unstopped drops the timers stopped after they were deferred from @timers, so that
+// they do not fire once their Stop returns true. It should be invoked with the wheel
+// lock held.
+func unstopped(timers []*Timer) []*Timer {
+	n := 0
+	for _, t := range timers {
+		if !t.stop {
+			timers[n] = t
+			n++
+		}
+	}
+	for i := n; i < len(timers); i++ {
+		timers[i] = nil
+	}
+
+	return timers[:n]
 }
 
 func (w *Wheel) Stop() {
-	w.once.Do(func() { w.ticker.Stop() })
+	w.once.Do(func() {
+		if w.ticker != nil {
+			w.ticker.Stop()
+		}
+	})
 }
 
 func (w *Wheel) After(timeout time.Duration) <-chan struct{} {
+	w.Lock()
 	if timeout >= w.period {
+		w.Unlock()
 		panic("@timeout over ring's life period")
 	}
 
@@ -90,8 +264,6 @@ func (w *Wheel) After(timeout time.Duration) <-chan struct{} {
 	if 0 < pos {
 		pos--
 	}
-
-	w.Lock()
 	pos = (w.index + pos) % len(w.ring)
 	if w.ring[pos] == nil {
 		w.ring[pos] = make(chan struct{})
@@ -102,6 +274,24 @@ func (w *Wheel) After(timeout time.Duration) <-chan struct{} {
 	return c
 }
 
+// Period returns the life period of the ring. @timeout of After should be less than it.
+func (w *Wheel) Period() time.Duration {
+	w.RLock()
+	period := w.period
+	w.RUnlock()
+
+	return period
+}
+
+// Span returns the current tick interval of the wheel.
+func (w *Wheel) Span() time.Duration {
+	w.RLock()
+	span := w.span
+	w.RUnlock()
+
+	return span
+}
+
 func (w *Wheel) Now() time.Time {
 	w.RLock()
 	now := w.now
@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"time"
+)
+
+const (
+	// budgetCheckInterval is the number of callbacks dispatched between two budget checks
+	budgetCheckInterval = 16
+)
+
+/////////////////////////////////////////
+// Wheel Options
+/////////////////////////////////////////
+
+// WheelOptions is optional settings for wheel
+type WheelOptions struct {
+	batchBudget time.Duration // max time spent on dispatching the callbacks of a tick
+	logger      Logger
+	selfCheck   bool
+	tolerance   time.Duration // fire time tolerance of the self-check mode
+	tuneMin     time.Duration // the span bounds of the auto-tune mode, which is off if tuneMax is zero
+	tuneMax     time.Duration
+	manualTick  bool // ticked by Advance instead of a ticker
+}
+
+type WheelOption func(*WheelOptions)
+
+// WithWheelBatchBudget limits the time spent on dispatching the expired callbacks
+// of one tick to @budget. The remainder is deferred to the next tick in order, so a
+// large batch of expired timers cannot stall the wheel loop.
+func WithWheelBatchBudget(budget time.Duration) WheelOption {
+	return func(o *WheelOptions) {
+		o.batchBudget = budget
+	}
+}
+
+// WithWheelLogger makes the wheel report its internal errors to @logger instead of stderr.
+// Use NopLogger to silence the wheel.
+func WithWheelLogger(logger Logger) WheelOption {
+	return func(o *WheelOptions) {
+		o.logger = logger
+	}
+}
+
+// WithWheelSelfCheck turns on the self-check mode, which is meant for canaries and
+// tests rather than production. The wheel tracks the logical deadline of every timer
+// apart from its slots, and reports every timer firing more than @tolerance out of
+// [deadline, deadline + span] or not firing at all to the wheel logger with the state
+// of the timer and the wheel. The violations are also counted in WheelStats.
+func WithWheelSelfCheck(tolerance time.Duration) WheelOption {
+	return func(o *WheelOptions) {
+		o.selfCheck = true
+		o.tolerance = tolerance
+	}
+}
+
+// WithWheelManualTick makes the wheel tick by Advance by the package time source of
+// SetTimeSource, instead of by a ticker of the wall clock. It is designed for the
+// simulations and the unit tests, in which the time source drives the triggers and
+// the cascades of the timers as well as Now().
+func WithWheelManualTick() WheelOption {
+	return func(o *WheelOptions) {
+		o.manualTick = true
+	}
+}
+
+// WithWheelAutoTune turns on the auto-tune mode. Every second the wheel looks at the
+// fire latency and the number of timers expired per tick, then doubles or halves its
+// span within [@min, @max]: coarser ticks save CPU for sparse timers or an overloaded
+// wheel loop, and finer ticks give dense timers better precision. The period of the
+// ring follows the span, so After panics sooner on a finer ring. The decisions are
+// logged at the info level and reported by WheelStats.
+func WithWheelAutoTune(min, max time.Duration) WheelOption {
+	return func(o *WheelOptions) {
+		o.tuneMin = min
+		o.tuneMax = max
+	}
+}
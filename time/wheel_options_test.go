@@ -0,0 +1,120 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxtime
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWheelBatchBudget(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10, WithWheelBatchBudget(time.Nanosecond))
+	defer wheel.Stop()
+
+	var (
+		cnt   int64
+		order = make(chan int, 100)
+	)
+	for i := 0; i < 100; i++ {
+		wheel.AddTimerTimes(func(arg interface{}) {
+			atomic.AddInt64(&cnt, 1)
+			order <- arg.(int)
+		}, TimeMillisecondDuration(20), 1, i)
+	}
+
+	var deferred bool
+	for i := 0; i < 20 && atomic.LoadInt64(&cnt) < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		deferred = deferred || wheel.Stats().Deferred > 0
+	}
+	assert.Equal(t, int64(100), atomic.LoadInt64(&cnt))
+	assert.True(t, deferred)
+
+	// the dispatched batches keep the timer order, but the callbacks of one batch run concurrently
+	first := make([]int, 0, budgetCheckInterval)
+	for i := 0; i < budgetCheckInterval; i++ {
+		first = append(first, <-order)
+	}
+	for _, i := range first {
+		assert.True(t, i < budgetCheckInterval, i)
+	}
+}
+
+func TestWheelBatchBudgetStop(t *testing.T) {
+	wheel := NewWheel(TimeMillisecondDuration(10), 10, WithWheelBatchBudget(time.Nanosecond))
+	defer wheel.Stop()
+
+	const n = 2 * budgetCheckInterval
+	var (
+		timers [n]*Timer
+		fired  [n]int64
+		ready  = make(chan struct{})
+		stops  = make(chan bool, n)
+	)
+	for i := 0; i < n; i++ {
+		i := i
+		timers[i] = wheel.AddTimerInline(func(interface{}) {
+			atomic.AddInt64(&fired[i], 1)
+			if i == 0 {
+				<-ready
+				// the timers after the first batch are deferred to the next tick
+				for j := budgetCheckInterval; j < n; j++ {
+					stops <- timers[j].Stop()
+				}
+			}
+		}, TimeMillisecondDuration(20), 3, nil)
+	}
+	close(ready)
+
+	time.Sleep(100 * time.Millisecond)
+	for j := budgetCheckInterval; j < n; j++ {
+		assert.True(t, <-stops)
+		assert.Equal(t, int64(0), atomic.LoadInt64(&fired[j]), j)
+	}
+}
+
+type recordLogger struct {
+	NopLogger
+	errs chan string
+}
+
+func (l *recordLogger) Error(format string, args ...interface{}) {
+	l.errs <- fmt.Sprintf(format, args...)
+}
+
+func TestWheelLogger(t *testing.T) {
+	logger := &recordLogger{errs: make(chan string, 1)}
+	wheel := NewWheel(TimeMillisecondDuration(10), 10, WithWheelLogger(logger))
+	defer wheel.Stop()
+
+	wheel.AddTimerTimes(func(interface{}) {
+		panic("boom")
+	}, TimeMillisecondDuration(10), 1, nil)
+	select {
+	case msg := <-logger.errs:
+		assert.Contains(t, msg, "boom")
+	case <-time.After(time.Second):
+		t.Fatal("callback panic is not logged")
+	}
+}